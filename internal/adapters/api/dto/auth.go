@@ -1,15 +1,31 @@
 package dto
 
-import "github.com/JGCaceres97/parking/internal/domain"
+import (
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/response"
+)
 
 type LoginRequest struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 }
 
+type LoginMFARequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+func (r LoginMFARequest) Validate() error {
+	return response.Required("mfa_token", r.MFAToken, "code", r.Code)
+}
+
 type LoginResponse struct {
-	Role      domain.Role `json:"role"`
-	ExpiresIn int64       `json:"expires_in"`
-	TokenType string      `json:"token_type"`
-	Token     string      `json:"token"`
+	Role                  domain.Role `json:"role"`
+	ExpiresIn             int64       `json:"expires_in"`
+	TokenType             string      `json:"token_type,omitempty"`
+	Token                 string      `json:"token,omitempty"`
+	MustChangePassword    bool        `json:"must_change_password"`
+	MFARequired           bool        `json:"mfa_required"`
+	MFAToken              string      `json:"mfa_token,omitempty"`
+	MFAEnrollmentRequired bool        `json:"mfa_enrollment_required"`
 }
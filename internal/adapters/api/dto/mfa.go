@@ -0,0 +1,17 @@
+package dto
+
+import "github.com/JGCaceres97/parking/pkg/response"
+
+type MFACodeRequest struct {
+	Code string `json:"code"`
+}
+
+func (r MFACodeRequest) Validate() error {
+	return response.Required("code", r.Code)
+}
+
+type MFAEnrollmentResponse struct {
+	Secret        string   `json:"secret"`
+	OTPAuthURI    string   `json:"otpauth_uri"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -1,10 +1,28 @@
 package dto
 
+import "github.com/JGCaceres97/parking/pkg/response"
+
 type EntryRequest struct {
 	VehicleTypeID string `json:"vehicle_type_id"`
 	LicensePlate  string `json:"license_plate"`
 }
 
+func (r EntryRequest) Validate() error {
+	return response.Required("vehicle_type_id", r.VehicleTypeID, "license_plate", r.LicensePlate)
+}
+
 type ExitRequest struct {
 	LicensePlate string `json:"license_plate"`
 }
+
+func (r ExitRequest) Validate() error {
+	return response.Required("license_plate", r.LicensePlate)
+}
+
+type VoidRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (r VoidRequest) Validate() error {
+	return response.Required("reason", r.Reason)
+}
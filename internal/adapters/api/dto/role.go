@@ -0,0 +1,14 @@
+package dto
+
+import "github.com/JGCaceres97/parking/internal/domain"
+
+type CreateRoleRequest struct {
+	Name        domain.Role         `json:"name"`
+	Description string              `json:"description"`
+	Permissions []domain.Permission `json:"permissions"`
+}
+
+type UpdateRoleRequest struct {
+	Description string              `json:"description"`
+	Permissions []domain.Permission `json:"permissions"`
+}
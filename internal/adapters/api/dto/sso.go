@@ -0,0 +1,12 @@
+package dto
+
+import "github.com/JGCaceres97/parking/pkg/response"
+
+type LinkIdentityRequest struct {
+	Subject string `json:"subject"`
+	Email   string `json:"email"`
+}
+
+func (r LinkIdentityRequest) Validate() error {
+	return response.Required("subject", r.Subject)
+}
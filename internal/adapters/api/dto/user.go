@@ -1,6 +1,9 @@
 package dto
 
-import "github.com/JGCaceres97/parking/internal/domain"
+import (
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/response"
+)
 
 type CreateUserRequest struct {
 	Username string      `json:"username"`
@@ -9,6 +12,10 @@ type CreateUserRequest struct {
 	IsActive bool        `json:"is_active"`
 }
 
+func (r CreateUserRequest) Validate() error {
+	return response.Required("username", r.Username, "password", r.Password, "role", string(r.Role))
+}
+
 type UpdateUserRequest struct {
 	Username string      `json:"username"`
 	Role     domain.Role `json:"role"`
@@ -18,3 +25,16 @@ type UpdateUserRequest struct {
 type ToggleActiveRequest struct {
 	IsActive bool `json:"is_active"`
 }
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+func (r ChangePasswordRequest) Validate() error {
+	return response.Required("current_password", r.CurrentPassword, "new_password", r.NewPassword)
+}
+
+type ResetPasswordResponse struct {
+	TemporaryPassword string `json:"temporary_password"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+import "github.com/JGCaceres97/parking/internal/domain"
+
+type WebhookSubscriptionRequest struct {
+	URL        string             `json:"url"`
+	EventTypes []domain.EventType `json:"event_types"`
+	Secret     string             `json:"secret"`
+	IsActive   *bool              `json:"is_active"`
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/adapters/api/problem"
+	"github.com/JGCaceres97/parking/internal/application/audit"
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/response"
+)
+
+type auditHandler struct {
+	service audit.Service
+}
+
+func NewAuditHandler(service audit.Service) *auditHandler {
+	return &auditHandler{service: service}
+}
+
+func (h *auditHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := domain.AuditFilter{
+		ActorID:    query.Get("actor_id"),
+		Action:     query.Get("action"),
+		EntityType: query.Get("entity_type"),
+		EntityID:   query.Get("entity_id"),
+		Limit:      100,
+	}
+
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 || n > 1000 {
+			problem.Write(w, r, response.ErrInvalidLimit)
+			return
+		}
+
+		filter.Limit = n
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(query, "from"); err != nil {
+		problem.Write(w, r, response.ErrInvalidAuditFilter)
+		return
+	}
+
+	if filter.To, err = parseTimeParam(query, "to"); err != nil {
+		problem.Write(w, r, response.ErrInvalidAuditFilter)
+		return
+	}
+
+	if value := query.Get("before_seq"); value != "" {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n <= 0 {
+			problem.Write(w, r, response.ErrInvalidAuditFilter)
+			return
+		}
+
+		filter.BeforeSeq = n
+	}
+
+	entries, err := h.service.List(r.Context(), filter)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, entries)
+}
+
+func (h *auditHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	result, err := h.service.Verify(r.Context())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, result)
+}
+
+// parseTimeParam interpreta un parámetro opcional de fecha en formato RFC 3339.
+func parseTimeParam(query url.Values, name string) (*time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+
+	t = t.UTC()
+	return &t, nil
+}
@@ -2,12 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/JGCaceres97/parking/internal/adapters/api/dto"
+	"github.com/JGCaceres97/parking/internal/adapters/api/middlewares"
+	"github.com/JGCaceres97/parking/internal/adapters/api/problem"
 	"github.com/JGCaceres97/parking/internal/application/auth"
-	"github.com/JGCaceres97/parking/internal/domain"
 	"github.com/JGCaceres97/parking/pkg/response"
 )
 
@@ -22,36 +23,81 @@ func NewAuthHandler(service auth.Service) *authHandler {
 func (h *authHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req dto.LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.ErrorJSON(w, response.ErrInvalidJSON, http.StatusBadRequest)
+		problem.Write(w, r, response.ErrInvalidJSON)
 		return
 	}
 
 	out, err := h.service.Login(
 		r.Context(),
-		auth.LoginInput{Username: req.Username, Password: req.Password})
+		auth.LoginInput{Username: req.Username, Password: req.Password, IP: middlewares.ClientIP(r)})
 
 	if err != nil {
-		if errors.Is(err, domain.ErrInvalidCredentials) {
-			response.ErrorJSON(w, response.ErrInvalidCredentials, http.StatusUnauthorized)
-			return
-		}
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, toLoginResponse(out))
+}
+
+func (h *authHandler) LoginMFA(w http.ResponseWriter, r *http.Request) {
+	var req dto.LoginMFARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, response.ErrInvalidJSON)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	out, err := h.service.LoginMFA(
+		r.Context(),
+		auth.LoginMFAInput{MFAToken: req.MFAToken, Code: req.Code, IP: middlewares.ClientIP(r)})
 
-		if errors.Is(err, domain.ErrUserInactive) {
-			response.ErrorJSON(w, response.ErrUserBlocked, http.StatusForbidden)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, toLoginResponse(out))
+}
+
+func (h *authHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	response.JSON(w, http.StatusOK, h.service.JWKS())
+}
+
+func (h *authHandler) ListLoginAttempts(w http.ResponseWriter, r *http.Request) {
+	limit := 100
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 || n > 1000 {
+			problem.Write(w, r, response.ErrInvalidLimit)
 			return
 		}
 
-		response.ErrorJSON(w, response.ErrInternalError, http.StatusInternalServerError)
+		limit = n
+	}
+
+	attempts, err := h.service.ListLoginAttempts(r.Context(), r.URL.Query().Get("username"), limit)
+	if err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
-	response.JSON(
-		w,
-		http.StatusOK,
-		dto.LoginResponse{
-			Token:     out.Token,
-			TokenType: out.TokenType,
-			ExpiresIn: out.ExpiresIn,
-			Role:      out.Role},
-	)
+	response.JSON(w, http.StatusOK, attempts)
+}
+
+func toLoginResponse(out *auth.LoginOutput) dto.LoginResponse {
+	return dto.LoginResponse{
+		Token:                 out.Token,
+		TokenType:             out.TokenType,
+		ExpiresIn:             out.ExpiresIn,
+		Role:                  out.Role,
+		MustChangePassword:    out.MustChangePassword,
+		MFARequired:           out.MFARequired,
+		MFAToken:              out.MFAToken,
+		MFAEnrollmentRequired: out.MFAEnrollmentRequired,
+	}
 }
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/JGCaceres97/parking/internal/adapters/api/problem"
+	"github.com/JGCaceres97/parking/internal/application/backup"
+	"github.com/JGCaceres97/parking/pkg/response"
+)
+
+type backupHandler struct {
+	service backup.Service
+}
+
+func NewBackupHandler(service backup.Service) *backupHandler {
+	return &backupHandler{service: service}
+}
+
+func (h *backupHandler) List(w http.ResponseWriter, r *http.Request) {
+	backups, err := h.service.List(r.Context())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, backups)
+}
+
+func (h *backupHandler) Create(w http.ResponseWriter, r *http.Request) {
+	created, err := h.service.Create(r.Context())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, created)
+}
+
+func (h *backupHandler) Download(w http.ResponseWriter, r *http.Request) {
+	file, backup, err := h.service.Open(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", "application/vnd.sqlite3")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+backup.Name+`"`)
+	w.Header().Set("Content-Length", strconv.FormatInt(backup.Size, 10))
+	w.WriteHeader(http.StatusOK)
+
+	io.Copy(w, file)
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+
+	"github.com/JGCaceres97/parking/internal/adapters/api/middlewares"
+	"github.com/JGCaceres97/parking/internal/adapters/api/problem"
+	"github.com/JGCaceres97/parking/internal/application/events"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// heartbeatInterval es el tiempo entre mensajes de control que mantienen viva la conexión a
+// través de proxies.
+const heartbeatInterval = 25 * time.Second
+
+type eventsHandler struct {
+	service events.Service
+}
+
+func NewEventsHandler(service events.Service) *eventsHandler {
+	return &eventsHandler{service: service}
+}
+
+// subscribe suscribe al usuario autenticado a los tipos de evento del parámetro types.
+func (h *eventsHandler) subscribe(w http.ResponseWriter, r *http.Request) (events.Subscription, string, bool) {
+	userID, err := middlewares.GetUserIDFromContext(r.Context())
+	if err != nil {
+		problem.Write(w, r, err)
+		return nil, "", false
+	}
+
+	userRole, _ := r.Context().Value(middlewares.UserRoleKey).(string)
+
+	var types []domain.EventType
+	if value := r.URL.Query().Get("types"); value != "" {
+		types = strings.Split(value, ",")
+	}
+
+	sub, err := h.service.Subscribe(r.Context(), userID, userRole, types)
+	if err != nil {
+		problem.Write(w, r, err)
+		return nil, "", false
+	}
+
+	return sub, userID, true
+}
+
+// Stream envía los eventos como Server-Sent Events.
+func (h *eventsHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	sub, userID, ok := h.subscribe(w, r)
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// El cliente reintenta a los 3 s si la conexión se corta.
+	fmt.Fprint(w, "retry: 3000\n\n")
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+
+			if isOwnDeactivation(event, userID) {
+				rc.Flush()
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// WebSocket envía los eventos como mensajes JSON por una conexión WebSocket.
+func (h *eventsHandler) WebSocket(w http.ResponseWriter, r *http.Request) {
+	sub, userID, ok := h.subscribe(w, r)
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		Subprotocols: []string{middlewares.WebSocketSubprotocol},
+	})
+
+	if err != nil {
+		return
+	}
+	defer conn.CloseNow()
+
+	// Los mensajes del cliente se ignoran; CloseRead cancela el contexto al cerrarse la conexión.
+	ctx := conn.CloseRead(r.Context())
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-heartbeat.C:
+			pingCtx, cancel := context.WithTimeout(ctx, heartbeatInterval)
+			err := conn.Ping(pingCtx)
+			cancel()
+
+			if err != nil {
+				return
+			}
+
+		case event, ok := <-sub.Events():
+			if !ok {
+				conn.Close(websocket.StatusTryAgainLater, "conexión terminada; vuelva a conectarse")
+				return
+			}
+
+			writeCtx, cancel := context.WithTimeout(ctx, heartbeatInterval)
+			err := wsjson.Write(writeCtx, conn, event)
+			cancel()
+
+			if err != nil {
+				return
+			}
+
+			if isOwnDeactivation(event, userID) {
+				conn.Close(websocket.StatusPolicyViolation, "usuario desactivado")
+				return
+			}
+		}
+	}
+}
+
+// isOwnDeactivation indica si el evento desactiva al usuario suscrito, en cuyo caso se cierra la
+// conexión.
+func isOwnDeactivation(event domain.Event, userID string) bool {
+	data, ok := event.Data.(domain.UserDeactivatedData)
+	return ok && data.UserID == userID
+}
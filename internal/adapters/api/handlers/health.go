@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/JGCaceres97/parking/internal/application/health"
+	"github.com/JGCaceres97/parking/internal/infrastructure/buildinfo"
+	"github.com/JGCaceres97/parking/pkg/response"
+)
+
+type healthHandler struct {
+	service health.Service
+	build   buildinfo.Info
+}
+
+func NewHealthHandler(service health.Service, build buildinfo.Info) *healthHandler {
+	return &healthHandler{service: service, build: build}
+}
+
+// Live responde mientras el proceso atienda solicitudes, sin consultar dependencias.
+func (h *healthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	response.JSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Ready responde 503 si la aplicación no puede recibir tráfico: al detenerse o si falla una
+// dependencia crítica. Las dependencias degradadas se informan con 200.
+func (h *healthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	result := h.service.Ready(r.Context())
+
+	status := http.StatusOK
+	if !result.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+
+	response.JSON(w, status, result)
+}
+
+func (h *healthHandler) Version(w http.ResponseWriter, r *http.Request) {
+	response.JSON(w, http.StatusOK, h.build)
+}
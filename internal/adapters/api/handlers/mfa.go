@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/JGCaceres97/parking/internal/adapters/api/dto"
+	"github.com/JGCaceres97/parking/internal/adapters/api/middlewares"
+	"github.com/JGCaceres97/parking/internal/adapters/api/problem"
+	"github.com/JGCaceres97/parking/internal/application/auth"
+	"github.com/JGCaceres97/parking/internal/application/mfa"
+	"github.com/JGCaceres97/parking/pkg/response"
+)
+
+type mfaHandler struct {
+	service mfa.Service
+	auth    auth.Service
+}
+
+func NewMFAHandler(service mfa.Service, auth auth.Service) *mfaHandler {
+	return &mfaHandler{service: service, auth: auth}
+}
+
+func (h *mfaHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	userID, err := middlewares.GetUserIDFromContext(r.Context())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	enrollment, err := h.service.Enroll(r.Context(), userID)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(
+		w,
+		http.StatusCreated,
+		dto.MFAEnrollmentResponse{
+			Secret:        enrollment.Secret,
+			OTPAuthURI:    enrollment.URI,
+			RecoveryCodes: enrollment.RecoveryCodes,
+		},
+	)
+}
+
+func (h *mfaHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.decodeCode(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Confirm(r.Context(), userID, req.Code); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	// El token vigente aún exige configurar el segundo factor.
+	out, err := h.auth.Reissue(r.Context(), userID)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, toLoginResponse(out))
+}
+
+func (h *mfaHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.decodeCode(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Disable(r.Context(), userID, req.Code); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, nil)
+}
+
+func (h *mfaHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	if userID == "" {
+		problem.Write(w, r, response.ErrInvalidID)
+		return
+	}
+
+	if err := h.service.Reset(r.Context(), userID); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, nil)
+}
+
+func (h *mfaHandler) decodeCode(w http.ResponseWriter, r *http.Request) (string, dto.MFACodeRequest, bool) {
+	var req dto.MFACodeRequest
+
+	userID, err := middlewares.GetUserIDFromContext(r.Context())
+	if err != nil {
+		problem.Write(w, r, err)
+		return "", req, false
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, response.ErrInvalidJSON)
+		return "", req, false
+	}
+
+	if err := req.Validate(); err != nil {
+		problem.Write(w, r, err)
+		return "", req, false
+	}
+
+	return userID, req, true
+}
@@ -2,15 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/JGCaceres97/parking/internal/adapters/api/dto"
 	"github.com/JGCaceres97/parking/internal/adapters/api/middlewares"
+	"github.com/JGCaceres97/parking/internal/adapters/api/problem"
 	"github.com/JGCaceres97/parking/internal/application/parking"
-	"github.com/JGCaceres97/parking/internal/domain"
 	"github.com/JGCaceres97/parking/pkg/response"
 )
 
@@ -25,34 +24,24 @@ func NewParkingHandler(service parking.Service) *parkingHandler {
 func (h *parkingHandler) RecordEntry(w http.ResponseWriter, r *http.Request) {
 	userID, err := middlewares.GetUserIDFromContext(r.Context())
 	if err != nil {
-		response.ErrorJSON(w, response.ErrInternalError, http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
 	var req dto.EntryRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.ErrorJSON(w, response.ErrInvalidJSON, http.StatusBadRequest)
+		problem.Write(w, r, response.ErrInvalidJSON)
 		return
 	}
 
-	if req.VehicleTypeID == "" || req.LicensePlate == "" {
-		response.ErrorJSON(w, response.ErrPlateAndTypeRequired, http.StatusBadRequest)
+	if err := req.Validate(); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
 	record, err := h.service.RecordEntry(r.Context(), userID, req.VehicleTypeID, req.LicensePlate)
 	if err != nil {
-		if errors.Is(err, domain.ErrActiveParkingAlreadyExists) {
-			response.ErrorJSON(w, err, http.StatusConflict)
-			return
-		}
-
-		if errors.Is(err, domain.ErrVehicleTypeNotFound) {
-			response.ErrorJSON(w, err, http.StatusNotFound)
-			return
-		}
-
-		response.ErrorJSON(w, response.ErrInternalError, http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -62,50 +51,73 @@ func (h *parkingHandler) RecordEntry(w http.ResponseWriter, r *http.Request) {
 func (h *parkingHandler) RecordExit(w http.ResponseWriter, r *http.Request) {
 	userID, err := middlewares.GetUserIDFromContext(r.Context())
 	if err != nil {
-		response.ErrorJSON(w, response.ErrInternalError, http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
 	var req dto.ExitRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.ErrorJSON(w, response.ErrInvalidJSON, http.StatusBadRequest)
+		problem.Write(w, r, response.ErrInvalidJSON)
 		return
 	}
 
-	if req.LicensePlate == "" {
-		response.ErrorJSON(w, response.ErrPlateRequired, http.StatusBadRequest)
+	if err := req.Validate(); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
 	record, err := h.service.RecordExit(r.Context(), userID, req.LicensePlate)
 	if err != nil {
-		if errors.Is(err, domain.ErrActiveParkingNotFound) {
-			response.ErrorJSON(w, err, http.StatusNotFound)
-			return
-		}
-
-		response.ErrorJSON(w, response.ErrInternalError, http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
 	response.JSON(w, http.StatusCreated, record)
 }
 
+func (h *parkingHandler) Void(w http.ResponseWriter, r *http.Request) {
+	userID, err := middlewares.GetUserIDFromContext(r.Context())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	recordID := chi.URLParam(r, "id")
+	if recordID == "" {
+		problem.Write(w, r, response.ErrRegistryIDRequired)
+		return
+	}
+
+	var req dto.VoidRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, response.ErrInvalidJSON)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	record, err := h.service.Void(r.Context(), userID, recordID, req.Reason)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, record)
+}
+
 func (h *parkingHandler) GetRecordByID(w http.ResponseWriter, r *http.Request) {
 	recordID := chi.URLParam(r, "id")
 	if recordID == "" {
-		response.ErrorJSON(w, response.ErrRegistryIDRequired, http.StatusBadRequest)
+		problem.Write(w, r, response.ErrRegistryIDRequired)
 		return
 	}
 
 	record, err := h.service.GetRecordByID(r.Context(), recordID)
 	if err != nil {
-		if errors.Is(err, domain.ErrParkingRecordNotFound) {
-			response.ErrorJSON(w, err, http.StatusNotFound)
-			return
-		}
-
-		response.ErrorJSON(w, response.ErrInternalError, http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -115,7 +127,7 @@ func (h *parkingHandler) GetRecordByID(w http.ResponseWriter, r *http.Request) {
 func (h *parkingHandler) GetCurrentlyParked(w http.ResponseWriter, r *http.Request) {
 	records, err := h.service.GetCurrentlyParked(r.Context())
 	if err != nil {
-		response.ErrorJSON(w, response.ErrInternalError, http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -125,7 +137,7 @@ func (h *parkingHandler) GetCurrentlyParked(w http.ResponseWriter, r *http.Reque
 func (h *parkingHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 	records, err := h.service.GetHistory(r.Context())
 	if err != nil {
-		response.ErrorJSON(w, response.ErrInternalError, http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
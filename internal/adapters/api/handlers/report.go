@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/JGCaceres97/parking/internal/adapters/api/problem"
+	"github.com/JGCaceres97/parking/internal/application/report"
+	"github.com/JGCaceres97/parking/pkg/response"
+)
+
+type reportHandler struct {
+	service report.Service
+}
+
+func NewReportHandler(service report.Service) *reportHandler {
+	return &reportHandler{service: service}
+}
+
+func (h *reportHandler) Revenue(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	result, err := h.service.Revenue(r.Context(), query.Get("from"), query.Get("to"))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/JGCaceres97/parking/internal/adapters/api/dto"
+	"github.com/JGCaceres97/parking/internal/adapters/api/problem"
+	"github.com/JGCaceres97/parking/internal/application/role"
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/response"
+)
+
+type roleHandler struct {
+	service role.Service
+}
+
+func NewRoleHandler(service role.Service) *roleHandler {
+	return &roleHandler{service: service}
+}
+
+func (h *roleHandler) ListPermissions(w http.ResponseWriter, r *http.Request) {
+	response.JSON(w, http.StatusOK, domain.Permissions)
+}
+
+func (h *roleHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.service.ListAll(r.Context())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, roles)
+}
+
+func (h *roleHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, response.ErrInvalidJSON)
+		return
+	}
+
+	newRole := &domain.RoleDefinition{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+	}
+
+	role, err := h.service.Create(r.Context(), newRole)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, role)
+}
+
+func (h *roleHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		problem.Write(w, r, response.ErrRoleNameRequired)
+		return
+	}
+
+	var req dto.UpdateRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, response.ErrInvalidJSON)
+		return
+	}
+
+	role, err := h.service.Update(r.Context(), name, &domain.RoleDefinition{
+		Description: req.Description,
+		Permissions: req.Permissions,
+	})
+
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, role)
+}
+
+func (h *roleHandler) DeleteRole(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		problem.Write(w, r, response.ErrRoleNameRequired)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), name); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, nil)
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/JGCaceres97/parking/internal/adapters/api/dto"
+	"github.com/JGCaceres97/parking/internal/adapters/api/middlewares"
+	"github.com/JGCaceres97/parking/internal/adapters/api/problem"
+	"github.com/JGCaceres97/parking/internal/application/auth"
+	"github.com/JGCaceres97/parking/internal/application/sso"
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/response"
+)
+
+// ssoStateCookie liga el state del flujo al navegador que lo inició.
+const ssoStateCookie = "sso_state"
+
+type ssoHandler struct {
+	service      sso.Service
+	postLoginURL string
+}
+
+// NewSSOHandler crea el handler del inicio de sesión único. Si postLoginURL no está vacío, el
+// callback redirige al frontend con el resultado en el fragmento de la URL en lugar de responder JSON.
+func NewSSOHandler(service sso.Service, postLoginURL string) *ssoHandler {
+	return &ssoHandler{service: service, postLoginURL: postLoginURL}
+}
+
+func (h *ssoHandler) Begin(w http.ResponseWriter, r *http.Request) {
+	authURL, state, err := h.service.Begin(r.Context())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	// SameSite=Lax, ya que el proveedor redirige al callback desde otro sitio.
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoStateCookie,
+		Value:    state,
+		Path:     "/api/v1/login/sso",
+		MaxAge:   int(sso.StateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+func (h *ssoHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var boundState string
+	if cookie, err := r.Cookie(ssoStateCookie); err == nil {
+		boundState = cookie.Value
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoStateCookie,
+		Path:     "/api/v1/login/sso",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	// El proveedor informa errores (p. ej. access_denied) mediante el parámetro error.
+	if query.Get("error") != "" {
+		h.writeError(w, r, domain.ErrSSOLoginFailed)
+		return
+	}
+
+	out, err := h.service.Complete(r.Context(), query.Get("state"), boundState, query.Get("code"), middlewares.ClientIP(r))
+	if err != nil {
+		// El detalle del proveedor no se expone al cliente.
+		if errors.Is(err, domain.ErrSSOLoginFailed) {
+			err = domain.ErrSSOLoginFailed
+		}
+
+		h.writeError(w, r, err)
+		return
+	}
+
+	if h.postLoginURL == "" {
+		response.JSON(w, http.StatusOK, toLoginResponse(out))
+		return
+	}
+
+	h.redirect(w, r, loginFragment(out))
+}
+
+func (h *ssoHandler) Link(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	if userID == "" {
+		problem.Write(w, r, response.ErrInvalidID)
+		return
+	}
+
+	var req dto.LinkIdentityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, response.ErrInvalidJSON)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	identity, err := h.service.Link(r.Context(), userID, req.Subject, req.Email)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, identity)
+}
+
+// writeError responde el error como problema o, si hay URL del frontend, redirige con el detalle
+// y el código en el fragmento.
+func (h *ssoHandler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	if h.postLoginURL == "" {
+		problem.Write(w, r, err)
+		return
+	}
+
+	p := problem.New(err)
+	if p.Status >= http.StatusInternalServerError {
+		response.LogServerError(r, err)
+	}
+
+	h.redirect(w, r, url.Values{"error": {p.Detail}, "code": {p.Code}})
+}
+
+// redirect envía el resultado en el fragmento para que no quede registrado en logs ni en la
+// cabecera Referer.
+func (h *ssoHandler) redirect(w http.ResponseWriter, r *http.Request, values url.Values) {
+	http.Redirect(w, r, h.postLoginURL+"#"+values.Encode(), http.StatusFound)
+}
+
+func loginFragment(out *auth.LoginOutput) url.Values {
+	return url.Values{
+		"token":      {out.Token},
+		"token_type": {out.TokenType},
+		"expires_in": {strconv.FormatInt(out.ExpiresIn, 10)},
+		"role":       {out.Role},
+	}
+}
+
+// isHTTPS indica si el cliente accedió por HTTPS, directamente o a través de un proxy.
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
@@ -2,13 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/JGCaceres97/parking/internal/adapters/api/dto"
 	"github.com/JGCaceres97/parking/internal/adapters/api/middlewares"
+	"github.com/JGCaceres97/parking/internal/adapters/api/problem"
 	"github.com/JGCaceres97/parking/internal/application/user"
 	"github.com/JGCaceres97/parking/internal/domain"
 	"github.com/JGCaceres97/parking/pkg/response"
@@ -25,13 +25,15 @@ func NewUserHandler(service user.Service) *userHandler {
 func (h *userHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 	userID, err := middlewares.GetUserIDFromContext(r.Context())
 	if err != nil {
-		response.ErrorJSON(w, err, http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
-	users, err := h.service.ListAll(r.Context(), userID)
+	includeDeleted := r.URL.Query().Get("include_deleted") == "true"
+
+	users, err := h.service.ListAll(r.Context(), userID, includeDeleted)
 	if err != nil {
-		response.ErrorJSON(w, response.ErrInternalError, http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -41,17 +43,12 @@ func (h *userHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 func (h *userHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.ErrorJSON(w, response.ErrInvalidJSON, http.StatusBadRequest)
-		return
-	}
-
-	if req.Username == "" || req.Password == "" || req.Role == "" {
-		response.ErrorJSON(w, response.ErrUserCreateValidation, http.StatusBadRequest)
+		problem.Write(w, r, response.ErrInvalidJSON)
 		return
 	}
 
-	if req.Role != domain.RoleAdmin && req.Role != domain.RoleCommon {
-		response.ErrorJSON(w, response.ErrInvalidRole, http.StatusBadRequest)
+	if err := req.Validate(); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -64,12 +61,7 @@ func (h *userHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.service.Create(r.Context(), newUser)
 	if err != nil {
-		if errors.Is(err, domain.ErrUsernameAlreadyExists) {
-			response.ErrorJSON(w, err, http.StatusConflict)
-			return
-		}
-
-		response.ErrorJSON(w, response.ErrInternalError, http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -79,34 +71,29 @@ func (h *userHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 func (h *userHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	authUserID, err := middlewares.GetUserIDFromContext(r.Context())
 	if err != nil {
-		response.ErrorJSON(w, response.ErrInternalError, http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
 	userID := chi.URLParam(r, "userID")
 	if userID == "" {
-		response.ErrorJSON(w, response.ErrInvalidID, http.StatusBadRequest)
+		problem.Write(w, r, response.ErrInvalidID)
 		return
 	}
 
 	var req dto.UpdateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.ErrorJSON(w, response.ErrInvalidJSON, http.StatusBadRequest)
+		problem.Write(w, r, response.ErrInvalidJSON)
 		return
 	}
 
 	if userID == authUserID && req.Role != "" {
-		response.ErrorJSON(w, response.ErrChangeOwnRole, http.StatusForbidden)
+		problem.Write(w, r, response.ErrChangeOwnRole)
 		return
 	}
 
 	if req.Username == "" && req.Role == "" {
-		response.ErrorJSON(w, response.ErrUpdateValidation, http.StatusBadRequest)
-		return
-	}
-
-	if req.Role != "" && req.Role != domain.RoleAdmin && req.Role != domain.RoleCommon {
-		response.ErrorJSON(w, response.ErrInvalidRole, http.StatusBadRequest)
+		problem.Write(w, r, response.ErrUpdateValidation)
 		return
 	}
 
@@ -119,17 +106,7 @@ func (h *userHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.service.Update(r.Context(), userID, updatedUser)
 	if err != nil {
-		if errors.Is(err, domain.ErrUserNotFound) {
-			response.ErrorJSON(w, err, http.StatusNotFound)
-			return
-		}
-
-		if errors.Is(err, domain.ErrUsernameAlreadyExists) || errors.Is(err, domain.ErrAdminProtected) {
-			response.ErrorJSON(w, err, http.StatusConflict)
-			return
-		}
-
-		response.ErrorJSON(w, response.ErrInternalError, http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -139,33 +116,33 @@ func (h *userHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 func (h *userHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	authUserID, err := middlewares.GetUserIDFromContext(r.Context())
 	if err != nil {
-		response.ErrorJSON(w, response.ErrInternalError, http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
 	userID := chi.URLParam(r, "userID")
 	if userID == "" {
-		response.ErrorJSON(w, response.ErrInvalidID, http.StatusBadRequest)
+		problem.Write(w, r, response.ErrInvalidID)
 		return
 	}
 
 	if userID == authUserID {
-		response.ErrorJSON(w, response.ErrOwnDelete, http.StatusForbidden)
+		problem.Write(w, r, response.ErrOwnDelete)
 		return
 	}
 
-	if err := h.service.Delete(r.Context(), userID); err != nil {
-		if errors.Is(err, domain.ErrUserNotFound) {
-			response.ErrorJSON(w, err, http.StatusNotFound)
-			return
-		}
+	mode := domain.UserDeleteSoft
+	if value := r.URL.Query().Get("mode"); value != "" {
+		mode = domain.UserDeleteMode(value)
+	}
 
-		if errors.Is(err, domain.ErrAdminProtected) {
-			response.ErrorJSON(w, err, http.StatusConflict)
-			return
-		}
+	if !mode.IsValid() {
+		problem.Write(w, r, response.ErrInvalidDeleteMode)
+		return
+	}
 
-		response.ErrorJSON(w, response.ErrInternalError, http.StatusInternalServerError)
+	if err := h.service.Delete(r.Context(), userID, mode); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -175,29 +152,19 @@ func (h *userHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 func (h *userHandler) UpdateUsername(w http.ResponseWriter, r *http.Request) {
 	userID, err := middlewares.GetUserIDFromContext(r.Context())
 	if err != nil {
-		response.ErrorJSON(w, response.ErrInternalError, http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
 	var req dto.UpdateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.ErrorJSON(w, response.ErrInvalidJSON, http.StatusBadRequest)
+		problem.Write(w, r, response.ErrInvalidJSON)
 		return
 	}
 
 	user, err := h.service.UpdateUsername(r.Context(), userID, req.Username)
 	if err != nil {
-		if errors.Is(err, domain.ErrUserNotFound) {
-			response.ErrorJSON(w, err, http.StatusNotFound)
-			return
-		}
-
-		if errors.Is(err, domain.ErrUsernameAlreadyExists) || errors.Is(err, domain.ErrAdminProtected) {
-			response.ErrorJSON(w, err, http.StatusConflict)
-			return
-		}
-
-		response.ErrorJSON(w, response.ErrInternalError, http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -207,29 +174,77 @@ func (h *userHandler) UpdateUsername(w http.ResponseWriter, r *http.Request) {
 func (h *userHandler) ToggleActiveStatus(w http.ResponseWriter, r *http.Request) {
 	userID := chi.URLParam(r, "userID")
 	if userID == "" {
-		response.ErrorJSON(w, response.ErrInvalidID, http.StatusBadRequest)
+		problem.Write(w, r, response.ErrInvalidID)
 		return
 	}
 
 	var req dto.ToggleActiveRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.ErrorJSON(w, response.ErrInvalidJSON, http.StatusBadRequest)
+		problem.Write(w, r, response.ErrInvalidJSON)
 		return
 	}
 
 	user, err := h.service.ToggleActive(r.Context(), userID, req.IsActive)
 	if err != nil {
-		if errors.Is(err, domain.ErrUserNotFound) {
-			response.ErrorJSON(w, err, http.StatusNotFound)
-			return
-		}
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, user)
+}
+
+func (h *userHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, err := middlewares.GetUserIDFromContext(r.Context())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, response.ErrInvalidJSON)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	if err := h.service.ChangePassword(r.Context(), userID, req.CurrentPassword, req.NewPassword); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, nil)
+}
+
+func (h *userHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	if userID == "" {
+		problem.Write(w, r, response.ErrInvalidID)
+		return
+	}
+
+	tempPassword, err := h.service.ResetPassword(r.Context(), userID)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
 
-		if errors.Is(err, domain.ErrAdminProtected) {
-			response.ErrorJSON(w, err, http.StatusConflict)
-			return
-		}
+	response.JSON(w, http.StatusOK, dto.ResetPasswordResponse{TemporaryPassword: tempPassword})
+}
 
-		response.ErrorJSON(w, response.ErrInternalError, http.StatusInternalServerError)
+func (h *userHandler) Unlock(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	if userID == "" {
+		problem.Write(w, r, response.ErrInvalidID)
+		return
+	}
+
+	user, err := h.service.Unlock(r.Context(), userID)
+	if err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
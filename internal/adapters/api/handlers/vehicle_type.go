@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/JGCaceres97/parking/internal/adapters/api/problem"
 	"github.com/JGCaceres97/parking/internal/application/vehicle_type"
 	"github.com/JGCaceres97/parking/pkg/response"
 )
@@ -18,7 +19,7 @@ func NewVehicleTypeHandler(service vehicle_type.Service) *vehicleTypeHandler {
 func (h *vehicleTypeHandler) ListAll(w http.ResponseWriter, r *http.Request) {
 	vts, err := h.service.ListAll(r.Context())
 	if err != nil {
-		response.ErrorJSON(w, response.ErrInternalError, http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/JGCaceres97/parking/internal/adapters/api/dto"
+	"github.com/JGCaceres97/parking/internal/adapters/api/problem"
+	"github.com/JGCaceres97/parking/internal/application/webhook"
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/response"
+)
+
+type webhookHandler struct {
+	service webhook.Service
+}
+
+func NewWebhookHandler(service webhook.Service) *webhookHandler {
+	return &webhookHandler{service: service}
+}
+
+func (h *webhookHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := h.service.ListSubscriptions(r.Context())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, subscriptions)
+}
+
+func (h *webhookHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	subscription, ok := decodeSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	created, err := h.service.CreateSubscription(r.Context(), subscription)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, created)
+}
+
+func (h *webhookHandler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	subscription, ok := decodeSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	updated, err := h.service.UpdateSubscription(r.Context(), chi.URLParam(r, "webhookID"), subscription)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, updated)
+}
+
+func (h *webhookHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteSubscription(r.Context(), chi.URLParam(r, "webhookID")); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, nil)
+}
+
+func (h *webhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	status := query.Get("status")
+	switch status {
+	case "", domain.WebhookDeliveryPending, domain.WebhookDeliveryDelivered, domain.WebhookDeliveryFailed:
+	default:
+		problem.Write(w, r, response.ErrInvalidDeliveryState)
+		return
+	}
+
+	limit := 100
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 || n > 1000 {
+			problem.Write(w, r, response.ErrInvalidLimit)
+			return
+		}
+
+		limit = n
+	}
+
+	deliveries, err := h.service.ListDeliveries(r.Context(), chi.URLParam(r, "webhookID"), status, limit)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, deliveries)
+}
+
+func (h *webhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.service.Redeliver(r.Context(), chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusAccepted, delivery)
+}
+
+// decodeSubscription lee la suscripción del cuerpo. is_active es verdadero si se omite.
+func decodeSubscription(w http.ResponseWriter, r *http.Request) (*domain.WebhookSubscription, bool) {
+	var req dto.WebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, response.ErrInvalidJSON)
+		return nil, false
+	}
+
+	subscription := &domain.WebhookSubscription{
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     req.Secret,
+		IsActive:   req.IsActive == nil || *req.IsActive,
+	}
+
+	return subscription, true
+}
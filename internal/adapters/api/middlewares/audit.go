@@ -0,0 +1,32 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/JGCaceres97/parking/internal/application/audit"
+)
+
+// maxRequestIDLength limita el ID de solicitud recibido del cliente para que no impida
+// registrar la auditoría.
+const maxRequestIDLength = 64
+
+// AuditMiddleware asocia la IP del cliente y el ID de la solicitud al contexto, para que los
+// servicios los incluyan en el registro de auditoría. El ID se devuelve en la cabecera
+// X-Request-Id para poder correlacionar la respuesta con sus entradas.
+func AuditMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		if len(requestID) > maxRequestIDLength {
+			requestID = requestID[:maxRequestIDLength]
+		}
+
+		if requestID != "" {
+			w.Header().Set(middleware.RequestIDHeader, requestID)
+		}
+
+		ctx := audit.WithActor(r.Context(), audit.Actor{IP: ClientIP(r), RequestID: requestID})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
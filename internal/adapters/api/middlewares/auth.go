@@ -2,10 +2,11 @@ package middlewares
 
 import (
 	"context"
-	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/JGCaceres97/parking/internal/adapters/api/problem"
+	"github.com/JGCaceres97/parking/internal/application/audit"
 	"github.com/JGCaceres97/parking/internal/application/auth"
 	"github.com/JGCaceres97/parking/pkg/response"
 )
@@ -13,8 +14,10 @@ import (
 type ContextKey string
 
 const (
-	UserIDKey   ContextKey = "userID"
-	UserRoleKey ContextKey = "userRole"
+	UserIDKey                 ContextKey = "userID"
+	UserRoleKey               ContextKey = "userRole"
+	PasswordChangeRequiredKey ContextKey = "passwordChangeRequired"
+	MFAEnrollmentRequiredKey  ContextKey = "mfaEnrollmentRequired"
 )
 
 func GetUserIDFromContext(ctx context.Context) (string, error) {
@@ -32,39 +35,33 @@ func AuthMiddleware(service auth.Service) func(http.Handler) http.Handler {
 			// Extraer token de la cabecera
 			header := r.Header.Get("Authorization")
 			if header == "" {
-				response.ErrorJSON(w, response.ErrMissingToken, http.StatusUnauthorized)
+				problem.Write(w, r, response.ErrMissingToken)
 				return
 			}
 
 			parts := strings.Split(header, " ")
 			if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-				response.ErrorJSON(w, response.ErrInvalidTokenFormat, http.StatusUnauthorized)
+				problem.Write(w, r, response.ErrInvalidTokenFormat)
 				return
 			}
 
 			token := parts[1]
 
 			// Validar el token
-			userID, role, err := service.ParseToken(token)
+			claims, err := service.Authenticate(r.Context(), token)
 			if err != nil {
-				if errors.Is(err, auth.ErrExpiredToken) {
-					response.ErrorJSON(w, response.ErrExpiredToken, http.StatusUnauthorized)
-					return
-				}
-
-				if errors.Is(err, auth.ErrInvalidToken) {
-					response.ErrorJSON(w, response.ErrInvalidToken, http.StatusUnauthorized)
-					return
-				}
-
-				response.ErrorJSON(w, response.ErrTokenValidationFailed, http.StatusInternalServerError)
+				problem.Write(w, r, err)
 				return
 			}
 
 			// Inyectar la información del usuario
 			ctx := r.Context()
-			ctx = context.WithValue(ctx, UserIDKey, userID)
-			ctx = context.WithValue(ctx, UserRoleKey, role)
+			ctx = context.WithValue(ctx, UserIDKey, claims.UserID)
+			ctx = context.WithValue(ctx, UserRoleKey, claims.Role)
+			ctx = context.WithValue(ctx, PasswordChangeRequiredKey, claims.MustChangePassword)
+			ctx = context.WithValue(ctx, MFAEnrollmentRequiredKey, claims.MFAEnrollmentRequired)
+			ctx = audit.WithUserID(ctx, claims.UserID)
+			setRequestUser(ctx, claims.UserID)
 
 			// Continuar flujo
 			next.ServeHTTP(w, r.WithContext(ctx))
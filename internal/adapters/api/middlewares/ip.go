@@ -0,0 +1,17 @@
+package middlewares
+
+import (
+	"net"
+	"net/http"
+)
+
+// ClientIP obtiene la IP del cliente a partir de la conexión. Si el servidor está detrás de un
+// proxy confiable, RemoteAddr debe reescribirse antes (ej. middleware.RealIP de chi).
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
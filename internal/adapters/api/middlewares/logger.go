@@ -0,0 +1,94 @@
+package middlewares
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/JGCaceres97/parking/pkg/response"
+)
+
+type requestUserKey struct{}
+
+// requestUser guarda el usuario autenticado para la línea de la solicitud, ya que AuthMiddleware
+// lo asocia a un contexto derivado que RequestLogger no ve.
+type requestUser struct {
+	id string
+}
+
+func setRequestUser(ctx context.Context, userID string) {
+	if user, ok := ctx.Value(requestUserKey{}).(*requestUser); ok {
+		user.id = userID
+	}
+}
+
+// RequestLogger registra una línea por solicitud con su resultado y duración. Las respuestas 5xx
+// se registran con nivel error. Debe ir después de AuditMiddleware para incluir el ID de la
+// solicitud.
+func RequestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		user := &requestUser{}
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		defer func() {
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", status),
+				slog.Int("bytes", ww.BytesWritten()),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("ip", ClientIP(r)),
+			}
+
+			if user.id != "" {
+				attrs = append(attrs, slog.String("user_id", user.id))
+			}
+
+			slog.LogAttrs(r.Context(), level, "solicitud atendida", attrs...)
+		}()
+
+		ctx := context.WithValue(r.Context(), requestUserKey{}, user)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+	})
+}
+
+// Recoverer responde 500 cuando un handler entra en pánico y registra el pánico con su traza.
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rvr := recover()
+			if rvr == nil {
+				return
+			}
+
+			// El servidor usa este pánico para abortar la respuesta sin registrarlo.
+			if rvr == http.ErrAbortHandler {
+				panic(rvr)
+			}
+
+			slog.ErrorContext(r.Context(), "pánico al atender la solicitud",
+				"method", r.Method, "path", r.URL.Path, "panic", rvr, "stack", string(debug.Stack()))
+
+			if r.Header.Get("Connection") != "Upgrade" {
+				response.InternalError(w, r)
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
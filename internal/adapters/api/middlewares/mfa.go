@@ -0,0 +1,21 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/JGCaceres97/parking/internal/adapters/api/problem"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// MFAEnrollmentMiddleware bloquea el acceso a los usuarios cuyo rol exige autenticación de dos
+// factores hasta que la configuren.
+func MFAEnrollmentMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if required, _ := r.Context().Value(MFAEnrollmentRequiredKey).(bool); required {
+			problem.Write(w, r, domain.ErrMFAEnrollmentRequired)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,21 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/JGCaceres97/parking/internal/adapters/api/problem"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// PasswordChangeMiddleware bloquea el acceso a los usuarios que deben cambiar su contraseña
+// (contraseña temporal o que no cumple la política) hasta que lo hagan.
+func PasswordChangeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if mustChange, _ := r.Context().Value(PasswordChangeRequiredKey).(bool); mustChange {
+			problem.Write(w, r, domain.ErrPasswordChangeRequired)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
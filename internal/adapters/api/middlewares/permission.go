@@ -0,0 +1,39 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/JGCaceres97/parking/internal/adapters/api/problem"
+	"github.com/JGCaceres97/parking/internal/application/role"
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/response"
+)
+
+// PermissionMiddleware verifica que el rol del usuario autenticado conceda todos los permisos
+// indicados.
+func PermissionMiddleware(service role.Service, permissions ...domain.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Extraer el rol
+			userRole, ok := r.Context().Value(UserRoleKey).(string)
+			if !ok {
+				problem.Write(w, r, response.ErrUserIDNotInContext)
+				return
+			}
+
+			// Verificar si el rol concede los permisos requeridos
+			allowed, err := service.HasPermissions(r.Context(), userRole, permissions...)
+			if err != nil {
+				problem.Write(w, r, err)
+				return
+			}
+
+			if !allowed {
+				problem.Write(w, r, response.ErrPermissionDenied)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/JGCaceres97/parking/internal/application/role"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// fakeRoleService concede a cada rol los permisos indicados. Los métodos no utilizados por el
+// middleware provienen de la interfaz embebida y no deben invocarse.
+type fakeRoleService struct {
+	role.Service
+	roles map[domain.Role][]domain.Permission
+	err   error
+}
+
+func (s *fakeRoleService) HasPermissions(_ context.Context, name domain.Role, permissions ...domain.Permission) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+
+	for _, p := range permissions {
+		if !slices.Contains(s.roles[name], p) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func TestPermissionMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       any
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "sin rol en el contexto", wantStatus: http.StatusInternalServerError},
+		{name: "rol con los permisos", role: domain.RoleSupervisor, wantStatus: http.StatusOK},
+		{name: "rol sin los permisos", role: domain.RoleCashier, wantStatus: http.StatusForbidden, wantCode: "PERMISSION_DENIED"},
+		{name: "rol inexistente", role: "fantasma", wantStatus: http.StatusForbidden, wantCode: "PERMISSION_DENIED"},
+		{name: "error al consultar el rol", role: domain.RoleSupervisor, err: errors.New("sin conexión"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeRoleService{
+				roles: map[domain.Role][]domain.Permission{
+					domain.RoleSupervisor: {domain.PermParkingRead, domain.PermParkingVoid},
+					domain.RoleCashier:    {domain.PermParkingRead},
+				},
+				err: tt.err,
+			}
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			handler := PermissionMiddleware(service, domain.PermParkingRead, domain.PermParkingVoid)(next)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/parking/1/void", nil)
+			if tt.role != nil {
+				req = req.WithContext(context.WithValue(req.Context(), UserRoleKey, tt.role))
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, se esperaba %d", rec.Code, tt.wantStatus)
+			}
+
+			if tt.wantCode == "" {
+				return
+			}
+
+			var body struct {
+				Code string `json:"code"`
+			}
+
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("respuesta inválida: %v", err)
+			}
+
+			if body.Code != tt.wantCode {
+				t.Errorf("code = %q, se esperaba %q", body.Code, tt.wantCode)
+			}
+		})
+	}
+}
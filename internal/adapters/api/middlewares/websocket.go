@@ -0,0 +1,32 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+)
+
+// WebSocketSubprotocol es el subprotocolo que negocia el flujo de eventos por WebSocket.
+const WebSocketSubprotocol = "parking.events"
+
+// websocketTokenPrefix identifica el subprotocolo que transporta el token de acceso.
+const websocketTokenPrefix = "bearer."
+
+// WebSocketTokenMiddleware permite autenticar conexiones WebSocket desde el navegador, que no puede
+// enviar la cabecera Authorization. El cliente envía el token como subprotocolo
+// (`new WebSocket(url, ["parking.events", "bearer." + token])`) y este middleware lo copia a la
+// cabecera antes de AuthMiddleware. No se aceptan tokens en la URL para no exponerlos en los logs.
+func WebSocketTokenMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" && strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+				for protocol := range strings.SplitSeq(header, ",") {
+					if token, ok := strings.CutPrefix(strings.TrimSpace(protocol), websocketTokenPrefix); ok {
+						r.Header.Set("Authorization", "Bearer "+token)
+					}
+				}
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,9 @@
+// Se carga como archivo aparte para que la política de seguridad de contenido no necesite
+// permitir scripts en línea.
+window.ui = SwaggerUIBundle({
+  url: "/api/v1/openapi.json",
+  dom_id: "#docs",
+  deepLinking: true,
+  persistAuthorization: true,
+  validatorUrl: null,
+});
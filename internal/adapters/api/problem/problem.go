@@ -0,0 +1,191 @@
+// Package problem traduce los errores de la aplicación a respuestas application/problem+json
+// (RFC 7807) con un código estable por error.
+package problem
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/JGCaceres97/parking/internal/application/auth"
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/response"
+)
+
+// ErrRouteNotFound se responde cuando ninguna ruta de la API coincide con la solicitud.
+var ErrRouteNotFound = errors.New("ruta no encontrada")
+
+// Entry asocia un error con su código y estado HTTP. Field, si no está vacío, es el campo de la
+// solicitud al que se atribuye el error.
+type Entry struct {
+	Err    error
+	Code   string
+	Status int
+	Field  string
+}
+
+// Catalog es la tabla de errores conocidos. Se evalúa en orden con errors.Is, por lo que los
+// errores más específicos deben ir primero.
+var Catalog = []Entry{
+	{Err: domain.ErrAdminProtected, Code: "ADMIN_PROTECTED", Status: http.StatusConflict},
+	{Err: domain.ErrAdminPasswordRequired, Code: "ADMIN_PASSWORD_REQUIRED", Status: http.StatusBadRequest},
+	{Err: domain.ErrInvalidCredentials, Code: "INVALID_CREDENTIALS", Status: http.StatusUnauthorized},
+	{Err: domain.ErrUserInactive, Code: "USER_INACTIVE", Status: http.StatusForbidden},
+	{Err: domain.ErrUserLocked, Code: "USER_LOCKED", Status: http.StatusForbidden},
+	{Err: domain.ErrTooManyAttempts, Code: "TOO_MANY_ATTEMPTS", Status: http.StatusTooManyRequests},
+
+	{Err: domain.ErrPasswordTooShort, Code: "PASSWORD_TOO_SHORT", Status: http.StatusBadRequest},
+	{Err: domain.ErrPasswordTooWeak, Code: "PASSWORD_TOO_WEAK", Status: http.StatusBadRequest},
+	{Err: domain.ErrPasswordReused, Code: "PASSWORD_REUSED", Status: http.StatusBadRequest},
+	{Err: domain.ErrCurrentPasswordInvalid, Code: "CURRENT_PASSWORD_INVALID", Status: http.StatusForbidden},
+	{Err: domain.ErrPasswordChangeRequired, Code: "PASSWORD_CHANGE_REQUIRED", Status: http.StatusForbidden},
+
+	{Err: domain.ErrMFANotEnrolled, Code: "MFA_NOT_ENROLLED", Status: http.StatusConflict},
+	{Err: domain.ErrMFAAlreadyEnabled, Code: "MFA_ALREADY_ENABLED", Status: http.StatusConflict},
+	{Err: domain.ErrInvalidMFACode, Code: "INVALID_MFA_CODE", Status: http.StatusBadRequest, Field: "code"},
+	{Err: domain.ErrMFAEnrollmentRequired, Code: "MFA_ENROLLMENT_REQUIRED", Status: http.StatusForbidden},
+	{Err: domain.ErrMFARequiredByRole, Code: "MFA_REQUIRED_BY_ROLE", Status: http.StatusConflict},
+
+	{Err: domain.ErrSSODisabled, Code: "SSO_DISABLED", Status: http.StatusNotFound},
+	{Err: domain.ErrSSOStateInvalid, Code: "SSO_STATE_INVALID", Status: http.StatusBadRequest},
+	{Err: domain.ErrSSOBusy, Code: "SSO_BUSY", Status: http.StatusServiceUnavailable},
+	{Err: domain.ErrSSOLoginFailed, Code: "SSO_LOGIN_FAILED", Status: http.StatusUnauthorized},
+	{Err: domain.ErrSSONoRole, Code: "SSO_NO_ROLE", Status: http.StatusForbidden},
+	{Err: domain.ErrIdentityNotFound, Code: "IDENTITY_NOT_FOUND", Status: http.StatusNotFound},
+	{Err: domain.ErrIdentityAlreadyLinked, Code: "IDENTITY_ALREADY_LINKED", Status: http.StatusConflict},
+
+	{Err: domain.ErrUserNotFound, Code: "USER_NOT_FOUND", Status: http.StatusNotFound},
+	{Err: domain.ErrVehicleTypeNotFound, Code: "VEHICLE_TYPE_NOT_FOUND", Status: http.StatusNotFound},
+	{Err: domain.ErrParkingRecordNotFound, Code: "PARKING_RECORD_NOT_FOUND", Status: http.StatusNotFound},
+	{Err: domain.ErrActiveParkingNotFound, Code: "PARKING_NOT_OPEN", Status: http.StatusNotFound},
+	{Err: domain.ErrParkingRecordVoided, Code: "PARKING_ALREADY_VOIDED", Status: http.StatusConflict},
+	{Err: domain.ErrInvalidVoidReason, Code: "INVALID_VOID_REASON", Status: http.StatusBadRequest, Field: "reason"},
+	{Err: domain.ErrUsernameAlreadyExists, Code: "USERNAME_TAKEN", Status: http.StatusConflict},
+	{Err: domain.ErrUserHasRecords, Code: "USER_HAS_RECORDS", Status: http.StatusConflict},
+	{Err: domain.ErrVehicleTypeNameAlreadyExists, Code: "VEHICLE_TYPE_NAME_TAKEN", Status: http.StatusConflict},
+	{Err: domain.ErrActiveParkingAlreadyExists, Code: "PARKING_ALREADY_OPEN", Status: http.StatusConflict},
+	{Err: domain.ErrVehicleTypeInUse, Code: "VEHICLE_TYPE_IN_USE", Status: http.StatusConflict},
+	{Err: domain.ErrInvalidVehicleTypeName, Code: "INVALID_VEHICLE_TYPE_NAME", Status: http.StatusBadRequest, Field: "name"},
+	{Err: domain.ErrInvalidHourlyRate, Code: "INVALID_HOURLY_RATE", Status: http.StatusBadRequest, Field: "hourly_rate"},
+	{Err: domain.ErrRoleNotFound, Code: "ROLE_NOT_FOUND", Status: http.StatusNotFound},
+	{Err: domain.ErrRoleAlreadyExists, Code: "ROLE_ALREADY_EXISTS", Status: http.StatusConflict},
+	{Err: domain.ErrRoleInUse, Code: "ROLE_IN_USE", Status: http.StatusConflict},
+	{Err: domain.ErrRoleProtected, Code: "ROLE_PROTECTED", Status: http.StatusConflict},
+	{Err: domain.ErrInvalidRoleName, Code: "INVALID_ROLE_NAME", Status: http.StatusBadRequest, Field: "name"},
+	{Err: domain.ErrInvalidPermission, Code: "INVALID_PERMISSION", Status: http.StatusBadRequest, Field: "permissions"},
+	{Err: domain.ErrInvalidRole, Code: "INVALID_ROLE", Status: http.StatusBadRequest, Field: "role"},
+	{Err: domain.ErrRoleNotGrantable, Code: "ROLE_NOT_GRANTABLE", Status: http.StatusForbidden},
+
+	{Err: domain.ErrArchiveConflict, Code: "ARCHIVE_CONFLICT", Status: http.StatusConflict},
+	{Err: domain.ErrInvalidDateRange, Code: "INVALID_DATE_RANGE", Status: http.StatusBadRequest},
+	{Err: domain.ErrInvalidEventType, Code: "INVALID_EVENT_TYPE", Status: http.StatusBadRequest, Field: "types"},
+
+	{Err: domain.ErrWebhookNotFound, Code: "WEBHOOK_NOT_FOUND", Status: http.StatusNotFound},
+	{Err: domain.ErrWebhookDeliveryNotFound, Code: "WEBHOOK_DELIVERY_NOT_FOUND", Status: http.StatusNotFound},
+	{Err: domain.ErrInvalidWebhookURL, Code: "INVALID_WEBHOOK_URL", Status: http.StatusBadRequest, Field: "url"},
+	{Err: domain.ErrInvalidWebhookEventType, Code: "INVALID_WEBHOOK_EVENT_TYPE", Status: http.StatusBadRequest, Field: "event_types"},
+
+	{Err: domain.ErrBackupNotSupported, Code: "BACKUP_NOT_SUPPORTED", Status: http.StatusNotImplemented},
+	{Err: domain.ErrBackupNotFound, Code: "BACKUP_NOT_FOUND", Status: http.StatusNotFound},
+	{Err: domain.ErrBackupExists, Code: "BACKUP_EXISTS", Status: http.StatusConflict},
+	{Err: domain.ErrBackupCorrupt, Code: "BACKUP_CORRUPT", Status: http.StatusUnprocessableEntity},
+	{Err: domain.ErrBackupSchemaNewer, Code: "BACKUP_SCHEMA_NEWER", Status: http.StatusConflict},
+	{Err: domain.ErrDatabaseInUse, Code: "DATABASE_IN_USE", Status: http.StatusConflict},
+
+	{Err: auth.ErrExpiredToken, Code: "TOKEN_EXPIRED", Status: http.StatusUnauthorized},
+	{Err: auth.ErrInvalidToken, Code: "TOKEN_INVALID", Status: http.StatusUnauthorized},
+
+	{Err: response.ErrInvalidJSON, Code: "INVALID_JSON", Status: http.StatusBadRequest},
+	{Err: response.ErrMissingToken, Code: "MISSING_TOKEN", Status: http.StatusUnauthorized},
+	{Err: response.ErrInvalidTokenFormat, Code: "INVALID_TOKEN_FORMAT", Status: http.StatusUnauthorized},
+	{Err: response.ErrPermissionDenied, Code: "PERMISSION_DENIED", Status: http.StatusForbidden},
+	{Err: response.ErrInvalidID, Code: "INVALID_ID", Status: http.StatusBadRequest},
+	{Err: response.ErrRegistryIDRequired, Code: "RECORD_ID_REQUIRED", Status: http.StatusBadRequest},
+	{Err: response.ErrRoleNameRequired, Code: "ROLE_NAME_REQUIRED", Status: http.StatusBadRequest},
+	{Err: response.ErrChangeOwnRole, Code: "CANNOT_CHANGE_OWN_ROLE", Status: http.StatusForbidden},
+	{Err: response.ErrOwnDelete, Code: "CANNOT_DELETE_SELF", Status: http.StatusForbidden},
+	{Err: response.ErrInvalidDeleteMode, Code: "INVALID_DELETE_MODE", Status: http.StatusBadRequest, Field: "mode"},
+	{Err: response.ErrUpdateValidation, Code: "NOTHING_TO_UPDATE", Status: http.StatusBadRequest},
+	{Err: response.ErrInvalidLimit, Code: "INVALID_LIMIT", Status: http.StatusBadRequest, Field: "limit"},
+	{Err: response.ErrInvalidDeliveryState, Code: "INVALID_DELIVERY_STATUS", Status: http.StatusBadRequest, Field: "status"},
+	{Err: response.ErrInvalidAuditFilter, Code: "INVALID_AUDIT_FILTER", Status: http.StatusBadRequest},
+	{Err: response.ErrMethodNotAllowed, Code: "METHOD_NOT_ALLOWED", Status: http.StatusMethodNotAllowed},
+	{Err: ErrRouteNotFound, Code: "ROUTE_NOT_FOUND", Status: http.StatusNotFound},
+}
+
+// Lookup retorna la entrada del catálogo que corresponde a err.
+func Lookup(err error) (Entry, bool) {
+	for _, entry := range Catalog {
+		if errors.Is(err, entry.Err) {
+			return entry, true
+		}
+	}
+
+	return Entry{}, false
+}
+
+// New construye el problema que corresponde a err. Los errores del catálogo se describen con el
+// mensaje de su entrada y no con el de err, que puede incluir detalles internos de la causa. Los
+// errores que no están en el catálogo se responden como INTERNAL_ERROR sin exponer su mensaje.
+func New(err error) *response.Problem {
+	var validation *response.ValidationError
+	if errors.As(err, &validation) {
+		return &response.Problem{
+			Status: http.StatusBadRequest,
+			Code:   response.CodeValidationFailed,
+			Detail: validation.Error(),
+			Errors: validation.Fields,
+		}
+	}
+
+	entry, ok := Lookup(err)
+	if !ok {
+		return &response.Problem{
+			Status: http.StatusInternalServerError,
+			Code:   response.CodeInternalError,
+			Detail: response.ErrInternalError.Error(),
+		}
+	}
+
+	p := &response.Problem{Status: entry.Status, Code: entry.Code, Detail: entry.Err.Error()}
+	if entry.Field != "" {
+		p.Errors = []response.FieldError{{Field: entry.Field, Code: entry.Code, Detail: p.Detail}}
+	}
+
+	return p
+}
+
+// Write responde err como problema. Los errores 5xx se registran con su causa, al igual que los
+// demás errores cuyo mensaje completo no se incluye en la respuesta. Los rechazos por espera
+// exponencial incluyen la cabecera Retry-After.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	p := New(err)
+	if p.Status >= http.StatusInternalServerError {
+		response.LogServerError(r, err)
+	} else if err.Error() != p.Detail {
+		slog.InfoContext(r.Context(), "solicitud rechazada",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"code", p.Code,
+			"error", err)
+	}
+
+	var throttled *auth.ThrottledError
+	if errors.As(err, &throttled) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+	}
+
+	response.WriteProblem(w, r, p)
+}
+
+// NotFound responde ROUTE_NOT_FOUND a las rutas que no existen.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, ErrRouteNotFound)
+}
+
+// MethodNotAllowed responde METHOD_NOT_ALLOWED a los métodos que la ruta no admite.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, response.ErrMethodNotAllowed)
+}
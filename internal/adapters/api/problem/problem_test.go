@@ -0,0 +1,174 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/JGCaceres97/parking/internal/application/auth"
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/response"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		status     int
+		code       string
+		detail     string
+		fields     []string
+		retryAfter string
+	}{
+		{
+			name:   "error de dominio",
+			err:    domain.ErrActiveParkingAlreadyExists,
+			status: http.StatusConflict,
+			code:   "PARKING_ALREADY_OPEN",
+			detail: domain.ErrActiveParkingAlreadyExists.Error(),
+		},
+		{
+			name:   "error envuelto atribuido a un campo",
+			err:    fmt.Errorf("%w: parking:fly", domain.ErrInvalidPermission),
+			status: http.StatusBadRequest,
+			code:   "INVALID_PERMISSION",
+			detail: domain.ErrInvalidPermission.Error(),
+			fields: []string{"permissions"},
+		},
+		{
+			name:   "error envuelto con detalles internos",
+			err:    fmt.Errorf("%w: token de identidad inválido: oidc: id token issued by a different provider", domain.ErrSSOLoginFailed),
+			status: http.StatusUnauthorized,
+			code:   "SSO_LOGIN_FAILED",
+			detail: domain.ErrSSOLoginFailed.Error(),
+		},
+		{
+			name:   "campos requeridos",
+			err:    response.Required("vehicle_type_id", "", "license_plate", " "),
+			status: http.StatusBadRequest,
+			code:   response.CodeValidationFailed,
+			detail: "la solicitud tiene campos inválidos: vehicle_type_id, license_plate",
+			fields: []string{"vehicle_type_id", "license_plate"},
+		},
+		{
+			name:       "espera exponencial",
+			err:        &auth.ThrottledError{RetryAfter: 1500 * time.Millisecond},
+			status:     http.StatusTooManyRequests,
+			code:       "TOO_MANY_ATTEMPTS",
+			detail:     domain.ErrTooManyAttempts.Error(),
+			retryAfter: "2",
+		},
+		{
+			name:   "error desconocido",
+			err:    errors.New("conexión rechazada"),
+			status: http.StatusInternalServerError,
+			code:   response.CodeInternalError,
+			detail: response.ErrInternalError.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/parking/entry", nil)
+
+			middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Write(w, r, tt.err)
+			})).ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("estado = %d, se esperaba %d", rec.Code, tt.status)
+			}
+
+			if got := rec.Header().Get("Content-Type"); got != response.ProblemContentType {
+				t.Errorf("Content-Type = %q, se esperaba %q", got, response.ProblemContentType)
+			}
+
+			if got := rec.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Errorf("Retry-After = %q, se esperaba %q", got, tt.retryAfter)
+			}
+
+			var p response.Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+				t.Fatalf("cuerpo inválido: %v", err)
+			}
+
+			if p.Status != tt.status || p.Code != tt.code || p.Detail != tt.detail {
+				t.Errorf("problema = %d %s %q, se esperaba %d %s %q", p.Status, p.Code, p.Detail, tt.status, tt.code, tt.detail)
+			}
+
+			if p.Instance != "/api/v1/parking/entry" || p.RequestID == "" {
+				t.Errorf("instance = %q, request_id = %q", p.Instance, p.RequestID)
+			}
+
+			fields := make([]string, len(p.Errors))
+			for i, field := range p.Errors {
+				fields[i] = field.Field
+			}
+
+			if strings.Join(fields, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("campos = %v, se esperaba %v", fields, tt.fields)
+			}
+		})
+	}
+}
+
+// TestCatalogCoversDomainErrors verifica que cada error exportado del dominio tenga una entrada en
+// el catálogo y que los códigos no se repitan.
+func TestCatalogCoversDomainErrors(t *testing.T) {
+	codes := make(map[string]bool)
+	for _, entry := range Catalog {
+		if codes[entry.Code] {
+			t.Errorf("código %s duplicado", entry.Code)
+		}
+
+		codes[entry.Code] = true
+	}
+
+	cataloged := make(map[string]bool)
+	ast.Inspect(parse(t, "problem.go"), func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "domain" {
+				cataloged[sel.Sel.Name] = true
+			}
+		}
+
+		return true
+	})
+
+	for _, decl := range parse(t, "../../../domain/errors.go").Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			for _, name := range spec.(*ast.ValueSpec).Names {
+				if name.IsExported() && !cataloged[name.Name] {
+					t.Errorf("domain.%s no está en el catálogo", name.Name)
+				}
+			}
+		}
+	}
+}
+
+func parse(t *testing.T, path string) *ast.File {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return file
+}
@@ -2,84 +2,240 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
 	"github.com/JGCaceres97/parking/internal/adapters/api/handlers"
 	"github.com/JGCaceres97/parking/internal/adapters/api/middlewares"
+	"github.com/JGCaceres97/parking/internal/adapters/api/openapi"
+	"github.com/JGCaceres97/parking/internal/adapters/api/problem"
+	"github.com/JGCaceres97/parking/internal/application/audit"
 	"github.com/JGCaceres97/parking/internal/application/auth"
+	"github.com/JGCaceres97/parking/internal/application/backup"
+	"github.com/JGCaceres97/parking/internal/application/events"
+	"github.com/JGCaceres97/parking/internal/application/mfa"
 	"github.com/JGCaceres97/parking/internal/application/parking"
+	"github.com/JGCaceres97/parking/internal/application/report"
+	"github.com/JGCaceres97/parking/internal/application/role"
+	"github.com/JGCaceres97/parking/internal/application/sso"
 	"github.com/JGCaceres97/parking/internal/application/user"
 	"github.com/JGCaceres97/parking/internal/application/vehicle_type"
+	"github.com/JGCaceres97/parking/internal/application/webhook"
 	"github.com/JGCaceres97/parking/internal/domain"
-	"github.com/JGCaceres97/parking/internal/infrastructure/config"
+	"github.com/JGCaceres97/parking/internal/infrastructure/metrics"
+	"github.com/JGCaceres97/parking/internal/infrastructure/tracing"
 	"github.com/JGCaceres97/parking/web"
 )
 
 type routerConfig struct {
-	auth        auth.Service
-	parking     parking.Service
-	user        user.Service
-	vehicleType vehicle_type.Service
+	audit           audit.Service
+	auth            auth.Service
+	backup          backup.Service
+	events          events.Service
+	mfa             mfa.Service
+	parking         parking.Service
+	report          report.Service
+	role            role.Service
+	sso             sso.Service
+	ssoPostLoginURL string
+	user            user.Service
+	vehicleType     vehicle_type.Service
+	webhook         webhook.Service
+	handlerTimeout  time.Duration
 }
 
-func New(auth auth.Service, parking parking.Service, user user.Service, vehicleType vehicle_type.Service) *routerConfig {
+func New(
+	audit audit.Service,
+	auth auth.Service,
+	backup backup.Service,
+	events events.Service,
+	mfa mfa.Service,
+	parking parking.Service,
+	report report.Service,
+	role role.Service,
+	sso sso.Service,
+	ssoPostLoginURL string,
+	user user.Service,
+	vehicleType vehicle_type.Service,
+	webhook webhook.Service,
+	handlerTimeout time.Duration,
+) *routerConfig {
 	return &routerConfig{
+		audit,
 		auth,
+		backup,
+		events,
+		mfa,
 		parking,
+		report,
+		role,
+		sso,
+		ssoPostLoginURL,
 		user,
 		vehicleType,
+		webhook,
+		handlerTimeout,
 	}
 }
 
 func (rc *routerConfig) SetHandler() http.Handler {
 	r := chi.NewRouter()
 
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(config.HandlerTimeout))
+	r.Use(middleware.RequestID)
+	r.Use(tracing.Middleware)
+	r.Use(middlewares.AuditMiddleware)
+	r.Use(middlewares.RequestLogger)
+	r.Use(metrics.Middleware)
+	r.Use(middlewares.Recoverer)
 
+	// Se definen antes de las rutas para que las subrutas de la API los hereden.
+	r.NotFound(problem.NotFound)
+	r.MethodNotAllowed(problem.MethodNotAllowed)
+
+	// Los flujos de eventos son conexiones de larga duración, por lo que el timeout se aplica
+	// por ruta.
+	timeout := middleware.Timeout(rc.handlerTimeout)
+
+	auditHandler := handlers.NewAuditHandler(rc.audit)
 	authHandler := handlers.NewAuthHandler(rc.auth)
+	backupHandler := handlers.NewBackupHandler(rc.backup)
+	eventsHandler := handlers.NewEventsHandler(rc.events)
+	mfaHandler := handlers.NewMFAHandler(rc.mfa, rc.auth)
 	parkingHandler := handlers.NewParkingHandler(rc.parking)
+	reportHandler := handlers.NewReportHandler(rc.report)
+	roleHandler := handlers.NewRoleHandler(rc.role)
+	ssoHandler := handlers.NewSSOHandler(rc.sso, rc.ssoPostLoginURL)
 	userHandler := handlers.NewUserHandler(rc.user)
 	vehicleTypeHandler := handlers.NewVehicleTypeHandler(rc.vehicleType)
+	webhookHandler := handlers.NewWebhookHandler(rc.webhook)
+
+	r.With(timeout).Handle("/*", web.Handler)
+	r.With(timeout).Get("/.well-known/jwks.json", authHandler.JWKS)
 
-	r.Handle("/*", web.Handler)
+	// Eventos en tiempo real
+	r.Route("/api/v1/events", func(r chi.Router) {
+		r.Use(middlewares.WebSocketTokenMiddleware)
+		r.Use(middlewares.AuthMiddleware(rc.auth))
+		r.Use(middlewares.PasswordChangeMiddleware)
+		r.Use(middlewares.MFAEnrollmentMiddleware)
 
-	r.Route("/api/v1", func(r chi.Router) {
+		r.Get("/", eventsHandler.Stream)
+		r.Get("/ws", eventsHandler.WebSocket)
+	})
+
+	r.With(timeout).Route("/api/v1", func(r chi.Router) {
 		// Rutas públicas
 		r.Post("/login", authHandler.Login)
+		r.Post("/login/mfa", authHandler.LoginMFA)
+		r.Get("/login/sso", ssoHandler.Begin)
+		r.Get("/login/sso/callback", ssoHandler.Callback)
+
+		// Documentación
+		r.Get("/openapi.json", openapi.SpecHandler)
+		r.Get("/docs", openapi.DocsHandler)
+		r.Get("/docs/assets/{file}", openapi.AssetsHandler)
 
 		// Rutas protegidas
 		r.Group(func(r chi.Router) {
 			r.Use(middlewares.AuthMiddleware(rc.auth))
 
-			// Parking
-			r.Post("/parking/entry", parkingHandler.RecordEntry)
-			r.Post("/parking/exit", parkingHandler.RecordExit)
-			r.Get("/parking/{id}", parkingHandler.GetRecordByID)
-			r.Get("/parking/current", parkingHandler.GetCurrentlyParked)
-			r.Get("/parking/history", parkingHandler.GetHistory)
-
-			// Users
-			r.Put("/users/me", userHandler.UpdateUsername)
-
-			// Vehicle types
-			r.Get("/vehicle-types", vehicleTypeHandler.ListAll)
-
-			// Admin
-			r.Route("/admin", func(r chi.Router) {
-				r.Use(middlewares.RoleMiddleware(domain.RoleAdmin))
-
-				r.Get("/users", userHandler.ListUsers)
-				r.Post("/users", userHandler.CreateUser)
-				r.Put("/users/{userID}", userHandler.UpdateUser)
-				r.Patch("/users/{userID}/active", userHandler.ToggleActiveStatus)
-				r.Delete("/users/{userID}", userHandler.DeleteUser)
+			// Disponible aunque el usuario deba cambiar su contraseña
+			r.Put("/users/me/password", userHandler.ChangePassword)
+
+			r.Group(func(r chi.Router) {
+				r.Use(middlewares.PasswordChangeMiddleware)
+
+				// Disponible aunque el usuario deba configurar la autenticación de dos factores
+				r.Post("/users/me/mfa", mfaHandler.Enroll)
+				r.Post("/users/me/mfa/confirm", mfaHandler.Confirm)
+
+				r.Group(func(r chi.Router) {
+					r.Use(middlewares.MFAEnrollmentMiddleware)
+
+					// Parking
+					r.With(rc.require(domain.PermParkingWrite)).Post("/parking/entry", parkingHandler.RecordEntry)
+					r.With(rc.require(domain.PermParkingWrite)).Post("/parking/exit", parkingHandler.RecordExit)
+					r.With(rc.require(domain.PermParkingRead)).Get("/parking/{id}", parkingHandler.GetRecordByID)
+					r.With(rc.require(domain.PermParkingVoid)).Post("/parking/{id}/void", parkingHandler.Void)
+					r.With(rc.require(domain.PermParkingRead)).Get("/parking/current", parkingHandler.GetCurrentlyParked)
+					r.With(rc.require(domain.PermParkingRead)).Get("/parking/history", parkingHandler.GetHistory)
+
+					// Reports
+					r.With(rc.require(domain.PermReportsRead)).Get("/reports/revenue", reportHandler.Revenue)
+
+					// Users
+					r.Put("/users/me", userHandler.UpdateUsername)
+					r.Delete("/users/me/mfa", mfaHandler.Disable)
+
+					// Vehicle types
+					r.With(rc.require(domain.PermVehicleTypesRead)).Get("/vehicle-types", vehicleTypeHandler.ListAll)
+
+					// Admin
+					r.Route("/admin", func(r chi.Router) {
+						r.With(rc.require(domain.PermUsersRead)).Get("/users", userHandler.ListUsers)
+
+						r.Group(func(r chi.Router) {
+							r.Use(rc.require(domain.PermUsersManage))
+
+							r.Post("/users", userHandler.CreateUser)
+							r.Put("/users/{userID}", userHandler.UpdateUser)
+							r.Patch("/users/{userID}/active", userHandler.ToggleActiveStatus)
+							r.Patch("/users/{userID}/unlock", userHandler.Unlock)
+							r.Post("/users/{userID}/password/reset", userHandler.ResetPassword)
+							r.Delete("/users/{userID}/mfa", mfaHandler.Reset)
+							r.Post("/users/{userID}/identities", ssoHandler.Link)
+							r.Delete("/users/{userID}", userHandler.DeleteUser)
+						})
+
+						r.Group(func(r chi.Router) {
+							r.Use(rc.require(domain.PermAuditRead))
+
+							r.Get("/audit", auditHandler.List)
+							r.Get("/audit/verify", auditHandler.Verify)
+							r.Get("/login-attempts", authHandler.ListLoginAttempts)
+						})
+
+						r.With(rc.require(domain.PermUsersRead)).Get("/roles", roleHandler.ListRoles)
+
+						r.Group(func(r chi.Router) {
+							r.Use(rc.require(domain.PermRolesManage))
+
+							r.Get("/permissions", roleHandler.ListPermissions)
+							r.Post("/roles", roleHandler.CreateRole)
+							r.Put("/roles/{name}", roleHandler.UpdateRole)
+							r.Delete("/roles/{name}", roleHandler.DeleteRole)
+						})
+
+						r.Group(func(r chi.Router) {
+							r.Use(rc.require(domain.PermWebhooksManage))
+
+							r.Get("/webhooks", webhookHandler.ListSubscriptions)
+							r.Post("/webhooks", webhookHandler.CreateSubscription)
+							r.Put("/webhooks/{webhookID}", webhookHandler.UpdateSubscription)
+							r.Delete("/webhooks/{webhookID}", webhookHandler.DeleteSubscription)
+							r.Get("/webhooks/{webhookID}/deliveries", webhookHandler.ListDeliveries)
+							r.Post("/webhooks/deliveries/{deliveryID}/redeliver", webhookHandler.Redeliver)
+						})
+
+						r.Group(func(r chi.Router) {
+							r.Use(rc.require(domain.PermBackupsManage))
+
+							r.Get("/backups", backupHandler.List)
+							r.Post("/backups", backupHandler.Create)
+							r.Get("/backups/{name}", backupHandler.Download)
+						})
+					})
+				})
 			})
 		})
 	})
 
 	return r
 }
+
+// require construye el middleware que exige los permisos indicados al usuario autenticado.
+func (rc *routerConfig) require(permissions ...domain.Permission) func(http.Handler) http.Handler {
+	return middlewares.PermissionMiddleware(rc.role, permissions...)
+}
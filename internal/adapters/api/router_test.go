@@ -0,0 +1,182 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/JGCaceres97/parking/internal/adapters/api/openapi"
+	"github.com/JGCaceres97/parking/internal/adapters/api/problem"
+	"github.com/JGCaceres97/parking/pkg/response"
+)
+
+// outsideRouter son las rutas documentadas que cmd atiende fuera del router.
+var outsideRouter = []string{"GET /healthz", "GET /readyz", "GET /version"}
+
+type spec struct {
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+// specOperations retorna las operaciones documentadas como "MÉTODO /ruta".
+func specOperations(t *testing.T) map[string]bool {
+	t.Helper()
+
+	var s spec
+	if err := json.Unmarshal(openapi.Spec, &s); err != nil {
+		t.Fatalf("la especificación no es JSON válido: %v", err)
+	}
+
+	operations := map[string]bool{}
+	for path, item := range s.Paths {
+		for method := range item {
+			if method == "parameters" {
+				continue
+			}
+
+			operations[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	return operations
+}
+
+// routerOperations retorna las rutas registradas en el router como "MÉTODO /ruta".
+func routerOperations(t *testing.T) map[string]bool {
+	t.Helper()
+
+	routes, ok := New(nil, nil, nil, nil, nil, nil, nil, nil, nil, "", nil, nil, nil, time.Minute).SetHandler().(chi.Routes)
+	if !ok {
+		t.Fatal("el router no es un chi.Routes")
+	}
+
+	operations := map[string]bool{}
+	err := chi.Walk(routes, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		// La aplicación web se sirve en el resto de las rutas.
+		if route == "/*" {
+			return nil
+		}
+
+		// Las subrutas montadas con Route("/x") registran "/x/" para su raíz.
+		if len(route) > 1 {
+			route = strings.TrimSuffix(route, "/")
+		}
+
+		operations[method+" "+route] = true
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("error al recorrer el router: %v", err)
+	}
+
+	return operations
+}
+
+func TestSpecCoversRoutes(t *testing.T) {
+	documented := specOperations(t)
+	registered := routerOperations(t)
+
+	for operation := range registered {
+		if !documented[operation] {
+			t.Errorf("la ruta %s no está en openapi.json", operation)
+		}
+	}
+
+	for _, operation := range outsideRouter {
+		registered[operation] = true
+	}
+
+	for operation := range documented {
+		if !registered[operation] {
+			t.Errorf("openapi.json documenta %s, que no está registrada", operation)
+		}
+	}
+}
+
+func TestSpecReferencesExist(t *testing.T) {
+	var document map[string]any
+	if err := json.Unmarshal(openapi.Spec, &document); err != nil {
+		t.Fatalf("la especificación no es JSON válido: %v", err)
+	}
+
+	var walk func(node any)
+	walk = func(node any) {
+		switch node := node.(type) {
+		case map[string]any:
+			if ref, ok := node["$ref"].(string); ok && !resolves(document, ref) {
+				t.Errorf("la referencia %s no existe", ref)
+			}
+
+			for _, child := range node {
+				walk(child)
+			}
+		case []any:
+			for _, child := range node {
+				walk(child)
+			}
+		}
+	}
+
+	walk(document)
+}
+
+func TestSpecDocumentsErrorCodes(t *testing.T) {
+	var document struct {
+		Components struct {
+			Schemas struct {
+				Problem struct {
+					Properties struct {
+						Code struct {
+							Enum []string `json:"enum"`
+						} `json:"code"`
+					} `json:"properties"`
+				} `json:"Problem"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+
+	if err := json.Unmarshal(openapi.Spec, &document); err != nil {
+		t.Fatalf("la especificación no es JSON válido: %v", err)
+	}
+
+	documented := map[string]bool{}
+	for _, code := range document.Components.Schemas.Problem.Properties.Code.Enum {
+		documented[code] = true
+	}
+
+	codes := []string{response.CodeInternalError, response.CodeValidationFailed}
+	for _, entry := range problem.Catalog {
+		codes = append(codes, entry.Code)
+	}
+
+	for _, code := range codes {
+		if !documented[code] {
+			t.Errorf("el código %s no está documentado en el esquema Problem", code)
+		}
+	}
+
+	if len(documented) != len(codes) {
+		t.Errorf("el esquema Problem documenta %d códigos, el catálogo define %d", len(documented), len(codes))
+	}
+}
+
+// resolves indica si la referencia local (#/a/b) apunta a un elemento del documento.
+func resolves(document map[string]any, ref string) bool {
+	node := any(document)
+	for _, key := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		object, ok := node.(map[string]any)
+		if !ok {
+			return false
+		}
+
+		if node, ok = object[key]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JGCaceres97/parking/internal/application/audit"
+	"github.com/JGCaceres97/parking/internal/application/backup"
+	"github.com/JGCaceres97/parking/internal/application/events"
+	"github.com/JGCaceres97/parking/internal/application/mfa"
+	"github.com/JGCaceres97/parking/internal/application/outbox"
+	"github.com/JGCaceres97/parking/internal/application/parking"
+	"github.com/JGCaceres97/parking/internal/application/report"
+	"github.com/JGCaceres97/parking/internal/application/retention"
+	"github.com/JGCaceres97/parking/internal/application/role"
+	"github.com/JGCaceres97/parking/internal/application/user"
+	"github.com/JGCaceres97/parking/internal/application/vehicle_type"
+	"github.com/JGCaceres97/parking/internal/application/webhook"
+	"github.com/JGCaceres97/parking/internal/infrastructure/archive"
+	"github.com/JGCaceres97/parking/internal/infrastructure/config"
+	"github.com/JGCaceres97/parking/internal/infrastructure/httpsender"
+	"github.com/JGCaceres97/parking/internal/infrastructure/metrics"
+	"github.com/JGCaceres97/parking/internal/infrastructure/persistence"
+)
+
+// app agrupa la configuración, la conexión a DB y los servicios de aplicación. Todos los comandos
+// utilizan los mismos servicios que la API, de modo que aplican las mismas validaciones y quedan
+// registrados en la auditoría.
+type app struct {
+	cfg      *config.Config
+	db       *sql.DB
+	migrator *persistence.Migrator
+	repos    *persistence.Repositories
+
+	audit       audit.Service
+	backup      backup.Service
+	eventBus    events.Bus
+	mfa         mfa.Service
+	outbox      outbox.Service
+	parking     parking.Service
+	report      report.Service
+	retention   retention.Service
+	role        role.Service
+	user        user.Service
+	vehicleType vehicle_type.Service
+	webhook     webhook.Service
+}
+
+// connect carga la configuración y abre la conexión a DB, sin verificar el esquema.
+func connect(cmd *cobra.Command) (*app, error) {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := persistence.NewConnection(cmd.Context(), cfg.DBDriver, cfg.DBConnString, cfg.DBTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("error al inicializar la conexión con base de datos: %w", err)
+	}
+
+	persistence.ConfigurePool(db, cfg.DBDriver, persistence.PoolConfig{
+		MaxOpenConns:    cfg.DBPool.MaxOpenConns,
+		MaxIdleConns:    cfg.DBPool.MaxIdleConns,
+		ConnMaxLifetime: cfg.DBPool.ConnMaxLifetime,
+		ConnMaxIdleTime: cfg.DBPool.ConnMaxIdleTime,
+	})
+
+	// Migraciones incluidas en el binario
+	migrator, err := persistence.NewMigrator(db, cfg.DBDriver)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error al preparar migraciones: %w", err)
+	}
+
+	return &app{cfg: cfg, db: db, migrator: migrator}, nil
+}
+
+// checkSchema verifica que la DB tenga aplicadas todas las migraciones del binario.
+func (a *app) checkSchema(ctx context.Context) error {
+	if err := a.migrator.CheckVersion(ctx); err != nil {
+		return fmt.Errorf("%w. Ejecute `parking-system migrate up` o active DB_AUTO_MIGRATE", err)
+	}
+
+	return nil
+}
+
+// initServices construye los repositorios y los servicios de aplicación.
+func (a *app) initServices() {
+	cfg := a.cfg
+
+	// -- A. Repositorios
+	a.repos = persistence.NewRepositories(a.db, cfg.DBDriver, cfg.DBTimeout)
+	a.repos.LoginAttempt = metrics.NewLoginAttemptRepository(a.repos.LoginAttempt)
+
+	// -- B. Servicios
+	a.audit = audit.NewService(a.repos.Audit)
+	a.eventBus = events.NewMemoryBus(cfg.EventBufferSize)
+	a.mfa = mfa.NewService(a.repos.UnitOfWork, a.repos.MFA, a.repos.User, a.repos.Role, a.audit, cfg.MFAIssuer, cfg.MFARequiredRoles)
+
+	a.webhook = webhook.NewService(
+		a.repos.UnitOfWork,
+		a.repos.Webhook,
+		httpsender.New(cfg.Webhooks.Timeout),
+		a.audit,
+		cfg.Webhooks.Retry)
+
+	a.outbox = outbox.NewService(a.repos.UnitOfWork, a.repos.Outbox, a.webhook)
+
+	a.parking = parking.NewService(
+		a.repos.UnitOfWork,
+		a.repos.Parking,
+		a.repos.VehicleType,
+		a.audit,
+		metrics.NewPublisher(a.eventBus),
+		a.outbox,
+		cfg.ParkingCapacity,
+		cfg.Pricing)
+
+	a.report = report.NewService(a.repos.Report)
+	a.retention = retention.NewService(
+		a.repos.Retention,
+		archive.NewFileExporter(cfg.Retention.Directory),
+		a.audit,
+		cfg.Retention)
+
+	a.backup = backup.NewService(
+		persistence.NewBackupStorage(a.db, cfg.DBDriver, cfg.Backup.Directory),
+		a.audit,
+		cfg.Backup)
+
+	a.role = role.NewService(a.repos.UnitOfWork, a.repos.Role, a.audit)
+	a.user = user.NewService(a.repos.UnitOfWork, a.repos.User, a.repos.Role, a.audit, a.eventBus, cfg.PasswordPolicy)
+	a.vehicleType = vehicle_type.NewService(a.repos.UnitOfWork, a.repos.VehicleType, a.audit)
+}
+
+// close libera la conexión a DB.
+func (a *app) close() error {
+	return a.db.Close()
+}
+
+// withConnection abre la DB sin verificar el esquema antes de ejecutar fn. La conexión se cierra
+// al terminar.
+func withConnection(cmd *cobra.Command, fn func(ctx context.Context, a *app) error) error {
+	a, err := connect(cmd)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	return fn(cmd.Context(), a)
+}
+
+// withServices abre la DB, verifica el esquema y construye los servicios antes de ejecutar fn.
+// La conexión se cierra al terminar.
+func withServices(cmd *cobra.Command, fn func(ctx context.Context, a *app) error) error {
+	return withConnection(cmd, func(ctx context.Context, a *app) error {
+		if err := a.checkSchema(ctx); err != nil {
+			return err
+		}
+
+		a.initServices()
+
+		return fn(ctx, a)
+	})
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+)
+
+func newAuditCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Tareas sobre el registro de auditoría",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "verify",
+		Short: "Verifica la integridad de la cadena de auditoría",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withServices(cmd, verifyAudit)
+		},
+	})
+
+	return cmd
+}
+
+func verifyAudit(ctx context.Context, a *app) error {
+	result, err := a.audit.Verify(ctx)
+	if err != nil {
+		return fmt.Errorf("error al verificar el registro de auditoría: %w", err)
+	}
+
+	if !result.Valid {
+		return fmt.Errorf("cadena de auditoría inválida en la entrada %d: %s", result.BrokenAtSeq, result.Reason)
+	}
+
+	slog.Info("✅ Cadena de auditoría íntegra", "entries", result.Entries)
+	return nil
+}
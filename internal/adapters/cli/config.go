@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JGCaceres97/parking/internal/infrastructure/config"
+	"github.com/JGCaceres97/parking/internal/infrastructure/logging"
+)
+
+func newConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Consulta la configuración",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "print",
+		Short: "Muestra la configuración efectiva con los secretos ocultos",
+		Long: "Valida la configuración y la muestra en el formato del archivo de configuración. Los\n" +
+			"secretos se ocultan y los valores que no son los predeterminados indican su origen:\n" +
+			"archivo, la variable de entorno o --set.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			return cfg.Print(cmd.OutOrStdout())
+		},
+	})
+
+	return cmd
+}
+
+// loadConfig carga la configuración con las opciones globales --config y --set, y configura el
+// registro de la aplicación.
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	file, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := cmd.Flags().GetStringArray("set")
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.Load(config.Options{File: file, Overrides: overrides})
+	if err != nil {
+		return nil, err
+	}
+
+	setupLogging(cfg.Log)
+
+	return cfg, nil
+}
+
+// setupLogging reemplaza el logger por defecto.
+func setupLogging(cfg config.LogConfig) {
+	slog.SetDefault(logging.New(os.Stderr, cfg.Level, cfg.Format))
+}
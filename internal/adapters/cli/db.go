@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JGCaceres97/parking/internal/infrastructure/persistence"
+)
+
+func newDBCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Tareas de mantenimiento de la base de datos",
+	}
+
+	cmd.AddCommand(newDBBackupCommand(), newDBRestoreCommand())
+
+	return cmd
+}
+
+func newDBBackupCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Crea una copia consistente de la base de datos SQLite sin detener el servidor",
+		Long: "Crea una copia consistente de la base de datos SQLite con VACUUM INTO, sin detener el\n" +
+			"servidor. Sin --output, la copia se guarda en BACKUP_DIR y se eliminan las más antiguas\n" +
+			"según BACKUP_KEEP, igual que las copias programadas.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withServices(cmd, func(ctx context.Context, a *app) error {
+				if output != "" {
+					if err := persistence.Backup(ctx, a.db, a.cfg.DBDriver, output); err != nil {
+						return err
+					}
+
+					slog.Info("✅ Copia de seguridad creada", "path", output)
+					return nil
+				}
+
+				backup, err := a.backup.Create(ctx)
+				if err != nil {
+					return err
+				}
+
+				slog.Info("✅ Copia de seguridad creada", "name", backup.Name, "bytes", backup.Size)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "archivo de destino fuera de BACKUP_DIR")
+
+	return cmd
+}
+
+func newDBRestoreCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore <archivo>",
+		Short: "Reemplaza la base de datos SQLite por una copia de seguridad",
+		Long: "Reemplaza la base de datos SQLite por una copia de seguridad. Detenga el servidor antes\n" +
+			"de restaurar.\n\n" +
+			"La copia se verifica antes del reemplazo: debe superar PRAGMA integrity_check y su\n" +
+			"esquema no puede ser más reciente que las migraciones de este binario. La base de datos\n" +
+			"reemplazada se conserva junto a la original como <archivo>.pre-restore-<fecha>.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// No se abre la DB actual: Restore la reemplaza a nivel de archivo.
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			previous, err := persistence.Restore(cmd.Context(), cfg.DBDriver, cfg.DBConnString, args[0])
+			if err != nil {
+				return fmt.Errorf("no se restauró la copia de seguridad: %w", err)
+			}
+
+			if previous != "" {
+				slog.Info("DB anterior conservada", "path", previous)
+			}
+
+			slog.Info("✅ Copia de seguridad restaurada. Ejecute `parking-system migrate up` si es de una versión anterior", "name", args[0])
+			return nil
+		},
+	}
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// historyColumns es la cabecera del CSV del historial.
+var historyColumns = []string{
+	"id",
+	"license_plate",
+	"vehicle_type_id",
+	"username",
+	"entry_time",
+	"exit_time",
+	"calculated_hours",
+	"total_charge",
+}
+
+func newExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Exporta datos del sistema",
+	}
+
+	cmd.AddCommand(newExportHistoryCommand())
+
+	return cmd
+}
+
+func newExportHistoryCommand() *cobra.Command {
+	var format, output string
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Exporta el historial de estacionamiento en CSV o JSON",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "csv" && format != "json" {
+				return fmt.Errorf("formato no soportado: %q. Use csv o json", format)
+			}
+
+			return withServices(cmd, func(ctx context.Context, a *app) error {
+				records, err := a.parking.GetHistory(ctx)
+				if err != nil {
+					return fmt.Errorf("error al obtener el historial: %w", err)
+				}
+
+				w := cmd.OutOrStdout()
+				if output != "" {
+					file, err := os.Create(output)
+					if err != nil {
+						return fmt.Errorf("error al crear el archivo de salida: %w", err)
+					}
+					defer file.Close()
+
+					w = file
+				}
+
+				if err := writeHistory(w, format, records); err != nil {
+					return err
+				}
+
+				if output != "" {
+					slog.Info("✅ Registros exportados", "records", len(records), "path", output)
+				}
+
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "csv", "formato de salida: csv o json")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "archivo de salida (por defecto, la salida estándar)")
+
+	return cmd
+}
+
+// writeHistory escribe los registros en w. Las fechas se escriben en UTC con formato RFC 3339.
+func writeHistory(w io.Writer, format string, records []domain.ParkingRecord) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(records); err != nil {
+			return fmt.Errorf("error al escribir el historial: %w", err)
+		}
+
+		return nil
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write(historyColumns)
+
+	for _, record := range records {
+		row := []string{
+			record.ID,
+			record.LicensePlate,
+			record.VehicleTypeID,
+			record.Username,
+			record.EntryTime.UTC().Format(time.RFC3339),
+			"",
+			"",
+			"",
+		}
+
+		if record.ExitTime != nil {
+			row[5] = record.ExitTime.UTC().Format(time.RFC3339)
+		}
+
+		if record.CalculatedHours != nil {
+			row[6] = strconv.Itoa(*record.CalculatedHours)
+		}
+
+		if record.TotalCharge != nil {
+			row[7] = strconv.FormatFloat(*record.TotalCharge, 'f', 2, 64)
+		}
+
+		writer.Write(row)
+	}
+
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("error al escribir el historial: %w", err)
+	}
+
+	return nil
+}
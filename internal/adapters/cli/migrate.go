@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Aplica, revierte o lista las migraciones incluidas en el binario",
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "up",
+			Short: "Aplica todas las migraciones pendientes",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return withConnection(cmd, migrateUp)
+			},
+		},
+		&cobra.Command{
+			Use:   "down",
+			Short: "Revierte la última migración aplicada",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return withConnection(cmd, migrateDown)
+			},
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: "Lista las migraciones y la fecha en que se aplicaron",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return withConnection(cmd, migrateStatus)
+			},
+		},
+	)
+
+	return cmd
+}
+
+func migrateUp(ctx context.Context, a *app) error {
+	results, err := a.migrator.Up(ctx)
+	logMigrations(results)
+
+	if err != nil {
+		return err
+	}
+
+	slog.Info("✅ Esquema actualizado", "applied", len(results))
+	return nil
+}
+
+func migrateDown(ctx context.Context, a *app) error {
+	result, err := a.migrator.Down(ctx)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("✅ Migración revertida", "migration", result.Source.Path)
+	return nil
+}
+
+func migrateStatus(ctx context.Context, a *app) error {
+	statuses, err := a.migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		appliedAt := "Pendiente"
+		if status.State == goose.StateApplied {
+			appliedAt = status.AppliedAt.UTC().Format(time.DateTime)
+		}
+
+		slog.Info("migración", "migration", status.Source.Path, "applied_at", appliedAt)
+	}
+
+	return nil
+}
+
+func logMigrations(results []*goose.MigrationResult) {
+	for _, result := range results {
+		slog.Info("migración aplicada", "migration", result.Source.Path, "duration", result.Duration)
+	}
+}
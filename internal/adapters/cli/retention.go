@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newRetentionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retention",
+		Short: "Tareas de archivado de registros antiguos",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "run",
+		Short: "Archiva los registros cerrados anteriores a la política de retención",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withServices(cmd, runRetention)
+		},
+	})
+
+	return cmd
+}
+
+func runRetention(ctx context.Context, a *app) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	result, err := a.retention.Run(ctx)
+	if result != nil && result.Archived > 0 {
+		slog.Info("registros de estacionamiento archivados", "archived", result.Archived, "cutoff", result.Cutoff.Format(time.DateOnly), "batches", result.Batches)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error al archivar registros: %w", err)
+	}
+
+	slog.Info("✅ Archivado completo")
+	return nil
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JGCaceres97/parking/internal/infrastructure/buildinfo"
+)
+
+// Execute ejecuta el comando indicado en los argumentos y retorna el código de salida. Sin
+// subcomando se inicia el servidor.
+func Execute() int {
+	if err := newRootCommand().ExecuteContext(context.Background()); err != nil {
+		slog.Error(fmt.Sprintf("❌ %v", err))
+		return 1
+	}
+
+	return 0
+}
+
+func newRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:   "parking-system",
+		Short: "Sistema de gestión de estacionamiento",
+		Long: "Sistema de gestión de estacionamiento.\n\n" +
+			"Sin subcomando inicia el servidor HTTP (equivale a `parking-system serve`). Los demás\n" +
+			"comandos utilizan la misma configuración y los mismos servicios que la API.\n\n" +
+			"La configuración se obtiene, de mayor a menor prioridad, de --set, las variables de\n" +
+			"entorno (incluido .env), el archivo --config y los valores por defecto.",
+		Version:       buildinfo.Get().Short(),
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE:          runServe,
+	}
+
+	root.CompletionOptions.DisableDefaultCmd = true
+
+	root.PersistentFlags().String("config", "", "archivo de configuración YAML (por defecto CONFIG_FILE)")
+	root.PersistentFlags().StringArray("set", nil, "reemplaza una opción de la configuración, ej. --set server.port=8080")
+
+	root.AddCommand(
+		newServeCommand(),
+		newMigrateCommand(),
+		newUserCommand(),
+		newVehicleTypeCommand(),
+		newExportCommand(),
+		newDBCommand(),
+		newAuditCommand(),
+		newRetentionCommand(),
+		newConfigCommand(),
+	)
+
+	return root
+}
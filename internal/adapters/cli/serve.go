@@ -0,0 +1,300 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JGCaceres97/parking/internal/adapters/api"
+	"github.com/JGCaceres97/parking/internal/adapters/api/handlers"
+	"github.com/JGCaceres97/parking/internal/adapters/api/middlewares"
+	"github.com/JGCaceres97/parking/internal/application/auth"
+	"github.com/JGCaceres97/parking/internal/application/events"
+	"github.com/JGCaceres97/parking/internal/application/health"
+	"github.com/JGCaceres97/parking/internal/application/sso"
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/internal/infrastructure/buildinfo"
+	"github.com/JGCaceres97/parking/internal/infrastructure/config"
+	"github.com/JGCaceres97/parking/internal/infrastructure/metrics"
+	"github.com/JGCaceres97/parking/internal/infrastructure/oidc"
+	"github.com/JGCaceres97/parking/internal/infrastructure/tracing"
+)
+
+func newServeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Inicia el servidor HTTP y los procesos en segundo plano",
+		Args:  cobra.NoArgs,
+		RunE:  runServe,
+	}
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	a, err := connect(cmd)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		slog.Info("cerrando la conexión a la DB")
+		if err := a.close(); err != nil {
+			slog.Warn("error al cerrar la DB", "error", err)
+		}
+
+		slog.Info("conexión a la DB cerrada")
+	}()
+
+	if a.cfg.DBAutoMigrate {
+		results, err := a.migrator.Up(ctx)
+		logMigrations(results)
+
+		if err != nil {
+			return fmt.Errorf("error al aplicar migraciones al iniciar: %w", err)
+		}
+	}
+
+	if err := a.checkSchema(ctx); err != nil {
+		return err
+	}
+
+	// Inyección de dependencias
+	a.initServices()
+	cfg := a.cfg
+
+	// Trazas (opcional). Se envían las pendientes al detener el servidor.
+	if cfg.Tracing.Enabled {
+		shutdown, err := tracing.Setup(ctx, cfg.Tracing.OTLPEndpoint, cfg.Tracing.SampleRatio)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := shutdown(ctx); err != nil {
+				slog.Warn("error al enviar las trazas pendientes", "error", err)
+			}
+		}()
+	}
+
+	keys := auth.NewHMACKeySet(cfg.JWTSecretKey)
+	if cfg.JWTPrivateKeyFile != "" {
+		keys, err = auth.LoadKeySet(cfg.JWTPrivateKeyFile, cfg.JWTPublicKeyFiles)
+		if err != nil {
+			return fmt.Errorf("error al cargar claves JWT: %w", err)
+		}
+	}
+
+	authService := auth.NewService(
+		a.repos.User,
+		a.repos.LoginAttempt,
+		a.mfa,
+		cfg.PasswordPolicy,
+		cfg.LockoutPolicy,
+		keys,
+		cfg.TokenDuration)
+
+	eventsService := events.NewService(a.eventBus, a.role)
+
+	// Inicio de sesión único (opcional)
+	var ssoProvider sso.Provider
+	if cfg.OIDC.IssuerURL != "" {
+		ssoProvider, err = oidc.New(ctx, oidc.Config{
+			IssuerURL:    cfg.OIDC.IssuerURL,
+			ClientID:     cfg.OIDC.ClientID,
+			ClientSecret: cfg.OIDC.ClientSecret,
+			RedirectURL:  cfg.OIDC.RedirectURL,
+			Scopes:       cfg.OIDC.Scopes,
+			GroupsClaim:  cfg.OIDC.GroupsClaim,
+		})
+
+		if err != nil {
+			return fmt.Errorf("error al configurar el proveedor OIDC: %w", err)
+		}
+	}
+
+	ssoService := sso.NewService(
+		a.repos.UnitOfWork,
+		ssoProvider,
+		cfg.OIDC.IssuerURL,
+		sso.NewMemoryStateStore(sso.StateTTL, sso.MaxPendingLogins),
+		a.repos.Identity,
+		a.repos.User,
+		a.repos.Role,
+		authService,
+		a.audit,
+		cfg.OIDC.GroupRoles,
+		cfg.OIDC.DefaultRole)
+
+	if err := ssoService.CheckRoles(ctx); err != nil {
+		return fmt.Errorf("error en la configuración de SSO: %w. Revise OIDC_GROUP_ROLES y OIDC_DEFAULT_ROLE", err)
+	}
+
+	// Admin User
+	if err := authService.CreateAdmin(ctx, cfg.AdminPassword); err != nil {
+		if errors.Is(err, domain.ErrAdminPasswordRequired) {
+			return fmt.Errorf("error asegurando usuario administrador: %w. Defina ADMIN_PASSWORD", err)
+		}
+
+		return fmt.Errorf("error asegurando usuario administrador: %w", err)
+	}
+
+	// Configuración del router
+	handler := api.New(
+		a.audit,
+		authService,
+		a.backup,
+		eventsService,
+		a.mfa,
+		a.parking,
+		a.report,
+		a.role,
+		ssoService,
+		cfg.OIDC.PostLoginURL,
+		a.user,
+		a.vehicleType,
+		a.webhook,
+		cfg.Server.HandlerTimeout,
+	).SetHandler()
+
+	if len(cfg.CORS.AllowedOrigins) > 0 {
+		handler = middlewares.CORS(cfg.CORS.AllowedOrigins, cfg.CORS.MaxAge)(handler)
+	}
+
+	// Rutas de operación: estado, versión y métricas. Se atienden fuera del router para no
+	// registrarse ni medirse a sí mismas.
+	healthService := a.newHealthService()
+	healthHandler := handlers.NewHealthHandler(healthService, buildinfo.Get())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", healthHandler.Live)
+	mux.HandleFunc("GET /readyz", healthHandler.Ready)
+	mux.HandleFunc("GET /version", healthHandler.Version)
+
+	// Métricas (opcional)
+	if cfg.Metrics.Enabled {
+		if err := metrics.RegisterDB(a.db); err != nil {
+			return fmt.Errorf("error al registrar las métricas de DB: %w", err)
+		}
+
+		if err := metrics.RegisterParked(a.repos.Parking, a.repos.VehicleType); err != nil {
+			return fmt.Errorf("error al registrar las métricas de estacionamiento: %w", err)
+		}
+
+		mux.Handle("GET /metrics", metrics.Handler(cfg.Metrics.Token))
+	}
+
+	mux.Handle("/", handler)
+
+	// Procesos en segundo plano: archivado de registros antiguos, copias de seguridad, bandeja de
+	// salida y envío de webhooks
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	defer stopJobs()
+
+	a.retention.Start(jobsCtx)
+	a.backup.Start(jobsCtx)
+	a.outbox.Start(jobsCtx, cfg.OutboxPollInterval)
+	a.webhook.Start(jobsCtx, cfg.Webhooks.PollInterval)
+
+	// Servidor
+	// Sin WriteTimeout: los flujos de eventos son conexiones de larga duración y las demás rutas
+	// están limitadas por SERVER_HANDLER_TIMEOUT.
+	srv := &http.Server{
+		Addr:              ":" + cfg.Server.Port,
+		Handler:           mux,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		ErrorLog:          slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
+	}
+
+	srv.RegisterOnShutdown(a.eventBus.Close)
+
+	return start(srv, cfg.Server, healthService)
+}
+
+// newHealthService construye las verificaciones de /readyz. La DB y su esquema son críticos; una
+// bandeja de salida atrasada solo retrasa los webhooks y degrada el servicio.
+func (a *app) newHealthService() health.Service {
+	return health.NewService(a.cfg.Health.Timeout,
+		health.Check{Name: "database", Critical: true, Run: a.db.PingContext},
+		health.Check{Name: "schema", Critical: true, Run: a.migrator.CheckVersion},
+		health.Check{Name: "outbox", Run: func(ctx context.Context) error {
+			lag, err := a.outbox.Backlog(ctx)
+			if err != nil {
+				return err
+			}
+
+			if lag > a.cfg.Health.OutboxMaxLag {
+				return fmt.Errorf("hay eventos pendientes desde hace %s", lag.Truncate(time.Second))
+			}
+
+			return nil
+		}},
+	)
+}
+
+// start atiende solicitudes hasta recibir una señal de apagado. Antes de detener el servidor se
+// marca como no disponible en /readyz.
+func start(srv *http.Server, cfg config.ServerConfig, readiness health.Service) error {
+	errCh := make(chan error, 1)
+
+	// Arrancar el servidor en una Go-routine.
+	go func() {
+		if cfg.TLS() {
+			slog.Info("servidor escuchando", "addr", srv.Addr, "tls", true)
+
+			errCh <- srv.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+			return
+		}
+
+		slog.Info("servidor escuchando", "addr", srv.Addr, "tls", false)
+
+		errCh <- srv.ListenAndServe()
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+
+	// Bloquear y esperar señal de apagado o error
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("error de servidor: %w", err)
+	case sig := <-quit:
+		slog.Info("señal recibida, deteniendo el servidor", "signal", sig.String())
+
+		readiness.SetReady(false)
+
+		// El servidor sigue atendiendo mientras el balanceador detecta que /readyz falla; cerrar
+		// el listener antes rechazaría las solicitudes que aún le envía.
+		if delay := cfg.ShutdownDelay; delay > 0 {
+			slog.Info("esperando que el balanceador retire el servidor", "delay", delay)
+
+			select {
+			case <-time.After(delay):
+			case <-quit:
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+
+		if err := srv.Shutdown(ctx); err != nil {
+			slog.Warn("el servidor se apagó forzosamente", "error", err)
+		}
+
+		slog.Info("servidor detenido")
+	}
+
+	return nil
+}
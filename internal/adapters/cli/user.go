@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+func newUserCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Administración de usuarios",
+	}
+
+	cmd.AddCommand(
+		newUserCreateCommand(),
+		newUserResetPasswordCommand(),
+		newUserUnlockCommand(),
+	)
+
+	return cmd
+}
+
+func newUserCreateCommand() *cobra.Command {
+	var (
+		username string
+		role     string
+		inactive bool
+	)
+
+	cmd := &cobra.Command{
+		Use:     "create",
+		Short:   "Crea un usuario con la contraseña leída de la entrada estándar",
+		Example: "  echo 'Cajero2026!' | parking-system user create --username cajero1 --role cashier",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// La contraseña no se recibe como argumento para que no quede en el historial de la
+			// terminal ni en la lista de procesos.
+			password, err := readPassword(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+
+			return withServices(cmd, func(ctx context.Context, a *app) error {
+				user, err := a.user.Create(ctx, &domain.User{
+					Username: username,
+					Password: password,
+					Role:     domain.Role(role),
+					IsActive: !inactive,
+				})
+
+				if err != nil {
+					return fmt.Errorf("error al crear el usuario: %w", err)
+				}
+
+				slog.Info("✅ Usuario creado", "username", user.Username, "role", user.Role, "user_id", user.ID)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&username, "username", "", "nombre de usuario")
+	cmd.Flags().StringVar(&role, "role", string(domain.RoleCommon), "rol asignado")
+	cmd.Flags().BoolVar(&inactive, "inactive", false, "crea el usuario bloqueado")
+	cmd.MarkFlagRequired("username")
+
+	return cmd
+}
+
+func newUserResetPasswordCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reset-password <username>",
+		Short: "Genera una contraseña temporal que el usuario debe cambiar al iniciar sesión",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withServices(cmd, func(ctx context.Context, a *app) error {
+				user, err := a.user.FindByUsername(ctx, args[0])
+				if err != nil {
+					return err
+				}
+
+				password, err := a.user.ResetPassword(ctx, user.ID)
+				if err != nil {
+					return fmt.Errorf("error al restablecer la contraseña: %w", err)
+				}
+
+				slog.Info("✅ Contraseña temporal generada", "username", user.Username)
+
+				// Solo la contraseña va a la salida estándar, para poder redirigirla.
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), password)
+				return err
+			})
+		},
+	}
+}
+
+func newUserUnlockCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unlock <username>",
+		Short: "Desbloquea una cuenta bloqueada por intentos fallidos de inicio de sesión",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withServices(cmd, func(ctx context.Context, a *app) error {
+				user, err := a.user.FindByUsername(ctx, args[0])
+				if err != nil {
+					return err
+				}
+
+				if _, err := a.user.Unlock(ctx, user.ID); err != nil {
+					return err
+				}
+
+				slog.Info("✅ Usuario desbloqueado", "username", user.Username)
+				return nil
+			})
+		},
+	}
+}
+
+// readPassword lee la contraseña de la primera línea de r.
+func readPassword(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("error al leer la contraseña: %w", err)
+	}
+
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("la contraseña es obligatoria: envíela por la entrada estándar")
+	}
+
+	return password, nil
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+func newVehicleTypeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vehicle-type",
+		Short: "Administración de tipos de vehículo",
+	}
+
+	cmd.AddCommand(newVehicleTypeImportCommand())
+
+	return cmd
+}
+
+func newVehicleTypeImportCommand() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "import <archivo>",
+		Short: "Crea o actualiza tipos de vehículo desde un archivo CSV o JSON",
+		Long: "Crea o actualiza tipos de vehículo desde un archivo CSV o JSON. Los tipos se identifican\n" +
+			"por nombre: si ya existe uno con el mismo nombre se actualizan su tarifa y descripción.\n" +
+			"La importación es atómica: si un tipo es inválido no se guarda ninguno.\n\n" +
+			"El CSV requiere una cabecera con las columnas name, hourly_rate y, opcionalmente,\n" +
+			"description. El JSON es un arreglo de objetos con los mismos campos. Use - para leer\n" +
+			"de la entrada estándar.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vehicleTypes, err := readVehicleTypes(cmd.InOrStdin(), args[0], format)
+			if err != nil {
+				return err
+			}
+
+			return withServices(cmd, func(ctx context.Context, a *app) error {
+				result, err := a.vehicleType.Import(ctx, vehicleTypes)
+				if err != nil {
+					return fmt.Errorf("error al importar tipos de vehículo: %w", err)
+				}
+
+				slog.Info("✅ Tipos de vehículo importados", "created", result.Created, "updated", result.Updated)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "formato del archivo: csv o json (por defecto, según la extensión)")
+
+	return cmd
+}
+
+// readVehicleTypes lee los tipos de vehículo de path, o de stdin si path es "-".
+func readVehicleTypes(stdin io.Reader, path, format string) ([]domain.VehicleType, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+
+	r := stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("error al abrir el archivo: %w", err)
+		}
+		defer file.Close()
+
+		r = file
+	}
+
+	switch format {
+	case "csv":
+		return decodeVehicleTypesCSV(r)
+
+	case "json":
+		var vehicleTypes []domain.VehicleType
+		if err := json.NewDecoder(r).Decode(&vehicleTypes); err != nil {
+			return nil, fmt.Errorf("error al leer el JSON: %w", err)
+		}
+
+		return vehicleTypes, nil
+
+	default:
+		return nil, fmt.Errorf("formato no soportado: %q. Use --format csv o --format json", format)
+	}
+}
+
+func decodeVehicleTypesCSV(r io.Reader) ([]domain.VehicleType, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("el CSV está vacío")
+		}
+
+		return nil, fmt.Errorf("error al leer la cabecera del CSV: %w", err)
+	}
+
+	index := map[string]int{}
+	for i, column := range header {
+		index[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	for _, column := range []string{"name", "hourly_rate"} {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("falta la columna %q en la cabecera del CSV", column)
+		}
+	}
+
+	vehicleTypes := []domain.VehicleType{}
+
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("error al leer el CSV: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(row[index["hourly_rate"]]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("línea %d: tarifa por hora inválida: %q", line, row[index["hourly_rate"]])
+		}
+
+		vehicleType := domain.VehicleType{
+			Name:       row[index["name"]],
+			HourlyRate: rate,
+		}
+
+		if i, ok := index["description"]; ok {
+			vehicleType.Description = row[i]
+		}
+
+		vehicleTypes = append(vehicleTypes, vehicleType)
+	}
+
+	return vehicleTypes, nil
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+func TestDecodeVehicleTypesCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []domain.VehicleType
+		wantErr bool
+	}{
+		{
+			name:  "Con descripción",
+			input: "name,hourly_rate,description\nNormal,15,Tarifa regular\nBicicleta, 1.5,\n",
+			want: []domain.VehicleType{
+				{Name: "Normal", HourlyRate: 15, Description: "Tarifa regular"},
+				{Name: "Bicicleta", HourlyRate: 1.5},
+			},
+		},
+		{
+			name:  "Columnas en otro orden y sin descripción",
+			input: "Hourly_Rate,Name\n0,Motocicleta\n",
+			want:  []domain.VehicleType{{Name: "Motocicleta", HourlyRate: 0}},
+		},
+		{name: "Falta la tarifa", input: "name,description\nNormal,x\n", wantErr: true},
+		{name: "Tarifa inválida", input: "name,hourly_rate\nNormal,quince\n", wantErr: true},
+		{name: "Archivo vacío", input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeVehicleTypesCSV(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeVehicleTypesCSV() error = %v, se esperaba error: %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeVehicleTypesCSV() = %+v, se esperaba %+v", got, tt.want)
+			}
+		})
+	}
+}
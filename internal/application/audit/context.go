@@ -0,0 +1,33 @@
+package audit
+
+import "context"
+
+type contextKey struct{}
+
+// Actor identifica quién realiza una acción y desde qué solicitud.
+type Actor struct {
+	UserID    string
+	IP        string
+	RequestID string
+}
+
+// WithActor asocia el actor al contexto de la solicitud.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, contextKey{}, actor)
+}
+
+// WithUserID asocia el usuario autenticado al actor del contexto, conservando la IP y el ID de
+// la solicitud.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	actor := ActorFromContext(ctx)
+	actor.UserID = userID
+
+	return WithActor(ctx, actor)
+}
+
+// ActorFromContext obtiene el actor del contexto. Las acciones del sistema (p. ej. al iniciar)
+// no tienen actor.
+func ActorFromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(contextKey{}).(Actor)
+	return actor
+}
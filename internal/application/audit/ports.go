@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// Recorder registra las acciones que no se guardan en una transacción de la base de datos, como
+// los respaldos y el archivado.
+type Recorder interface {
+	// Record agrega una entrada al registro de auditoría con el estado de la entidad antes y
+	// después de la acción (nil si no aplica). El actor, la IP y el ID de la solicitud se obtienen
+	// del contexto. Un error al registrar no interrumpe la operación auditada.
+	Record(ctx context.Context, action, entityType, entityID string, before, after any)
+}
+
+// TxRecorder registra la auditoría como parte de la transacción de la operación auditada.
+type TxRecorder interface {
+	// RecordTx agrega la entrada igual que Recorder.Record, pero usa ctx sin desligarlo y retorna
+	// el error. Llamado dentro de transaction.UnitOfWork.Do, la entrada se confirma o se revierte
+	// junto con la operación.
+	RecordTx(ctx context.Context, action, entityType, entityID string, before, after any) error
+}
+
+type Service interface {
+	Recorder
+	TxRecorder
+
+	// List retorna las entradas que cumplen el filtro, de la más reciente a la más antigua.
+	List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error)
+
+	// Verify recorre la cadena completa y comprueba el hash y el enlace de cada entrada.
+	Verify(ctx context.Context) (*domain.AuditVerification, error)
+}
+
+type Repository interface {
+	// Append asigna a la entrada el siguiente número de secuencia y el hash de la anterior,
+	// calcula su propio hash y la guarda. Las entradas se agregan de forma serializada.
+	Append(ctx context.Context, entry *domain.AuditEntry) error
+
+	// List retorna las entradas que cumplen el filtro, ordenadas por secuencia descendente.
+	List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error)
+
+	// ListAfter retorna hasta limit entradas con secuencia mayor a afterSeq, en orden ascendente.
+	ListAfter(ctx context.Context, afterSeq int64, limit int) ([]domain.AuditEntry, error)
+
+	// Head retorna la secuencia y el hash de la última entrada registrada.
+	Head(ctx context.Context) (int64, string, error)
+}
@@ -0,0 +1,152 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/ulid"
+)
+
+var tracer = otel.Tracer("github.com/JGCaceres97/parking/internal/application/audit")
+
+// verifyBatchSize es la cantidad de entradas leídas por consulta al verificar la cadena.
+const verifyBatchSize = 500
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Record(ctx context.Context, action, entityType, entityID string, before, after any) {
+	ctx, span := tracer.Start(ctx, "audit.Record")
+	defer span.End()
+
+	entry := newEntry(ctx, action, entityType, entityID, before, after)
+
+	// La acción ya se realizó: se registra aunque el cliente haya cancelado la solicitud.
+	if err := s.repo.Append(context.WithoutCancel(ctx), &entry); err != nil {
+		slog.WarnContext(ctx, "no se pudo registrar la auditoría", "action", action, "entity_type", entityType, "entity_id", entityID, "error", err)
+	}
+}
+
+func (s *service) RecordTx(ctx context.Context, action, entityType, entityID string, before, after any) error {
+	ctx, span := tracer.Start(ctx, "audit.RecordTx")
+	defer span.End()
+
+	entry := newEntry(ctx, action, entityType, entityID, before, after)
+
+	if err := s.repo.Append(ctx, &entry); err != nil {
+		return fmt.Errorf("error al registrar la auditoría: %w", err)
+	}
+
+	return nil
+}
+
+// newEntry arma la entrada con el actor, la IP y el ID de la solicitud del contexto.
+func newEntry(ctx context.Context, action, entityType, entityID string, before, after any) domain.AuditEntry {
+	actor := ActorFromContext(ctx)
+
+	return domain.AuditEntry{
+		ID:         ulid.GenerateNewULID(),
+		ActorID:    actor.UserID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     snapshot(before),
+		After:      snapshot(after),
+		IP:         actor.IP,
+		RequestID:  actor.RequestID,
+		CreatedAt:  time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func (s *service) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	ctx, span := tracer.Start(ctx, "audit.List")
+	defer span.End()
+
+	return s.repo.List(ctx, filter)
+}
+
+func (s *service) Verify(ctx context.Context) (*domain.AuditVerification, error) {
+	ctx, span := tracer.Start(ctx, "audit.Verify")
+	defer span.End()
+
+	result := &domain.AuditVerification{Valid: true}
+	prevHash := domain.AuditGenesisHash
+
+	for {
+		entries, err := s.repo.ListAfter(ctx, result.Entries, verifyBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("error al leer el registro de auditoría: %w", err)
+		}
+
+		for _, entry := range entries {
+			if reason := checkEntry(entry, result.Entries+1, prevHash); reason != "" {
+				result.Valid = false
+				result.BrokenAtSeq = result.Entries + 1
+				result.Reason = reason
+
+				return result, nil
+			}
+
+			prevHash = entry.Hash
+			result.Entries++
+		}
+
+		if len(entries) < verifyBatchSize {
+			break
+		}
+	}
+
+	// La cabeza permite detectar la eliminación de las últimas entradas.
+	headSeq, headHash, err := s.repo.Head(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer la cabeza de la cadena de auditoría: %w", err)
+	}
+
+	if headSeq != result.Entries || headHash != prevHash {
+		result.Valid = false
+		result.BrokenAtSeq = result.Entries + 1
+		result.Reason = fmt.Sprintf("la cadena termina en la entrada %d pero la cabeza registra %d", result.Entries, headSeq)
+	}
+
+	return result, nil
+}
+
+// checkEntry compara la entrada con la posición y el hash esperados. Retorna el motivo del
+// fallo o una cadena vacía si es válida.
+func checkEntry(entry domain.AuditEntry, seq int64, prevHash string) string {
+	switch {
+	case entry.Seq != seq:
+		return fmt.Sprintf("falta la entrada %d", seq)
+	case entry.PrevHash != prevHash:
+		return "el hash previo no coincide con la entrada anterior"
+	case entry.ComputeHash() != entry.Hash:
+		return "el contenido de la entrada fue modificado"
+	default:
+		return ""
+	}
+}
+
+// snapshot serializa el estado de una entidad. Los valores nil se registran como ausentes.
+func snapshot(value any) json.RawMessage {
+	if value == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil || string(data) == "null" {
+		return nil
+	}
+
+	return data
+}
@@ -0,0 +1,94 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// memoryRepository guarda la cadena en memoria y permite alterarla para simular manipulaciones.
+type memoryRepository struct {
+	entries  []domain.AuditEntry
+	headSeq  int64
+	headHash string
+}
+
+func (m *memoryRepository) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	prevHash := domain.AuditGenesisHash
+	if m.headSeq > 0 {
+		prevHash = m.headHash
+	}
+
+	entry.Link(m.headSeq+1, prevHash)
+	m.entries = append(m.entries, *entry)
+	m.headSeq, m.headHash = entry.Seq, entry.Hash
+
+	return nil
+}
+
+func (m *memoryRepository) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	return m.entries, nil
+}
+
+func (m *memoryRepository) ListAfter(ctx context.Context, afterSeq int64, limit int) ([]domain.AuditEntry, error) {
+	result := []domain.AuditEntry{}
+	for _, entry := range m.entries {
+		if entry.Seq > afterSeq && len(result) < limit {
+			result = append(result, entry)
+		}
+	}
+
+	return result, nil
+}
+
+func (m *memoryRepository) Head(ctx context.Context) (int64, string, error) {
+	if m.headSeq == 0 {
+		return 0, domain.AuditGenesisHash, nil
+	}
+
+	return m.headSeq, m.headHash, nil
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name       string
+		tamper     func(m *memoryRepository)
+		wantValid  bool
+		wantBroken int64
+	}{
+		{"Cadena íntegra", func(m *memoryRepository) {}, true, 0},
+		{"Contenido modificado", func(m *memoryRepository) { m.entries[1].After = []byte(`{"role":"admin"}`) }, false, 2},
+		{"Hash recalculado sin reenlazar", func(m *memoryRepository) {
+			m.entries[1].ActorID = "otro"
+			m.entries[1].Hash = m.entries[1].ComputeHash()
+		}, false, 3},
+		{"Entrada intermedia eliminada", func(m *memoryRepository) {
+			m.entries = append(m.entries[:1], m.entries[2:]...)
+		}, false, 2},
+		{"Última entrada eliminada", func(m *memoryRepository) { m.entries = m.entries[:2] }, false, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryRepository{}
+			s := NewService(repo)
+
+			ctx := WithActor(context.Background(), Actor{UserID: "01ADMIN", IP: "10.0.0.1"})
+			s.Record(ctx, domain.AuditUserCreate, domain.AuditEntityUser, "01A", nil, map[string]string{"role": "common"})
+			s.Record(ctx, domain.AuditUserUpdate, domain.AuditEntityUser, "01A", map[string]string{"role": "common"}, map[string]string{"role": "cashier"})
+			s.Record(ctx, domain.AuditUserDelete, domain.AuditEntityUser, "01A", map[string]string{"role": "cashier"}, nil)
+
+			tt.tamper(repo)
+
+			result, err := s.Verify(context.Background())
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+
+			if result.Valid != tt.wantValid || result.BrokenAtSeq != tt.wantBroken {
+				t.Errorf("Verify() = %+v, se esperaba valid=%t broken_at_seq=%d", result, tt.wantValid, tt.wantBroken)
+			}
+		})
+	}
+}
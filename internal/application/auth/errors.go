@@ -1,6 +1,25 @@
 package auth
 
-import "errors"
+import (
+	"errors"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
 
 var ErrInvalidToken = errors.New("token JWT inválido o mal formado")
 var ErrExpiredToken = errors.New("token JWT expirado")
+
+// ThrottledError indica que el inicio de sesión fue rechazado por espera exponencial.
+// Envuelve domain.ErrTooManyAttempts.
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return domain.ErrTooManyAttempts.Error()
+}
+
+func (e *ThrottledError) Unwrap() error {
+	return domain.ErrTooManyAttempts
+}
@@ -0,0 +1,309 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// hmacKeyID identifica la clave compartida HS256. Se usa también para aceptar los tokens emitidos
+// antes de incluir la cabecera kid.
+const hmacKeyID = "hs256"
+
+var ErrUnsupportedKey = errors.New("tipo de clave no soportado: se esperaba RSA o Ed25519")
+
+// verificationKey es una clave capaz de verificar tokens firmados con un método concreto.
+type verificationKey struct {
+	id     string
+	method jwt.SigningMethod
+	key    any
+}
+
+// KeySet agrupa la clave con la que se firman los tokens y las claves aceptadas al verificarlos.
+// Durante una rotación, la clave anterior se conserva como clave de verificación hasta que
+// expiren los tokens firmados con ella.
+type KeySet struct {
+	signingID     string
+	signingMethod jwt.SigningMethod
+	signingKey    any
+	verify        map[string]verificationKey
+}
+
+// NewHMACKeySet crea un KeySet con una clave compartida HS256. Solo se recomienda para desarrollo,
+// ya que cualquier servicio capaz de verificar los tokens también puede emitirlos.
+func NewHMACKeySet(secret string) *KeySet {
+	key := []byte(secret)
+
+	return &KeySet{
+		signingID:     hmacKeyID,
+		signingMethod: jwt.SigningMethodHS256,
+		signingKey:    key,
+		verify: map[string]verificationKey{
+			hmacKeyID: {id: hmacKeyID, method: jwt.SigningMethodHS256, key: key},
+		},
+	}
+}
+
+// NewKeySet crea un KeySet que firma con la clave privada indicada (RSA para RS256 o Ed25519 para
+// EdDSA) y acepta además las claves públicas adicionales durante la rotación.
+func NewKeySet(signer crypto.Signer, previous ...crypto.PublicKey) (*KeySet, error) {
+	signingKey, err := newVerificationKey(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	ks := &KeySet{
+		signingID:     signingKey.id,
+		signingMethod: signingKey.method,
+		signingKey:    signer,
+		verify:        map[string]verificationKey{signingKey.id: signingKey},
+	}
+
+	for _, pub := range previous {
+		key, err := newVerificationKey(pub)
+		if err != nil {
+			return nil, err
+		}
+
+		ks.verify[key.id] = key
+	}
+
+	return ks, nil
+}
+
+// LoadKeySet lee la clave privada de firma y las claves públicas de verificación desde archivos PEM.
+func LoadKeySet(privateKeyFile string, publicKeyFiles []string) (*KeySet, error) {
+	data, err := os.ReadFile(privateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer clave privada JWT: %w", err)
+	}
+
+	signer, err := ParsePrivateKeyPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("error en clave privada JWT %s: %w", privateKeyFile, err)
+	}
+
+	previous := make([]crypto.PublicKey, 0, len(publicKeyFiles))
+	for _, file := range publicKeyFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error al leer clave pública JWT: %w", err)
+		}
+
+		pub, err := ParsePublicKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("error en clave pública JWT %s: %w", file, err)
+		}
+
+		previous = append(previous, pub)
+	}
+
+	return NewKeySet(signer, previous...)
+}
+
+// ParsePrivateKeyPEM interpreta una clave privada RSA (PKCS#1 o PKCS#8) o Ed25519 (PKCS#8).
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no se encontró un bloque PEM")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("clave privada inválida: %w", err)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case ed25519.PrivateKey:
+		return k, nil
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
+
+// ParsePublicKeyPEM interpreta una clave pública RSA o Ed25519 (PKIX), o una clave pública RSA PKCS#1.
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no se encontró un bloque PEM")
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("clave pública inválida: %w", err)
+	}
+
+	return key, nil
+}
+
+// Sign firma los claims con la clave activa e incluye su identificador en la cabecera kid.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signingMethod, claims)
+	token.Header["kid"] = ks.signingID
+
+	return token.SignedString(ks.signingKey)
+}
+
+// Keyfunc selecciona la clave de verificación según la cabecera kid del token y exige que el
+// algoritmo coincida con el de la clave, evitando ataques de confusión de algoritmo.
+func (ks *KeySet) Keyfunc(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	// Los tokens emitidos antes de incluir kid solo pueden haber sido firmados con HS256.
+	if kid == "" {
+		kid = hmacKeyID
+	}
+
+	key, ok := ks.verify[kid]
+	if !ok {
+		return nil, fmt.Errorf("clave de firma desconocida: %q", kid)
+	}
+
+	if t.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("método de firma inesperado: %v", t.Header["alg"])
+	}
+
+	return key.key, nil
+}
+
+// Methods retorna los algoritmos aceptados al verificar.
+func (ks *KeySet) Methods() []string {
+	methods := []string{}
+	seen := map[string]bool{}
+
+	for _, key := range ks.verify {
+		if alg := key.method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+
+	return methods
+}
+
+// JSONWebKey es la representación pública de una clave según la RFC 7517.
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JWKS retorna las claves públicas de verificación. La clave compartida HS256 nunca se publica.
+func (ks *KeySet) JWKS() JSONWebKeySet {
+	set := JSONWebKeySet{Keys: []JSONWebKey{}}
+
+	// La clave de firma va primero; el resto en orden estable.
+	if key, ok := ks.verify[ks.signingID]; ok {
+		if jwk, ok := toJWK(key); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+
+	ids := make([]string, 0, len(ks.verify))
+	for id := range ks.verify {
+		if id != ks.signingID {
+			ids = append(ids, id)
+		}
+	}
+
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		if jwk, ok := toJWK(ks.verify[id]); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+
+	return set
+}
+
+func newVerificationKey(pub crypto.PublicKey) (verificationKey, error) {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		key := verificationKey{method: jwt.SigningMethodRS256, key: k}
+		key.id = thumbprint(map[string]string{
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+		})
+
+		return key, nil
+
+	case ed25519.PublicKey:
+		key := verificationKey{method: jwt.SigningMethodEdDSA, key: k}
+		key.id = thumbprint(map[string]string{
+			"crv": "Ed25519",
+			"kty": "OKP",
+			"x":   base64.RawURLEncoding.EncodeToString(k),
+		})
+
+		return key, nil
+
+	default:
+		return verificationKey{}, ErrUnsupportedKey
+	}
+}
+
+// thumbprint calcula el identificador de la clave según la RFC 7638: SHA-256 del JSON con los
+// miembros requeridos en orden lexicográfico.
+func thumbprint(members map[string]string) string {
+	// encoding/json ordena las claves de los mapas, tal como exige la RFC.
+	data, _ := json.Marshal(members)
+	sum := sha256.Sum256(data)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func toJWK(key verificationKey) (JSONWebKey, bool) {
+	jwk := JSONWebKey{KeyID: key.id, Use: "sig", Algorithm: key.method.Alg()}
+
+	switch k := key.key.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(k.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(k)
+
+	default:
+		return JSONWebKey{}, false
+	}
+
+	return jwk, true
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTestClaims() *Claims {
+	return &Claims{
+		UserID: "01TEST",
+		Role:   "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+}
+
+func parseWith(ks *KeySet, tokenStr string) error {
+	_, err := jwt.ParseWithClaims(tokenStr, &Claims{}, ks.Keyfunc, jwt.WithValidMethods(ks.Methods()))
+	return err
+}
+
+func TestKeySetSignAndVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error al generar clave RSA: %v", err)
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("error al generar clave Ed25519: %v", err)
+	}
+
+	rsaSet, _ := NewKeySet(rsaKey)
+	edSet, _ := NewKeySet(edKey)
+	hmacSet := NewHMACKeySet("secreto")
+
+	// Tras rotar, la clave Ed25519 firma y la RSA anterior solo verifica.
+	rotatedSet, _ := NewKeySet(edKey, rsaKey.Public())
+
+	rsaToken, _ := rsaSet.Sign(newTestClaims())
+	edToken, _ := edSet.Sign(newTestClaims())
+	hmacToken, _ := hmacSet.Sign(newTestClaims())
+
+	// Token HS256 firmado con el módulo público RSA como secreto (confusión de algoritmo).
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, newTestClaims())
+	confused.Header["kid"] = rsaSet.signingID
+	confusedToken, _ := confused.SignedString(x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey))
+
+	// Token emitido antes de incluir la cabecera kid.
+	legacyToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, newTestClaims()).SignedString([]byte("secreto"))
+
+	tests := []struct {
+		name    string
+		set     *KeySet
+		token   string
+		wantErr bool
+	}{
+		{"RS256 con su propia clave", rsaSet, rsaToken, false},
+		{"EdDSA con su propia clave", edSet, edToken, false},
+		{"HS256 con su propio secreto", hmacSet, hmacToken, false},
+		{"HS256 sin kid (token anterior)", hmacSet, legacyToken, false},
+		{"Clave anterior aceptada durante la rotación", rotatedSet, rsaToken, false},
+		{"Clave nueva tras la rotación", rotatedSet, edToken, false},
+		{"kid desconocido", edSet, rsaToken, true},
+		{"HS256 rechazado por un KeySet asimétrico", rsaSet, hmacToken, true},
+		{"Confusión de algoritmo RS256 a HS256", rsaSet, confusedToken, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parseWith(tt.set, tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, se esperaba error: %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeySetJWKS(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	ks, err := NewKeySet(edKey, rsaKey.Public())
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	jwks := ks.JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("se esperaban 2 claves, se obtuvieron %d", len(jwks.Keys))
+	}
+
+	if jwks.Keys[0].KeyID != ks.signingID || jwks.Keys[0].Algorithm != "EdDSA" || jwks.Keys[0].Curve != "Ed25519" {
+		t.Errorf("la clave de firma debe publicarse primero: %+v", jwks.Keys[0])
+	}
+
+	if jwks.Keys[1].Algorithm != "RS256" || jwks.Keys[1].N == "" || jwks.Keys[1].E != "AQAB" {
+		t.Errorf("clave RSA inesperada: %+v", jwks.Keys[1])
+	}
+
+	if keys := NewHMACKeySet("secreto").JWKS().Keys; len(keys) != 0 {
+		t.Errorf("la clave HS256 no debe publicarse: %+v", keys)
+	}
+}
+
+func TestThumbprint(t *testing.T) {
+	// Ejemplo de la sección 3.1 de la RFC 7638.
+	n := "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw"
+
+	got := thumbprint(map[string]string{"e": "AQAB", "kty": "RSA", "n": n})
+	want := "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+
+	if got != want {
+		t.Errorf("thumbprint() = %s, se esperaba %s", got, want)
+	}
+}
+
+func TestParseKeysPEM(t *testing.T) {
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	der, _ := x509.MarshalPKCS8PrivateKey(edKey)
+	signer, err := ParsePrivateKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("error al interpretar clave privada: %v", err)
+	}
+
+	pubDer, _ := x509.MarshalPKIXPublicKey(edKey.Public())
+	pub, err := ParsePublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDer}))
+	if err != nil {
+		t.Fatalf("error al interpretar clave pública: %v", err)
+	}
+
+	if !pub.(interface{ Equal(crypto.PublicKey) bool }).Equal(signer.Public()) {
+		t.Error("la clave pública no corresponde a la clave privada")
+	}
+
+	if _, err := ParsePrivateKeyPEM([]byte("no es PEM")); err == nil {
+		t.Error("se esperaba error con contenido inválido")
+	}
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"time"
 
 	"github.com/JGCaceres97/parking/internal/domain"
 )
@@ -9,23 +10,75 @@ import (
 type LoginInput struct {
 	Username string
 	Password string
+	IP       string
 }
 
 type LoginOutput struct {
-	Token     string
-	TokenType string
-	ExpiresIn int64
-	Role      domain.Role
+	Token              string
+	TokenType          string
+	ExpiresIn          int64
+	Role               domain.Role
+	MustChangePassword bool
+	// MFARequired indica que la contraseña es correcta pero falta verificar el segundo factor.
+	// En ese caso Token está vacío y MFAToken debe enviarse a LoginMFA junto con el código.
+	MFARequired bool
+	MFAToken    string
+	// MFAEnrollmentRequired indica que el rol del usuario exige configurar el segundo factor.
+	MFAEnrollmentRequired bool
+}
+
+type LoginMFAInput struct {
+	MFAToken string
+	Code     string
+	IP       string
 }
 
 type Service interface {
-	// CreateAdmin configura el primer usuario administrador del sistema.
+	// CreateAdmin configura el primer usuario administrador del sistema. Si la contraseña no
+	// cumple la política vigente, el administrador deberá cambiarla en su primer inicio de sesión.
 	CreateAdmin(ctx context.Context, password string) error
 
 	// Login verifica las credenciales y, si son válidas, genera un token JWT.
 	// Retorna un LoginResponse que incluye el token y su expiración.
+	// Si el usuario tiene activa la autenticación de dos factores, retorna únicamente un token
+	// temporal (MFARequired) que debe completarse con LoginMFA.
 	Login(ctx context.Context, req LoginInput) (*LoginOutput, error)
 
-	// ParseToken verifica la validez del JWT y extrae los claims. Retorna el ID de usuario y el rol.
-	ParseToken(tokenStr string) (userId string, role domain.Role, err error)
+	// LoginMFA completa un inicio de sesión pendiente verificando el código TOTP o un código de
+	// recuperación.
+	LoginMFA(ctx context.Context, req LoginMFAInput) (*LoginOutput, error)
+
+	// IssueToken emite el token de acceso para un usuario autenticado por un proveedor externo
+	// (inicio de sesión único) y registra el acceso.
+	IssueToken(ctx context.Context, user *domain.User, ip string) (*LoginOutput, error)
+
+	// Reissue emite un nuevo token de acceso con el estado actual del usuario, por ejemplo tras
+	// confirmar el segundo factor, sin registrar un inicio de sesión.
+	Reissue(ctx context.Context, userID string) (*LoginOutput, error)
+
+	// ParseToken verifica la validez del JWT y extrae los claims.
+	ParseToken(tokenStr string) (*Claims, error)
+
+	// Authenticate verifica el JWT y que el usuario exista y no haya revocado sus tokens.
+	// El rol y la obligación de cambiar la contraseña se toman del estado actual del usuario.
+	Authenticate(ctx context.Context, tokenStr string) (*Claims, error)
+
+	// JWKS retorna las claves públicas con las que otros servicios pueden verificar los tokens.
+	JWKS() JSONWebKeySet
+
+	// ListLoginAttempts lista los intentos de inicio de sesión más recientes, opcionalmente
+	// filtrados por nombre de usuario.
+	ListLoginAttempts(ctx context.Context, username string, limit int) ([]domain.LoginAttempt, error)
+}
+
+type LoginAttemptRepository interface {
+	// Create registra un intento de inicio de sesión, exitoso o fallido.
+	Create(ctx context.Context, attempt *domain.LoginAttempt) error
+
+	// FailuresByIP cuenta los intentos fallidos desde una IP a partir de la fecha indicada y
+	// retorna la fecha del último de ellos.
+	FailuresByIP(ctx context.Context, ip string, since time.Time) (int, *time.Time, error)
+
+	// List lista los intentos más recientes. Si username está vacío, no se filtra.
+	List(ctx context.Context, username string, limit int) ([]domain.LoginAttempt, error)
 }
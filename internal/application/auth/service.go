@@ -4,54 +4,96 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"go.opentelemetry.io/otel"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/JGCaceres97/parking/internal/application/mfa"
 	"github.com/JGCaceres97/parking/internal/application/user"
 	"github.com/JGCaceres97/parking/internal/domain"
 	"github.com/JGCaceres97/parking/pkg/ulid"
 )
 
+var tracer = otel.Tracer("github.com/JGCaceres97/parking/internal/application/auth")
+
+// mfaTokenDuration es la vigencia del token temporal emitido mientras se espera el segundo factor.
+const mfaTokenDuration = 5 * time.Minute
+
+// tokenUseMFA identifica a los tokens temporales del segundo factor, que no autorizan el acceso.
+const tokenUseMFA = "mfa"
+
 type service struct {
 	repo          user.Repository
-	secretKey     []byte
+	attempts      LoginAttemptRepository
+	mfa           mfa.Service
+	policy        domain.PasswordPolicy
+	lockout       domain.LockoutPolicy
+	keys          *KeySet
 	tokenDuration time.Duration
 }
 
 type Claims struct {
-	UserID string `json:"user_id"`
-	Role   string `json:"role"`
+	UserID             string `json:"user_id"`
+	Role               string `json:"role"`
+	MustChangePassword bool   `json:"must_change_password,omitempty"`
+	// MFAEnrollmentRequired indica que el usuario debe configurar el segundo factor.
+	MFAEnrollmentRequired bool `json:"mfa_enrollment_required,omitempty"`
+	// TokenUse distingue los tokens temporales del segundo factor de los tokens de acceso.
+	TokenUse string `json:"token_use,omitempty"`
+	// TokenVersion es la versión de los tokens del usuario al emitirse; ver domain.User.
+	TokenVersion int `json:"ver,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func NewService(repo user.Repository, secretKey string, tokenDuration time.Duration) Service {
+func NewService(
+	repo user.Repository,
+	attempts LoginAttemptRepository,
+	mfa mfa.Service,
+	policy domain.PasswordPolicy,
+	lockout domain.LockoutPolicy,
+	keys *KeySet,
+	tokenDuration time.Duration,
+) Service {
 	return &service{
 		repo:          repo,
-		secretKey:     []byte(secretKey),
+		attempts:      attempts,
+		mfa:           mfa,
+		policy:        policy,
+		lockout:       lockout,
+		keys:          keys,
 		tokenDuration: tokenDuration,
 	}
 }
 
 func (s *service) CreateAdmin(ctx context.Context, password string) error {
+	ctx, span := tracer.Start(ctx, "auth.CreateAdmin")
+	defer span.End()
+
 	exists := s.repo.ExistsUsername(ctx, domain.AdminUsername)
 	if exists {
 		return nil
 	}
 
+	if password == "" {
+		return domain.ErrAdminPasswordRequired
+	}
+
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return fmt.Errorf("error al hashear contraseña de administrador: %w", err)
 	}
 
 	admin := &domain.User{
-		ID:        ulid.GenerateNewULID(),
-		Username:  domain.AdminUsername,
-		Password:  string(hashedPassword),
-		Role:      domain.RoleAdmin,
-		IsActive:  true,
-		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		ID:                 ulid.GenerateNewULID(),
+		Username:           domain.AdminUsername,
+		Password:           string(hashedPassword),
+		Role:               domain.RoleAdmin,
+		IsActive:           true,
+		MustChangePassword: s.policy.Validate(password) != nil,
+		CreatedAt:          time.Now().UTC().Truncate(time.Second),
 	}
 
 	if err := s.repo.Create(ctx, admin); err != nil {
@@ -62,84 +104,404 @@ func (s *service) CreateAdmin(ctx context.Context, password string) error {
 }
 
 func (s *service) Login(ctx context.Context, req LoginInput) (*LoginOutput, error) {
+	ctx, span := tracer.Start(ctx, "auth.Login")
+	defer span.End()
+
+	now := time.Now().UTC()
+
+	// Espera exponencial por IP, sin importar el usuario utilizado.
+	if err := s.checkIPThrottle(ctx, req.IP, now); err != nil {
+		var throttled *ThrottledError
+		if errors.As(err, &throttled) {
+			return nil, s.recordFailure(ctx, req, domain.LoginReasonThrottled, err)
+		}
+
+		return nil, err
+	}
+
 	user, err := s.repo.FindByUsername(ctx, req.Username)
 	if err != nil {
 		if errors.Is(err, domain.ErrUserNotFound) {
-			return nil, domain.ErrInvalidCredentials
+			return nil, s.recordFailure(ctx, req, domain.LoginReasonUnknownUser, domain.ErrInvalidCredentials)
 		}
 
 		return nil, fmt.Errorf("error del repositorio al buscar usuario: %w", err)
 	}
 
-	if !user.IsActive {
-		return nil, domain.ErrUserInactive
+	if err := s.checkBackoff(ctx, req, user, now); err != nil {
+		return nil, err
 	}
 
 	if err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
 		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-			return nil, domain.ErrInvalidCredentials
+			return nil, s.registerFailure(ctx, req, user, now, domain.LoginReasonInvalidPassword, domain.ErrInvalidCredentials)
 		}
 
 		return nil, fmt.Errorf("error al comparar hash: %w", err)
 	}
 
-	tokenStr, expirationTime, err := s.generateToken(user.ID, user.Role)
+	// El estado de la cuenta se revela solo a quien conoce la contraseña.
+	if err := s.checkState(ctx, req, user); err != nil {
+		return nil, err
+	}
+
+	mfaEnabled, err := s.mfa.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error al verificar autenticación de dos factores: %w", err)
+	}
+
+	// La contraseña es correcta, pero el acceso se concede hasta verificar el segundo factor.
+	if mfaEnabled {
+		tokenStr, expirationTime, err := s.signToken(&Claims{UserID: user.ID, TokenUse: tokenUseMFA}, user.ID, mfaTokenDuration)
+		if err != nil {
+			return nil, fmt.Errorf("error al generar token de dos factores: %w", err)
+		}
+
+		return &LoginOutput{
+			Role:        user.Role,
+			MFARequired: true,
+			MFAToken:    tokenStr,
+			ExpiresIn:   int64(time.Until(expirationTime).Seconds()),
+		}, nil
+	}
+
+	return s.completeLogin(ctx, req, user, s.mfa.IsRequired(user.Role))
+}
+
+func (s *service) LoginMFA(ctx context.Context, req LoginMFAInput) (*LoginOutput, error) {
+	ctx, span := tracer.Start(ctx, "auth.LoginMFA")
+	defer span.End()
+
+	now := time.Now().UTC()
+
+	claims, err := s.parseToken(req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.TokenUse != tokenUseMFA {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.repo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, ErrInvalidToken
+		}
+
+		return nil, fmt.Errorf("error del repositorio al buscar usuario: %w", err)
+	}
+
+	login := LoginInput{Username: user.Username, IP: req.IP}
+
+	if err := s.checkIPThrottle(ctx, req.IP, now); err != nil {
+		var throttled *ThrottledError
+		if errors.As(err, &throttled) {
+			return nil, s.recordFailure(ctx, login, domain.LoginReasonThrottled, err)
+		}
+
+		return nil, err
+	}
+
+	if err := s.checkBackoff(ctx, login, user, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkState(ctx, login, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.mfa.Verify(ctx, user.ID, req.Code); err != nil {
+		if errors.Is(err, domain.ErrInvalidMFACode) {
+			return nil, s.registerFailure(ctx, login, user, now, domain.LoginReasonInvalidMFACode, domain.ErrInvalidMFACode)
+		}
+
+		return nil, fmt.Errorf("error al verificar código de dos factores: %w", err)
+	}
+
+	return s.completeLogin(ctx, login, user, false)
+}
+
+func (s *service) IssueToken(ctx context.Context, user *domain.User, ip string) (*LoginOutput, error) {
+	ctx, span := tracer.Start(ctx, "auth.IssueToken")
+	defer span.End()
+
+	req := LoginInput{Username: user.Username, IP: ip}
+
+	// El proveedor de identidad es responsable del segundo factor.
+	return s.completeLogin(ctx, req, user, false)
+}
+
+func (s *service) Reissue(ctx context.Context, userID string) (*LoginOutput, error) {
+	ctx, span := tracer.Start(ctx, "auth.Reissue")
+	defer span.End()
+
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.LockedAt != nil {
+		return nil, domain.ErrUserLocked
+	}
+
+	if !user.IsActive {
+		return nil, domain.ErrUserInactive
+	}
+
+	mfaEnrollmentRequired := false
+	if s.mfa.IsRequired(user.Role) {
+		enabled, err := s.mfa.IsEnabled(ctx, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error al verificar autenticación de dos factores: %w", err)
+		}
+
+		mfaEnrollmentRequired = !enabled
+	}
+
+	return s.issueToken(user, mfaEnrollmentRequired)
+}
+
+func (s *service) ListLoginAttempts(ctx context.Context, username string, limit int) ([]domain.LoginAttempt, error) {
+	ctx, span := tracer.Start(ctx, "auth.ListLoginAttempts")
+	defer span.End()
+
+	return s.attempts.List(ctx, username, limit)
+}
+
+// checkBackoff rechaza a los usuarios en espera exponencial tras un fallo reciente. Se verifica
+// antes de la contraseña para que la espera limite los intentos de fuerza bruta.
+func (s *service) checkBackoff(ctx context.Context, req LoginInput, user *domain.User, now time.Time) error {
+	if user.LastFailedLoginAt != nil {
+		retryAt := user.LastFailedLoginAt.Add(s.lockout.Backoff(user.FailedLoginAttempts))
+		if now.Before(retryAt) {
+			return s.recordFailure(ctx, req, domain.LoginReasonThrottled, &ThrottledError{RetryAfter: retryAt.Sub(now)})
+		}
+	}
+
+	return nil
+}
+
+// checkState rechaza a los usuarios bloqueados o inactivos.
+func (s *service) checkState(ctx context.Context, req LoginInput, user *domain.User) error {
+	if user.LockedAt != nil {
+		return s.recordFailure(ctx, req, domain.LoginReasonLocked, domain.ErrUserLocked)
+	}
+
+	if !user.IsActive {
+		return s.recordFailure(ctx, req, domain.LoginReasonInactive, domain.ErrUserInactive)
+	}
+
+	return nil
+}
+
+// completeLogin reinicia los fallos consecutivos, registra el acceso y emite el token.
+func (s *service) completeLogin(ctx context.Context, req LoginInput, user *domain.User, mfaEnrollmentRequired bool) (*LoginOutput, error) {
+	if user.FailedLoginAttempts > 0 {
+		user.FailedLoginAttempts = 0
+		user.LastFailedLoginAt = nil
+
+		if err := s.repo.UpdateLoginState(ctx, user); err != nil {
+			return nil, fmt.Errorf("error al reiniciar intentos fallidos: %w", err)
+		}
+	}
+
+	if err := s.recordAttempt(ctx, req, true, domain.LoginReasonSuccess); err != nil {
+		return nil, err
+	}
+
+	return s.issueToken(user, mfaEnrollmentRequired)
+}
+
+// issueToken firma el token de acceso con el estado del usuario.
+func (s *service) issueToken(user *domain.User, mfaEnrollmentRequired bool) (*LoginOutput, error) {
+	claims := &Claims{
+		UserID:                user.ID,
+		Role:                  user.Role,
+		MustChangePassword:    user.MustChangePassword,
+		MFAEnrollmentRequired: mfaEnrollmentRequired,
+		TokenVersion:          user.TokenVersion,
+	}
+
+	tokenStr, expirationTime, err := s.signToken(claims, user.ID, s.tokenDuration)
 	if err != nil {
 		return nil, fmt.Errorf("error al generar token: %w", err)
 	}
 
 	response := &LoginOutput{
-		Role:      user.Role,
-		Token:     tokenStr,
-		TokenType: "Bearer",
-		ExpiresIn: int64(time.Until(expirationTime).Seconds()),
+		Role:                  user.Role,
+		Token:                 tokenStr,
+		TokenType:             "Bearer",
+		ExpiresIn:             int64(time.Until(expirationTime).Seconds()),
+		MustChangePassword:    user.MustChangePassword,
+		MFAEnrollmentRequired: mfaEnrollmentRequired,
 	}
 
 	return response, nil
 }
 
-func (s *service) ParseToken(tokenStr string) (userID string, role domain.Role, err error) {
-	claims := &Claims{}
+func (s *service) checkIPThrottle(ctx context.Context, ip string, now time.Time) error {
+	if s.lockout.IPMaxFailures <= 0 {
+		return nil
+	}
+
+	failures, last, err := s.attempts.FailuresByIP(ctx, ip, now.Add(-s.lockout.Window))
+	if err != nil {
+		return fmt.Errorf("error al verificar intentos fallidos por IP: %w", err)
+	}
+
+	if last == nil || failures < s.lockout.IPMaxFailures {
+		return nil
+	}
+
+	retryAt := last.Add(s.lockout.Backoff(failures - s.lockout.IPMaxFailures + 1))
+	if now.Before(retryAt) {
+		return &ThrottledError{RetryAfter: retryAt.Sub(now)}
+	}
+
+	return nil
+}
+
+// registerFailure incrementa los fallos consecutivos del usuario (contraseña o código de
+// verificación incorrectos) y bloquea la cuenta al alcanzar el máximo permitido. El cliente
+// recibe result aunque la cuenta se bloquee, para no revelar su estado. El usuario 'admin' no se
+// bloquea, ya que cualquiera podría dejarlo sin acceso; queda protegido por la espera exponencial.
+func (s *service) registerFailure(ctx context.Context, req LoginInput, user *domain.User, now time.Time, reason string, result error) error {
+	maxFailures := s.lockout.MaxFailures
+	if user.Username == domain.AdminUsername {
+		maxFailures = 0
+	}
 
-	token, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (any, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("método de firma inesperado: %v", t.Header["alg"])
+	_, locked, err := s.repo.RegisterLoginFailure(ctx, user.ID, now.Truncate(time.Second), maxFailures)
+	if err != nil {
+		return fmt.Errorf("error al registrar intento fallido: %w", err)
+	}
+
+	if locked && user.LockedAt == nil {
+		reason = domain.LoginReasonLocked
+	}
+
+	return s.recordFailure(ctx, req, reason, result)
+}
+
+// recordFailure registra el intento fallido y retorna el error que debe recibir el cliente.
+func (s *service) recordFailure(ctx context.Context, req LoginInput, reason string, result error) error {
+	if err := s.recordAttempt(ctx, req, false, reason); err != nil {
+		return err
+	}
+
+	return result
+}
+
+func (s *service) recordAttempt(ctx context.Context, req LoginInput, success bool, reason string) error {
+	attempt := &domain.LoginAttempt{
+		ID:        ulid.GenerateNewULID(),
+		Username:  strings.ToLower(req.Username),
+		IP:        req.IP,
+		Success:   success,
+		Reason:    reason,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+
+	if err := s.attempts.Create(ctx, attempt); err != nil {
+		return fmt.Errorf("error al registrar intento de inicio de sesión: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) ParseToken(tokenStr string) (*Claims, error) {
+	claims, err := s.parseToken(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Los tokens del segundo factor no autorizan el acceso a la API.
+	if claims.TokenUse != "" {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+func (s *service) Authenticate(ctx context.Context, tokenStr string) (*Claims, error) {
+	ctx, span := tracer.Start(ctx, "auth.Authenticate")
+	defer span.End()
+
+	claims, err := s.ParseToken(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, ErrInvalidToken
 		}
 
-		return s.secretKey, nil
-	}, jwt.WithLeeway(5*time.Second))
+		return nil, fmt.Errorf("error del repositorio al buscar usuario: %w", err)
+	}
+
+	if claims.TokenVersion != user.TokenVersion || user.DeletedAt != nil {
+		return nil, ErrInvalidToken
+	}
+
+	// Desactivar o bloquear la cuenta corta el acceso de inmediato, sin esperar a que el token
+	// expire.
+	if user.LockedAt != nil {
+		return nil, domain.ErrUserLocked
+	}
+
+	if !user.IsActive {
+		return nil, domain.ErrUserInactive
+	}
+
+	// El rol y la obligación de cambiar la contraseña pueden haber cambiado desde la emisión.
+	claims.Role = user.Role
+	claims.MustChangePassword = user.MustChangePassword
+
+	return claims, nil
+}
+
+func (s *service) JWKS() JSONWebKeySet {
+	return s.keys.JWKS()
+}
+
+func (s *service) parseToken(tokenStr string) (*Claims, error) {
+	claims := &Claims{}
+
+	token, err := jwt.ParseWithClaims(
+		tokenStr,
+		claims,
+		s.keys.Keyfunc,
+		jwt.WithValidMethods(s.keys.Methods()),
+		jwt.WithLeeway(5*time.Second))
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
-			return "", "", ErrExpiredToken
+			return nil, ErrExpiredToken
 		}
 
-		return "", "", ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
 
 	if !token.Valid {
-		return "", "", ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
 
-	return claims.UserID, claims.Role, nil
+	return claims, nil
 }
 
-func (s *service) generateToken(userID, role string) (string, time.Time, error) {
-	expirationTime := time.Now().Add(s.tokenDuration)
+func (s *service) signToken(claims *Claims, subject string, duration time.Duration) (string, time.Time, error) {
+	expirationTime := time.Now().Add(duration)
 
-	claims := &Claims{
-		UserID: userID,
-		Role:   role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Subject:   userID,
-		},
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(expirationTime),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		Subject:   subject,
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenStr, err := token.SignedString(s.secretKey)
-
+	tokenStr, err := s.keys.Sign(claims)
 	if err != nil {
 		return "", time.Time{}, err
 	}
@@ -0,0 +1,371 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/JGCaceres97/parking/internal/application/mfa"
+	"github.com/JGCaceres97/parking/internal/application/user"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// fakeUserRepo guarda un único usuario en memoria. Los métodos no utilizados por el servicio de
+// autenticación provienen de la interfaz embebida y no deben invocarse.
+type fakeUserRepo struct {
+	user.Repository
+	user *domain.User
+}
+
+func (r *fakeUserRepo) FindByUsername(_ context.Context, username string) (*domain.User, error) {
+	if r.user == nil || r.user.Username != username {
+		return nil, domain.ErrUserNotFound
+	}
+
+	copied := *r.user
+	return &copied, nil
+}
+
+func (r *fakeUserRepo) FindByID(_ context.Context, id string) (*domain.User, error) {
+	if r.user == nil || r.user.ID != id {
+		return nil, domain.ErrUserNotFound
+	}
+
+	copied := *r.user
+	return &copied, nil
+}
+
+func (r *fakeUserRepo) UpdateLoginState(_ context.Context, u *domain.User) error {
+	r.user.FailedLoginAttempts = u.FailedLoginAttempts
+	r.user.LastFailedLoginAt = u.LastFailedLoginAt
+	r.user.LockedAt = u.LockedAt
+	return nil
+}
+
+func (r *fakeUserRepo) RegisterLoginFailure(_ context.Context, _ string, at time.Time, maxFailures int) (int, bool, error) {
+	r.user.FailedLoginAttempts++
+	r.user.LastFailedLoginAt = &at
+
+	if r.user.LockedAt == nil && maxFailures > 0 && r.user.FailedLoginAttempts >= maxFailures {
+		r.user.LockedAt = &at
+	}
+
+	return r.user.FailedLoginAttempts, r.user.LockedAt != nil, nil
+}
+
+type fakeAttemptRepo struct {
+	attempts []domain.LoginAttempt
+}
+
+func (r *fakeAttemptRepo) Create(_ context.Context, attempt *domain.LoginAttempt) error {
+	r.attempts = append(r.attempts, *attempt)
+	return nil
+}
+
+func (r *fakeAttemptRepo) FailuresByIP(context.Context, string, time.Time) (int, *time.Time, error) {
+	return 0, nil, nil
+}
+
+func (r *fakeAttemptRepo) List(context.Context, string, int) ([]domain.LoginAttempt, error) {
+	return r.attempts, nil
+}
+
+func (r *fakeAttemptRepo) lastReason() string {
+	if len(r.attempts) == 0 {
+		return ""
+	}
+
+	return r.attempts[len(r.attempts)-1].Reason
+}
+
+// fakeMFA acepta únicamente el código indicado.
+type fakeMFA struct {
+	mfa.Service
+	enabled  bool
+	required bool
+	code     string
+}
+
+func (m *fakeMFA) IsEnabled(context.Context, string) (bool, error) {
+	return m.enabled, nil
+}
+
+func (m *fakeMFA) IsRequired(domain.Role) bool {
+	return m.required
+}
+
+func (m *fakeMFA) Verify(_ context.Context, _ string, code string) error {
+	if code != m.code {
+		return domain.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+const testPassword = "Correcta123"
+
+func newTestService(t *testing.T, u *domain.User, mfaSvc *fakeMFA) (*service, *fakeUserRepo, *fakeAttemptRepo) {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u.Password = string(hash)
+
+	repo := &fakeUserRepo{user: u}
+	attempts := &fakeAttemptRepo{}
+	lockout := domain.LockoutPolicy{MaxFailures: 3}
+
+	svc := NewService(repo, attempts, mfaSvc, domain.PasswordPolicy{}, lockout, NewHMACKeySet("secreto"), time.Hour)
+
+	return svc.(*service), repo, attempts
+}
+
+func TestLoginChecksPasswordBeforeState(t *testing.T) {
+	locked := time.Now().UTC()
+
+	tests := []struct {
+		name     string
+		user     domain.User
+		password string
+		want     error
+		reason   string
+	}{
+		{
+			name:     "bloqueado con contraseña incorrecta",
+			user:     domain.User{ID: "1", Username: "cajero", IsActive: true, LockedAt: &locked},
+			password: "incorrecta",
+			want:     domain.ErrInvalidCredentials,
+			reason:   domain.LoginReasonInvalidPassword,
+		},
+		{
+			name:     "bloqueado con contraseña correcta",
+			user:     domain.User{ID: "1", Username: "cajero", IsActive: true, LockedAt: &locked},
+			password: testPassword,
+			want:     domain.ErrUserLocked,
+			reason:   domain.LoginReasonLocked,
+		},
+		{
+			name:     "inactivo con contraseña incorrecta",
+			user:     domain.User{ID: "1", Username: "cajero"},
+			password: "incorrecta",
+			want:     domain.ErrInvalidCredentials,
+			reason:   domain.LoginReasonInvalidPassword,
+		},
+		{
+			name:     "inactivo con contraseña correcta",
+			user:     domain.User{ID: "1", Username: "cajero"},
+			password: testPassword,
+			want:     domain.ErrUserInactive,
+			reason:   domain.LoginReasonInactive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, attempts := newTestService(t, &tt.user, &fakeMFA{})
+
+			_, err := svc.Login(context.Background(), LoginInput{Username: "cajero", Password: tt.password, IP: "10.0.0.1"})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, se esperaba %v", err, tt.want)
+			}
+
+			if got := attempts.lastReason(); got != tt.reason {
+				t.Errorf("motivo = %q, se esperaba %q", got, tt.reason)
+			}
+		})
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	tests := []struct {
+		name       string
+		username   string
+		wantLocked bool
+	}{
+		{name: "usuario común", username: "cajero", wantLocked: true},
+		{name: "administrador", username: domain.AdminUsername, wantLocked: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, attempts := newTestService(t, &domain.User{ID: "1", Username: tt.username, IsActive: true}, &fakeMFA{})
+
+			for range 3 {
+				// Se omite la espera exponencial entre intentos.
+				repo.user.LastFailedLoginAt = nil
+
+				_, err := svc.Login(context.Background(), LoginInput{Username: tt.username, Password: "incorrecta"})
+				if !errors.Is(err, domain.ErrInvalidCredentials) {
+					t.Fatalf("error = %v, se esperaba %v", err, domain.ErrInvalidCredentials)
+				}
+			}
+
+			if locked := repo.user.LockedAt != nil; locked != tt.wantLocked {
+				t.Fatalf("bloqueado = %v, se esperaba %v", locked, tt.wantLocked)
+			}
+
+			wantReason := domain.LoginReasonInvalidPassword
+			if tt.wantLocked {
+				wantReason = domain.LoginReasonLocked
+			}
+
+			if got := attempts.lastReason(); got != wantReason {
+				t.Errorf("motivo = %q, se esperaba %q", got, wantReason)
+			}
+		})
+	}
+}
+
+func TestAuthenticateUsesCurrentUserState(t *testing.T) {
+	svc, repo, _ := newTestService(t, &domain.User{ID: "1", Username: "cajero", Role: domain.RoleCashier, IsActive: true, MustChangePassword: true}, &fakeMFA{})
+
+	out, err := svc.Login(context.Background(), LoginInput{Username: "cajero", Password: testPassword})
+	if err != nil {
+		t.Fatalf("Login() = %v", err)
+	}
+
+	// Tras cambiar la contraseña, el mismo token deja de exigir el cambio.
+	repo.user.MustChangePassword = false
+	repo.user.Role = domain.RoleSupervisor
+
+	claims, err := svc.Authenticate(context.Background(), out.Token)
+	if err != nil {
+		t.Fatalf("Authenticate() = %v", err)
+	}
+
+	if claims.MustChangePassword || claims.Role != domain.RoleSupervisor {
+		t.Errorf("claims = %+v, se esperaba el estado actual del usuario", claims)
+	}
+
+	// Al restablecer la contraseña se revocan los tokens emitidos.
+	repo.user.TokenVersion++
+
+	if _, err := svc.Authenticate(context.Background(), out.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Authenticate() con token revocado = %v, se esperaba %v", err, ErrInvalidToken)
+	}
+
+	repo.user = nil
+
+	if _, err := svc.Authenticate(context.Background(), out.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Authenticate() de usuario eliminado = %v, se esperaba %v", err, ErrInvalidToken)
+	}
+}
+
+func TestAuthenticateRejectsDisabledAccounts(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		disable func(u *domain.User)
+		wantErr error
+	}{
+		{"Usuario desactivado", func(u *domain.User) { u.IsActive = false }, domain.ErrUserInactive},
+		{"Cuenta bloqueada", func(u *domain.User) { u.LockedAt = &now }, domain.ErrUserLocked},
+		{"Usuario eliminado", func(u *domain.User) { u.DeletedAt, u.IsActive = &now, false }, ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, _ := newTestService(t, &domain.User{ID: "1", Username: "cajero", Role: domain.RoleCashier, IsActive: true}, &fakeMFA{})
+
+			out, err := svc.Login(context.Background(), LoginInput{Username: "cajero", Password: testPassword})
+			if err != nil {
+				t.Fatalf("Login() = %v", err)
+			}
+
+			tt.disable(repo.user)
+
+			if _, err := svc.Authenticate(context.Background(), out.Token); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Authenticate() = %v, se esperaba %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoginMFA(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, attempts := newTestService(t, &domain.User{ID: "1", Username: "cajero", Role: domain.RoleCashier, IsActive: true}, &fakeMFA{enabled: true, code: "123456"})
+
+	out, err := svc.Login(ctx, LoginInput{Username: "cajero", Password: testPassword})
+	if err != nil {
+		t.Fatalf("Login() = %v", err)
+	}
+
+	if !out.MFARequired || out.MFAToken == "" || out.Token != "" {
+		t.Fatalf("Login() = %+v, se esperaba solo el token del segundo factor", out)
+	}
+
+	// El token del segundo factor no autoriza el acceso a la API.
+	if _, err := svc.Authenticate(ctx, out.MFAToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Authenticate() con el token del segundo factor = %v, se esperaba %v", err, ErrInvalidToken)
+	}
+
+	if _, err := svc.LoginMFA(ctx, LoginMFAInput{MFAToken: out.MFAToken, Code: "000000"}); !errors.Is(err, domain.ErrInvalidMFACode) {
+		t.Fatalf("LoginMFA() con código incorrecto = %v, se esperaba %v", err, domain.ErrInvalidMFACode)
+	}
+
+	if repo.user.FailedLoginAttempts != 1 || attempts.lastReason() != domain.LoginReasonInvalidMFACode {
+		t.Errorf("fallos = %d, motivo = %q, se esperaba un fallo por código incorrecto", repo.user.FailedLoginAttempts, attempts.lastReason())
+	}
+
+	// Se omite la espera exponencial tras el fallo.
+	repo.user.LastFailedLoginAt = nil
+
+	completed, err := svc.LoginMFA(ctx, LoginMFAInput{MFAToken: out.MFAToken, Code: "123456"})
+	if err != nil {
+		t.Fatalf("LoginMFA() = %v", err)
+	}
+
+	if completed.Token == "" || repo.user.FailedLoginAttempts != 0 {
+		t.Errorf("LoginMFA() = %+v con %d fallos, se esperaba el token de acceso y los fallos reiniciados", completed, repo.user.FailedLoginAttempts)
+	}
+
+	if _, err := svc.Authenticate(ctx, completed.Token); err != nil {
+		t.Errorf("Authenticate() = %v", err)
+	}
+
+	// Un token de acceso no reemplaza al del segundo factor.
+	if _, err := svc.LoginMFA(ctx, LoginMFAInput{MFAToken: completed.Token, Code: "123456"}); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("LoginMFA() con un token de acceso = %v, se esperaba %v", err, ErrInvalidToken)
+	}
+}
+
+func TestReissueAfterMFAEnrollment(t *testing.T) {
+	ctx := context.Background()
+	mfaSvc := &fakeMFA{required: true}
+	svc, _, attempts := newTestService(t, &domain.User{ID: "1", Username: "cajero", Role: domain.RoleCashier, IsActive: true}, mfaSvc)
+
+	out, err := svc.Login(ctx, LoginInput{Username: "cajero", Password: testPassword})
+	if err != nil {
+		t.Fatalf("Login() = %v", err)
+	}
+
+	claims, err := svc.Authenticate(ctx, out.Token)
+	if err != nil || !claims.MFAEnrollmentRequired {
+		t.Fatalf("Authenticate() = %+v, %v, se esperaba exigir la configuración del segundo factor", claims, err)
+	}
+
+	// Tras confirmar el segundo factor, el nuevo token ya no lo exige.
+	mfaSvc.enabled = true
+	logins := len(attempts.attempts)
+
+	reissued, err := svc.Reissue(ctx, "1")
+	if err != nil {
+		t.Fatalf("Reissue() = %v", err)
+	}
+
+	claims, err = svc.Authenticate(ctx, reissued.Token)
+	if err != nil || claims.MFAEnrollmentRequired || reissued.MFAEnrollmentRequired {
+		t.Errorf("Authenticate() = %+v, %v, no se esperaba exigir el segundo factor", claims, err)
+	}
+
+	if len(attempts.attempts) != logins {
+		t.Errorf("Reissue() registró %d inicios de sesión, no se esperaba ninguno", len(attempts.attempts)-logins)
+	}
+}
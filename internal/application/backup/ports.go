@@ -0,0 +1,41 @@
+package backup
+
+import (
+	"context"
+	"io"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type Service interface {
+	// Create escribe una copia consistente de la DB sin detener el servidor y elimina las copias
+	// que excedan la cantidad que conserva la política.
+	Create(ctx context.Context) (*domain.Backup, error)
+
+	// List lista las copias de seguridad, de la más reciente a la más antigua.
+	List(ctx context.Context) ([]domain.Backup, error)
+
+	// Open abre una copia de seguridad para descargarla. El llamador debe cerrar el lector.
+	Open(ctx context.Context, name string) (io.ReadCloser, *domain.Backup, error)
+
+	// Start crea copias periódicamente hasta que se cancele el contexto. Si la última copia es
+	// más antigua que el intervalo, crea una al iniciar.
+	Start(ctx context.Context)
+}
+
+// Storage escribe y administra las copias de seguridad de la DB. Solo existe para los drivers
+// que permiten copias en caliente.
+type Storage interface {
+	// Snapshot escribe una copia consistente de la DB con el nombre indicado. Retorna
+	// ErrBackupExists si ya existe.
+	Snapshot(ctx context.Context, name string) (*domain.Backup, error)
+
+	// List lista las copias guardadas, en cualquier orden.
+	List(ctx context.Context) ([]domain.Backup, error)
+
+	// Open abre una copia guardada. Retorna ErrBackupNotFound si no existe.
+	Open(ctx context.Context, name string) (io.ReadCloser, *domain.Backup, error)
+
+	// Delete elimina una copia guardada.
+	Delete(ctx context.Context, name string) error
+}
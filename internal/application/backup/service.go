@@ -0,0 +1,153 @@
+package backup
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/JGCaceres97/parking/internal/application/audit"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+var tracer = otel.Tracer("github.com/JGCaceres97/parking/internal/application/backup")
+
+type service struct {
+	storage Storage
+	audit   audit.Recorder
+	policy  domain.BackupPolicy
+	now     func() time.Time
+}
+
+// NewService crea el servicio de copias de seguridad. storage es nil si el driver de DB no
+// permite copias en caliente; en ese caso las operaciones retornan ErrBackupNotSupported.
+func NewService(storage Storage, audit audit.Recorder, policy domain.BackupPolicy) Service {
+	return &service{
+		storage: storage,
+		audit:   audit,
+		policy:  policy,
+		now:     time.Now,
+	}
+}
+
+func (s *service) Create(ctx context.Context) (*domain.Backup, error) {
+	ctx, span := tracer.Start(ctx, "backup.Create")
+	defer span.End()
+
+	if s.storage == nil {
+		return nil, domain.ErrBackupNotSupported
+	}
+
+	backup, err := s.storage.Snapshot(ctx, domain.BackupName(s.now()))
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditBackupCreate, domain.AuditEntityBackup, backup.Name, nil, backup)
+
+	// La copia nueva ya está guardada: un error al rotar no la invalida.
+	if err := s.rotate(ctx); err != nil {
+		slog.WarnContext(ctx, "error al eliminar copias de seguridad antiguas", "error", err)
+	}
+
+	return backup, nil
+}
+
+func (s *service) List(ctx context.Context) ([]domain.Backup, error) {
+	ctx, span := tracer.Start(ctx, "backup.List")
+	defer span.End()
+
+	if s.storage == nil {
+		return nil, domain.ErrBackupNotSupported
+	}
+
+	backups, err := s.storage.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Name > backups[j].Name
+	})
+
+	return backups, nil
+}
+
+func (s *service) Open(ctx context.Context, name string) (io.ReadCloser, *domain.Backup, error) {
+	ctx, span := tracer.Start(ctx, "backup.Open")
+	defer span.End()
+
+	if s.storage == nil {
+		return nil, nil, domain.ErrBackupNotSupported
+	}
+
+	if !domain.BackupNamePattern.MatchString(name) {
+		return nil, nil, domain.ErrBackupNotFound
+	}
+
+	return s.storage.Open(ctx, name)
+}
+
+// rotate elimina las copias más antiguas que excedan policy.Keep.
+func (s *service) rotate(ctx context.Context) error {
+	if s.policy.Keep <= 0 {
+		return nil
+	}
+
+	backups, err := s.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, backup := range backups[min(s.policy.Keep, len(backups)):] {
+		if err := s.storage.Delete(ctx, backup.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *service) Start(ctx context.Context) {
+	if s.storage == nil || !s.policy.Scheduled() {
+		return
+	}
+
+	go func() {
+		// La primera copia se crea cuando vence el intervalo contado desde la última, de modo que
+		// reiniciar el servidor no la adelante ni la posponga indefinidamente.
+		wait := s.policy.Interval
+		if backups, err := s.List(ctx); err == nil {
+			wait = 0
+			if len(backups) > 0 {
+				wait = max(backups[0].CreatedAt.Add(s.policy.Interval).Sub(s.now()), 0)
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			if backup, err := s.Create(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+
+				slog.WarnContext(ctx, "error al crear copia de seguridad programada", "error", err)
+			} else {
+				slog.InfoContext(ctx, "copia de seguridad creada", "name", backup.Name, "bytes", backup.Size)
+			}
+
+			timer.Reset(s.policy.Interval)
+		}
+	}()
+}
@@ -0,0 +1,100 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// memoryStorage guarda las copias en memoria.
+type memoryStorage struct {
+	backups map[string]domain.Backup
+}
+
+func (s *memoryStorage) Snapshot(ctx context.Context, name string) (*domain.Backup, error) {
+	if _, ok := s.backups[name]; ok {
+		return nil, domain.ErrBackupExists
+	}
+
+	backup := domain.Backup{Name: name, Size: 1}
+	s.backups[name] = backup
+
+	return &backup, nil
+}
+
+func (s *memoryStorage) List(ctx context.Context) ([]domain.Backup, error) {
+	backups := []domain.Backup{}
+	for _, backup := range s.backups {
+		backups = append(backups, backup)
+	}
+
+	return backups, nil
+}
+
+func (s *memoryStorage) Open(ctx context.Context, name string) (io.ReadCloser, *domain.Backup, error) {
+	return nil, nil, domain.ErrBackupNotFound
+}
+
+func (s *memoryStorage) Delete(ctx context.Context, name string) error {
+	delete(s.backups, name)
+	return nil
+}
+
+type noopRecorder struct{}
+
+func (noopRecorder) Record(ctx context.Context, action, entityType, entityID string, before, after any) {
+}
+
+func TestCreateRotatesOldBackups(t *testing.T) {
+	storage := &memoryStorage{backups: map[string]domain.Backup{}}
+	svc := NewService(storage, noopRecorder{}, domain.BackupPolicy{Keep: 2}).(*service)
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	for range 3 {
+		if _, err := svc.Create(context.Background()); err != nil {
+			t.Fatalf("Create() = %v", err)
+		}
+
+		now = now.Add(time.Hour)
+	}
+
+	if _, err := svc.Create(context.Background()); err != nil {
+		t.Fatalf("Create() = %v", err)
+	}
+
+	backups, err := svc.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() = %v", err)
+	}
+
+	want := []string{"parking-20260101-150000.db", "parking-20260101-140000.db"}
+	if len(backups) != len(want) || backups[0].Name != want[0] || backups[1].Name != want[1] {
+		t.Errorf("List() = %+v, se esperaban las copias %v", backups, want)
+	}
+
+	if _, err := svc.Create(context.Background()); !errors.Is(err, domain.ErrBackupExists) {
+		t.Errorf("Create() en el mismo segundo = %v, se esperaba %v", err, domain.ErrBackupExists)
+	}
+
+	if _, _, err := svc.Open(context.Background(), "../parking.db"); !errors.Is(err, domain.ErrBackupNotFound) {
+		t.Errorf("Open() fuera del directorio = %v, se esperaba %v", err, domain.ErrBackupNotFound)
+	}
+}
+
+func TestUnsupportedDriver(t *testing.T) {
+	svc := NewService(nil, noopRecorder{}, domain.BackupPolicy{Keep: 2})
+
+	if _, err := svc.Create(context.Background()); !errors.Is(err, domain.ErrBackupNotSupported) {
+		t.Errorf("Create() = %v, se esperaba %v", err, domain.ErrBackupNotSupported)
+	}
+
+	if _, _, err := svc.Open(context.Background(), "parking-20260101-120000.db"); !errors.Is(err, domain.ErrBackupNotSupported) {
+		t.Errorf("Open() = %v, se esperaba %v", err, domain.ErrBackupNotSupported)
+	}
+}
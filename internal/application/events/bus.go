@@ -0,0 +1,122 @@
+package events
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/ulid"
+)
+
+type subscription struct {
+	bus    *memoryBus
+	ch     chan domain.Event
+	filter func(domain.Event) bool
+	once   sync.Once
+}
+
+func (s *subscription) Events() <-chan domain.Event {
+	return s.ch
+}
+
+func (s *subscription) Close() {
+	s.bus.remove(s)
+}
+
+// memoryBus distribuye los eventos en memoria. Es suficiente para una sola instancia; con varias
+// réplicas cada una publica solo los eventos que ella misma genera.
+type memoryBus struct {
+	buffer int
+
+	mu          sync.RWMutex
+	subscribers map[*subscription]struct{}
+	closed      bool
+}
+
+// NewMemoryBus crea un bus en memoria. buffer es la cantidad de eventos pendientes que se
+// conservan por suscriptor antes de desconectarlo.
+func NewMemoryBus(buffer int) Bus {
+	return &memoryBus{
+		buffer:      buffer,
+		subscribers: make(map[*subscription]struct{}),
+	}
+}
+
+func (b *memoryBus) Publish(eventType domain.EventType, data any) {
+	event := domain.Event{
+		ID:         ulid.GenerateNewULID(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+
+	var lagging []*subscription
+
+	b.mu.RLock()
+	for sub := range b.subscribers {
+		if sub.filter != nil && !sub.filter(event) {
+			continue
+		}
+
+		// Nunca se bloquea al publicador: un suscriptor con el buffer lleno se desconecta y
+		// debe reconectarse y volver a consultar el estado actual.
+		select {
+		case sub.ch <- event:
+		default:
+			lagging = append(lagging, sub)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range lagging {
+		slog.Warn("se desconecta un suscriptor de eventos lento")
+		b.remove(sub)
+	}
+}
+
+func (b *memoryBus) Subscribe(filter func(domain.Event) bool) Subscription {
+	sub := &subscription{
+		bus:    b,
+		ch:     make(chan domain.Event, b.buffer),
+		filter: filter,
+	}
+
+	b.mu.Lock()
+	closed := b.closed
+	if !closed {
+		b.subscribers[sub] = struct{}{}
+	}
+	b.mu.Unlock()
+
+	if closed {
+		sub.once.Do(func() { close(sub.ch) })
+	}
+
+	return sub
+}
+
+func (b *memoryBus) Close() {
+	b.mu.Lock()
+	b.closed = true
+
+	subscribers := make([]*subscription, 0, len(b.subscribers))
+	for sub := range b.subscribers {
+		subscribers = append(subscribers, sub)
+	}
+	b.mu.Unlock()
+
+	for _, sub := range subscribers {
+		b.remove(sub)
+	}
+}
+
+func (b *memoryBus) remove(sub *subscription) {
+	sub.once.Do(func() {
+		b.mu.Lock()
+		delete(b.subscribers, sub)
+		b.mu.Unlock()
+
+		close(sub.ch)
+	})
+}
@@ -0,0 +1,62 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+func TestMemoryBus(t *testing.T) {
+	bus := NewMemoryBus(2)
+
+	all := bus.Subscribe(nil)
+	defer all.Close()
+
+	usersOnly := bus.Subscribe(func(e domain.Event) bool { return e.Type == domain.EventUserDeactivated })
+	defer usersOnly.Close()
+
+	slow := bus.Subscribe(nil)
+
+	bus.Publish(domain.EventVehicleEntered, nil)
+	bus.Publish(domain.EventUserDeactivated, domain.UserDeactivatedData{UserID: "01A"})
+
+	tests := []struct {
+		name  string
+		sub   Subscription
+		types []domain.EventType
+	}{
+		{"Sin filtro recibe todos", all, []domain.EventType{domain.EventVehicleEntered, domain.EventUserDeactivated}},
+		{"Con filtro recibe solo los aceptados", usersOnly, []domain.EventType{domain.EventUserDeactivated}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, want := range tt.types {
+				if got := <-tt.sub.Events(); got.Type != want {
+					t.Errorf("evento = %s, se esperaba %s", got.Type, want)
+				}
+			}
+
+			select {
+			case e := <-tt.sub.Events():
+				t.Errorf("evento inesperado: %s", e.Type)
+			default:
+			}
+		})
+	}
+
+	t.Run("Suscriptor lento se desconecta sin bloquear", func(t *testing.T) {
+		bus.Publish(domain.EventVehicleExited, nil)
+
+		received := 0
+		for range slow.Events() {
+			received++
+		}
+
+		if received != 2 {
+			t.Errorf("se recibieron %d eventos antes de la desconexión, se esperaban 2", received)
+		}
+
+		slow.Close()
+	})
+}
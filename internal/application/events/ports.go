@@ -0,0 +1,40 @@
+package events
+
+import (
+	"context"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// Publisher publica eventos de dominio. Lo utilizan los demás servicios de aplicación.
+type Publisher interface {
+	// Publish entrega el evento a los suscriptores sin bloquear al llamador.
+	Publish(eventType domain.EventType, data any)
+}
+
+type Service interface {
+	// Subscribe suscribe a un usuario a los eventos de los tipos indicados (todos si está vacío)
+	// que su rol le permite ver. Retorna domain.ErrInvalidEventType si algún tipo no existe. El
+	// usuario recibe siempre su propia desactivación para poder cerrar la conexión.
+	Subscribe(ctx context.Context, userID string, role domain.Role, types []domain.EventType) (Subscription, error)
+}
+
+type Bus interface {
+	Publisher
+
+	// Subscribe registra un suscriptor que recibe los eventos aceptados por filter. El canal se
+	// cierra al llamar Close o si el suscriptor no consume los eventos a tiempo.
+	Subscribe(filter func(domain.Event) bool) Subscription
+
+	// Close desconecta a todos los suscriptores. Se usa al apagar el servidor para terminar las
+	// conexiones abiertas.
+	Close()
+}
+
+type Subscription interface {
+	// Events retorna el canal por el que se reciben los eventos.
+	Events() <-chan domain.Event
+
+	// Close cancela la suscripción. Puede llamarse más de una vez.
+	Close()
+}
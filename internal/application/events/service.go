@@ -0,0 +1,59 @@
+package events
+
+import (
+	"context"
+	"slices"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/JGCaceres97/parking/internal/application/role"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+var tracer = otel.Tracer("github.com/JGCaceres97/parking/internal/application/events")
+
+type service struct {
+	bus   Bus
+	roles role.Service
+}
+
+func NewService(bus Bus, roles role.Service) Service {
+	return &service{bus: bus, roles: roles}
+}
+
+func (s *service) Subscribe(ctx context.Context, userID string, userRole domain.Role, types []domain.EventType) (Subscription, error) {
+	ctx, span := tracer.Start(ctx, "events.Subscribe")
+	defer span.End()
+
+	for _, t := range types {
+		if !slices.Contains(domain.EventTypes(), t) {
+			return nil, domain.ErrInvalidEventType
+		}
+	}
+
+	if len(types) == 0 {
+		types = domain.EventTypes()
+	}
+
+	// Los permisos se evalúan al suscribirse; un cambio de rol aplica al reconectar.
+	allowed := map[domain.EventType]bool{}
+
+	for _, t := range types {
+		ok, err := s.roles.HasPermissions(ctx, userRole, domain.EventPermission(t))
+		if err != nil {
+			return nil, err
+		}
+
+		allowed[t] = ok
+	}
+
+	filter := func(e domain.Event) bool {
+		if data, ok := e.Data.(domain.UserDeactivatedData); ok && data.UserID == userID {
+			return true
+		}
+
+		return allowed[e.Type]
+	}
+
+	return s.bus.Subscribe(filter), nil
+}
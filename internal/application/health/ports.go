@@ -0,0 +1,26 @@
+package health
+
+import (
+	"context"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type Service interface {
+	// Ready ejecuta todas las verificaciones en paralelo, cada una con el tiempo máximo del
+	// servicio. Una verificación crítica fallida deja a la aplicación no disponible; una no
+	// crítica solo la degrada.
+	Ready(ctx context.Context) domain.Health
+
+	// SetReady marca si el servidor acepta tráfico. Al detenerse se marca como no disponible
+	// sin ejecutar las verificaciones.
+	SetReady(ready bool)
+}
+
+// Check verifica una dependencia de la aplicación (ej. la DB).
+type Check struct {
+	Name string
+	// Critical indica si la aplicación deja de estar disponible cuando la verificación falla.
+	Critical bool
+	Run      func(ctx context.Context) error
+}
@@ -0,0 +1,95 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// serverCheck es el nombre con el que se informa que el servidor se está deteniendo.
+const serverCheck = "server"
+
+type service struct {
+	checks  []Check
+	timeout time.Duration
+	ready   atomic.Bool
+}
+
+// NewService crea el servicio de verificación de estado. Inicia marcado como disponible.
+func NewService(timeout time.Duration, checks ...Check) Service {
+	s := &service{checks: checks, timeout: timeout}
+	s.ready.Store(true)
+
+	return s
+}
+
+func (s *service) Ready(ctx context.Context) domain.Health {
+	if !s.ready.Load() {
+		return domain.Health{
+			Status: domain.HealthUnavailable,
+			Checks: map[string]domain.HealthStatus{serverCheck: domain.HealthUnavailable},
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	errs := make([]error, len(s.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		wg.Go(func() {
+			errs[i] = run(ctx, check)
+		})
+	}
+
+	wg.Wait()
+
+	health := domain.Health{Status: domain.HealthOK, Checks: make(map[string]domain.HealthStatus, len(s.checks))}
+
+	for i, check := range s.checks {
+		if errs[i] == nil {
+			health.Checks[check.Name] = domain.HealthOK
+			continue
+		}
+
+		slog.WarnContext(ctx, "verificación de estado fallida", "check", check.Name, "critical", check.Critical, "error", errs[i])
+
+		if check.Critical {
+			health.Checks[check.Name] = domain.HealthUnavailable
+			health.Status = domain.HealthUnavailable
+			continue
+		}
+
+		health.Checks[check.Name] = domain.HealthDegraded
+		if health.Status == domain.HealthOK {
+			health.Status = domain.HealthDegraded
+		}
+	}
+
+	return health
+}
+
+func (s *service) SetReady(ready bool) {
+	s.ready.Store(ready)
+}
+
+// run ejecuta la verificación y la da por fallida si no termina dentro del tiempo máximo, aunque
+// la dependencia no respete el contexto.
+func run(ctx context.Context, check Check) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Run(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+func ok(ctx context.Context) error { return nil }
+
+func fail(ctx context.Context) error { return errors.New("sin conexión") }
+
+func hang(ctx context.Context) error {
+	time.Sleep(time.Second)
+	return nil
+}
+
+func TestReady(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []Check
+		want   domain.HealthStatus
+	}{
+		{
+			name:   "todas las dependencias responden",
+			checks: []Check{{Name: "database", Critical: true, Run: ok}, {Name: "outbox", Run: ok}},
+			want:   domain.HealthOK,
+		},
+		{
+			name:   "falla una dependencia no crítica",
+			checks: []Check{{Name: "database", Critical: true, Run: ok}, {Name: "outbox", Run: fail}},
+			want:   domain.HealthDegraded,
+		},
+		{
+			name:   "falla una dependencia crítica",
+			checks: []Check{{Name: "database", Critical: true, Run: fail}, {Name: "outbox", Run: fail}},
+			want:   domain.HealthUnavailable,
+		},
+		{
+			name:   "una dependencia crítica excede el tiempo máximo",
+			checks: []Check{{Name: "database", Critical: true, Run: hang}},
+			want:   domain.HealthUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health := NewService(50*time.Millisecond, tt.checks...).Ready(context.Background())
+
+			if health.Status != tt.want {
+				t.Errorf("estado = %q, se esperaba %q (%v)", health.Status, tt.want, health.Checks)
+			}
+
+			if len(health.Checks) != len(tt.checks) {
+				t.Errorf("se informaron %d verificaciones, se esperaban %d", len(health.Checks), len(tt.checks))
+			}
+		})
+	}
+}
+
+func TestReadyWhileShuttingDown(t *testing.T) {
+	called := false
+	svc := NewService(time.Second, Check{Name: "database", Critical: true, Run: func(ctx context.Context) error {
+		called = true
+		return nil
+	}})
+
+	svc.SetReady(false)
+
+	if health := svc.Ready(context.Background()); health.Ready() {
+		t.Errorf("el servidor se informó disponible al detenerse: %v", health)
+	}
+
+	if called {
+		t.Error("se ejecutaron las verificaciones al detenerse")
+	}
+}
@@ -0,0 +1,64 @@
+package mfa
+
+import (
+	"context"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// Enrollment contiene los datos que se muestran al usuario una única vez al inscribirse.
+type Enrollment struct {
+	Secret        string
+	URI           string
+	RecoveryCodes []string
+}
+
+type Service interface {
+	// Enroll genera un nuevo secreto TOTP y códigos de recuperación para el usuario. La
+	// inscripción queda pendiente hasta que se confirme con un código válido.
+	Enroll(ctx context.Context, userID string) (*Enrollment, error)
+
+	// Confirm activa la autenticación de dos factores verificando un código de la aplicación.
+	Confirm(ctx context.Context, userID string, code string) error
+
+	// Verify valida un código TOTP o un código de recuperación (que se consume al usarse).
+	Verify(ctx context.Context, userID string, code string) error
+
+	// IsEnabled indica si el usuario tiene la autenticación de dos factores activa.
+	IsEnabled(ctx context.Context, userID string) (bool, error)
+
+	// IsRequired indica si el rol exige autenticación de dos factores.
+	IsRequired(role domain.Role) bool
+
+	// Disable desactiva la autenticación de dos factores del propio usuario, previa verificación
+	// de un código. No está permitido si su rol la exige.
+	Disable(ctx context.Context, userID string, code string) error
+
+	// Reset elimina la configuración de dos factores de un usuario (uso administrativo, p. ej.
+	// ante la pérdida del dispositivo).
+	Reset(ctx context.Context, userID string) error
+}
+
+type Repository interface {
+	// Find obtiene la configuración de dos factores del usuario.
+	Find(ctx context.Context, userID string) (*domain.UserMFA, error)
+
+	// Save registra (o reemplaza) una inscripción pendiente junto con los hashes de sus códigos
+	// de recuperación.
+	Save(ctx context.Context, mfa *domain.UserMFA, recoveryCodeHashes []string) error
+
+	// Enable marca la inscripción del usuario como confirmada.
+	Enable(ctx context.Context, userID string, enabledAt time.Time) error
+
+	// ConsumeStep registra el periodo TOTP utilizado. Retorna false si el periodo ya había sido
+	// utilizado (o uno posterior), lo que indica un intento de reutilización.
+	ConsumeStep(ctx context.Context, userID string, step int64) (bool, error)
+
+	// UseRecoveryCode marca como utilizado el código de recuperación con el hash indicado.
+	// Retorna false si no existe o ya fue utilizado.
+	UseRecoveryCode(ctx context.Context, userID string, codeHash string, usedAt time.Time) (bool, error)
+
+	// Delete elimina la configuración de dos factores del usuario y sus códigos de recuperación.
+	Delete(ctx context.Context, userID string) error
+}
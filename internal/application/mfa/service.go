@@ -0,0 +1,322 @@
+package mfa
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/JGCaceres97/parking/internal/application/audit"
+	"github.com/JGCaceres97/parking/internal/application/role"
+	"github.com/JGCaceres97/parking/internal/application/transaction"
+	"github.com/JGCaceres97/parking/internal/application/user"
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/totp"
+)
+
+var tracer = otel.Tracer("github.com/JGCaceres97/parking/internal/application/mfa")
+
+// skew es la cantidad de periodos adyacentes aceptados para tolerar desfases de reloj.
+const skew = 1
+
+// recoveryCodeAlphabet omite caracteres que se confunden fácilmente (0/o, 1/l/i).
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+type service struct {
+	uow           transaction.UnitOfWork
+	repo          Repository
+	userRepo      user.Repository
+	roleRepo      role.Repository
+	audit         audit.TxRecorder
+	issuer        string
+	requiredRoles []domain.Role
+}
+
+// NewService crea el servicio de segundo factor. La activación, desactivación y restablecimiento
+// se guardan junto con su entrada de auditoría en una sola transacción.
+func NewService(
+	uow transaction.UnitOfWork,
+	repo Repository,
+	userRepo user.Repository,
+	roleRepo role.Repository,
+	audit audit.TxRecorder,
+	issuer string,
+	requiredRoles []domain.Role,
+) Service {
+	return &service{
+		uow:           uow,
+		repo:          repo,
+		userRepo:      userRepo,
+		roleRepo:      roleRepo,
+		audit:         audit,
+		issuer:        issuer,
+		requiredRoles: requiredRoles,
+	}
+}
+
+func (s *service) Enroll(ctx context.Context, userID string) (*Enrollment, error) {
+	ctx, span := tracer.Start(ctx, "mfa.Enroll")
+	defer span.End()
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.Find(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrMFANotEnrolled) {
+		return nil, fmt.Errorf("error al verificar inscripción de dos factores: %w", err)
+	}
+
+	if current != nil && current.IsEnabled() {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, domain.RecoveryCodeCount)
+	hashes := make([]string, domain.RecoveryCodeCount)
+
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, fmt.Errorf("error al generar código de recuperación: %w", err)
+		}
+
+		codes[i] = code
+		hashes[i] = hashRecoveryCode(code)
+	}
+
+	enrollment := &domain.UserMFA{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+
+	if err := s.repo.Save(ctx, enrollment, hashes); err != nil {
+		return nil, err
+	}
+
+	return &Enrollment{
+		Secret:        secret,
+		URI:           totp.URI(s.issuer, user.Username, secret),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+func (s *service) Confirm(ctx context.Context, userID string, code string) error {
+	ctx, span := tracer.Start(ctx, "mfa.Confirm")
+	defer span.End()
+
+	current, err := s.repo.Find(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if current.IsEnabled() {
+		return domain.ErrMFAAlreadyEnabled
+	}
+
+	if err := s.verifyTOTP(ctx, current, code); err != nil {
+		return err
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.Enable(ctx, userID, time.Now().UTC().Truncate(time.Second)); err != nil {
+			return err
+		}
+
+		return s.audit.RecordTx(ctx, domain.AuditMFAEnable, domain.AuditEntityMFA, userID, nil, nil)
+	})
+}
+
+func (s *service) Verify(ctx context.Context, userID string, code string) error {
+	ctx, span := tracer.Start(ctx, "mfa.Verify")
+	defer span.End()
+
+	current, err := s.repo.Find(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !current.IsEnabled() {
+		return domain.ErrMFANotEnrolled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		return s.verifyTOTP(ctx, current, code)
+	}
+
+	used, err := s.repo.UseRecoveryCode(ctx, userID, hashRecoveryCode(code), time.Now().UTC().Truncate(time.Second))
+	if err != nil {
+		return fmt.Errorf("error al verificar código de recuperación: %w", err)
+	}
+
+	if !used {
+		return domain.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+func (s *service) IsEnabled(ctx context.Context, userID string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "mfa.IsEnabled")
+	defer span.End()
+
+	current, err := s.repo.Find(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMFANotEnrolled) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return current.IsEnabled(), nil
+}
+
+func (s *service) IsRequired(role domain.Role) bool {
+	return slices.Contains(s.requiredRoles, role)
+}
+
+func (s *service) Disable(ctx context.Context, userID string, code string) error {
+	ctx, span := tracer.Start(ctx, "mfa.Disable")
+	defer span.End()
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if s.IsRequired(user.Role) {
+		return domain.ErrMFARequiredByRole
+	}
+
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+
+	return s.remove(ctx, userID, domain.AuditMFADisable)
+}
+
+func (s *service) Reset(ctx context.Context, userID string) error {
+	ctx, span := tracer.Start(ctx, "mfa.Reset")
+	defer span.End()
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkGrant(ctx, user.Role); err != nil {
+		return err
+	}
+
+	return s.remove(ctx, userID, domain.AuditMFAReset)
+}
+
+// remove elimina la configuración de segundo factor del usuario y registra action en la misma
+// transacción.
+func (s *service) remove(ctx context.Context, userID, action string) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.Delete(ctx, userID); err != nil {
+			return err
+		}
+
+		return s.audit.RecordTx(ctx, action, domain.AuditEntityMFA, userID, nil, nil)
+	})
+}
+
+// checkGrant verifica que el usuario que realiza la acción tenga todos los permisos de role, ya
+// que restablecer el segundo factor de una cuenta con más privilegios facilitaría tomar su
+// control. Las acciones sin usuario autenticado no se restringen.
+func (s *service) checkGrant(ctx context.Context, role domain.Role) error {
+	actorID := audit.ActorFromContext(ctx).UserID
+	if actorID == "" {
+		return nil
+	}
+
+	actor, err := s.userRepo.FindByID(ctx, actorID)
+	if err != nil {
+		return fmt.Errorf("error al obtener el usuario actual: %w", err)
+	}
+
+	granted, err := s.roleRepo.FindByName(ctx, actor.Role)
+	if err != nil {
+		return fmt.Errorf("error al obtener el rol del usuario actual: %w", err)
+	}
+
+	target, err := s.roleRepo.FindByName(ctx, role)
+	if err != nil {
+		return fmt.Errorf("error al verificar rol: %w", err)
+	}
+
+	for _, p := range target.Permissions {
+		if !granted.Has(p) {
+			return domain.ErrRoleNotGrantable
+		}
+	}
+
+	return nil
+}
+
+// verifyTOTP valida el código contra el secreto y registra el periodo utilizado para impedir
+// que el mismo código se acepte dos veces.
+func (s *service) verifyTOTP(ctx context.Context, current *domain.UserMFA, code string) error {
+	step, ok := totp.Validate(current.Secret, code, time.Now(), skew)
+	if !ok || step <= current.LastUsedStep {
+		return domain.ErrInvalidMFACode
+	}
+
+	consumed, err := s.repo.ConsumeStep(ctx, current.UserID, step)
+	if err != nil {
+		return fmt.Errorf("error al registrar código de verificación: %w", err)
+	}
+
+	if !consumed {
+		return domain.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+// generateRecoveryCode genera un código con formato "xxxxx-xxxxx".
+func generateRecoveryCode() (string, error) {
+	var sb strings.Builder
+
+	for i := range 10 {
+		if i == 5 {
+			sb.WriteByte('-')
+		}
+
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(recoveryCodeAlphabet))))
+		if err != nil {
+			return "", err
+		}
+
+		sb.WriteByte(recoveryCodeAlphabet[n.Int64()])
+	}
+
+	return sb.String(), nil
+}
+
+// hashRecoveryCode normaliza el código (sin guiones, espacios ni mayúsculas) y retorna su hash.
+// Los códigos tienen entropía suficiente para no requerir un hash lento como bcrypt.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,354 @@
+package mfa
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/audit"
+	"github.com/JGCaceres97/parking/internal/application/role"
+	"github.com/JGCaceres97/parking/internal/application/user"
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/totp"
+)
+
+// memoryRepository guarda las inscripciones y los hashes de los códigos de recuperación en memoria.
+type memoryRepository struct {
+	enrollments map[string]domain.UserMFA
+	recovery    map[string][]string
+}
+
+func (r *memoryRepository) Find(_ context.Context, userID string) (*domain.UserMFA, error) {
+	enrollment, ok := r.enrollments[userID]
+	if !ok {
+		return nil, domain.ErrMFANotEnrolled
+	}
+
+	return &enrollment, nil
+}
+
+func (r *memoryRepository) Save(_ context.Context, mfa *domain.UserMFA, recoveryCodeHashes []string) error {
+	r.enrollments[mfa.UserID] = *mfa
+	r.recovery[mfa.UserID] = slices.Clone(recoveryCodeHashes)
+	return nil
+}
+
+func (r *memoryRepository) Enable(_ context.Context, userID string, enabledAt time.Time) error {
+	enrollment := r.enrollments[userID]
+	enrollment.EnabledAt = &enabledAt
+	r.enrollments[userID] = enrollment
+	return nil
+}
+
+func (r *memoryRepository) ConsumeStep(_ context.Context, userID string, step int64) (bool, error) {
+	enrollment := r.enrollments[userID]
+	if step <= enrollment.LastUsedStep {
+		return false, nil
+	}
+
+	enrollment.LastUsedStep = step
+	r.enrollments[userID] = enrollment
+	return true, nil
+}
+
+func (r *memoryRepository) UseRecoveryCode(_ context.Context, userID string, codeHash string, _ time.Time) (bool, error) {
+	i := slices.Index(r.recovery[userID], codeHash)
+	if i < 0 {
+		return false, nil
+	}
+
+	r.recovery[userID] = slices.Delete(r.recovery[userID], i, i+1)
+	return true, nil
+}
+
+func (r *memoryRepository) Delete(_ context.Context, userID string) error {
+	delete(r.enrollments, userID)
+	delete(r.recovery, userID)
+	return nil
+}
+
+// fakeUserRepo resuelve los usuarios por ID. Los métodos no utilizados por el servicio provienen
+// de la interfaz embebida y no deben invocarse.
+type fakeUserRepo struct {
+	user.Repository
+	users map[string]domain.User
+}
+
+func (r *fakeUserRepo) FindByID(_ context.Context, id string) (*domain.User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+
+	return &u, nil
+}
+
+// memoryRoleRepo resuelve los roles por nombre. Los métodos no utilizados por el servicio
+// provienen de la interfaz embebida y no deben invocarse.
+type memoryRoleRepo struct {
+	role.Repository
+	roles map[domain.Role]domain.RoleDefinition
+}
+
+func (r *memoryRoleRepo) FindByName(_ context.Context, name domain.Role) (*domain.RoleDefinition, error) {
+	role, ok := r.roles[name]
+	if !ok {
+		return nil, domain.ErrRoleNotFound
+	}
+
+	return &role, nil
+}
+
+type memoryRecorder struct {
+	actions []string
+}
+
+func (r *memoryRecorder) RecordTx(_ context.Context, action, _, _ string, _, _ any) error {
+	r.actions = append(r.actions, action)
+	return nil
+}
+
+// directUoW ejecuta fn sin transacción, ya que los repositorios en memoria no la necesitan.
+type directUoW struct{}
+
+func (directUoW) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func newTestService() (*service, *memoryRepository, *memoryRecorder) {
+	repo := &memoryRepository{enrollments: map[string]domain.UserMFA{}, recovery: map[string][]string{}}
+	users := &fakeUserRepo{users: map[string]domain.User{
+		"cajero": {ID: "cajero", Username: "cajero", Role: domain.RoleCashier},
+		"admin":  {ID: "admin", Username: "admin", Role: domain.RoleAdmin},
+		"gestor": {ID: "gestor", Username: "gestor", Role: "gestor"},
+	}}
+
+	roles := &memoryRoleRepo{roles: map[domain.Role]domain.RoleDefinition{
+		domain.RoleAdmin:   {Name: domain.RoleAdmin, Permissions: domain.Permissions},
+		domain.RoleCashier: {Name: domain.RoleCashier, Permissions: []domain.Permission{domain.PermParkingRead, domain.PermParkingWrite}},
+		"gestor":           {Name: "gestor", Permissions: []domain.Permission{domain.PermParkingRead, domain.PermParkingWrite, domain.PermUsersRead, domain.PermUsersManage}},
+	}}
+
+	recorder := &memoryRecorder{}
+	svc := NewService(directUoW{}, repo, users, roles, recorder, "Parking", []domain.Role{domain.RoleAdmin})
+
+	return svc.(*service), repo, recorder
+}
+
+// codeAt retorna el código TOTP del secreto para el periodo indicado.
+func codeAt(t *testing.T, secret string, step int64) string {
+	t.Helper()
+
+	code, err := totp.Code(secret, step)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return code
+}
+
+// enable inscribe al usuario y confirma el segundo factor con el código del periodo actual, que
+// retorna junto con la inscripción.
+func enable(t *testing.T, svc *service, userID string) (*Enrollment, int64) {
+	t.Helper()
+
+	enrollment, err := svc.Enroll(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("Enroll() = %v", err)
+	}
+
+	step := totp.Step(time.Now())
+	if err := svc.Confirm(context.Background(), userID, codeAt(t, enrollment.Secret, step)); err != nil {
+		t.Fatalf("Confirm() = %v", err)
+	}
+
+	return enrollment, step
+}
+
+func TestEnrollAndConfirm(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, recorder := newTestService()
+
+	if _, err := svc.Enroll(ctx, "fantasma"); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("Enroll() de un usuario inexistente = %v, se esperaba %v", err, domain.ErrUserNotFound)
+	}
+
+	if err := svc.Confirm(ctx, "cajero", "123456"); !errors.Is(err, domain.ErrMFANotEnrolled) {
+		t.Errorf("Confirm() sin inscripción = %v, se esperaba %v", err, domain.ErrMFANotEnrolled)
+	}
+
+	enrollment, err := svc.Enroll(ctx, "cajero")
+	if err != nil {
+		t.Fatalf("Enroll() = %v", err)
+	}
+
+	if len(enrollment.RecoveryCodes) != domain.RecoveryCodeCount || len(repo.recovery["cajero"]) != domain.RecoveryCodeCount {
+		t.Errorf("Enroll() generó %d códigos de recuperación, se esperaban %d", len(enrollment.RecoveryCodes), domain.RecoveryCodeCount)
+	}
+
+	// Los códigos de recuperación solo se guardan como hash.
+	if slices.Contains(repo.recovery["cajero"], enrollment.RecoveryCodes[0]) {
+		t.Error("el código de recuperación se guardó en texto plano")
+	}
+
+	if enabled, _ := svc.IsEnabled(ctx, "cajero"); enabled {
+		t.Fatal("IsEnabled() = true antes de confirmar")
+	}
+
+	if err := svc.Confirm(ctx, "cajero", enrollment.RecoveryCodes[0]); !errors.Is(err, domain.ErrInvalidMFACode) {
+		t.Errorf("Confirm() con un código de recuperación = %v, se esperaba %v", err, domain.ErrInvalidMFACode)
+	}
+
+	code := codeAt(t, enrollment.Secret, totp.Step(time.Now()))
+
+	if err := svc.Confirm(ctx, "cajero", code); err != nil {
+		t.Fatalf("Confirm() = %v", err)
+	}
+
+	if enabled, _ := svc.IsEnabled(ctx, "cajero"); !enabled {
+		t.Error("IsEnabled() = false tras confirmar")
+	}
+
+	if err := svc.Confirm(ctx, "cajero", code); !errors.Is(err, domain.ErrMFAAlreadyEnabled) {
+		t.Errorf("Confirm() repetido = %v, se esperaba %v", err, domain.ErrMFAAlreadyEnabled)
+	}
+
+	if _, err := svc.Enroll(ctx, "cajero"); !errors.Is(err, domain.ErrMFAAlreadyEnabled) {
+		t.Errorf("Enroll() con el segundo factor activo = %v, se esperaba %v", err, domain.ErrMFAAlreadyEnabled)
+	}
+
+	if !slices.Equal(recorder.actions, []string{domain.AuditMFAEnable}) {
+		t.Errorf("auditoría = %v, se esperaba %v", recorder.actions, domain.AuditMFAEnable)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService()
+
+	if err := svc.Verify(ctx, "cajero", "123456"); !errors.Is(err, domain.ErrMFANotEnrolled) {
+		t.Errorf("Verify() sin inscripción = %v, se esperaba %v", err, domain.ErrMFANotEnrolled)
+	}
+
+	enrollment, step := enable(t, svc, "cajero")
+
+	// Los casos se ejecutan en orden, ya que cada código aceptado se consume.
+	tests := []struct {
+		name string
+		code string
+		want error
+	}{
+		{name: "código usado al confirmar", code: codeAt(t, enrollment.Secret, step), want: domain.ErrInvalidMFACode},
+		{name: "código del periodo siguiente", code: codeAt(t, enrollment.Secret, step+1)},
+		{name: "código fuera de la tolerancia", code: codeAt(t, enrollment.Secret, step+3), want: domain.ErrInvalidMFACode},
+		{name: "código de recuperación", code: " " + enrollment.RecoveryCodes[0] + " "},
+		{name: "código de recuperación utilizado", code: enrollment.RecoveryCodes[0], want: domain.ErrInvalidMFACode},
+		{name: "código de recuperación sin guion", code: strings.ToUpper(strings.ReplaceAll(enrollment.RecoveryCodes[1], "-", ""))},
+		{name: "código inexistente", code: "aaaaa-aaaaa", want: domain.ErrInvalidMFACode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := svc.Verify(ctx, "cajero", tt.code); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() = %v, se esperaba %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisableAndReset(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		action     func(s *service, enrollment *Enrollment) error
+		want       error
+		wantAction string
+	}{
+		{
+			name:   "desactivar con un código válido",
+			userID: "cajero",
+			action: func(s *service, enrollment *Enrollment) error {
+				return s.Disable(context.Background(), "cajero", enrollment.RecoveryCodes[0])
+			},
+			wantAction: domain.AuditMFADisable,
+		},
+		{
+			name:   "desactivar con un código incorrecto",
+			userID: "cajero",
+			action: func(s *service, _ *Enrollment) error {
+				return s.Disable(context.Background(), "cajero", "aaaaa-aaaaa")
+			},
+			want: domain.ErrInvalidMFACode,
+		},
+		{
+			name:   "desactivar cuando el rol lo exige",
+			userID: "admin",
+			action: func(s *service, enrollment *Enrollment) error {
+				return s.Disable(context.Background(), "admin", enrollment.RecoveryCodes[0])
+			},
+			want: domain.ErrMFARequiredByRole,
+		},
+		{
+			name:   "restablecer por un administrador",
+			userID: "admin",
+			action: func(s *service, _ *Enrollment) error {
+				return s.Reset(context.Background(), "admin")
+			},
+			wantAction: domain.AuditMFAReset,
+		},
+		{
+			name:   "restablecer un usuario con menos permisos",
+			userID: "cajero",
+			action: func(s *service, _ *Enrollment) error {
+				ctx := audit.WithActor(context.Background(), audit.Actor{UserID: "gestor"})
+				return s.Reset(ctx, "cajero")
+			},
+			wantAction: domain.AuditMFAReset,
+		},
+		{
+			name:   "restablecer un usuario con más permisos",
+			userID: "admin",
+			action: func(s *service, _ *Enrollment) error {
+				ctx := audit.WithActor(context.Background(), audit.Actor{UserID: "gestor"})
+				return s.Reset(ctx, "admin")
+			},
+			want: domain.ErrRoleNotGrantable,
+		},
+		{
+			name:   "restablecer un usuario inexistente",
+			userID: "cajero",
+			action: func(s *service, _ *Enrollment) error {
+				return s.Reset(context.Background(), "fantasma")
+			},
+			want: domain.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, recorder := newTestService()
+			enrollment, _ := enable(t, svc, tt.userID)
+			recorder.actions = nil
+
+			if err := tt.action(svc, enrollment); !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, se esperaba %v", err, tt.want)
+			}
+
+			enabled, err := svc.IsEnabled(context.Background(), tt.userID)
+			if err != nil {
+				t.Fatalf("IsEnabled() = %v", err)
+			}
+
+			if wantEnabled := tt.want != nil; enabled != wantEnabled {
+				t.Errorf("IsEnabled() = %v, se esperaba %v", enabled, wantEnabled)
+			}
+
+			if tt.wantAction != "" && !slices.Equal(recorder.actions, []string{tt.wantAction}) {
+				t.Errorf("auditoría = %v, se esperaba %v", recorder.actions, tt.wantAction)
+			}
+		})
+	}
+}
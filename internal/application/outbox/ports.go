@@ -0,0 +1,51 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type Service interface {
+	Outbox
+
+	// Relay entrega los mensajes pendientes a los consumidores, en orden, y retorna cuántos
+	// procesó. Cada mensaje se marca como procesado en la misma transacción que sus consumidores,
+	// por lo que un fallo lo deja pendiente para el siguiente intento.
+	Relay(ctx context.Context) (int, error)
+
+	// Backlog retorna la antigüedad del mensaje pendiente más antiguo, o 0 si no hay pendientes.
+	Backlog(ctx context.Context) (time.Duration, error)
+
+	// Start ejecuta Relay periódicamente hasta que se cancele el contexto, y elimina los mensajes
+	// procesados antiguos.
+	Start(ctx context.Context, interval time.Duration)
+}
+
+// Outbox registra eventos para los consumidores durables.
+type Outbox interface {
+	// Add guarda el evento como mensaje pendiente. Debe llamarse dentro de la unidad de trabajo
+	// del cambio que lo origina.
+	Add(ctx context.Context, eventType domain.EventType, data any) error
+}
+
+// Consumer procesa los mensajes de la bandeja de salida (ej. webhooks). Se ejecuta dentro de la
+// transacción que marca el mensaje como procesado.
+type Consumer interface {
+	Consume(ctx context.Context, message domain.OutboxMessage) error
+}
+
+type Repository interface {
+	// Insert guarda un mensaje pendiente.
+	Insert(ctx context.Context, message *domain.OutboxMessage) error
+
+	// ListPending lista hasta limit mensajes pendientes, del más antiguo al más reciente.
+	ListPending(ctx context.Context, limit int) ([]domain.OutboxMessage, error)
+
+	// MarkProcessed marca el mensaje como procesado. Retorna false si otra instancia ya lo procesó.
+	MarkProcessed(ctx context.Context, id string, processedAt time.Time) (bool, error)
+
+	// DeleteProcessed elimina los mensajes procesados antes de before y retorna cuántos eliminó.
+	DeleteProcessed(ctx context.Context, before time.Time) (int64, error)
+}
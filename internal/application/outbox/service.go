@@ -0,0 +1,137 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/JGCaceres97/parking/internal/application/transaction"
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/ulid"
+)
+
+var tracer = otel.Tracer("github.com/JGCaceres97/parking/internal/application/outbox")
+
+const (
+	// relayBatchSize es la cantidad de mensajes procesados por ejecución.
+	relayBatchSize = 100
+	// processedRetention es el tiempo que se conservan los mensajes ya procesados.
+	processedRetention = 7 * 24 * time.Hour
+)
+
+type service struct {
+	uow       transaction.UnitOfWork
+	repo      Repository
+	consumers []Consumer
+	now       func() time.Time
+}
+
+func NewService(uow transaction.UnitOfWork, repo Repository, consumers ...Consumer) Service {
+	return &service{
+		uow:       uow,
+		repo:      repo,
+		consumers: consumers,
+		now:       time.Now,
+	}
+}
+
+func (s *service) Add(ctx context.Context, eventType domain.EventType, data any) error {
+	ctx, span := tracer.Start(ctx, "outbox.Add")
+	defer span.End()
+
+	message, err := domain.NewOutboxMessage(domain.Event{
+		ID:         ulid.GenerateNewULID(),
+		Type:       eventType,
+		OccurredAt: s.now().UTC().Truncate(time.Second),
+		Data:       data,
+	})
+
+	if err != nil {
+		return fmt.Errorf("error al serializar el evento: %w", err)
+	}
+
+	return s.repo.Insert(ctx, message)
+}
+
+func (s *service) Relay(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "outbox.Relay")
+	defer span.End()
+
+	messages, err := s.repo.ListPending(ctx, relayBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+
+	for _, message := range messages {
+		err := s.uow.Do(ctx, func(ctx context.Context) error {
+			claimed, err := s.repo.MarkProcessed(ctx, message.ID, s.now().UTC().Truncate(time.Second))
+			if err != nil || !claimed {
+				return err
+			}
+
+			for _, consumer := range s.consumers {
+				if err := consumer.Consume(ctx, message); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+
+		// Se detiene el lote para respetar el orden de los eventos.
+		if err != nil {
+			return processed, fmt.Errorf("error al procesar el mensaje %s: %w", message.ID, err)
+		}
+
+		processed++
+	}
+
+	return processed, nil
+}
+
+func (s *service) Backlog(ctx context.Context) (time.Duration, error) {
+	ctx, span := tracer.Start(ctx, "outbox.Backlog")
+	defer span.End()
+
+	messages, err := s.repo.ListPending(ctx, 1)
+	if err != nil || len(messages) == 0 {
+		return 0, err
+	}
+
+	return s.now().Sub(messages[0].OccurredAt), nil
+}
+
+func (s *service) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		lastPurge := time.Time{}
+
+		for {
+			if _, err := s.Relay(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				slog.WarnContext(ctx, "error al procesar la bandeja de salida", "error", err)
+			}
+
+			if now := s.now(); now.Sub(lastPurge) >= time.Hour {
+				if _, err := s.repo.DeleteProcessed(ctx, now.Add(-processedRetention)); err != nil && !errors.Is(err, context.Canceled) {
+					slog.WarnContext(ctx, "error al depurar la bandeja de salida", "error", err)
+				}
+
+				lastPurge = now
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
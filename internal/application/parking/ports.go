@@ -19,6 +19,10 @@ type Service interface {
 	// GetHistory lista todos los registros, incluyendo los cerrados.
 	GetHistory(ctx context.Context) ([]domain.ParkingRecord, error)
 
+	// Void anula un registro, abierto o cerrado, indicando el motivo. El registro se conserva,
+	// pero deja de contar en los reportes.
+	Void(ctx context.Context, userID string, id string, reason string) (*domain.ParkingRecord, error)
+
 	// GetRecordByID obtiene un registro específico.
 	GetRecordByID(ctx context.Context, id string) (*domain.ParkingRecord, error)
 }
@@ -37,9 +41,16 @@ type Repository interface {
 	// UpdateExit completa un registro de estacionamiento al registra la salida y el cobro.
 	UpdateExit(ctx context.Context, record *domain.ParkingRecord) error
 
+	// Void marca el registro como anulado. Si está abierto, lo cierra con la hora de salida
+	// indicada. Retorna domain.ErrParkingRecordNotFound si no existe o ya fue anulado.
+	Void(ctx context.Context, record *domain.ParkingRecord) error
+
 	// ListCurrent lista todos los vehículos que aún están estacionados (exit_time IS NULL).
 	ListCurrent(ctx context.Context) ([]domain.ParkingRecord, error)
 
 	// ListHistory lista todos los registros de estacionamiento (historial).
 	ListHistory(ctx context.Context) ([]domain.ParkingRecord, error)
+
+	// CountCurrent cuenta los vehículos que aún están estacionados.
+	CountCurrent(ctx context.Context) (int, error)
 }
@@ -4,46 +4,66 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"math"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"go.opentelemetry.io/otel"
+
+	"github.com/JGCaceres97/parking/internal/application/audit"
+	"github.com/JGCaceres97/parking/internal/application/events"
+	"github.com/JGCaceres97/parking/internal/application/outbox"
+	"github.com/JGCaceres97/parking/internal/application/transaction"
 	"github.com/JGCaceres97/parking/internal/application/vehicle_type"
 	"github.com/JGCaceres97/parking/internal/domain"
 	"github.com/JGCaceres97/parking/pkg/ulid"
 )
 
+var tracer = otel.Tracer("github.com/JGCaceres97/parking/internal/application/parking")
+
 type service struct {
+	uow         transaction.UnitOfWork
 	repo        Repository
 	vehicleRepo vehicle_type.Repository
+	audit       audit.TxRecorder
+	events      events.Publisher
+	outbox      outbox.Outbox
+	capacity    int
+	pricing     domain.PricingPolicy
 }
 
-func NewService(repo Repository, vehicleRepo vehicle_type.Repository) Service {
+// NewService crea el servicio de estacionamiento. capacity es la cantidad de espacios que se
+// informa en los eventos de ocupación (0 si no hay límite definido) y pricing las reglas de
+// redondeo del cobro.
+func NewService(
+	uow transaction.UnitOfWork,
+	repo Repository,
+	vehicleRepo vehicle_type.Repository,
+	audit audit.TxRecorder,
+	events events.Publisher,
+	outbox outbox.Outbox,
+	capacity int,
+	pricing domain.PricingPolicy,
+) Service {
 	return &service{
+		uow:         uow,
 		repo:        repo,
 		vehicleRepo: vehicleRepo,
+		audit:       audit,
+		events:      events,
+		outbox:      outbox,
+		capacity:    capacity,
+		pricing:     pricing,
 	}
 }
 
+// RecordEntry valida y registra la entrada en una sola transacción, junto con la auditoría y el
+// evento en la bandeja de salida. Los eventos en tiempo real se publican después de confirmar.
 func (s *service) RecordEntry(ctx context.Context, userID, vehicleTypeID, licensePlate string) (*domain.ParkingRecord, error) {
-	// Verificar si ya existe registro abierto para la placa.
-	_, err := s.repo.FindOpenByLicensePlate(ctx, licensePlate)
-	if err == nil {
-		return nil, domain.ErrActiveParkingAlreadyExists
-	}
-
-	if !errors.Is(err, domain.ErrParkingRecordNotFound) {
-		return nil, fmt.Errorf("error al verificar registro abierto: %w", err)
-	}
-
-	// Verificar que el tipo de vehículo sea válido.
-	_, err = s.vehicleRepo.FindByID(ctx, vehicleTypeID)
-	if err != nil {
-		if errors.Is(err, domain.ErrVehicleTypeNotFound) {
-			return nil, domain.ErrVehicleTypeNotFound
-		}
-
-		return nil, fmt.Errorf("error al buscar tipo de vehículo: %w", err)
-	}
+	ctx, span := tracer.Start(ctx, "parking.RecordEntry")
+	defer span.End()
 
 	record := domain.ParkingRecord{
 		ID:            ulid.GenerateNewULID(),
@@ -53,53 +73,189 @@ func (s *service) RecordEntry(ctx context.Context, userID, vehicleTypeID, licens
 		EntryTime:     time.Now().UTC().Truncate(time.Second),
 	}
 
-	if err = s.repo.CreateEntry(ctx, &record); err != nil {
-		return nil, fmt.Errorf("error al guardar registro de entrada: %w", err)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		// Verificar si ya existe registro abierto para la placa.
+		_, err := s.repo.FindOpenByLicensePlate(ctx, licensePlate)
+		if err == nil {
+			return domain.ErrActiveParkingAlreadyExists
+		}
+
+		if !errors.Is(err, domain.ErrParkingRecordNotFound) {
+			return fmt.Errorf("error al verificar registro abierto: %w", err)
+		}
+
+		// Verificar que el tipo de vehículo sea válido.
+		_, err = s.vehicleRepo.FindByID(ctx, vehicleTypeID)
+		if err != nil {
+			if errors.Is(err, domain.ErrVehicleTypeNotFound) {
+				return domain.ErrVehicleTypeNotFound
+			}
+
+			return fmt.Errorf("error al buscar tipo de vehículo: %w", err)
+		}
+
+		if err = s.repo.CreateEntry(ctx, &record); err != nil {
+			return fmt.Errorf("error al guardar registro de entrada: %w", err)
+		}
+
+		if err = s.audit.RecordTx(ctx, domain.AuditParkingEntry, domain.AuditEntityParkingRecord, record.ID, nil, record); err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, domain.EventVehicleEntered, record)
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
+	s.events.Publish(domain.EventVehicleEntered, record)
+	s.publishCapacity(ctx)
+
 	return &record, nil
 }
 
+// RecordExit calcula el cobro y cierra el registro en una sola transacción, junto con la
+// auditoría y el evento en la bandeja de salida.
 func (s *service) RecordExit(ctx context.Context, userID, licensePlate string) (*domain.ParkingRecord, error) {
-	record, err := s.repo.FindOpenByLicensePlate(ctx, licensePlate)
-	if err != nil {
-		if errors.Is(err, domain.ErrParkingRecordNotFound) {
-			return nil, domain.ErrActiveParkingNotFound
+	ctx, span := tracer.Start(ctx, "parking.RecordExit")
+	defer span.End()
+
+	var record, before domain.ParkingRecord
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		open, err := s.repo.FindOpenByLicensePlate(ctx, licensePlate)
+		if err != nil {
+			if errors.Is(err, domain.ErrParkingRecordNotFound) {
+				return domain.ErrActiveParkingNotFound
+			}
+
+			return fmt.Errorf("error al buscar registro abierto: %w", err)
 		}
 
-		return nil, fmt.Errorf("error al buscar registro abierto: %w", err)
-	}
+		vehicleType, err := s.vehicleRepo.FindByID(ctx, open.VehicleTypeID)
+		if err != nil {
+			return fmt.Errorf("no se pudo obtener la tarifa: %w", err)
+		}
+
+		record, before = *open, *open
+
+		exitTime := time.Now().UTC()
+		hours, charge := calculateCharge(record.EntryTime, exitTime, vehicleType.HourlyRate, s.pricing)
+
+		truncatedExitTime := exitTime.Truncate(time.Second)
+
+		record.ExitTime = &truncatedExitTime
+		record.TotalCharge = &charge
+		record.CalculatedHours = &hours
+
+		if err = s.repo.UpdateExit(ctx, &record); err != nil {
+			return fmt.Errorf("error al actualizar registro de salida: %w", err)
+		}
+
+		if err = s.audit.RecordTx(ctx, domain.AuditParkingExit, domain.AuditEntityParkingRecord, record.ID, before, record); err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, domain.EventVehicleExited, record)
+	})
 
-	vehicleType, err := s.vehicleRepo.FindByID(ctx, record.VehicleTypeID)
 	if err != nil {
-		return nil, fmt.Errorf("no se pudo obtener la tarifa: %w", err)
+		return nil, err
 	}
 
-	exitTime := time.Now().UTC()
-	hours, charge := calculateCharge(record.EntryTime, exitTime, vehicleType.HourlyRate)
+	s.events.Publish(domain.EventVehicleExited, record)
+	s.publishCapacity(ctx)
 
-	truncatedExitTime := exitTime.Truncate(time.Second)
+	return &record, nil
+}
 
-	record.ExitTime = &truncatedExitTime
-	record.TotalCharge = &charge
-	record.CalculatedHours = &hours
+// Void anula el registro en una sola transacción, junto con la auditoría y el evento en la bandeja
+// de salida. Un registro abierto se cierra sin cobro, lo que libera la placa.
+func (s *service) Void(ctx context.Context, userID, id, reason string) (*domain.ParkingRecord, error) {
+	ctx, span := tracer.Start(ctx, "parking.Void")
+	defer span.End()
 
-	if err = s.repo.UpdateExit(ctx, record); err != nil {
-		return nil, fmt.Errorf("error al actualizar registro de salida: %w", err)
+	reason = strings.TrimSpace(reason)
+	if reason == "" || utf8.RuneCountInString(reason) > domain.MaxVoidReasonLength {
+		return nil, domain.ErrInvalidVoidReason
 	}
 
-	return record, nil
+	var record, before domain.ParkingRecord
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		existing, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if existing.IsVoided() {
+			return domain.ErrParkingRecordVoided
+		}
+
+		record, before = *existing, *existing
+
+		now := time.Now().UTC().Truncate(time.Second)
+
+		if record.ExitTime == nil {
+			record.ExitTime = &now
+		}
+
+		record.VoidedAt = &now
+		record.VoidedBy = userID
+		record.VoidReason = reason
+
+		if err := s.repo.Void(ctx, &record); err != nil {
+			// Otra solicitud lo anuló entre la lectura y la actualización.
+			if errors.Is(err, domain.ErrParkingRecordNotFound) {
+				return domain.ErrParkingRecordVoided
+			}
+
+			return fmt.Errorf("error al anular registro: %w", err)
+		}
+
+		// La auditoría guarda solo el ID del operador, ya que no se modifica al anonimizarlo.
+		beforeAudit, afterAudit := before, record
+		beforeAudit.Username, afterAudit.Username = "", ""
+
+		if err := s.audit.RecordTx(ctx, domain.AuditParkingVoid, domain.AuditEntityParkingRecord, record.ID, beforeAudit, afterAudit); err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, domain.EventParkingVoided, record)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(domain.EventParkingVoided, record)
+
+	if before.ExitTime == nil {
+		s.publishCapacity(ctx)
+	}
+
+	return &record, nil
 }
 
 func (s *service) GetCurrentlyParked(ctx context.Context) ([]domain.ParkingRecord, error) {
+	ctx, span := tracer.Start(ctx, "parking.GetCurrentlyParked")
+	defer span.End()
+
 	return s.repo.ListCurrent(ctx)
 }
 
 func (s *service) GetHistory(ctx context.Context) ([]domain.ParkingRecord, error) {
+	ctx, span := tracer.Start(ctx, "parking.GetHistory")
+	defer span.End()
+
 	return s.repo.ListHistory(ctx)
 }
 
 func (s *service) GetRecordByID(ctx context.Context, id string) (*domain.ParkingRecord, error) {
+	ctx, span := tracer.Start(ctx, "parking.GetRecordByID")
+	defer span.End()
+
 	record, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -108,36 +264,38 @@ func (s *service) GetRecordByID(ctx context.Context, id string) (*domain.Parking
 	return record, nil
 }
 
-func calculateCharge(entryTime, exitTime time.Time, hourlyRate float64) (int, float64) {
-	if hourlyRate == 0.00 {
-		duration := exitTime.Sub(entryTime)
-
-		calculatedHours := int(math.Ceil(duration.Hours()))
-		if calculatedHours == 0 {
-			calculatedHours = 1 // Mínimo 1 hora
-		}
-
-		return calculatedHours, 0.00
+// publishCapacity publica la ocupación actual. Un error al contarla no interrumpe la operación.
+func (s *service) publishCapacity(ctx context.Context) {
+	occupied, err := s.repo.CountCurrent(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "no se pudo calcular la ocupación", "error", err)
+		return
 	}
 
-	minutes := exitTime.Sub(entryTime).Minutes()
-
-	if minutes <= 0 {
-		return 1, hourlyRate // Mínimo 1 hora
+	status := domain.CapacityStatus{Occupied: occupied, Capacity: s.capacity}
+	if s.capacity > 0 {
+		status.Available = max(s.capacity-occupied, 0)
 	}
 
-	fullHours := int(minutes / 60.0)
-	remainingMinutes := minutes - (float64(fullHours) * 60.0)
+	s.events.Publish(domain.EventCapacityChanged, status)
+}
 
-	calculatedHours := fullHours
-	if remainingMinutes >= 30.0 {
-		calculatedHours += 1
-	}
+// calculateCharge calcula las horas cobradas y el total de una estadía. En los vehículos exentos
+// las horas solo son informativas, por lo que toda fracción cuenta como hora completa.
+func calculateCharge(entryTime, exitTime time.Time, hourlyRate float64, pricing domain.PricingPolicy) (int, float64) {
+	duration := max(exitTime.Sub(entryTime), 0)
 
-	if calculatedHours == 0 {
-		calculatedHours = 1
+	var calculatedHours int
+	if hourlyRate == 0.00 {
+		calculatedHours = int(math.Ceil(duration.Hours()))
+	} else {
+		calculatedHours = int(duration / time.Hour)
+		if duration%time.Hour >= pricing.RoundUpAfter {
+			calculatedHours += 1
+		}
 	}
 
+	calculatedHours = max(calculatedHours, pricing.MinimumHours)
 	totalCharge := float64(calculatedHours) * hourlyRate
 
 	return calculatedHours, totalCharge
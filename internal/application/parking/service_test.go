@@ -1,8 +1,15 @@
 package parking
 
 import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
 	"testing"
 	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/vehicle_type"
+	"github.com/JGCaceres97/parking/internal/domain"
 )
 
 func TestCalculateCharge(t *testing.T) {
@@ -12,24 +19,31 @@ func TestCalculateCharge(t *testing.T) {
 		rate           float64
 		expectedHours  int
 		expectedCharge float64
+		pricing        *domain.PricingPolicy
 	}{
 		// Caso 1: Menos de 1 minuto (se cobra 1 hora)
-		{"Menos de un minuto", time.Minute * 0, 15.00, 1, 15.00},
+		{"Menos de un minuto", time.Minute * 0, 15.00, 1, 15.00, nil},
 
 		// Caso 2: 1 hora justa
-		{"1 hora justa", time.Hour * 1, 15.00, 1, 15.00},
+		{"1 hora justa", time.Hour * 1, 15.00, 1, 15.00, nil},
 
 		// Caso 3: 1 hora y 29 minutos (Se queda en 1 hora)
-		{"1h 29min (Normal)", time.Hour*1 + time.Minute*29, 15.00, 1, 15.00},
+		{"1h 29min (Normal)", time.Hour*1 + time.Minute*29, 15.00, 1, 15.00, nil},
 
 		// Caso 4: 1 hora y 30 minutos (Redondea a 2 horas)
-		{"1h 30min (Especial)", time.Hour*1 + time.Minute*30, 5.00, 2, 10.00},
+		{"1h 30min (Especial)", time.Hour*1 + time.Minute*30, 5.00, 2, 10.00, nil},
 
 		// Caso 5: 2 horas y 1 minuto (Se queda en 2 horas)
-		{"2h 1min (Normal)", time.Hour*2 + time.Minute*1, 15.00, 2, 30.00},
+		{"2h 1min (Normal)", time.Hour*2 + time.Minute*1, 15.00, 2, 30.00, nil},
 
 		// Caso 6: Motocicleta exenta (3 horas y 45 minutos)
-		{"Motocicleta Exenta", time.Hour*3 + time.Minute*45, 0.00, 4, 0.00},
+		{"Motocicleta Exenta", time.Hour*3 + time.Minute*45, 0.00, 4, 0.00, nil},
+
+		// Caso 7: Mínimo de 2 horas configurado
+		{"Mínimo configurado", time.Minute * 50, 10.00, 2, 20.00, &domain.PricingPolicy{MinimumHours: 2, RoundUpAfter: 30 * time.Minute}},
+
+		// Caso 8: Redondeo configurado a partir de 15 minutos
+		{"Redondeo configurado", time.Hour*1 + time.Minute*15, 10.00, 2, 20.00, &domain.PricingPolicy{MinimumHours: 1, RoundUpAfter: 15 * time.Minute}},
 	}
 
 	for _, tt := range tests {
@@ -37,7 +51,12 @@ func TestCalculateCharge(t *testing.T) {
 			entryTime := time.Now()
 			exitTime := entryTime.Add(tt.duration)
 
-			hours, charge := calculateCharge(entryTime, exitTime, tt.rate)
+			pricing := domain.PricingPolicy{MinimumHours: 1, RoundUpAfter: 30 * time.Minute}
+			if tt.pricing != nil {
+				pricing = *tt.pricing
+			}
+
+			hours, charge := calculateCharge(entryTime, exitTime, tt.rate, pricing)
 
 			if hours != tt.expectedHours {
 				t.Errorf("Horas cobradas incorrectas. Esperado: %d, Obtenido: %d", tt.expectedHours, hours)
@@ -49,3 +68,172 @@ func TestCalculateCharge(t *testing.T) {
 		})
 	}
 }
+
+// memoryStore reúne el estado que la unidad de trabajo confirma o revierte en conjunto.
+type memoryStore struct {
+	records  map[string]domain.ParkingRecord
+	audit    []string
+	messages []domain.EventType
+}
+
+// memoryUoW revierte el estado del almacén si fn falla, como una transacción.
+type memoryUoW struct {
+	store *memoryStore
+}
+
+func (u memoryUoW) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	records := maps.Clone(u.store.records)
+	audit, messages := slices.Clone(u.store.audit), slices.Clone(u.store.messages)
+
+	if err := fn(ctx); err != nil {
+		u.store.records, u.store.audit, u.store.messages = records, audit, messages
+		return err
+	}
+
+	return nil
+}
+
+type memoryParkingRepo struct {
+	Repository
+	store *memoryStore
+}
+
+func (r memoryParkingRepo) FindOpenByLicensePlate(ctx context.Context, licensePlate string) (*domain.ParkingRecord, error) {
+	for _, record := range r.store.records {
+		if record.LicensePlate == licensePlate && record.ExitTime == nil {
+			return &record, nil
+		}
+	}
+
+	return nil, domain.ErrParkingRecordNotFound
+}
+
+func (r memoryParkingRepo) FindByID(ctx context.Context, id string) (*domain.ParkingRecord, error) {
+	record, ok := r.store.records[id]
+	if !ok {
+		return nil, domain.ErrParkingRecordNotFound
+	}
+
+	return &record, nil
+}
+
+func (r memoryParkingRepo) CreateEntry(ctx context.Context, record *domain.ParkingRecord) error {
+	r.store.records[record.ID] = *record
+	return nil
+}
+
+func (r memoryParkingRepo) UpdateExit(ctx context.Context, record *domain.ParkingRecord) error {
+	r.store.records[record.ID] = *record
+	return nil
+}
+
+func (r memoryParkingRepo) Void(ctx context.Context, record *domain.ParkingRecord) error {
+	r.store.records[record.ID] = *record
+	return nil
+}
+
+func (r memoryParkingRepo) CountCurrent(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+type fakeVehicleTypeRepo struct {
+	vehicle_type.Repository
+}
+
+func (fakeVehicleTypeRepo) FindByID(ctx context.Context, id string) (*domain.VehicleType, error) {
+	return &domain.VehicleType{ID: id, HourlyRate: 10}, nil
+}
+
+// memoryRecorder escribe en el almacén y falla mientras err no sea nil.
+type memoryRecorder struct {
+	store *memoryStore
+	err   error
+}
+
+func (r *memoryRecorder) RecordTx(_ context.Context, action, _, _ string, _, _ any) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	r.store.audit = append(r.store.audit, action)
+	return nil
+}
+
+type memoryOutbox struct {
+	store *memoryStore
+}
+
+func (o memoryOutbox) Add(ctx context.Context, eventType domain.EventType, data any) error {
+	o.store.messages = append(o.store.messages, eventType)
+	return nil
+}
+
+type memoryPublisher struct {
+	published []domain.EventType
+}
+
+func (p *memoryPublisher) Publish(eventType domain.EventType, data any) {
+	p.published = append(p.published, eventType)
+}
+
+func TestAuditFailureRollsBackOperation(t *testing.T) {
+	store := &memoryStore{records: map[string]domain.ParkingRecord{}}
+	recorder := &memoryRecorder{store: store}
+	publisher := &memoryPublisher{}
+
+	svc := NewService(
+		memoryUoW{store},
+		memoryParkingRepo{store: store},
+		fakeVehicleTypeRepo{},
+		recorder,
+		publisher,
+		memoryOutbox{store},
+		0,
+		domain.PricingPolicy{MinimumHours: 1, RoundUpAfter: 30 * time.Minute},
+	)
+
+	ctx := context.Background()
+
+	entry, err := svc.RecordEntry(ctx, "operador", "normal", "ABC123")
+	if err != nil {
+		t.Fatalf("RecordEntry() = %v", err)
+	}
+
+	recorder.err = errors.New("auditoría no disponible")
+	published := len(publisher.published)
+
+	operations := []struct {
+		name string
+		run  func() error
+	}{
+		{"Entrada", func() error {
+			_, err := svc.RecordEntry(ctx, "operador", "normal", "XYZ789")
+			return err
+		}},
+		{"Salida", func() error {
+			_, err := svc.RecordExit(ctx, "operador", "ABC123")
+			return err
+		}},
+		{"Anulación", func() error {
+			_, err := svc.Void(ctx, "operador", entry.ID, "entrada duplicada")
+			return err
+		}},
+	}
+
+	for _, op := range operations {
+		t.Run(op.name, func(t *testing.T) {
+			if err := op.run(); !errors.Is(err, recorder.err) {
+				t.Fatalf("error = %v, se esperaba %v", err, recorder.err)
+			}
+
+			if len(store.records) != 1 || store.records[entry.ID].ExitTime != nil || store.records[entry.ID].IsVoided() {
+				t.Errorf("registros = %+v, se esperaba solo la entrada abierta %s", store.records, entry.ID)
+			}
+
+			if len(store.audit) != 1 || len(store.messages) != 1 || len(publisher.published) != published {
+				t.Errorf("auditoría %v, bandeja %v y eventos %v; la operación fallida no debía dejar rastro",
+					store.audit, store.messages, publisher.published)
+			}
+		})
+	}
+}
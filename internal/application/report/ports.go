@@ -0,0 +1,22 @@
+package report
+
+import (
+	"context"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type Service interface {
+	// Revenue calcula los ingresos diarios entre dos fechas (YYYY-MM-DD, inclusivas), incluyendo
+	// los registros ya archivados.
+	Revenue(ctx context.Context, from, to string) (*domain.RevenueReport, error)
+}
+
+type Repository interface {
+	// DailySummaries agrega los registros cerrados de PARKING_RECORDS por día de salida y tipo
+	// de vehículo.
+	DailySummaries(ctx context.Context, from, to string) ([]domain.DailyParkingSummary, error)
+
+	// ArchivedDailySummaries retorna los totales diarios de los registros archivados.
+	ArchivedDailySummaries(ctx context.Context, from, to string) ([]domain.DailyParkingSummary, error)
+}
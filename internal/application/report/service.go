@@ -0,0 +1,66 @@
+package report
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+var tracer = otel.Tracer("github.com/JGCaceres97/parking/internal/application/report")
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Revenue(ctx context.Context, from, to string) (*domain.RevenueReport, error) {
+	ctx, span := tracer.Start(ctx, "report.Revenue")
+	defer span.End()
+
+	fromDay, errFrom := time.Parse(time.DateOnly, from)
+	toDay, errTo := time.Parse(time.DateOnly, to)
+
+	if errFrom != nil || errTo != nil || toDay.Before(fromDay) {
+		return nil, domain.ErrInvalidDateRange
+	}
+
+	live, err := s.repo.DailySummaries(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	archived, err := s.repo.ArchivedDailySummaries(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.RevenueReport{
+		From: from,
+		To:   to,
+		Days: domain.MergeDailySummaries(live, archived),
+	}
+
+	for i, day := range report.Days {
+		report.Days[i].Revenue = roundCents(day.Revenue)
+
+		report.Records += day.Records
+		report.Hours += day.Hours
+		report.Revenue += day.Revenue
+	}
+
+	report.Revenue = roundCents(report.Revenue)
+
+	return report, nil
+}
+
+// roundCents evita mostrar los errores de redondeo de sumar montos en punto flotante.
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
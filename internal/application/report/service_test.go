@@ -0,0 +1,76 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type fakeRepo struct {
+	live, archived []domain.DailyParkingSummary
+}
+
+func (r *fakeRepo) DailySummaries(ctx context.Context, from, to string) ([]domain.DailyParkingSummary, error) {
+	return r.live, nil
+}
+
+func (r *fakeRepo) ArchivedDailySummaries(ctx context.Context, from, to string) ([]domain.DailyParkingSummary, error) {
+	return r.archived, nil
+}
+
+func TestRevenueMergesLiveAndArchived(t *testing.T) {
+	repo := &fakeRepo{
+		live: []domain.DailyParkingSummary{
+			{Day: "2026-01-02", VehicleTypeID: "car", Records: 1, Hours: 2, Revenue: 0.1},
+			{Day: "2026-01-03", VehicleTypeID: "car", Records: 2, Hours: 3, Revenue: 30},
+		},
+		archived: []domain.DailyParkingSummary{
+			{Day: "2026-01-01", VehicleTypeID: "moto", Records: 4, Hours: 4, Revenue: 20},
+			{Day: "2026-01-02", VehicleTypeID: "car", Records: 3, Hours: 5, Revenue: 0.2},
+		},
+	}
+
+	got, err := NewService(repo).Revenue(context.Background(), "2026-01-01", "2026-01-03")
+	if err != nil {
+		t.Fatalf("Revenue() = %v", err)
+	}
+
+	want := []domain.DailyParkingSummary{
+		{Day: "2026-01-01", VehicleTypeID: "moto", Records: 4, Hours: 4, Revenue: 20},
+		{Day: "2026-01-02", VehicleTypeID: "car", Records: 4, Hours: 7, Revenue: 0.3},
+		{Day: "2026-01-03", VehicleTypeID: "car", Records: 2, Hours: 3, Revenue: 30},
+	}
+
+	if len(got.Days) != len(want) {
+		t.Fatalf("Revenue() = %+v, se esperaban los días %+v", got.Days, want)
+	}
+
+	for i := range want {
+		if got.Days[i] != want[i] {
+			t.Errorf("día %d = %+v, se esperaba %+v", i, got.Days[i], want[i])
+		}
+	}
+
+	if got.Records != 10 || got.Hours != 14 || got.Revenue != 50.3 {
+		t.Errorf("totales = %d registros, %d horas, %v ingresos; se esperaban 10, 14 y 50.3", got.Records, got.Hours, got.Revenue)
+	}
+}
+
+func TestRevenueRejectsInvalidRange(t *testing.T) {
+	tests := []struct {
+		name, from, to string
+	}{
+		{"Fecha inválida", "2026-13-01", "2026-12-31"},
+		{"Rango invertido", "2026-02-01", "2026-01-31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewService(&fakeRepo{}).Revenue(context.Background(), tt.from, tt.to); !errors.Is(err, domain.ErrInvalidDateRange) {
+				t.Errorf("Revenue() = %v, se esperaba %v", err, domain.ErrInvalidDateRange)
+			}
+		})
+	}
+}
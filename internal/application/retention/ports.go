@@ -0,0 +1,36 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type Service interface {
+	// Run archiva todos los registros cerrados anteriores al corte de la política, en lotes.
+	// Puede interrumpirse en cualquier momento: cada lote se confirma por separado y la siguiente
+	// ejecución continúa con los registros pendientes.
+	Run(ctx context.Context) (*domain.RetentionResult, error)
+
+	// Start ejecuta Run periódicamente hasta que se cancele el contexto.
+	Start(ctx context.Context)
+}
+
+type Repository interface {
+	// ListExpired retorna hasta limit registros cerrados con salida anterior a cutoff, ordenados
+	// por hora de salida.
+	ListExpired(ctx context.Context, cutoff time.Time, limit int) ([]domain.ParkingRecord, error)
+
+	// Archive elimina los registros de PARKING_RECORDS y suma los resúmenes a los totales diarios
+	// en una sola transacción. Si keepRows es verdadero, copia además los registros a
+	// PARKING_RECORDS_ARCHIVE. Retorna domain.ErrArchiveConflict si algún registro ya no existe.
+	Archive(ctx context.Context, records []domain.ParkingRecord, summaries []domain.DailyParkingSummary, keepRows bool, archivedAt time.Time) error
+}
+
+// Exporter escribe un lote de registros fuera de la base de datos. Debe ser idempotente por ID:
+// exportar de nuevo un registro reemplaza la copia anterior, aunque llegue en un lote distinto,
+// de modo que reintentar tras un fallo nunca lo duplica.
+type Exporter interface {
+	Export(ctx context.Context, records []domain.ParkingRecord) error
+}
@@ -0,0 +1,134 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/JGCaceres97/parking/internal/application/audit"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+var tracer = otel.Tracer("github.com/JGCaceres97/parking/internal/application/retention")
+
+type service struct {
+	repo     Repository
+	exporter Exporter
+	audit    audit.Recorder
+	policy   domain.RetentionPolicy
+	now      func() time.Time
+}
+
+// NewService crea el servicio de archivado. exporter solo se usa en modo file.
+func NewService(repo Repository, exporter Exporter, audit audit.Recorder, policy domain.RetentionPolicy) Service {
+	return &service{
+		repo:     repo,
+		exporter: exporter,
+		audit:    audit,
+		policy:   policy,
+		now:      time.Now,
+	}
+}
+
+func (s *service) Run(ctx context.Context) (*domain.RetentionResult, error) {
+	ctx, span := tracer.Start(ctx, "retention.Run")
+	defer span.End()
+
+	result := &domain.RetentionResult{Cutoff: s.policy.Cutoff(s.now())}
+
+	if !s.policy.Enabled() {
+		return result, nil
+	}
+
+	// Se registra lo archivado aunque un lote posterior falle.
+	defer func() {
+		if result.Archived > 0 {
+			s.audit.Record(ctx, domain.AuditParkingArchive, domain.AuditEntityParkingRecord, result.Cutoff.Format(time.DateOnly), nil, result)
+		}
+	}()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		records, err := s.repo.ListExpired(ctx, result.Cutoff, s.policy.BatchSize)
+		if err != nil {
+			return result, err
+		}
+
+		if len(records) == 0 {
+			return result, nil
+		}
+
+		if err := s.archiveBatch(ctx, records); err != nil {
+			return result, err
+		}
+
+		result.Archived += len(records)
+		result.Batches++
+	}
+}
+
+// archiveBatch archiva un lote. En modo file el archivo se escribe antes de eliminar los
+// registros: si la transacción falla, el lote se vuelve a exportar en la siguiente ejecución y el
+// exportador reemplaza las copias anteriores por ID. Exportar después de confirmar perdería los
+// registros si la escritura del archivo fallara.
+func (s *service) archiveBatch(ctx context.Context, records []domain.ParkingRecord) error {
+	// Los totales se calculan con las placas originales; no dependen de ellas.
+	summaries := domain.SummarizeDaily(records)
+
+	if s.policy.PlateKey != "" {
+		for i := range records {
+			records[i].LicensePlate = domain.PseudonymizePlate(s.policy.PlateKey, records[i].LicensePlate)
+		}
+	}
+
+	keepRows := s.policy.Mode != domain.RetentionModeFile
+
+	if !keepRows {
+		if err := s.exporter.Export(ctx, records); err != nil {
+			return fmt.Errorf("error al exportar registros archivados: %w", err)
+		}
+	}
+
+	return s.repo.Archive(ctx, records, summaries, keepRows, s.now().UTC())
+}
+
+func (s *service) Start(ctx context.Context) {
+	if !s.policy.Enabled() {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.policy.Interval)
+		defer ticker.Stop()
+
+		for {
+			result, err := s.Run(ctx)
+
+			switch {
+			case errors.Is(err, context.Canceled):
+				return
+			case errors.Is(err, domain.ErrArchiveConflict):
+				slog.WarnContext(ctx, "no se pudieron archivar los registros de estacionamiento", "error", err)
+			case err != nil:
+				slog.WarnContext(ctx, "error al archivar registros de estacionamiento", "error", err)
+			}
+
+			if result != nil && result.Archived > 0 {
+				slog.InfoContext(ctx, "registros de estacionamiento archivados", "archived", result.Archived, "cutoff", result.Cutoff.Format(time.DateOnly))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,248 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// memoryRepo simula PARKING_RECORDS y los totales diarios. Archive aplica el lote completo o
+// ninguna parte, como la transacción de los repositorios reales.
+type memoryRepo struct {
+	Repository
+
+	records  []domain.ParkingRecord
+	archived []domain.ParkingRecord
+	totals   []domain.DailyParkingSummary
+	calls    int
+
+	// failOn hace fallar la llamada número failOn a Archive (desde 1).
+	failOn int
+}
+
+func (r *memoryRepo) ListExpired(ctx context.Context, cutoff time.Time, limit int) ([]domain.ParkingRecord, error) {
+	expired := []domain.ParkingRecord{}
+
+	for _, record := range r.records {
+		if record.ExitTime.Before(cutoff) && len(expired) < limit {
+			expired = append(expired, record)
+		}
+	}
+
+	return expired, nil
+}
+
+func (r *memoryRepo) Archive(ctx context.Context, records []domain.ParkingRecord, summaries []domain.DailyParkingSummary, keepRows bool, archivedAt time.Time) error {
+	r.calls++
+
+	if r.calls == r.failOn {
+		return errors.New("conexión perdida")
+	}
+
+	for _, record := range records {
+		if !slices.ContainsFunc(r.records, func(stored domain.ParkingRecord) bool { return stored.ID == record.ID }) {
+			return domain.ErrArchiveConflict
+		}
+	}
+
+	r.records = slices.DeleteFunc(r.records, func(stored domain.ParkingRecord) bool {
+		return slices.ContainsFunc(records, func(record domain.ParkingRecord) bool { return record.ID == stored.ID })
+	})
+
+	if keepRows {
+		r.archived = append(r.archived, records...)
+	}
+
+	r.totals = domain.MergeDailySummaries(r.totals, summaries)
+
+	return nil
+}
+
+// memoryExporter guarda una copia por ID, como el exportador de archivos.
+type memoryExporter struct {
+	exported map[string]domain.ParkingRecord
+	calls    int
+	err      error
+}
+
+func (e *memoryExporter) Export(ctx context.Context, records []domain.ParkingRecord) error {
+	e.calls++
+
+	if e.err != nil {
+		return e.err
+	}
+
+	for _, record := range records {
+		e.exported[record.ID] = record
+	}
+
+	return nil
+}
+
+type memoryRecorder struct {
+	actions []string
+}
+
+func (r *memoryRecorder) Record(_ context.Context, action, _, _ string, _, _ any) {
+	r.actions = append(r.actions, action)
+}
+
+var testNow = time.Date(2026, 6, 15, 10, 0, 0, 0, time.UTC)
+
+// closedRecords crea n registros cerrados hace más de un año, con una hora y 10 de cobro.
+func closedRecords(n int) []domain.ParkingRecord {
+	records := make([]domain.ParkingRecord, n)
+
+	for i := range records {
+		exit := testNow.AddDate(-1, 0, 0).Add(time.Duration(i) * time.Hour)
+		hours, charge := 1, 10.0
+
+		records[i] = domain.ParkingRecord{
+			ID:              string(rune('a' + i)),
+			VehicleTypeID:   "car",
+			LicensePlate:    "ABC12" + string(rune('0'+i)),
+			EntryTime:       exit.Add(-time.Hour),
+			ExitTime:        &exit,
+			CalculatedHours: &hours,
+			TotalCharge:     &charge,
+		}
+	}
+
+	return records
+}
+
+func newTestService(repo *memoryRepo, exporter *memoryExporter, recorder *memoryRecorder, mode string) *service {
+	policy := domain.RetentionPolicy{Months: 6, Mode: mode, BatchSize: 2, Interval: time.Hour}
+
+	svc := NewService(repo, exporter, recorder, policy).(*service)
+	svc.now = func() time.Time { return testNow }
+
+	return svc
+}
+
+func archivedTotal(summaries []domain.DailyParkingSummary) (records int, revenue float64) {
+	for _, s := range summaries {
+		records += s.Records
+		revenue += s.Revenue
+	}
+
+	return records, revenue
+}
+
+func TestRunResumesAfterFailedBatch(t *testing.T) {
+	repo := &memoryRepo{records: closedRecords(5), failOn: 2}
+	recorder := &memoryRecorder{}
+	svc := newTestService(repo, nil, recorder, domain.RetentionModeTable)
+
+	result, err := svc.Run(context.Background())
+	if err == nil {
+		t.Fatal("Run() con un lote fallido no retornó error")
+	}
+
+	if result.Archived != 2 || result.Batches != 1 {
+		t.Errorf("Run() = %d registros en %d lotes, se esperaban 2 en 1", result.Archived, result.Batches)
+	}
+
+	if len(recorder.actions) != 1 {
+		t.Errorf("auditoría tras el fallo = %v, se esperaba registrar el lote confirmado", recorder.actions)
+	}
+
+	result, err = svc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() al reanudar = %v", err)
+	}
+
+	if result.Archived != 3 || result.Batches != 2 {
+		t.Errorf("Run() al reanudar = %d registros en %d lotes, se esperaban 3 en 2", result.Archived, result.Batches)
+	}
+
+	if len(repo.records) != 0 || len(repo.archived) != 5 {
+		t.Errorf("quedaron %d registros en línea y %d archivados, se esperaban 0 y 5", len(repo.records), len(repo.archived))
+	}
+
+	if records, revenue := archivedTotal(repo.totals); records != 5 || revenue != 50 {
+		t.Errorf("totales diarios = %d registros y %v de ingresos, se esperaban 5 y 50", records, revenue)
+	}
+}
+
+func TestRunStopsOnArchiveConflict(t *testing.T) {
+	records := closedRecords(3)
+
+	// Un registro del primer lote desaparece entre ListExpired y Archive, como si otra ejecución lo
+	// hubiese archivado.
+	repo := &conflictRepo{memoryRepo: &memoryRepo{records: records}, vanish: records[1].ID}
+	recorder := &memoryRecorder{}
+
+	svc := NewService(repo, nil, recorder, domain.RetentionPolicy{Months: 6, BatchSize: 2}).(*service)
+	svc.now = func() time.Time { return testNow }
+
+	result, err := svc.Run(context.Background())
+	if !errors.Is(err, domain.ErrArchiveConflict) {
+		t.Fatalf("Run() = %v, se esperaba %v", err, domain.ErrArchiveConflict)
+	}
+
+	if result.Archived != 0 || len(recorder.actions) != 0 {
+		t.Errorf("Run() = %d archivados y auditoría %v, no se esperaba contar el lote revertido", result.Archived, recorder.actions)
+	}
+
+	if len(repo.records) != 2 || len(repo.totals) != 0 {
+		t.Errorf("tras el conflicto quedaron %d registros y %d totales, se esperaban 2 y 0", len(repo.records), len(repo.totals))
+	}
+}
+
+// conflictRepo elimina un registro justo antes de archivar.
+type conflictRepo struct {
+	*memoryRepo
+	vanish string
+}
+
+func (r *conflictRepo) Archive(ctx context.Context, records []domain.ParkingRecord, summaries []domain.DailyParkingSummary, keepRows bool, archivedAt time.Time) error {
+	r.memoryRepo.records = slices.DeleteFunc(r.memoryRepo.records, func(stored domain.ParkingRecord) bool {
+		return stored.ID == r.vanish
+	})
+
+	return r.memoryRepo.Archive(ctx, records, summaries, keepRows, archivedAt)
+}
+
+func TestRunFileMode(t *testing.T) {
+	t.Run("Exporta antes de eliminar y reintenta sin duplicar", func(t *testing.T) {
+		repo := &memoryRepo{records: closedRecords(3), failOn: 1}
+		exporter := &memoryExporter{exported: map[string]domain.ParkingRecord{}}
+		svc := newTestService(repo, exporter, &memoryRecorder{}, domain.RetentionModeFile)
+
+		if _, err := svc.Run(context.Background()); err == nil {
+			t.Fatal("Run() con el primer lote fallido no retornó error")
+		}
+
+		if len(exporter.exported) != 2 || len(repo.records) != 3 {
+			t.Fatalf("tras el fallo hay %d exportados y %d en línea, se esperaban 2 y 3", len(exporter.exported), len(repo.records))
+		}
+
+		if _, err := svc.Run(context.Background()); err != nil {
+			t.Fatalf("Run() al reanudar = %v", err)
+		}
+
+		if len(exporter.exported) != 3 || len(repo.records) != 0 || len(repo.archived) != 0 {
+			t.Errorf("tras reanudar hay %d exportados, %d en línea y %d en la tabla de archivo, se esperaban 3, 0 y 0",
+				len(exporter.exported), len(repo.records), len(repo.archived))
+		}
+	})
+
+	t.Run("Un error al exportar conserva los registros", func(t *testing.T) {
+		repo := &memoryRepo{records: closedRecords(3)}
+		exporter := &memoryExporter{err: errors.New("disco lleno")}
+		svc := newTestService(repo, exporter, &memoryRecorder{}, domain.RetentionModeFile)
+
+		if _, err := svc.Run(context.Background()); err == nil {
+			t.Fatal("Run() con el exportador fallido no retornó error")
+		}
+
+		if repo.calls != 0 || len(repo.records) != 3 {
+			t.Errorf("Archive() llamado %d veces con %d registros en línea, se esperaban 0 y 3", repo.calls, len(repo.records))
+		}
+	})
+}
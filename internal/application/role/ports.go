@@ -0,0 +1,44 @@
+package role
+
+import (
+	"context"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type Service interface {
+	// ListAll lista todos los roles con sus permisos.
+	ListAll(ctx context.Context) ([]domain.RoleDefinition, error)
+
+	// Create crea un rol personalizado con el conjunto de permisos indicado.
+	Create(ctx context.Context, role *domain.RoleDefinition) (*domain.RoleDefinition, error)
+
+	// Update reemplaza la descripción y los permisos de un rol.
+	Update(ctx context.Context, name domain.Role, role *domain.RoleDefinition) (*domain.RoleDefinition, error)
+
+	// Delete elimina un rol personalizado que no esté asignado a ningún usuario.
+	Delete(ctx context.Context, name domain.Role) error
+
+	// HasPermissions indica si el rol concede todos los permisos indicados.
+	HasPermissions(ctx context.Context, name domain.Role, permissions ...domain.Permission) (bool, error)
+}
+
+type Repository interface {
+	// ListAll lista todos los roles con sus permisos, ordenados por nombre.
+	ListAll(ctx context.Context) ([]domain.RoleDefinition, error)
+
+	// FindByName busca un rol por su nombre.
+	FindByName(ctx context.Context, name domain.Role) (*domain.RoleDefinition, error)
+
+	// Create registra un rol y sus permisos.
+	Create(ctx context.Context, role *domain.RoleDefinition) error
+
+	// Update reemplaza la descripción y los permisos de un rol.
+	Update(ctx context.Context, role *domain.RoleDefinition) error
+
+	// Delete elimina un rol y sus permisos.
+	Delete(ctx context.Context, name domain.Role) error
+
+	// IsInUse indica si el rol está asignado a algún usuario.
+	IsInUse(ctx context.Context, name domain.Role) (bool, error)
+}
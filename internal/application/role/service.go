@@ -0,0 +1,239 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/JGCaceres97/parking/internal/application/audit"
+	"github.com/JGCaceres97/parking/internal/application/transaction"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+var tracer = otel.Tracer("github.com/JGCaceres97/parking/internal/application/role")
+
+// cacheTTL es el tiempo que se conservan en memoria los permisos de un rol. Los cambios hechos
+// desde esta instancia invalidan la caché de inmediato.
+const cacheTTL = 30 * time.Second
+
+type cachedRole struct {
+	role     *domain.RoleDefinition
+	loadedAt time.Time
+}
+
+type service struct {
+	uow   transaction.UnitOfWork
+	repo  Repository
+	audit audit.TxRecorder
+
+	mu    sync.RWMutex
+	cache map[domain.Role]cachedRole
+}
+
+// NewService crea el servicio de roles. Cada cambio se guarda junto con su entrada de auditoría en
+// una sola transacción.
+func NewService(uow transaction.UnitOfWork, repo Repository, audit audit.TxRecorder) Service {
+	return &service{
+		uow:   uow,
+		repo:  repo,
+		audit: audit,
+		cache: make(map[domain.Role]cachedRole),
+	}
+}
+
+func (s *service) ListAll(ctx context.Context) ([]domain.RoleDefinition, error) {
+	ctx, span := tracer.Start(ctx, "role.ListAll")
+	defer span.End()
+
+	return s.repo.ListAll(ctx)
+}
+
+func (s *service) Create(ctx context.Context, role *domain.RoleDefinition) (*domain.RoleDefinition, error) {
+	ctx, span := tracer.Start(ctx, "role.Create")
+	defer span.End()
+
+	if !domain.RoleNamePattern.MatchString(role.Name) {
+		return nil, domain.ErrInvalidRoleName
+	}
+
+	permissions, err := normalizePermissions(role.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.FindByName(ctx, role.Name); err == nil {
+		return nil, domain.ErrRoleAlreadyExists
+	} else if !errors.Is(err, domain.ErrRoleNotFound) {
+		return nil, fmt.Errorf("error al verificar existencia del rol: %w", err)
+	}
+
+	role.Permissions = permissions
+	role.IsSystem = false
+	role.CreatedAt = time.Now().UTC().Truncate(time.Second)
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, role); err != nil {
+			return fmt.Errorf("error al guardar el rol: %w", err)
+		}
+
+		return s.audit.RecordTx(ctx, domain.AuditRoleCreate, domain.AuditEntityRole, role.Name, nil, role)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+func (s *service) Update(ctx context.Context, name domain.Role, roleUpdated *domain.RoleDefinition) (*domain.RoleDefinition, error) {
+	ctx, span := tracer.Start(ctx, "role.Update")
+	defer span.End()
+
+	if name == domain.RoleAdmin {
+		return nil, domain.ErrRoleProtected
+	}
+
+	existing, err := s.repo.FindByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := normalizePermissions(roleUpdated.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *existing
+
+	existing.Description = roleUpdated.Description
+	existing.Permissions = permissions
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, existing); err != nil {
+			return fmt.Errorf("error al actualizar el rol: %w", err)
+		}
+
+		return s.audit.RecordTx(ctx, domain.AuditRoleUpdate, domain.AuditEntityRole, name, before, existing)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate(name)
+
+	return existing, nil
+}
+
+func (s *service) Delete(ctx context.Context, name domain.Role) error {
+	ctx, span := tracer.Start(ctx, "role.Delete")
+	defer span.End()
+
+	existing, err := s.repo.FindByName(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if existing.IsSystem {
+		return domain.ErrRoleProtected
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		inUse, err := s.repo.IsInUse(ctx, name)
+		if err != nil {
+			return fmt.Errorf("error al verificar uso del rol: %w", err)
+		}
+
+		if inUse {
+			return domain.ErrRoleInUse
+		}
+
+		if err := s.repo.Delete(ctx, name); err != nil {
+			return fmt.Errorf("error al eliminar el rol: %w", err)
+		}
+
+		return s.audit.RecordTx(ctx, domain.AuditRoleDelete, domain.AuditEntityRole, name, existing, nil)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	s.invalidate(name)
+
+	return nil
+}
+
+func (s *service) HasPermissions(ctx context.Context, name domain.Role, permissions ...domain.Permission) (bool, error) {
+	ctx, span := tracer.Start(ctx, "role.HasPermissions")
+	defer span.End()
+
+	role, err := s.load(ctx, name)
+	if err != nil {
+		if errors.Is(err, domain.ErrRoleNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	for _, p := range permissions {
+		if !role.Has(p) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// load obtiene el rol desde la caché o, si expiró, desde el repositorio.
+func (s *service) load(ctx context.Context, name domain.Role) (*domain.RoleDefinition, error) {
+	s.mu.RLock()
+	cached, ok := s.cache[name]
+	s.mu.RUnlock()
+
+	if ok && time.Since(cached.loadedAt) < cacheTTL {
+		return cached.role, nil
+	}
+
+	role, err := s.repo.FindByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[name] = cachedRole{role: role, loadedAt: time.Now()}
+	s.mu.Unlock()
+
+	return role, nil
+}
+
+func (s *service) invalidate(name domain.Role) {
+	s.mu.Lock()
+	delete(s.cache, name)
+	s.mu.Unlock()
+}
+
+// normalizePermissions valida los permisos contra el catálogo y elimina duplicados.
+func normalizePermissions(permissions []domain.Permission) ([]domain.Permission, error) {
+	result := make([]domain.Permission, 0, len(permissions))
+
+	for _, p := range permissions {
+		if !domain.IsValidPermission(p) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrInvalidPermission, p)
+		}
+
+		if !slices.Contains(result, p) {
+			result = append(result, p)
+		}
+	}
+
+	slices.Sort(result)
+	return result, nil
+}
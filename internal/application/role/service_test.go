@@ -0,0 +1,265 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// memoryRepository guarda los roles en memoria y cuenta las lecturas para verificar la caché. Los
+// métodos no utilizados en las pruebas provienen de la interfaz embebida y no deben invocarse.
+type memoryRepository struct {
+	Repository
+	roles map[domain.Role]domain.RoleDefinition
+	inUse map[domain.Role]bool
+	reads int
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{
+		roles: map[domain.Role]domain.RoleDefinition{
+			domain.RoleAdmin:   {Name: domain.RoleAdmin, Permissions: domain.Permissions, IsSystem: true},
+			domain.RoleCashier: {Name: domain.RoleCashier, Permissions: []domain.Permission{domain.PermParkingRead, domain.PermParkingWrite}, IsSystem: true},
+			"gestor":           {Name: "gestor", Permissions: []domain.Permission{domain.PermUsersRead}},
+		},
+		inUse: map[domain.Role]bool{},
+	}
+}
+
+func (r *memoryRepository) FindByName(_ context.Context, name domain.Role) (*domain.RoleDefinition, error) {
+	r.reads++
+
+	role, ok := r.roles[name]
+	if !ok {
+		return nil, domain.ErrRoleNotFound
+	}
+
+	role.Permissions = slices.Clone(role.Permissions)
+	return &role, nil
+}
+
+func (r *memoryRepository) Create(_ context.Context, role *domain.RoleDefinition) error {
+	r.roles[role.Name] = *role
+	return nil
+}
+
+func (r *memoryRepository) Update(_ context.Context, role *domain.RoleDefinition) error {
+	r.roles[role.Name] = *role
+	return nil
+}
+
+func (r *memoryRepository) Delete(_ context.Context, name domain.Role) error {
+	delete(r.roles, name)
+	return nil
+}
+
+func (r *memoryRepository) IsInUse(_ context.Context, name domain.Role) (bool, error) {
+	return r.inUse[name], nil
+}
+
+type memoryRecorder struct {
+	actions []string
+}
+
+func (r *memoryRecorder) RecordTx(_ context.Context, action, _, _ string, _, _ any) error {
+	r.actions = append(r.actions, action)
+	return nil
+}
+
+// directUoW ejecuta fn sin transacción, ya que los repositorios en memoria no la necesitan.
+type directUoW struct{}
+
+func (directUoW) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name string
+		role domain.RoleDefinition
+		want error
+	}{
+		{
+			name: "nombre inválido",
+			role: domain.RoleDefinition{Name: "Gestor Nocturno"},
+			want: domain.ErrInvalidRoleName,
+		},
+		{
+			name: "permiso inexistente",
+			role: domain.RoleDefinition{Name: "nocturno", Permissions: []domain.Permission{"parking:delete"}},
+			want: domain.ErrInvalidPermission,
+		},
+		{
+			name: "nombre repetido",
+			role: domain.RoleDefinition{Name: "gestor"},
+			want: domain.ErrRoleAlreadyExists,
+		},
+		{
+			name: "rol válido",
+			role: domain.RoleDefinition{Name: "nocturno", Permissions: []domain.Permission{domain.PermParkingWrite, domain.PermParkingRead, domain.PermParkingWrite}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			recorder := &memoryRecorder{}
+			svc := NewService(directUoW{}, repo, recorder)
+
+			role := tt.role
+			role.IsSystem = true
+
+			created, err := svc.Create(context.Background(), &role)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Create() = %v, se esperaba %v", err, tt.want)
+			}
+
+			if tt.want != nil {
+				if len(recorder.actions) != 0 {
+					t.Errorf("auditoría = %v, no se esperaba ningún registro", recorder.actions)
+				}
+
+				return
+			}
+
+			// Los permisos se ordenan sin duplicados y el rol nunca se crea como del sistema.
+			want := []domain.Permission{domain.PermParkingRead, domain.PermParkingWrite}
+			if !slices.Equal(created.Permissions, want) || created.IsSystem {
+				t.Errorf("Create() = %+v, se esperaban los permisos %v y un rol personalizado", created, want)
+			}
+
+			if _, ok := repo.roles[role.Name]; !ok {
+				t.Error("el rol no se guardó en el repositorio")
+			}
+
+			if !slices.Equal(recorder.actions, []string{domain.AuditRoleCreate}) {
+				t.Errorf("auditoría = %v, se esperaba %v", recorder.actions, domain.AuditRoleCreate)
+			}
+		})
+	}
+}
+
+func TestUpdateAndDelete(t *testing.T) {
+	tests := []struct {
+		name   string
+		inUse  bool
+		action func(s Service) error
+		want   error
+	}{
+		{
+			name: "actualizar el rol admin",
+			action: func(s Service) error {
+				_, err := s.Update(context.Background(), domain.RoleAdmin, &domain.RoleDefinition{})
+				return err
+			},
+			want: domain.ErrRoleProtected,
+		},
+		{
+			name: "actualizar un rol inexistente",
+			action: func(s Service) error {
+				_, err := s.Update(context.Background(), "fantasma", &domain.RoleDefinition{})
+				return err
+			},
+			want: domain.ErrRoleNotFound,
+		},
+		{
+			name: "actualizar con un permiso inexistente",
+			action: func(s Service) error {
+				_, err := s.Update(context.Background(), "gestor", &domain.RoleDefinition{Permissions: []domain.Permission{"parking:delete"}})
+				return err
+			},
+			want: domain.ErrInvalidPermission,
+		},
+		{
+			name: "actualizar un rol del sistema",
+			action: func(s Service) error {
+				_, err := s.Update(context.Background(), domain.RoleCashier, &domain.RoleDefinition{Permissions: []domain.Permission{domain.PermParkingRead}})
+				return err
+			},
+		},
+		{
+			name: "eliminar un rol del sistema",
+			action: func(s Service) error {
+				return s.Delete(context.Background(), domain.RoleCashier)
+			},
+			want: domain.ErrRoleProtected,
+		},
+		{
+			name:  "eliminar un rol asignado",
+			inUse: true,
+			action: func(s Service) error {
+				return s.Delete(context.Background(), "gestor")
+			},
+			want: domain.ErrRoleInUse,
+		},
+		{
+			name: "eliminar un rol personalizado",
+			action: func(s Service) error {
+				return s.Delete(context.Background(), "gestor")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			repo.inUse["gestor"] = tt.inUse
+
+			if err := tt.action(NewService(directUoW{}, repo, &memoryRecorder{})); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, se esperaba %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasPermissions(t *testing.T) {
+	repo := newMemoryRepository()
+	svc := NewService(directUoW{}, repo, &memoryRecorder{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		role        domain.Role
+		permissions []domain.Permission
+		want        bool
+	}{
+		{name: "todos los permisos", role: domain.RoleCashier, permissions: []domain.Permission{domain.PermParkingRead, domain.PermParkingWrite}, want: true},
+		{name: "falta un permiso", role: domain.RoleCashier, permissions: []domain.Permission{domain.PermParkingRead, domain.PermParkingVoid}},
+		{name: "rol inexistente", role: "fantasma", permissions: []domain.Permission{domain.PermParkingRead}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.HasPermissions(ctx, tt.role, tt.permissions...)
+			if err != nil {
+				t.Fatalf("HasPermissions() = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("HasPermissions() = %v, se esperaba %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("caché invalidada al actualizar", func(t *testing.T) {
+		if ok, _ := svc.HasPermissions(ctx, "gestor", domain.PermReportsRead); ok {
+			t.Fatal("HasPermissions() = true antes de conceder el permiso")
+		}
+
+		reads := repo.reads
+		if _, err := svc.HasPermissions(ctx, "gestor", domain.PermUsersRead); err != nil || repo.reads != reads {
+			t.Fatalf("HasPermissions() consultó el repositorio %d veces, se esperaba usar la caché", repo.reads-reads)
+		}
+
+		if _, err := svc.Update(ctx, "gestor", &domain.RoleDefinition{Permissions: []domain.Permission{domain.PermReportsRead}}); err != nil {
+			t.Fatalf("Update() = %v", err)
+		}
+
+		if ok, _ := svc.HasPermissions(ctx, "gestor", domain.PermReportsRead); !ok {
+			t.Error("HasPermissions() = false tras conceder el permiso, se esperaba la caché invalidada")
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package sso
+
+import (
+	"context"
+
+	"github.com/JGCaceres97/parking/internal/application/auth"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type Service interface {
+	// Enabled indica si hay un proveedor de identidad configurado.
+	Enabled() bool
+
+	// Begin inicia el flujo authorization code + PKCE y retorna la URL del proveedor a la que
+	// debe redirigirse al usuario y el state, que debe quedar ligado al navegador que inició el
+	// flujo.
+	Begin(ctx context.Context) (authURL string, state string, err error)
+
+	// Complete canjea el código recibido en el callback, vincula o crea el usuario local y emite
+	// el token JWT propio del sistema. boundState es el state ligado al navegador en Begin; si no
+	// coincide con state, el callback no fue iniciado por ese navegador y se rechaza.
+	Complete(ctx context.Context, state, boundState, code, ip string) (*auth.LoginOutput, error)
+
+	// CheckRoles verifica que existan los roles asignados a los grupos y el rol por defecto. Se
+	// invoca al iniciar para no aprovisionar usuarios con un rol inexistente.
+	CheckRoles(ctx context.Context) error
+
+	// Link vincula el subject del proveedor con un usuario local existente, para cuentas creadas
+	// antes de habilitar el SSO. Retorna domain.ErrIdentityAlreadyLinked si el subject ya está
+	// vinculado y domain.ErrRoleNotGrantable si el usuario actual no tiene los permisos del rol de
+	// la cuenta.
+	Link(ctx context.Context, userID, subject, email string) (*domain.UserIdentity, error)
+}
+
+// Provider abstrae al proveedor de identidad OIDC.
+type Provider interface {
+	// AuthCodeURL construye la URL de autorización con el state, el nonce y el desafío PKCE
+	// derivado del verifier.
+	AuthCodeURL(state, nonce, verifier string) string
+
+	// Exchange canjea el código de autorización, verifica el token de identidad (firma, audiencia
+	// y nonce) y retorna sus claims.
+	Exchange(ctx context.Context, code, verifier, nonce string) (*domain.ExternalIdentity, error)
+}
+
+// PendingLogin conserva los datos de un flujo iniciado hasta que el proveedor redirige de vuelta.
+type PendingLogin struct {
+	Nonce    string
+	Verifier string
+}
+
+type StateStore interface {
+	// Save guarda el flujo pendiente asociado al state. Retorna domain.ErrSSOBusy si hay
+	// demasiados flujos pendientes.
+	Save(state string, pending PendingLogin) error
+
+	// Consume obtiene y elimina el flujo pendiente; cada state solo puede usarse una vez.
+	Consume(state string) (PendingLogin, bool)
+}
+
+type IdentityRepository interface {
+	// FindBySubject busca la identidad externa por emisor y subject.
+	FindBySubject(ctx context.Context, issuer, subject string) (*domain.UserIdentity, error)
+
+	// Create vincula una identidad externa con un usuario local.
+	Create(ctx context.Context, identity *domain.UserIdentity) error
+}
@@ -0,0 +1,407 @@
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/oauth2"
+
+	"github.com/JGCaceres97/parking/internal/application/audit"
+	"github.com/JGCaceres97/parking/internal/application/auth"
+	"github.com/JGCaceres97/parking/internal/application/role"
+	"github.com/JGCaceres97/parking/internal/application/transaction"
+	"github.com/JGCaceres97/parking/internal/application/user"
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/ulid"
+)
+
+var tracer = otel.Tracer("github.com/JGCaceres97/parking/internal/application/sso")
+
+type service struct {
+	uow         transaction.UnitOfWork
+	provider    Provider
+	issuer      string
+	states      StateStore
+	identities  IdentityRepository
+	userRepo    user.Repository
+	roleRepo    role.Repository
+	auth        auth.Service
+	audit       audit.TxRecorder
+	groupRoles  []domain.GroupRoleMapping
+	defaultRole domain.Role
+}
+
+// NewService crea el servicio de inicio de sesión único. Si provider es nil, el SSO queda
+// deshabilitado. issuer es el emisor de los tokens del proveedor, con el que se vinculan las
+// identidades desde la administración. groupRoles se evalúa en orden: el primer grupo del usuario
+// con un rol asignado determina su rol; si ninguno coincide se usa defaultRole, y si está vacío se
+// rechaza el acceso. Los cambios en las cuentas se guardan junto con su entrada de auditoría en una
+// sola transacción.
+func NewService(
+	uow transaction.UnitOfWork,
+	provider Provider,
+	issuer string,
+	states StateStore,
+	identities IdentityRepository,
+	userRepo user.Repository,
+	roleRepo role.Repository,
+	auth auth.Service,
+	audit audit.TxRecorder,
+	groupRoles []domain.GroupRoleMapping,
+	defaultRole domain.Role,
+) Service {
+	return &service{
+		uow:         uow,
+		provider:    provider,
+		issuer:      issuer,
+		states:      states,
+		identities:  identities,
+		userRepo:    userRepo,
+		roleRepo:    roleRepo,
+		auth:        auth,
+		audit:       audit,
+		groupRoles:  groupRoles,
+		defaultRole: defaultRole,
+	}
+}
+
+func (s *service) Enabled() bool {
+	return s.provider != nil
+}
+
+func (s *service) CheckRoles(ctx context.Context) error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	roles := []domain.Role{}
+	for _, mapping := range s.groupRoles {
+		roles = append(roles, mapping.Role)
+	}
+
+	if s.defaultRole != "" {
+		roles = append(roles, s.defaultRole)
+	}
+
+	for _, name := range roles {
+		if _, err := s.roleRepo.FindByName(ctx, name); err != nil {
+			if errors.Is(err, domain.ErrRoleNotFound) {
+				return fmt.Errorf("%w: %q", domain.ErrRoleNotFound, name)
+			}
+
+			return fmt.Errorf("error al verificar rol: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) Begin(ctx context.Context) (string, string, error) {
+	ctx, span := tracer.Start(ctx, "sso.Begin")
+	defer span.End()
+
+	if !s.Enabled() {
+		return "", "", domain.ErrSSODisabled
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	nonce, err := randomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	verifier := oauth2.GenerateVerifier()
+	if err := s.states.Save(state, PendingLogin{Nonce: nonce, Verifier: verifier}); err != nil {
+		return "", "", err
+	}
+
+	return s.provider.AuthCodeURL(state, nonce, verifier), state, nil
+}
+
+func (s *service) Complete(ctx context.Context, state, boundState, code, ip string) (*auth.LoginOutput, error) {
+	ctx, span := tracer.Start(ctx, "sso.Complete")
+	defer span.End()
+
+	if !s.Enabled() {
+		return nil, domain.ErrSSODisabled
+	}
+
+	// Sin esta verificación, un atacante podría completar su propio inicio de sesión y enviar el
+	// callback a la víctima, que quedaría autenticada con la cuenta del atacante.
+	if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(boundState)) != 1 {
+		return nil, domain.ErrSSOStateInvalid
+	}
+
+	pending, ok := s.states.Consume(state)
+	if !ok {
+		return nil, domain.ErrSSOStateInvalid
+	}
+
+	identity, err := s.provider.Exchange(ctx, code, pending.Verifier, pending.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.resolveRole(ctx, identity.Groups)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.findOrProvision(ctx, identity, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.LockedAt != nil {
+		return nil, domain.ErrUserLocked
+	}
+
+	if !account.IsActive {
+		return nil, domain.ErrUserInactive
+	}
+
+	return s.auth.IssueToken(ctx, account, ip)
+}
+
+// findOrProvision obtiene el usuario vinculado a la identidad externa o lo crea. El rol se
+// sincroniza en cada inicio de sesión, ya que el proveedor es la fuente de verdad de los grupos.
+func (s *service) findOrProvision(ctx context.Context, identity *domain.ExternalIdentity, role domain.Role) (*domain.User, error) {
+	linked, err := s.identities.FindBySubject(ctx, identity.Issuer, identity.Subject)
+	if err == nil {
+		account, err := s.userRepo.FindByID(ctx, linked.UserID)
+		if err != nil {
+			// La cuenta vinculada fue eliminada: no se aprovisiona una nueva.
+			if errors.Is(err, domain.ErrUserNotFound) {
+				return nil, domain.ErrUserInactive
+			}
+
+			return nil, fmt.Errorf("error al buscar usuario vinculado: %w", err)
+		}
+
+		if account.Role != role && account.Username != domain.AdminUsername {
+			before := *account
+
+			account.Role = role
+
+			err := s.uow.Do(ctx, func(ctx context.Context) error {
+				if err := s.userRepo.Update(ctx, account); err != nil {
+					return fmt.Errorf("error al sincronizar rol del usuario: %w", err)
+				}
+
+				return s.audit.RecordTx(ctx, domain.AuditUserRoleSync, domain.AuditEntityUser, account.ID, before.AuditSnapshot(), account.AuditSnapshot())
+			})
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return account, nil
+	}
+
+	if !errors.Is(err, domain.ErrIdentityNotFound) {
+		return nil, fmt.Errorf("error al buscar identidad externa: %w", err)
+	}
+
+	username := usernameFor(identity)
+	if s.userRepo.ExistsUsername(ctx, username) {
+		// No se vincula automáticamente con una cuenta local existente por nombre de usuario,
+		// ya que permitiría apropiarse de ella desde el proveedor. Un administrador puede
+		// vincularla con Link.
+		return nil, domain.ErrUsernameAlreadyExists
+	}
+
+	// Los usuarios aprovisionados no tienen contraseña local utilizable.
+	password, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("error al hashear contraseña: %w", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	account := &domain.User{
+		ID:        ulid.GenerateNewULID(),
+		Username:  username,
+		Password:  string(hashedPassword),
+		Role:      role,
+		IsActive:  true,
+		CreatedAt: now,
+	}
+
+	link := &domain.UserIdentity{
+		ID:        ulid.GenerateNewULID(),
+		UserID:    account.ID,
+		Issuer:    identity.Issuer,
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+		CreatedAt: now,
+	}
+
+	// Si la vinculación falla, el usuario no debe quedar aprovisionado: un nuevo intento no podría
+	// vincularlo, ya que su nombre de usuario estaría ocupado.
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Create(ctx, account); err != nil {
+			return fmt.Errorf("error al aprovisionar usuario: %w", err)
+		}
+
+		if err := s.identities.Create(ctx, link); err != nil {
+			return fmt.Errorf("error al vincular identidad externa: %w", err)
+		}
+
+		return s.audit.RecordTx(ctx, domain.AuditUserProvision, domain.AuditEntityUser, account.ID, nil, account.AuditSnapshot())
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (s *service) resolveRole(ctx context.Context, groups []string) (domain.Role, error) {
+	role := s.defaultRole
+
+	for _, mapping := range s.groupRoles {
+		if slices.Contains(groups, mapping.Group) {
+			role = mapping.Role
+			break
+		}
+	}
+
+	if role == "" {
+		return "", domain.ErrSSONoRole
+	}
+
+	// CheckRoles valida las asignaciones al iniciar, pero el rol pudo eliminarse después.
+	if _, err := s.roleRepo.FindByName(ctx, role); err != nil {
+		if errors.Is(err, domain.ErrRoleNotFound) {
+			return "", fmt.Errorf("%w: el rol %q asignado no existe", domain.ErrSSONoRole, role)
+		}
+
+		return "", fmt.Errorf("error al verificar rol: %w", err)
+	}
+
+	return role, nil
+}
+
+func (s *service) Link(ctx context.Context, userID, subject, email string) (*domain.UserIdentity, error) {
+	ctx, span := tracer.Start(ctx, "sso.Link")
+	defer span.End()
+
+	if !s.Enabled() {
+		return nil, domain.ErrSSODisabled
+	}
+
+	account, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkGrant(ctx, account.Role); err != nil {
+		return nil, err
+	}
+
+	link := &domain.UserIdentity{
+		ID:        ulid.GenerateNewULID(),
+		UserID:    account.ID,
+		Issuer:    s.issuer,
+		Subject:   strings.TrimSpace(subject),
+		Email:     strings.TrimSpace(email),
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+
+	// El correo se omite de la auditoría, al igual que el nombre de usuario.
+	snapshot := *link
+	snapshot.Email = ""
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.identities.Create(ctx, link); err != nil {
+			if errors.Is(err, domain.ErrIdentityAlreadyLinked) {
+				return err
+			}
+
+			return fmt.Errorf("error al vincular identidad externa: %w", err)
+		}
+
+		return s.audit.RecordTx(ctx, domain.AuditUserIdentityLink, domain.AuditEntityUser, account.ID, nil, snapshot)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// checkGrant verifica que el usuario que realiza la acción tenga todos los permisos de role, ya
+// que la identidad vinculada da acceso a la cuenta hasta el próximo inicio de sesión, en el que se
+// sincroniza su rol (el del usuario admin nunca se sincroniza). Las acciones sin usuario
+// autenticado no se restringen.
+func (s *service) checkGrant(ctx context.Context, role domain.Role) error {
+	actorID := audit.ActorFromContext(ctx).UserID
+	if actorID == "" {
+		return nil
+	}
+
+	actor, err := s.userRepo.FindByID(ctx, actorID)
+	if err != nil {
+		return fmt.Errorf("error al obtener el usuario actual: %w", err)
+	}
+
+	granted, err := s.roleRepo.FindByName(ctx, actor.Role)
+	if err != nil {
+		return fmt.Errorf("error al obtener el rol del usuario actual: %w", err)
+	}
+
+	target, err := s.roleRepo.FindByName(ctx, role)
+	if err != nil {
+		return fmt.Errorf("error al verificar rol: %w", err)
+	}
+
+	for _, p := range target.Permissions {
+		if !granted.Has(p) {
+			return domain.ErrRoleNotGrantable
+		}
+	}
+
+	return nil
+}
+
+// usernameFor deriva el nombre de usuario local a partir de los claims del proveedor.
+func usernameFor(identity *domain.ExternalIdentity) string {
+	switch {
+	case identity.PreferredUsername != "":
+		return strings.ToLower(identity.PreferredUsername)
+	case identity.Email != "":
+		return strings.ToLower(identity.Email)
+	default:
+		return "sso-" + strings.ToLower(identity.Subject)
+	}
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("error al generar valor aleatorio: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,400 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/audit"
+	"github.com/JGCaceres97/parking/internal/application/role"
+	"github.com/JGCaceres97/parking/internal/application/user"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// fakeProvider retorna identity al canjear el código o, si es nil, rechaza el inicio de sesión.
+type fakeProvider struct {
+	exchanged bool
+	identity  *domain.ExternalIdentity
+}
+
+func (p *fakeProvider) AuthCodeURL(state, nonce, verifier string) string {
+	return "https://idp.example/authorize?state=" + state
+}
+
+func (p *fakeProvider) Exchange(ctx context.Context, code, verifier, nonce string) (*domain.ExternalIdentity, error) {
+	p.exchanged = true
+
+	if p.identity == nil {
+		return nil, domain.ErrSSOLoginFailed
+	}
+
+	return p.identity, nil
+}
+
+// memoryRoleRepo resuelve los roles por nombre. Los métodos no utilizados por el servicio
+// provienen de la interfaz embebida y no deben invocarse.
+type memoryRoleRepo struct {
+	role.Repository
+	roles map[domain.Role]domain.RoleDefinition
+}
+
+func (r *memoryRoleRepo) FindByName(_ context.Context, name domain.Role) (*domain.RoleDefinition, error) {
+	role, ok := r.roles[name]
+	if !ok {
+		return nil, domain.ErrRoleNotFound
+	}
+
+	return &role, nil
+}
+
+// fakeUserRepo resuelve los usuarios por ID. Los métodos no utilizados por el servicio provienen
+// de la interfaz embebida y no deben invocarse.
+type fakeUserRepo struct {
+	user.Repository
+	users map[string]domain.User
+}
+
+func (r *fakeUserRepo) FindByID(_ context.Context, id string) (*domain.User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+
+	return &u, nil
+}
+
+func (r *fakeUserRepo) ExistsUsername(_ context.Context, username string) bool {
+	return slices.ContainsFunc(slices.Collect(maps.Values(r.users)), func(u domain.User) bool { return u.Username == username })
+}
+
+func (r *fakeUserRepo) Create(_ context.Context, u *domain.User) error {
+	r.users[u.ID] = *u
+	return nil
+}
+
+// memoryIdentityRepo guarda las identidades vinculadas por emisor y subject. Si err no es nil,
+// Create falla con ese error.
+type memoryIdentityRepo struct {
+	identities map[string]domain.UserIdentity
+	err        error
+}
+
+func (r *memoryIdentityRepo) FindBySubject(_ context.Context, issuer, subject string) (*domain.UserIdentity, error) {
+	identity, ok := r.identities[issuer+"|"+subject]
+	if !ok {
+		return nil, domain.ErrIdentityNotFound
+	}
+
+	return &identity, nil
+}
+
+func (r *memoryIdentityRepo) Create(_ context.Context, identity *domain.UserIdentity) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	key := identity.Issuer + "|" + identity.Subject
+	if _, ok := r.identities[key]; ok {
+		return domain.ErrIdentityAlreadyLinked
+	}
+
+	r.identities[key] = *identity
+	return nil
+}
+
+type memoryRecorder struct {
+	actions []string
+	err     error
+}
+
+func (r *memoryRecorder) RecordTx(_ context.Context, action, _, _ string, _, _ any) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	r.actions = append(r.actions, action)
+	return nil
+}
+
+// memoryUoW revierte los usuarios, las identidades y la auditoría si fn falla, como una
+// transacción.
+type memoryUoW struct {
+	users      *fakeUserRepo
+	identities *memoryIdentityRepo
+	recorder   *memoryRecorder
+}
+
+func (u memoryUoW) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	users, identities := maps.Clone(u.users.users), maps.Clone(u.identities.identities)
+	actions := slices.Clone(u.recorder.actions)
+
+	if err := fn(ctx); err != nil {
+		u.users.users, u.identities.identities, u.recorder.actions = users, identities, actions
+		return err
+	}
+
+	return nil
+}
+
+const testIssuer = "https://idp.example"
+
+func newTestService(provider Provider, groupRoles []domain.GroupRoleMapping, defaultRole domain.Role) (*service, *memoryIdentityRepo, *memoryRecorder) {
+	roles := &memoryRoleRepo{roles: map[domain.Role]domain.RoleDefinition{
+		domain.RoleAdmin:   {Name: domain.RoleAdmin, Permissions: domain.Permissions},
+		domain.RoleCashier: {Name: domain.RoleCashier, Permissions: []domain.Permission{domain.PermParkingRead, domain.PermParkingWrite}},
+		"gestor":           {Name: "gestor", Permissions: []domain.Permission{domain.PermParkingRead, domain.PermParkingWrite, domain.PermUsersRead, domain.PermUsersManage}},
+	}}
+
+	users := &fakeUserRepo{users: map[string]domain.User{
+		"admin":  {ID: "admin", Username: domain.AdminUsername, Role: domain.RoleAdmin},
+		"cajero": {ID: "cajero", Username: "cajero", Role: domain.RoleCashier},
+		"gestor": {ID: "gestor", Username: "gestor", Role: "gestor"},
+	}}
+
+	identities := &memoryIdentityRepo{identities: map[string]domain.UserIdentity{}}
+	recorder := &memoryRecorder{}
+
+	uow := memoryUoW{users: users, identities: identities, recorder: recorder}
+
+	svc := NewService(uow, provider, testIssuer, NewMemoryStateStore(time.Minute, 10), identities, users, roles, nil, recorder, groupRoles, defaultRole)
+
+	return svc.(*service), identities, recorder
+}
+
+func TestCompleteRequiresBoundState(t *testing.T) {
+	tests := []struct {
+		name       string
+		boundState func(state string) string
+		want       error
+	}{
+		{
+			name:       "el navegador no inició el flujo",
+			boundState: func(string) string { return "" },
+			want:       domain.ErrSSOStateInvalid,
+		},
+		{
+			name:       "el navegador inició otro flujo",
+			boundState: func(string) string { return "otro" },
+			want:       domain.ErrSSOStateInvalid,
+		},
+		{
+			name:       "el navegador inició el flujo",
+			boundState: func(state string) string { return state },
+			want:       domain.ErrSSOLoginFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &fakeProvider{}
+			s, _, _ := newTestService(provider, nil, "")
+
+			_, state, err := s.Begin(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = s.Complete(context.Background(), state, tt.boundState(state), "code", "10.0.0.1")
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, se esperaba %v", err, tt.want)
+			}
+
+			if provider.exchanged != (tt.want == domain.ErrSSOLoginFailed) {
+				t.Errorf("canje = %v con error %v", provider.exchanged, err)
+			}
+		})
+	}
+}
+
+func TestMemoryStateStoreLimit(t *testing.T) {
+	store := NewMemoryStateStore(time.Minute, 2)
+
+	for _, state := range []string{"a", "b"} {
+		if err := store.Save(state, PendingLogin{}); err != nil {
+			t.Fatalf("Save(%s) = %v", state, err)
+		}
+	}
+
+	if err := store.Save("c", PendingLogin{}); !errors.Is(err, domain.ErrSSOBusy) {
+		t.Errorf("Save al superar el límite = %v, se esperaba %v", err, domain.ErrSSOBusy)
+	}
+
+	if _, ok := store.Consume("a"); !ok {
+		t.Fatal("no se encontró el flujo a")
+	}
+
+	if err := store.Save("c", PendingLogin{}); err != nil {
+		t.Errorf("Save tras liberar espacio = %v", err)
+	}
+
+	expiring := NewMemoryStateStore(-time.Second, 1)
+	expiring.Save("a", PendingLogin{})
+
+	if err := expiring.Save("b", PendingLogin{}); err != nil {
+		t.Errorf("Save con flujos expirados = %v, se esperaba que se limpiaran", err)
+	}
+}
+
+func TestCheckRoles(t *testing.T) {
+	tests := []struct {
+		name        string
+		provider    Provider
+		groupRoles  []domain.GroupRoleMapping
+		defaultRole domain.Role
+		want        error
+	}{
+		{
+			name:       "SSO deshabilitado",
+			groupRoles: []domain.GroupRoleMapping{{Group: "noche", Role: "nocturno"}},
+		},
+		{
+			name:        "roles existentes",
+			provider:    &fakeProvider{},
+			groupRoles:  []domain.GroupRoleMapping{{Group: "caja", Role: domain.RoleCashier}},
+			defaultRole: "gestor",
+		},
+		{
+			name:       "rol de grupo inexistente",
+			provider:   &fakeProvider{},
+			groupRoles: []domain.GroupRoleMapping{{Group: "caja", Role: domain.RoleCashier}, {Group: "noche", Role: "nocturno"}},
+			want:       domain.ErrRoleNotFound,
+		},
+		{
+			name:        "rol por defecto inexistente",
+			provider:    &fakeProvider{},
+			defaultRole: "nocturno",
+			want:        domain.ErrRoleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, _ := newTestService(tt.provider, tt.groupRoles, tt.defaultRole)
+
+			if err := s.CheckRoles(context.Background()); !errors.Is(err, tt.want) {
+				t.Errorf("CheckRoles() = %v, se esperaba %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompleteRejectsMissingRole(t *testing.T) {
+	// El rol se eliminó después de iniciar: no se aprovisiona el usuario (el repositorio de
+	// usuarios falla si se invoca Create).
+	provider := &fakeProvider{identity: &domain.ExternalIdentity{Issuer: testIssuer, Subject: "sub-1", Groups: []string{"noche"}}}
+	s, _, _ := newTestService(provider, []domain.GroupRoleMapping{{Group: "noche", Role: "nocturno"}}, "")
+
+	_, state, err := s.Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Complete(context.Background(), state, state, "code", "10.0.0.1"); !errors.Is(err, domain.ErrSSONoRole) {
+		t.Errorf("Complete() = %v, se esperaba %v", err, domain.ErrSSONoRole)
+	}
+}
+
+// TestProvisionRollsBack verifica que un error al vincular la identidad o al auditar no deje un
+// usuario aprovisionado sin identidad.
+func TestProvisionRollsBack(t *testing.T) {
+	failure := errors.New("base de datos no disponible")
+
+	tests := []struct {
+		name  string
+		setup func(identities *memoryIdentityRepo, recorder *memoryRecorder)
+	}{
+		{
+			name:  "falla la vinculación",
+			setup: func(identities *memoryIdentityRepo, _ *memoryRecorder) { identities.err = failure },
+		},
+		{
+			name:  "falla la auditoría",
+			setup: func(_ *memoryIdentityRepo, recorder *memoryRecorder) { recorder.err = failure },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &fakeProvider{identity: &domain.ExternalIdentity{Issuer: testIssuer, Subject: "sub-1", PreferredUsername: "nuevo"}}
+			s, identities, recorder := newTestService(provider, nil, domain.RoleCashier)
+			tt.setup(identities, recorder)
+
+			_, state, err := s.Begin(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := s.Complete(context.Background(), state, state, "code", "10.0.0.1"); !errors.Is(err, failure) {
+				t.Fatalf("Complete() = %v, se esperaba %v", err, failure)
+			}
+
+			if s.userRepo.ExistsUsername(context.Background(), "nuevo") {
+				t.Error("el usuario aprovisionado quedó guardado sin identidad")
+			}
+
+			if len(identities.identities) != 0 || len(recorder.actions) != 0 {
+				t.Errorf("identidades = %v, auditoría = %v, no se esperaba ninguna", identities.identities, recorder.actions)
+			}
+		})
+	}
+}
+
+func TestLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		actor    string
+		userID   string
+		subject  string
+		disabled bool
+		want     error
+	}{
+		{name: "SSO deshabilitado", actor: "admin", userID: "cajero", subject: "sub-1", disabled: true, want: domain.ErrSSODisabled},
+		{name: "usuario inexistente", actor: "admin", userID: "fantasma", subject: "sub-1", want: domain.ErrUserNotFound},
+		{name: "cuenta con más privilegios", actor: "gestor", userID: "admin", subject: "sub-1", want: domain.ErrRoleNotGrantable},
+		{name: "subject ya vinculado", actor: "admin", userID: "cajero", subject: "vinculado", want: domain.ErrIdentityAlreadyLinked},
+		{name: "cuenta administrable", actor: "gestor", userID: "cajero", subject: " sub-1 "},
+		{name: "desde la línea de comandos", userID: "admin", subject: "sub-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var provider Provider = &fakeProvider{}
+			if tt.disabled {
+				provider = nil
+			}
+
+			s, identities, recorder := newTestService(provider, nil, "")
+			identities.identities[testIssuer+"|vinculado"] = domain.UserIdentity{UserID: "gestor", Issuer: testIssuer, Subject: "vinculado"}
+
+			ctx := context.Background()
+			if tt.actor != "" {
+				ctx = audit.WithActor(ctx, audit.Actor{UserID: tt.actor})
+			}
+
+			identity, err := s.Link(ctx, tt.userID, tt.subject, "cajero@example.com")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Link() = %v, se esperaba %v", err, tt.want)
+			}
+
+			if tt.want != nil {
+				if len(recorder.actions) != 0 {
+					t.Errorf("auditoría = %v, no se esperaba ningún registro", recorder.actions)
+				}
+
+				return
+			}
+
+			linked, err := s.identities.FindBySubject(ctx, testIssuer, "sub-1")
+			if err != nil || linked.UserID != tt.userID || identity.ID != linked.ID {
+				t.Errorf("FindBySubject() = %+v, %v; se esperaba vinculado a %s", linked, err, tt.userID)
+			}
+
+			if !slices.Equal(recorder.actions, []string{domain.AuditUserIdentityLink}) {
+				t.Errorf("auditoría = %v, se esperaba %v", recorder.actions, domain.AuditUserIdentityLink)
+			}
+		})
+	}
+}
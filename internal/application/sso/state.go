@@ -0,0 +1,84 @@
+package sso
+
+import (
+	"sync"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+const (
+	// StateTTL es el tiempo que el usuario tiene para completar el inicio de sesión en el
+	// proveedor.
+	StateTTL = 10 * time.Minute
+
+	// MaxPendingLogins limita los flujos pendientes que se conservan a la vez.
+	MaxPendingLogins = 10000
+)
+
+type memoryEntry struct {
+	pending   PendingLogin
+	expiresAt time.Time
+}
+
+// memoryStateStore guarda los flujos pendientes en memoria. Es suficiente para una sola instancia;
+// con varias réplicas se requiere afinidad de sesión o un almacén compartido.
+type memoryStateStore struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+// NewMemoryStateStore crea el almacén con un límite de maxEntries flujos pendientes, ya que
+// cualquier cliente anónimo puede iniciar uno.
+func NewMemoryStateStore(ttl time.Duration, maxEntries int) StateStore {
+	return &memoryStateStore{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]memoryEntry),
+	}
+}
+
+func (m *memoryStateStore) Save(state string, pending PendingLogin) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	// Los flujos abandonados solo se limpian al alcanzar el límite, para no recorrer el mapa en
+	// cada llamada.
+	if len(m.entries) >= m.maxEntries {
+		for key, entry := range m.entries {
+			if now.After(entry.expiresAt) {
+				delete(m.entries, key)
+			}
+		}
+
+		if len(m.entries) >= m.maxEntries {
+			return domain.ErrSSOBusy
+		}
+	}
+
+	m.entries[state] = memoryEntry{pending: pending, expiresAt: now.Add(m.ttl)}
+	return nil
+}
+
+func (m *memoryStateStore) Consume(state string) (PendingLogin, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[state]
+	if !ok {
+		return PendingLogin{}, false
+	}
+
+	delete(m.entries, state)
+
+	if time.Now().After(entry.expiresAt) {
+		return PendingLogin{}, false
+	}
+
+	return entry.pending, true
+}
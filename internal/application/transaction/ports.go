@@ -0,0 +1,11 @@
+package transaction
+
+import "context"
+
+// UnitOfWork agrupa varias operaciones de repositorio en una sola transacción.
+type UnitOfWork interface {
+	// Do ejecuta fn dentro de una transacción que se confirma si fn no retorna error y se revierte
+	// en caso contrario. Los repositorios llamados con el contexto recibido por fn participan de
+	// la transacción. Si ctx ya pertenece a una transacción, fn se ejecuta dentro de ella.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/JGCaceres97/parking/internal/domain"
 )
@@ -18,25 +19,43 @@ type Service interface {
 	// ToggleActive bloquea o desbloquea (elimina lógicamente) a un usuario.
 	ToggleActive(ctx context.Context, id string, isActive bool) (*domain.User, error)
 
-	// Delete eliminar un usuario.
-	Delete(ctx context.Context, id string) error
+	// Delete elimina un usuario según el modo indicado: lógico (por defecto), anonimización o
+	// definitivo. La eliminación definitiva falla con ErrUserHasRecords si el usuario registró
+	// entradas o salidas.
+	Delete(ctx context.Context, id string, mode domain.UserDeleteMode) error
+
+	// ListAll lista todos los usuarios, incluidos los eliminados si se indica.
+	ListAll(ctx context.Context, id string, includeDeleted bool) ([]domain.User, error)
+
+	// FindByUsername busca un usuario no eliminado por su nombre de usuario.
+	FindByUsername(ctx context.Context, username string) (*domain.User, error)
 
-	// ListAll lista todos los usuarios.
-	ListAll(ctx context.Context, id string) ([]domain.User, error)
+	// Unlock desbloquea una cuenta bloqueada por intentos fallidos de inicio de sesión.
+	Unlock(ctx context.Context, id string) (*domain.User, error)
+
+	// ResetPassword genera una contraseña temporal de un solo uso para el usuario y lo obliga
+	// a cambiarla en su próximo inicio de sesión. Retorna la contraseña temporal en texto plano.
+	ResetPassword(ctx context.Context, id string) (string, error)
 
 	// -- Common
 
 	// UpdateUsername permite a un usuario editar únicamente su propio username.
 	UpdateUsername(ctx context.Context, id string, newUsername string) (*domain.User, error)
+
+	// ChangePassword permite a un usuario cambiar su propia contraseña, verificando la actual.
+	ChangePassword(ctx context.Context, id string, currentPassword string, newPassword string) error
 }
 
 type Repository interface {
 	// Create registra un nuevo usuario en la base de datos.
 	Create(ctx context.Context, user *domain.User) error
 
-	// FindByID busca un usuario por su ULID
+	// FindByID busca un usuario no eliminado por su ULID
 	FindByID(ctx context.Context, id string) (*domain.User, error)
 
+	// FindByIDWithDeleted busca un usuario por su ULID, aunque haya sido eliminado.
+	FindByIDWithDeleted(ctx context.Context, id string) (*domain.User, error)
+
 	// FindByUsername busca un usuario por su nombre de usuario para el login.
 	FindByUsername(ctx context.Context, username string) (*domain.User, error)
 
@@ -46,9 +65,37 @@ type Repository interface {
 	// Update actualiza la información del usuario.
 	Update(ctx context.Context, user *domain.User) error
 
-	// Delete eliminar un usuario.
+	// UpdateLoginState actualiza el contador de intentos fallidos y el bloqueo del usuario.
+	UpdateLoginState(ctx context.Context, user *domain.User) error
+
+	// RegisterLoginFailure incrementa de forma atómica los fallos consecutivos del usuario y lo
+	// bloquea si alcanza maxFailures (0 no bloquea). Retorna el nuevo contador y si la cuenta
+	// quedó bloqueada.
+	RegisterLoginFailure(ctx context.Context, id string, at time.Time, maxFailures int) (int, bool, error)
+
+	// UpdatePassword reemplaza el hash de contraseña del usuario y lo agrega a su historial.
+	UpdatePassword(ctx context.Context, id string, passwordHash string, mustChange bool) error
+
+	// RevokeTokens incrementa la versión de los tokens del usuario, lo que invalida todos los
+	// tokens emitidos hasta el momento.
+	RevokeTokens(ctx context.Context, id string) error
+
+	// ListPasswordHistory obtiene los hashes de las últimas contraseñas del usuario,
+	// de la más reciente a la más antigua.
+	ListPasswordHistory(ctx context.Context, id string, limit int) ([]string, error)
+
+	// SoftDelete marca al usuario como eliminado e inactivo. Su nombre de usuario queda libre.
+	SoftDelete(ctx context.Context, id string, deletedAt time.Time) error
+
+	// Anonymize reemplaza el nombre de usuario y la contraseña, elimina el historial de
+	// contraseñas, el segundo factor y las identidades externas, y anonimiza los intentos de
+	// inicio de sesión. Si el usuario no estaba eliminado, lo elimina lógicamente.
+	Anonymize(ctx context.Context, id string, username string, deletedAt time.Time) error
+
+	// Delete elimina definitivamente un usuario y sus datos asociados. Retorna
+	// ErrUserHasRecords si tiene registros de estacionamiento.
 	Delete(ctx context.Context, id string) error
 
-	// ListAll lista todos los usuarios, excepto a ti mismo.
-	ListAll(ctx context.Context, id string) ([]domain.User, error)
+	// ListAll lista todos los usuarios, excepto a ti mismo, incluidos los eliminados si se indica.
+	ListAll(ctx context.Context, id string, includeDeleted bool) ([]domain.User, error)
 }
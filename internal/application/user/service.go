@@ -2,48 +2,111 @@ package user
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"math/big"
 	"time"
 
+	"go.opentelemetry.io/otel"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/JGCaceres97/parking/internal/application/audit"
+	"github.com/JGCaceres97/parking/internal/application/events"
+	"github.com/JGCaceres97/parking/internal/application/role"
+	"github.com/JGCaceres97/parking/internal/application/transaction"
 	"github.com/JGCaceres97/parking/internal/domain"
 	"github.com/JGCaceres97/parking/pkg/ulid"
 )
 
+var tracer = otel.Tracer("github.com/JGCaceres97/parking/internal/application/user")
+
+// temporaryPasswordLength es la longitud mínima de las contraseñas temporales generadas.
+const temporaryPasswordLength = 12
+
+const (
+	upperChars  = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	lowerChars  = "abcdefghijkmnopqrstuvwxyz"
+	digitChars  = "23456789"
+	symbolChars = "!@#$%&*-_=+?"
+)
+
 type service struct {
-	repo Repository
+	uow      transaction.UnitOfWork
+	repo     Repository
+	roleRepo role.Repository
+	audit    audit.TxRecorder
+	events   events.Publisher
+	policy   domain.PasswordPolicy
 }
 
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+// NewService crea el servicio de usuarios. Cada cambio se guarda junto con su entrada de auditoría
+// en una sola transacción.
+func NewService(
+	uow transaction.UnitOfWork,
+	repo Repository,
+	roleRepo role.Repository,
+	audit audit.TxRecorder,
+	events events.Publisher,
+	policy domain.PasswordPolicy,
+) Service {
+	return &service{
+		uow:      uow,
+		repo:     repo,
+		roleRepo: roleRepo,
+		audit:    audit,
+		events:   events,
+		policy:   policy,
+	}
 }
 
 func (s *service) Create(ctx context.Context, user *domain.User) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "user.Create")
+	defer span.End()
+
 	exists := s.repo.ExistsUsername(ctx, user.Username)
 	if exists {
 		return nil, domain.ErrUsernameAlreadyExists
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	if err := s.checkGrant(ctx, user.Role); err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.Validate(user.Password); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := hashPassword(user.Password)
 	if err != nil {
-		return nil, fmt.Errorf("error al hashear contraseña: %w", err)
+		return nil, err
 	}
 
 	user.ID = ulid.GenerateNewULID()
-	user.Password = string(hashedPassword)
+	user.Password = hashedPassword
 	user.CreatedAt = time.Now().UTC().Truncate(time.Second)
 
-	if err = s.repo.Create(ctx, user); err != nil {
-		return nil, fmt.Errorf("error al guardar el usuario: %w", err)
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, user); err != nil {
+			return fmt.Errorf("error al guardar el usuario: %w", err)
+		}
+
+		return s.audit.RecordTx(ctx, domain.AuditUserCreate, domain.AuditEntityUser, user.ID, nil, user.AuditSnapshot())
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
 	user.Password = ""
+
 	return user, nil
 }
 
 func (s *service) Update(ctx context.Context, id string, userUpdated *domain.User) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "user.Update")
+	defer span.End()
+
 	if userUpdated.Username == domain.AdminUsername {
 		return nil, domain.ErrAdminProtected
 	}
@@ -57,24 +120,56 @@ func (s *service) Update(ctx context.Context, id string, userUpdated *domain.Use
 		return nil, domain.ErrUsernameAlreadyExists
 	}
 
-	existingUser.Username = userUpdated.Username
-	existingUser.Role = userUpdated.Role
-	existingUser.IsActive = userUpdated.IsActive
+	if err := s.checkGrant(ctx, existingUser.Role); err != nil {
+		return nil, err
+	}
 
-	if err := s.repo.Update(ctx, existingUser); err != nil {
-		if errors.Is(err, domain.ErrUserNotFound) {
+	before := *existingUser
+
+	if userUpdated.Role != "" {
+		if err := s.checkGrant(ctx, userUpdated.Role); err != nil {
 			return nil, err
 		}
 
-		return nil, fmt.Errorf("error al actualizar usuario en repo: %w", err)
+		existingUser.Role = userUpdated.Role
+	}
+
+	if userUpdated.Username != "" {
+		existingUser.Username = userUpdated.Username
+	}
+
+	existingUser.IsActive = userUpdated.IsActive
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, existingUser); err != nil {
+			if errors.Is(err, domain.ErrUserNotFound) {
+				return err
+			}
+
+			return fmt.Errorf("error al actualizar usuario en repo: %w", err)
+		}
+
+		return s.audit.RecordTx(ctx, domain.AuditUserUpdate, domain.AuditEntityUser, id, before.AuditSnapshot(), existingUser.AuditSnapshot())
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
 	existingUser.Password = ""
+
+	if before.IsActive && !existingUser.IsActive {
+		s.events.Publish(domain.EventUserDeactivated, domain.UserDeactivatedData{UserID: id})
+	}
+
 	return existingUser, nil
 }
 
-func (s *service) Delete(ctx context.Context, id string) error {
-	user, err := s.repo.FindByID(ctx, id)
+func (s *service) Delete(ctx context.Context, id string, mode domain.UserDeleteMode) error {
+	ctx, span := tracer.Start(ctx, "user.Delete")
+	defer span.End()
+
+	user, err := s.repo.FindByIDWithDeleted(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -83,18 +178,67 @@ func (s *service) Delete(ctx context.Context, id string) error {
 		return domain.ErrAdminProtected
 	}
 
-	if err := s.repo.Delete(ctx, id); err != nil {
-		if errors.Is(err, domain.ErrUserNotFound) {
-			return err
+	if err := s.checkGrant(ctx, user.Role); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		switch mode {
+		case domain.UserDeleteAnonymize:
+			if err := s.repo.Anonymize(ctx, id, domain.AnonymizedUsername(id), now); err != nil {
+				return fmt.Errorf("error al anonimizar usuario en repo: %w", err)
+			}
+
+			// El estado anterior contiene los datos personales que se están eliminando.
+			return s.audit.RecordTx(ctx, domain.AuditUserAnonymize, domain.AuditEntityUser, id, nil, nil)
+
+		case domain.UserDeleteHard:
+			if err := s.repo.Delete(ctx, id); err != nil {
+				if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrUserHasRecords) {
+					return err
+				}
+
+				return fmt.Errorf("error al eliminar usuario en repo: %w", err)
+			}
+
+			return s.audit.RecordTx(ctx, domain.AuditUserHardDelete, domain.AuditEntityUser, id, user.AuditSnapshot(), nil)
+
+		default:
+			if user.DeletedAt != nil {
+				return domain.ErrUserNotFound
+			}
+
+			if err := s.repo.SoftDelete(ctx, id, now); err != nil {
+				if errors.Is(err, domain.ErrUserNotFound) {
+					return err
+				}
+
+				return fmt.Errorf("error al eliminar usuario en repo: %w", err)
+			}
+
+			after := *user
+			after.IsActive = false
+			after.DeletedAt = &now
+
+			return s.audit.RecordTx(ctx, domain.AuditUserDelete, domain.AuditEntityUser, id, user.AuditSnapshot(), after.AuditSnapshot())
 		}
+	})
 
-		return fmt.Errorf("error al eliminar usuario en repo: %w", err)
+	if err != nil {
+		return err
 	}
 
+	s.events.Publish(domain.EventUserDeactivated, domain.UserDeactivatedData{UserID: id})
+
 	return nil
 }
 
 func (s *service) ToggleActive(ctx context.Context, id string, isActive bool) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "user.ToggleActive")
+	defer span.End()
+
 	user, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -104,23 +248,98 @@ func (s *service) ToggleActive(ctx context.Context, id string, isActive bool) (*
 		return nil, domain.ErrAdminProtected
 	}
 
+	if err := s.checkGrant(ctx, user.Role); err != nil {
+		return nil, err
+	}
+
 	if user.IsActive == isActive {
 		return user, nil
 	}
 
+	before := *user
+
 	user.IsActive = isActive
-	if err := s.repo.Update(ctx, user); err != nil {
-		return nil, fmt.Errorf("error al cambiar estado activo del usuario: %w", err)
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, user); err != nil {
+			return fmt.Errorf("error al cambiar estado activo del usuario: %w", err)
+		}
+
+		return s.audit.RecordTx(ctx, domain.AuditUserToggleActive, domain.AuditEntityUser, id, before.AuditSnapshot(), user.AuditSnapshot())
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !isActive {
+		s.events.Publish(domain.EventUserDeactivated, domain.UserDeactivatedData{UserID: id})
+	}
+
+	return user, nil
+}
+
+func (s *service) Unlock(ctx context.Context, id string) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "user.Unlock")
+	defer span.End()
+
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkGrant(ctx, user.Role); err != nil {
+		return nil, err
+	}
+
+	before := *user
+
+	user.FailedLoginAttempts = 0
+	user.LastFailedLoginAt = nil
+	user.LockedAt = nil
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateLoginState(ctx, user); err != nil {
+			return fmt.Errorf("error al desbloquear usuario: %w", err)
+		}
+
+		return s.audit.RecordTx(ctx, domain.AuditUserUnlock, domain.AuditEntityUser, id, before.AuditSnapshot(), user.AuditSnapshot())
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
+	user.Password = ""
+
 	return user, nil
 }
 
-func (s *service) ListAll(ctx context.Context, id string) ([]domain.User, error) {
-	return s.repo.ListAll(ctx, id)
+func (s *service) ListAll(ctx context.Context, id string, includeDeleted bool) ([]domain.User, error) {
+	ctx, span := tracer.Start(ctx, "user.ListAll")
+	defer span.End()
+
+	return s.repo.ListAll(ctx, id, includeDeleted)
+}
+
+func (s *service) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "user.FindByUsername")
+	defer span.End()
+
+	user, err := s.repo.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Password = ""
+
+	return user, nil
 }
 
 func (s *service) UpdateUsername(ctx context.Context, id string, newUsername string) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "user.UpdateUsername")
+	defer span.End()
+
 	user, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -134,11 +353,246 @@ func (s *service) UpdateUsername(ctx context.Context, id string, newUsername str
 		return nil, domain.ErrUsernameAlreadyExists
 	}
 
+	before := *user
+
 	user.Username = newUsername
-	if err := s.repo.Update(ctx, user); err != nil {
-		return nil, fmt.Errorf("error al actualizar username: %w", err)
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, user); err != nil {
+			return fmt.Errorf("error al actualizar username: %w", err)
+		}
+
+		return s.audit.RecordTx(ctx, domain.AuditUserUsernameChange, domain.AuditEntityUser, id, before.AuditSnapshot(), user.AuditSnapshot())
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
 	user.Password = ""
+
 	return user, nil
 }
+
+func (s *service) ChangePassword(ctx context.Context, id, currentPassword, newPassword string) error {
+	ctx, span := tracer.Start(ctx, "user.ChangePassword")
+	defer span.End()
+
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return domain.ErrCurrentPasswordInvalid
+		}
+
+		return fmt.Errorf("error al comparar hash: %w", err)
+	}
+
+	if err := s.policy.Validate(newPassword); err != nil {
+		return err
+	}
+
+	if err := s.checkHistory(ctx, id, newPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.setPassword(ctx, id, hashedPassword, false); err != nil {
+			return err
+		}
+
+		// Nunca se registran contraseñas ni sus hashes.
+		return s.audit.RecordTx(ctx, domain.AuditUserPasswordChange, domain.AuditEntityUser, id, nil, nil)
+	})
+}
+
+func (s *service) ResetPassword(ctx context.Context, id string) (string, error) {
+	ctx, span := tracer.Start(ctx, "user.ResetPassword")
+	defer span.End()
+
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	if user.Username == domain.AdminUsername {
+		return "", domain.ErrAdminProtected
+	}
+
+	// La contraseña temporal da acceso a la cuenta, por lo que aplica la misma restricción.
+	if err := s.checkGrant(ctx, user.Role); err != nil {
+		return "", err
+	}
+
+	tempPassword, err := generateTemporaryPassword(max(s.policy.MinLength, temporaryPasswordLength))
+	if err != nil {
+		return "", fmt.Errorf("error al generar contraseña temporal: %w", err)
+	}
+
+	hashedPassword, err := hashPassword(tempPassword)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.setPassword(ctx, id, hashedPassword, true); err != nil {
+			return err
+		}
+
+		// La sesión abierta con la contraseña anterior no debe seguir siendo válida.
+		if err := s.repo.RevokeTokens(ctx, id); err != nil {
+			return err
+		}
+
+		return s.audit.RecordTx(ctx, domain.AuditUserPasswordReset, domain.AuditEntityUser, id, nil, nil)
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return tempPassword, nil
+}
+
+// checkGrant verifica que los roles existan y que el usuario que realiza la acción tenga todos sus
+// permisos, de modo que users:manage no permita crear, promover ni administrar usuarios con más
+// privilegios. Un rol inexistente se reporta como domain.ErrInvalidRole, ya que es un dato
+// inválido de la solicitud y no un recurso ausente. Las acciones sin usuario autenticado (línea
+// de comandos) no se restringen.
+func (s *service) checkGrant(ctx context.Context, roles ...domain.Role) error {
+	definitions := make([]*domain.RoleDefinition, 0, len(roles))
+
+	for _, name := range roles {
+		role, err := s.roleRepo.FindByName(ctx, name)
+		if err != nil {
+			if errors.Is(err, domain.ErrRoleNotFound) {
+				return domain.ErrInvalidRole
+			}
+
+			return fmt.Errorf("error al verificar rol: %w", err)
+		}
+
+		definitions = append(definitions, role)
+	}
+
+	actorID := audit.ActorFromContext(ctx).UserID
+	if actorID == "" {
+		return nil
+	}
+
+	actor, err := s.repo.FindByID(ctx, actorID)
+	if err != nil {
+		return fmt.Errorf("error al obtener el usuario actual: %w", err)
+	}
+
+	granted, err := s.roleRepo.FindByName(ctx, actor.Role)
+	if err != nil {
+		return fmt.Errorf("error al obtener el rol del usuario actual: %w", err)
+	}
+
+	for _, role := range definitions {
+		for _, p := range role.Permissions {
+			if !granted.Has(p) {
+				return domain.ErrRoleNotGrantable
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkHistory verifica que la nueva contraseña no coincida con ninguna de las últimas
+// contraseñas del usuario, según el tamaño de historial definido en la política.
+func (s *service) checkHistory(ctx context.Context, id, password string) error {
+	if s.policy.HistorySize <= 0 {
+		return nil
+	}
+
+	hashes, err := s.repo.ListPasswordHistory(ctx, id, s.policy.HistorySize)
+	if err != nil {
+		return fmt.Errorf("error al obtener historial de contraseñas: %w", err)
+	}
+
+	for _, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			return domain.ErrPasswordReused
+		}
+	}
+
+	return nil
+}
+
+// hashPassword calcula el hash de la contraseña. Se llama antes de iniciar la transacción para no
+// retenerla durante el cálculo.
+func hashPassword(password string) (string, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("error al hashear contraseña: %w", err)
+	}
+
+	return string(hashedPassword), nil
+}
+
+func (s *service) setPassword(ctx context.Context, id, hashedPassword string, mustChange bool) error {
+	if err := s.repo.UpdatePassword(ctx, id, hashedPassword, mustChange); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("error al actualizar contraseña: %w", err)
+	}
+
+	return nil
+}
+
+// generateTemporaryPassword genera una contraseña aleatoria que contiene al menos una
+// mayúscula, una minúscula, un número y un símbolo, de modo que cumpla cualquier política.
+func generateTemporaryPassword(length int) (string, error) {
+	sets := []string{upperChars, lowerChars, digitChars, symbolChars}
+	all := upperChars + lowerChars + digitChars + symbolChars
+
+	password := make([]byte, 0, length)
+
+	for i := range length {
+		set := all
+		if i < len(sets) {
+			set = sets[i]
+		}
+
+		c, err := randomChar(set)
+		if err != nil {
+			return "", err
+		}
+
+		password = append(password, c)
+	}
+
+	// Mezclar para que los caracteres obligatorios no queden siempre al inicio.
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
+
+func randomChar(set string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
+	if err != nil {
+		return 0, err
+	}
+
+	return set[n.Int64()], nil
+}
@@ -0,0 +1,445 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/audit"
+	"github.com/JGCaceres97/parking/internal/application/role"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// memoryRepository guarda los usuarios en memoria. Los métodos no utilizados en las pruebas
+// provienen de la interfaz embebida y no deben invocarse.
+type memoryRepository struct {
+	Repository
+	users map[string]*domain.User
+}
+
+func (r *memoryRepository) find(id string, withDeleted bool) (*domain.User, error) {
+	u, ok := r.users[id]
+	if !ok || (!withDeleted && u.DeletedAt != nil) {
+		return nil, domain.ErrUserNotFound
+	}
+
+	copied := *u
+	return &copied, nil
+}
+
+func (r *memoryRepository) FindByID(_ context.Context, id string) (*domain.User, error) {
+	return r.find(id, false)
+}
+
+func (r *memoryRepository) FindByIDWithDeleted(_ context.Context, id string) (*domain.User, error) {
+	return r.find(id, true)
+}
+
+func (r *memoryRepository) FindByUsername(_ context.Context, username string) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.Username == username && u.DeletedAt == nil {
+			return r.find(u.ID, false)
+		}
+	}
+
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *memoryRepository) ExistsUsername(ctx context.Context, username string) bool {
+	_, err := r.FindByUsername(ctx, username)
+	return err == nil
+}
+
+func (r *memoryRepository) Create(_ context.Context, u *domain.User) error {
+	copied := *u
+	r.users[u.ID] = &copied
+	return nil
+}
+
+func (r *memoryRepository) Update(_ context.Context, u *domain.User) error {
+	copied := *u
+	r.users[u.ID] = &copied
+	return nil
+}
+
+func (r *memoryRepository) UpdatePassword(_ context.Context, id, passwordHash string, mustChange bool) error {
+	r.users[id].Password = passwordHash
+	r.users[id].MustChangePassword = mustChange
+	return nil
+}
+
+func (r *memoryRepository) ListPasswordHistory(context.Context, string, int) ([]string, error) {
+	return nil, nil
+}
+
+func (r *memoryRepository) RevokeTokens(_ context.Context, id string) error {
+	r.users[id].TokenVersion++
+	return nil
+}
+
+func (r *memoryRepository) SoftDelete(_ context.Context, id string, deletedAt time.Time) error {
+	r.users[id].IsActive = false
+	r.users[id].DeletedAt = &deletedAt
+	return nil
+}
+
+func (r *memoryRepository) Anonymize(_ context.Context, id, username string, deletedAt time.Time) error {
+	r.users[id].Username = username
+	r.users[id].Password = ""
+	r.users[id].IsActive = false
+
+	if r.users[id].DeletedAt == nil {
+		r.users[id].DeletedAt = &deletedAt
+	}
+
+	return nil
+}
+
+type memoryRoleRepository struct {
+	role.Repository
+	roles map[domain.Role][]domain.Permission
+}
+
+func (r *memoryRoleRepository) FindByName(_ context.Context, name domain.Role) (*domain.RoleDefinition, error) {
+	permissions, ok := r.roles[name]
+	if !ok {
+		return nil, domain.ErrRoleNotFound
+	}
+
+	return &domain.RoleDefinition{Name: name, Permissions: permissions}, nil
+}
+
+type auditEntry struct {
+	action        string
+	before, after any
+}
+
+type memoryRecorder struct {
+	entries []auditEntry
+	err     error
+}
+
+func (r *memoryRecorder) RecordTx(_ context.Context, action, _, _ string, before, after any) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	r.entries = append(r.entries, auditEntry{action: action, before: before, after: after})
+	return nil
+}
+
+// memoryUoW revierte los usuarios y la auditoría si fn falla, como una transacción.
+type memoryUoW struct {
+	repo     *memoryRepository
+	recorder *memoryRecorder
+}
+
+func (u memoryUoW) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	users := make(map[string]*domain.User, len(u.repo.users))
+	for id, user := range u.repo.users {
+		copied := *user
+		users[id] = &copied
+	}
+
+	entries := slices.Clone(u.recorder.entries)
+
+	if err := fn(ctx); err != nil {
+		u.repo.users, u.recorder.entries = users, entries
+		return err
+	}
+
+	return nil
+}
+
+type memoryPublisher struct {
+	events []domain.EventType
+}
+
+func (p *memoryPublisher) Publish(eventType domain.EventType, _ any) {
+	p.events = append(p.events, eventType)
+}
+
+func newTestService(users ...domain.User) (*service, *memoryRepository, *memoryRecorder, *memoryPublisher) {
+	repo := &memoryRepository{users: map[string]*domain.User{}}
+	for _, u := range users {
+		repo.users[u.ID] = &u
+	}
+
+	roles := &memoryRoleRepository{roles: map[domain.Role][]domain.Permission{
+		domain.RoleAdmin:   domain.Permissions,
+		domain.RoleCashier: {domain.PermParkingRead, domain.PermParkingWrite},
+		"gestor":           {domain.PermUsersRead, domain.PermUsersManage, domain.PermParkingRead, domain.PermParkingWrite},
+	}}
+
+	recorder := &memoryRecorder{}
+	publisher := &memoryPublisher{}
+	policy := domain.PasswordPolicy{MinLength: 8}
+
+	uow := memoryUoW{repo: repo, recorder: recorder}
+
+	return NewService(uow, repo, roles, recorder, publisher, policy).(*service), repo, recorder, publisher
+}
+
+func TestRoleGrant(t *testing.T) {
+	manager := domain.User{ID: "gestor", Username: "gestor", Role: "gestor", IsActive: true}
+	cashier := domain.User{ID: "cajero", Username: "cajero", Role: domain.RoleCashier, IsActive: true}
+	admin := domain.User{ID: "admin2", Username: "admin2", Role: domain.RoleAdmin, IsActive: true}
+
+	tests := []struct {
+		name   string
+		actor  string
+		action func(s *service, ctx context.Context) error
+		want   error
+	}{
+		{
+			name:  "crear un usuario con un rol menor",
+			actor: manager.ID,
+			action: func(s *service, ctx context.Context) error {
+				_, err := s.Create(ctx, &domain.User{Username: "nuevo", Password: "Nuevo12345", Role: domain.RoleCashier})
+				return err
+			},
+		},
+		{
+			name:  "crear un administrador",
+			actor: manager.ID,
+			action: func(s *service, ctx context.Context) error {
+				_, err := s.Create(ctx, &domain.User{Username: "nuevo", Password: "Nuevo12345", Role: domain.RoleAdmin})
+				return err
+			},
+			want: domain.ErrRoleNotGrantable,
+		},
+		{
+			name:  "crear un usuario con un rol inexistente",
+			actor: manager.ID,
+			action: func(s *service, ctx context.Context) error {
+				_, err := s.Create(ctx, &domain.User{Username: "nuevo", Password: "Nuevo12345", Role: "fantasma"})
+				return err
+			},
+			want: domain.ErrInvalidRole,
+		},
+		{
+			name:  "promover a administrador",
+			actor: manager.ID,
+			action: func(s *service, ctx context.Context) error {
+				_, err := s.Update(ctx, cashier.ID, &domain.User{Username: cashier.Username, Role: domain.RoleAdmin, IsActive: true})
+				return err
+			},
+			want: domain.ErrRoleNotGrantable,
+		},
+		{
+			name:  "restablecer la contraseña de un administrador",
+			actor: manager.ID,
+			action: func(s *service, ctx context.Context) error {
+				_, err := s.ResetPassword(ctx, admin.ID)
+				return err
+			},
+			want: domain.ErrRoleNotGrantable,
+		},
+		{
+			name:  "desactivar a un administrador",
+			actor: manager.ID,
+			action: func(s *service, ctx context.Context) error {
+				_, err := s.ToggleActive(ctx, admin.ID, false)
+				return err
+			},
+			want: domain.ErrRoleNotGrantable,
+		},
+		{
+			name:  "eliminar a un administrador",
+			actor: manager.ID,
+			action: func(s *service, ctx context.Context) error {
+				return s.Delete(ctx, admin.ID, domain.UserDeleteSoft)
+			},
+			want: domain.ErrRoleNotGrantable,
+		},
+		{
+			name:  "un administrador promueve",
+			actor: admin.ID,
+			action: func(s *service, ctx context.Context) error {
+				_, err := s.Update(ctx, cashier.ID, &domain.User{Username: cashier.Username, Role: domain.RoleAdmin, IsActive: true})
+				return err
+			},
+		},
+		{
+			name: "línea de comandos",
+			action: func(s *service, ctx context.Context) error {
+				_, err := s.ResetPassword(ctx, admin.ID)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, _, _ := newTestService(manager, cashier, admin)
+
+			ctx := context.Background()
+			if tt.actor != "" {
+				ctx = audit.WithUserID(ctx, tt.actor)
+			}
+
+			if err := tt.action(svc, ctx); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, se esperaba %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	deletedAt := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	active := domain.User{ID: "cajero", Username: "juan.perez", Role: domain.RoleCashier, IsActive: true}
+	deleted := domain.User{ID: "borrado", Username: "ana.gomez", Role: domain.RoleCashier, DeletedAt: &deletedAt}
+	admin := domain.User{ID: "admin", Username: domain.AdminUsername, Role: domain.RoleAdmin, IsActive: true}
+
+	tests := []struct {
+		name       string
+		id         string
+		mode       domain.UserDeleteMode
+		want       error
+		wantAction string
+		wantName   string
+	}{
+		{name: "eliminación lógica", id: active.ID, mode: domain.UserDeleteSoft, wantAction: domain.AuditUserDelete, wantName: active.Username},
+		{name: "eliminación lógica repetida", id: deleted.ID, mode: domain.UserDeleteSoft, want: domain.ErrUserNotFound},
+		{name: "anonimizar", id: active.ID, mode: domain.UserDeleteAnonymize, wantAction: domain.AuditUserAnonymize, wantName: domain.AnonymizedUsername(active.ID)},
+		{name: "anonimizar un usuario eliminado", id: deleted.ID, mode: domain.UserDeleteAnonymize, wantAction: domain.AuditUserAnonymize, wantName: domain.AnonymizedUsername(deleted.ID)},
+		{name: "usuario inexistente", id: "fantasma", mode: domain.UserDeleteSoft, want: domain.ErrUserNotFound},
+		{name: "administrador", id: admin.ID, mode: domain.UserDeleteAnonymize, want: domain.ErrAdminProtected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, recorder, publisher := newTestService(active, deleted, admin)
+
+			if err := svc.Delete(context.Background(), tt.id, tt.mode); !errors.Is(err, tt.want) {
+				t.Fatalf("Delete() = %v, se esperaba %v", err, tt.want)
+			}
+
+			if tt.want != nil {
+				if len(recorder.entries) != 0 || len(publisher.events) != 0 {
+					t.Errorf("auditoría = %v, eventos = %v, no se esperaba ninguno", recorder.entries, publisher.events)
+				}
+
+				return
+			}
+
+			stored := repo.users[tt.id]
+			if stored.DeletedAt == nil || stored.IsActive || stored.Username != tt.wantName {
+				t.Errorf("usuario = %+v, se esperaba eliminado, inactivo y con el nombre %q", stored, tt.wantName)
+			}
+
+			// La eliminación previa conserva su fecha.
+			if tt.id == deleted.ID && !stored.DeletedAt.Equal(deletedAt) {
+				t.Errorf("deleted_at = %v, se esperaba %v", stored.DeletedAt, deletedAt)
+			}
+
+			if len(recorder.entries) != 1 || recorder.entries[0].action != tt.wantAction {
+				t.Fatalf("auditoría = %+v, se esperaba %s", recorder.entries, tt.wantAction)
+			}
+
+			if !slices.Equal(publisher.events, []domain.EventType{domain.EventUserDeactivated}) {
+				t.Errorf("eventos = %v, se esperaba %v", publisher.events, domain.EventUserDeactivated)
+			}
+		})
+	}
+}
+
+// TestAuditOmitsUsername verifica que la auditoría, que no se modifica al anonimizar, no guarde el
+// nombre de usuario.
+func TestAuditOmitsUsername(t *testing.T) {
+	ctx := context.Background()
+	svc, _, recorder, _ := newTestService(domain.User{ID: "cajero", Username: "juan.perez", Role: domain.RoleCashier, IsActive: true})
+
+	created, err := svc.Create(ctx, &domain.User{Username: "maria.lopez", Password: "Nuevo12345", Role: domain.RoleCashier})
+	if err != nil {
+		t.Fatalf("Create() = %v", err)
+	}
+
+	if _, err := svc.Update(ctx, "cajero", &domain.User{Username: "juan.p", Role: domain.RoleCashier}); err != nil {
+		t.Fatalf("Update() = %v", err)
+	}
+
+	if _, err := svc.UpdateUsername(ctx, created.ID, "maria.l"); err != nil {
+		t.Fatalf("UpdateUsername() = %v", err)
+	}
+
+	if err := svc.Delete(ctx, "cajero", domain.UserDeleteAnonymize); err != nil {
+		t.Fatalf("Delete() = %v", err)
+	}
+
+	for _, entry := range recorder.entries {
+		data, err := json.Marshal([]any{entry.before, entry.after})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, name := range []string{"juan.perez", "juan.p", "maria.lopez", "maria.l"} {
+			if strings.Contains(string(data), name) {
+				t.Errorf("%s guardó el nombre %q: %s", entry.action, name, data)
+			}
+		}
+	}
+}
+
+// TestAuditFailureRollsBack verifica que un error al auditar revierta el cambio y no publique
+// eventos.
+func TestAuditFailureRollsBack(t *testing.T) {
+	cashier := domain.User{ID: "cajero", Username: "juan.perez", Role: domain.RoleCashier, IsActive: true}
+	auditErr := errors.New("auditoría no disponible")
+
+	tests := []struct {
+		name   string
+		action func(s *service) error
+	}{
+		{
+			name: "crear",
+			action: func(s *service) error {
+				_, err := s.Create(context.Background(), &domain.User{Username: "maria.lopez", Password: "Nuevo12345", Role: domain.RoleCashier})
+				return err
+			},
+		},
+		{
+			name: "desactivar",
+			action: func(s *service) error {
+				_, err := s.ToggleActive(context.Background(), cashier.ID, false)
+				return err
+			},
+		},
+		{
+			name: "restablecer contraseña",
+			action: func(s *service) error {
+				_, err := s.ResetPassword(context.Background(), cashier.ID)
+				return err
+			},
+		},
+		{
+			name: "eliminar",
+			action: func(s *service) error {
+				return s.Delete(context.Background(), cashier.ID, domain.UserDeleteSoft)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, recorder, publisher := newTestService(cashier)
+			recorder.err = auditErr
+
+			if err := tt.action(svc); !errors.Is(err, auditErr) {
+				t.Fatalf("error = %v, se esperaba %v", err, auditErr)
+			}
+
+			if len(repo.users) != 1 || *repo.users[cashier.ID] != cashier {
+				t.Errorf("usuarios = %v, se esperaba solo %+v sin cambios", repo.users, cashier)
+			}
+
+			if len(publisher.events) != 0 {
+				t.Errorf("eventos = %v, no se esperaba ninguno", publisher.events)
+			}
+		})
+	}
+}
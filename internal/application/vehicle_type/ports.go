@@ -12,6 +12,11 @@ type Service interface {
 
 	// ListAll obtiene una lista de todos los tipos de vehículo disponibles.
 	ListAll(ctx context.Context) ([]domain.VehicleType, error)
+
+	// Import crea o actualiza los tipos de vehículo indicados en una sola transacción. Los tipos
+	// se identifican por nombre, sin distinguir mayúsculas de minúsculas: si ya existe uno con el
+	// mismo nombre se actualizan su tarifa y descripción.
+	Import(ctx context.Context, vehicleTypes []domain.VehicleType) (*domain.VehicleTypeImportResult, error)
 }
 
 type Repository interface {
@@ -19,6 +24,16 @@ type Repository interface {
 	// Esto es necesario para obtener la tarifa horario aplicada.
 	FindByID(ctx context.Context, id string) (*domain.VehicleType, error)
 
+	// FindByName busca un tipo de vehículo por su nombre, sin distinguir mayúsculas de minúsculas.
+	FindByName(ctx context.Context, name string) (*domain.VehicleType, error)
+
 	// ListAll obtiene una lista de todos los tipos de vehículo.
 	ListAll(ctx context.Context) ([]domain.VehicleType, error)
+
+	// Create registra un nuevo tipo de vehículo. Retorna ErrVehicleTypeNameAlreadyExists si el
+	// nombre ya está en uso.
+	Create(ctx context.Context, vehicleType *domain.VehicleType) error
+
+	// Update reemplaza el nombre, la tarifa y la descripción de un tipo de vehículo.
+	Update(ctx context.Context, vehicleType *domain.VehicleType) error
 }
@@ -2,19 +2,34 @@ package vehicle_type
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
+	"go.opentelemetry.io/otel"
+
+	"github.com/JGCaceres97/parking/internal/application/audit"
+	"github.com/JGCaceres97/parking/internal/application/transaction"
 	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/ulid"
 )
 
+var tracer = otel.Tracer("github.com/JGCaceres97/parking/internal/application/vehicle_type")
+
 type service struct {
-	repo Repository
+	uow   transaction.UnitOfWork
+	repo  Repository
+	audit audit.TxRecorder
 }
 
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+func NewService(uow transaction.UnitOfWork, repo Repository, audit audit.TxRecorder) Service {
+	return &service{uow: uow, repo: repo, audit: audit}
 }
 
 func (s *service) FindByID(ctx context.Context, id string) (*domain.VehicleType, error) {
+	ctx, span := tracer.Start(ctx, "vehicle_type.FindByID")
+	defer span.End()
+
 	vehicleType, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -24,6 +39,9 @@ func (s *service) FindByID(ctx context.Context, id string) (*domain.VehicleType,
 }
 
 func (s *service) ListAll(ctx context.Context) ([]domain.VehicleType, error) {
+	ctx, span := tracer.Start(ctx, "vehicle_type.ListAll")
+	defer span.End()
+
 	vehicleTypes, err := s.repo.ListAll(ctx)
 	if err != nil {
 		return nil, err
@@ -31,3 +49,79 @@ func (s *service) ListAll(ctx context.Context) ([]domain.VehicleType, error) {
 
 	return vehicleTypes, nil
 }
+
+func (s *service) Import(ctx context.Context, vehicleTypes []domain.VehicleType) (*domain.VehicleTypeImportResult, error) {
+	ctx, span := tracer.Start(ctx, "vehicle_type.Import")
+	defer span.End()
+
+	seen := make(map[string]bool, len(vehicleTypes))
+
+	for i := range vehicleTypes {
+		vehicleTypes[i].Name = strings.TrimSpace(vehicleTypes[i].Name)
+		vehicleTypes[i].Description = strings.TrimSpace(vehicleTypes[i].Description)
+
+		if err := vehicleTypes[i].Validate(); err != nil {
+			return nil, fmt.Errorf("tipo de vehículo %d: %w", i+1, err)
+		}
+
+		key := strings.ToLower(vehicleTypes[i].Name)
+		if seen[key] {
+			return nil, fmt.Errorf("tipo de vehículo %d (%s): %w", i+1, vehicleTypes[i].Name, domain.ErrVehicleTypeNameAlreadyExists)
+		}
+
+		seen[key] = true
+	}
+
+	result := &domain.VehicleTypeImportResult{}
+
+	// Las entradas de auditoría se registran en la misma transacción, de modo que un cambio
+	// revertido no queda auditado.
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		for _, vehicleType := range vehicleTypes {
+			existing, err := s.repo.FindByName(ctx, vehicleType.Name)
+			if err != nil && !errors.Is(err, domain.ErrVehicleTypeNotFound) {
+				return err
+			}
+
+			if existing == nil {
+				vehicleType.ID = ulid.GenerateNewULID()
+
+				if err := s.repo.Create(ctx, &vehicleType); err != nil {
+					return fmt.Errorf("error al crear el tipo de vehículo %s: %w", vehicleType.Name, err)
+				}
+
+				if err := s.audit.RecordTx(ctx, domain.AuditVehicleTypeCreate, domain.AuditEntityVehicleType, vehicleType.ID, nil, vehicleType); err != nil {
+					return err
+				}
+
+				result.Created++
+
+				continue
+			}
+
+			vehicleType.ID = existing.ID
+
+			if *existing == vehicleType {
+				continue
+			}
+
+			if err := s.repo.Update(ctx, &vehicleType); err != nil {
+				return fmt.Errorf("error al actualizar el tipo de vehículo %s: %w", vehicleType.Name, err)
+			}
+
+			if err := s.audit.RecordTx(ctx, domain.AuditVehicleTypeUpdate, domain.AuditEntityVehicleType, vehicleType.ID, existing, vehicleType); err != nil {
+				return err
+			}
+
+			result.Updated++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/outbox"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type Service interface {
+	// Consume crea una entrega pendiente del mensaje por cada suscripción activa a su tipo de
+	// evento.
+	outbox.Consumer
+
+	// ListSubscriptions lista las suscripciones sin sus secretos.
+	ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+
+	// CreateSubscription registra una suscripción. Si no se indica un secreto se genera uno; el
+	// secreto solo se retorna en esta respuesta.
+	CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+
+	// UpdateSubscription reemplaza la URL, los eventos y el estado. El secreto solo cambia si se
+	// indica uno nuevo.
+	UpdateSubscription(ctx context.Context, id string, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+
+	// DeleteSubscription elimina la suscripción y su historial de entregas.
+	DeleteSubscription(ctx context.Context, id string) error
+
+	// ListDeliveries lista las entregas de una suscripción, de la más reciente a la más antigua.
+	// status vacío no filtra.
+	ListDeliveries(ctx context.Context, subscriptionID, status string, limit int) ([]domain.WebhookDelivery, error)
+
+	// Redeliver crea una nueva entrega pendiente con el mismo evento que la indicada.
+	Redeliver(ctx context.Context, deliveryID string) (*domain.WebhookDelivery, error)
+
+	// Dispatch envía las entregas pendientes cuyo siguiente intento ya venció y retorna cuántas
+	// procesó.
+	Dispatch(ctx context.Context) (int, error)
+
+	// Start ejecuta Dispatch periódicamente hasta que se cancele el contexto.
+	Start(ctx context.Context, interval time.Duration)
+}
+
+// Sender realiza la petición HTTP de una entrega.
+type Sender interface {
+	// Send envía body por POST con las cabeceras indicadas y retorna el código de respuesta.
+	Send(ctx context.Context, url string, headers map[string]string, body []byte) (int, error)
+}
+
+type Repository interface {
+	// ListSubscriptions lista todas las suscripciones, incluidos sus secretos.
+	ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+
+	// FindSubscription busca una suscripción por su identificador.
+	FindSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+
+	// CreateSubscription registra una suscripción.
+	CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error
+
+	// UpdateSubscription actualiza una suscripción.
+	UpdateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error
+
+	// DeleteSubscription elimina una suscripción y sus entregas.
+	DeleteSubscription(ctx context.Context, id string) error
+
+	// InsertDeliveries registra entregas pendientes.
+	InsertDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error
+
+	// FindDelivery busca una entrega por su identificador.
+	FindDelivery(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+
+	// ListDeliveries lista las entregas de una suscripción, ordenadas por creación descendente.
+	ListDeliveries(ctx context.Context, subscriptionID, status string, limit int) ([]domain.WebhookDelivery, error)
+
+	// ListDue lista hasta limit entregas pendientes cuyo siguiente intento es anterior a now.
+	ListDue(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error)
+
+	// Claim reserva una entrega hasta leaseUntil si su siguiente intento sigue siendo
+	// expectedNextAttempt. Retorna false si otra instancia la reservó antes.
+	Claim(ctx context.Context, id string, expectedNextAttempt, leaseUntil time.Time) (bool, error)
+
+	// UpdateDelivery guarda el resultado de un intento.
+	UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+}
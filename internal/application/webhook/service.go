@@ -0,0 +1,391 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/JGCaceres97/parking/internal/application/audit"
+	"github.com/JGCaceres97/parking/internal/application/transaction"
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/ulid"
+)
+
+var tracer = otel.Tracer("github.com/JGCaceres97/parking/internal/application/webhook")
+
+const (
+	// dispatchBatchSize es la cantidad de entregas procesadas por ejecución del despachador.
+	dispatchBatchSize = 50
+	// claimLease es el tiempo que una entrega queda reservada mientras se envía. Si la instancia
+	// se detiene antes de guardar el resultado, la entrega se reintenta al vencer.
+	claimLease = 2 * time.Minute
+	// maxErrorLength limita el error guardado de cada intento.
+	maxErrorLength = 500
+)
+
+type service struct {
+	uow    transaction.UnitOfWork
+	repo   Repository
+	sender Sender
+	audit  audit.TxRecorder
+	policy domain.WebhookRetryPolicy
+	now    func() time.Time
+}
+
+// NewService crea el servicio de webhooks. Los cambios hechos desde la administración se guardan
+// junto con su entrada de auditoría en una sola transacción.
+func NewService(
+	uow transaction.UnitOfWork,
+	repo Repository,
+	sender Sender,
+	audit audit.TxRecorder,
+	policy domain.WebhookRetryPolicy,
+) Service {
+	return &service{
+		uow:    uow,
+		repo:   repo,
+		sender: sender,
+		audit:  audit,
+		policy: policy,
+		now:    time.Now,
+	}
+}
+
+func (s *service) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	ctx, span := tracer.Start(ctx, "webhook.ListSubscriptions")
+	defer span.End()
+
+	subscriptions, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+
+	return subscriptions, nil
+}
+
+func (s *service) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	ctx, span := tracer.Start(ctx, "webhook.CreateSubscription")
+	defer span.End()
+
+	if err := subscription.Validate(); err != nil {
+		return nil, err
+	}
+
+	if subscription.Secret == "" {
+		subscription.Secret = generateSecret()
+	}
+
+	subscription.ID = ulid.GenerateNewULID()
+	subscription.CreatedAt = s.now().UTC().Truncate(time.Second)
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateSubscription(ctx, subscription); err != nil {
+			return fmt.Errorf("error al guardar la suscripción: %w", err)
+		}
+
+		return s.audit.RecordTx(ctx, domain.AuditWebhookCreate, domain.AuditEntityWebhook, subscription.ID, nil, withoutSecret(*subscription))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (s *service) UpdateSubscription(ctx context.Context, id string, updated *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	ctx, span := tracer.Start(ctx, "webhook.UpdateSubscription")
+	defer span.End()
+
+	if err := updated.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := withoutSecret(*existing)
+
+	existing.URL = updated.URL
+	existing.EventTypes = updated.EventTypes
+	existing.IsActive = updated.IsActive
+
+	if updated.Secret != "" {
+		existing.Secret = updated.Secret
+	}
+
+	after := withoutSecret(*existing)
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateSubscription(ctx, existing); err != nil {
+			return fmt.Errorf("error al actualizar la suscripción: %w", err)
+		}
+
+		return s.audit.RecordTx(ctx, domain.AuditWebhookUpdate, domain.AuditEntityWebhook, id, before, after)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &after, nil
+}
+
+func (s *service) DeleteSubscription(ctx context.Context, id string) error {
+	ctx, span := tracer.Start(ctx, "webhook.DeleteSubscription")
+	defer span.End()
+
+	existing, err := s.repo.FindSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteSubscription(ctx, id); err != nil {
+			return fmt.Errorf("error al eliminar la suscripción: %w", err)
+		}
+
+		return s.audit.RecordTx(ctx, domain.AuditWebhookDelete, domain.AuditEntityWebhook, id, withoutSecret(*existing), nil)
+	})
+}
+
+func (s *service) ListDeliveries(ctx context.Context, subscriptionID, status string, limit int) ([]domain.WebhookDelivery, error) {
+	ctx, span := tracer.Start(ctx, "webhook.ListDeliveries")
+	defer span.End()
+
+	if _, err := s.repo.FindSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListDeliveries(ctx, subscriptionID, status, limit)
+}
+
+func (s *service) Redeliver(ctx context.Context, deliveryID string) (*domain.WebhookDelivery, error) {
+	ctx, span := tracer.Start(ctx, "webhook.Redeliver")
+	defer span.End()
+
+	original, err := s.repo.FindDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+
+	// Se conserva el ID del evento para que el receptor pueda descartar duplicados.
+	delivery := domain.WebhookDelivery{
+		ID:             ulid.GenerateNewULID(),
+		SubscriptionID: original.SubscriptionID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		Status:         domain.WebhookDeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.InsertDeliveries(ctx, []domain.WebhookDelivery{delivery}); err != nil {
+			return fmt.Errorf("error al registrar la entrega: %w", err)
+		}
+
+		return s.audit.RecordTx(ctx, domain.AuditWebhookRedeliver, domain.AuditEntityWebhook, original.SubscriptionID,
+			map[string]string{"delivery_id": original.ID},
+			map[string]string{"delivery_id": delivery.ID})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+func (s *service) Consume(ctx context.Context, message domain.OutboxMessage) error {
+	ctx, span := tracer.Start(ctx, "webhook.Consume")
+	defer span.End()
+
+	subscriptions, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("error al obtener suscripciones de webhook: %w", err)
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+	deliveries := []domain.WebhookDelivery{}
+
+	for _, subscription := range subscriptions {
+		if !subscription.Accepts(message.EventType) {
+			continue
+		}
+
+		deliveries = append(deliveries, domain.WebhookDelivery{
+			ID:             ulid.GenerateNewULID(),
+			SubscriptionID: subscription.ID,
+			EventID:        message.ID,
+			EventType:      message.EventType,
+			Payload:        message.Payload,
+			Status:         domain.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return s.repo.InsertDeliveries(ctx, deliveries)
+}
+
+func (s *service) Dispatch(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "webhook.Dispatch")
+	defer span.End()
+
+	now := s.now().UTC().Truncate(time.Second)
+
+	due, err := s.repo.ListDue(ctx, now, dispatchBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	subscriptions := map[string]*domain.WebhookSubscription{}
+	processed := 0
+
+	for _, delivery := range due {
+		claimed, err := s.repo.Claim(ctx, delivery.ID, delivery.NextAttemptAt, now.Add(claimLease))
+		if err != nil {
+			return processed, err
+		}
+
+		if !claimed {
+			continue
+		}
+
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = s.repo.FindSubscription(ctx, delivery.SubscriptionID)
+			if err != nil && !errors.Is(err, domain.ErrWebhookNotFound) {
+				return processed, err
+			}
+
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		if err := s.deliver(ctx, &delivery, subscription); err != nil {
+			return processed, err
+		}
+
+		processed++
+	}
+
+	return processed, nil
+}
+
+// deliver realiza un intento de entrega y guarda el resultado.
+func (s *service) deliver(ctx context.Context, delivery *domain.WebhookDelivery, subscription *domain.WebhookSubscription) error {
+	now := s.now().UTC().Truncate(time.Second)
+
+	if subscription == nil || !subscription.IsActive {
+		delivery.Status = domain.WebhookDeliveryFailed
+		delivery.LastError = "la suscripción está inactiva"
+
+		return s.repo.UpdateDelivery(ctx, delivery)
+	}
+
+	headers := map[string]string{
+		"Content-Type":        "application/json",
+		"X-Parking-Event":     delivery.EventType,
+		"X-Parking-Event-Id":  delivery.EventID,
+		"X-Parking-Delivery":  delivery.ID,
+		"X-Parking-Timestamp": strconv.FormatInt(now.Unix(), 10),
+		"X-Parking-Signature": domain.SignWebhook(subscription.Secret, now, delivery.Payload),
+	}
+
+	status, err := s.sender.Send(ctx, subscription.URL, headers, delivery.Payload)
+
+	delivery.Attempts++
+	delivery.LastStatusCode = status
+
+	if err == nil && status >= 200 && status < 300 {
+		delivery.Status = domain.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+
+		return s.repo.UpdateDelivery(ctx, delivery)
+	}
+
+	if err != nil {
+		delivery.LastError = err.Error()
+	} else {
+		delivery.LastError = fmt.Sprintf("el receptor respondió %d", status)
+	}
+
+	delivery.LastError = truncate(delivery.LastError, maxErrorLength)
+
+	if delivery.Attempts >= s.policy.MaxAttempts {
+		delivery.Status = domain.WebhookDeliveryFailed
+	} else {
+		delivery.NextAttemptAt = now.Add(s.policy.NextAttempt(delivery.Attempts))
+	}
+
+	return s.repo.UpdateDelivery(ctx, delivery)
+}
+
+func (s *service) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.Dispatch(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				slog.WarnContext(ctx, "error al despachar webhooks", "error", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// generateSecret genera un secreto aleatorio de 256 bits para firmar las entregas.
+func generateSecret() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+
+	return "whsec_" + hex.EncodeToString(b)
+}
+
+func withoutSecret(subscription domain.WebhookSubscription) domain.WebhookSubscription {
+	subscription.Secret = ""
+	return subscription
+}
+
+// truncate recorta s a un máximo de n bytes sin partir un carácter UTF-8, que dejaría un texto
+// inválido para las columnas de la base de datos.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n]
+}
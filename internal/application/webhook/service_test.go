@@ -0,0 +1,172 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// memoryRepository guarda una suscripción y sus entregas en memoria.
+type memoryRepository struct {
+	Repository
+	subscription domain.WebhookSubscription
+	deliveries   map[string]*domain.WebhookDelivery
+}
+
+func (r *memoryRepository) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return []domain.WebhookSubscription{r.subscription}, nil
+}
+
+func (r *memoryRepository) FindSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	subscription := r.subscription
+	return &subscription, nil
+}
+
+func (r *memoryRepository) InsertDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	for _, delivery := range deliveries {
+		r.deliveries[delivery.ID] = &delivery
+	}
+
+	return nil
+}
+
+func (r *memoryRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	due := []domain.WebhookDelivery{}
+	for _, delivery := range r.deliveries {
+		if delivery.Status == domain.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, *delivery)
+		}
+	}
+
+	return due, nil
+}
+
+func (r *memoryRepository) Claim(ctx context.Context, id string, expected, leaseUntil time.Time) (bool, error) {
+	r.deliveries[id].NextAttemptAt = leaseUntil
+	return true, nil
+}
+
+func (r *memoryRepository) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	stored := *delivery
+	r.deliveries[delivery.ID] = &stored
+	return nil
+}
+
+// stubSender responde con el código indicado y recuerda las cabeceras del último envío.
+type stubSender struct {
+	status  int
+	err     error
+	headers map[string]string
+	body    []byte
+}
+
+func (s *stubSender) Send(ctx context.Context, url string, headers map[string]string, body []byte) (int, error) {
+	s.headers, s.body = headers, body
+	return s.status, s.err
+}
+
+func TestDispatch(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	policy := domain.WebhookRetryPolicy{MaxAttempts: 3, BackoffBase: 30 * time.Second, BackoffMax: time.Hour}
+
+	tests := []struct {
+		name         string
+		status       int
+		err          error
+		attempts     int // Intentos previos
+		wantStatus   string
+		wantAttempts int
+		wantNext     time.Duration // Espera hasta el siguiente intento (solo si sigue pendiente)
+	}{
+		{"Entrega exitosa", 204, nil, 0, domain.WebhookDeliveryDelivered, 1, 0},
+		{"Error del receptor reintenta", 500, nil, 0, domain.WebhookDeliveryPending, 1, 30 * time.Second},
+		{"Backoff exponencial", 503, nil, 1, domain.WebhookDeliveryPending, 2, time.Minute},
+		{"Error de red reintenta", 0, errors.New("conexión rechazada"), 0, domain.WebhookDeliveryPending, 1, 30 * time.Second},
+		{"Falla al agotar los intentos", 500, nil, 2, domain.WebhookDeliveryFailed, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryRepository{
+				subscription: domain.WebhookSubscription{
+					ID:         "sub",
+					URL:        "https://example.com/hook",
+					EventTypes: []domain.EventType{domain.EventVehicleEntered},
+					Secret:     "whsec_test",
+					IsActive:   true,
+				},
+				deliveries: map[string]*domain.WebhookDelivery{},
+			}
+
+			sender := &stubSender{status: tt.status, err: tt.err}
+			svc := NewService(nil, repo, sender, nil, policy).(*service)
+			svc.now = func() time.Time { return start }
+
+			message, _ := domain.NewOutboxMessage(domain.Event{
+				ID:         "evt",
+				Type:       domain.EventVehicleEntered,
+				OccurredAt: start,
+				Data:       map[string]string{"plate": "ABC123"},
+			})
+
+			if err := svc.Consume(context.Background(), *message); err != nil || len(repo.deliveries) != 1 {
+				t.Fatalf("Consume() = %d entregas, %v; se esperaba 1", len(repo.deliveries), err)
+			}
+
+			var delivery *domain.WebhookDelivery
+			for _, d := range repo.deliveries {
+				delivery = d
+			}
+
+			delivery.Attempts = tt.attempts
+
+			if n, err := svc.Dispatch(context.Background()); err != nil || n != 1 {
+				t.Fatalf("Dispatch() = %d, %v; se esperaba 1 entrega procesada", n, err)
+			}
+
+			got := repo.deliveries[delivery.ID]
+			if got.Status != tt.wantStatus || got.Attempts != tt.wantAttempts {
+				t.Errorf("estado = %s (%d intentos), se esperaba %s (%d intentos)", got.Status, got.Attempts, tt.wantStatus, tt.wantAttempts)
+			}
+
+			if tt.wantStatus == domain.WebhookDeliveryPending && !got.NextAttemptAt.Equal(start.Add(tt.wantNext)) {
+				t.Errorf("siguiente intento = %v, se esperaba %v", got.NextAttemptAt, start.Add(tt.wantNext))
+			}
+
+			signature := domain.SignWebhook("whsec_test", start, sender.body)
+			if sender.headers["X-Parking-Signature"] != signature {
+				t.Errorf("firma = %q, se esperaba %q", sender.headers["X-Parking-Signature"], signature)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		n    int
+		want string
+	}{
+		{"Texto corto", "conexión rechazada", 50, "conexión rechazada"},
+		{"Corte en ASCII", "timeout", 4, "time"},
+		{"Corte dentro de un carácter de dos bytes", "conexión", 7, "conexi"},
+		{"Corte después de un carácter de dos bytes", "conexión", 8, "conexió"},
+		{"Corte dentro de un carácter de cuatro bytes", "error 🚗", 8, "error "},
+		{"Longitud máxima de error", strings.Repeat("ñ", maxErrorLength), maxErrorLength, strings.Repeat("ñ", maxErrorLength/2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncate(tt.s, tt.n)
+			if got != tt.want || !utf8.ValidString(got) || len(got) > tt.n {
+				t.Errorf("truncate(%q, %d) = %q, se esperaba %q", tt.s, tt.n, got, tt.want)
+			}
+		})
+	}
+}
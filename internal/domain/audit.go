@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// AuditGenesisHash es el hash previo de la primera entrada de la cadena de auditoría.
+var AuditGenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// Tipos de entidad registrados en la auditoría.
+const (
+	AuditEntityUser          = "user"
+	AuditEntityRole          = "role"
+	AuditEntityParkingRecord = "parking_record"
+	AuditEntityMFA           = "user_mfa"
+	AuditEntityWebhook       = "webhook"
+	AuditEntityVehicleType   = "vehicle_type"
+	AuditEntityBackup        = "backup"
+)
+
+// Acciones registradas en la auditoría.
+const (
+	AuditUserCreate         = "user.create"
+	AuditUserUpdate         = "user.update"
+	AuditUserToggleActive   = "user.toggle_active"
+	AuditUserDelete         = "user.delete"
+	AuditUserAnonymize      = "user.anonymize"
+	AuditUserHardDelete     = "user.hard_delete"
+	AuditUserUnlock         = "user.unlock"
+	AuditUserUsernameChange = "user.username_change"
+	AuditUserPasswordChange = "user.password_change"
+	AuditUserPasswordReset  = "user.password_reset"
+	AuditUserProvision      = "user.provision"
+	AuditUserRoleSync       = "user.role_sync"
+	AuditUserIdentityLink   = "user.identity_link"
+	AuditRoleCreate         = "role.create"
+	AuditRoleUpdate         = "role.update"
+	AuditRoleDelete         = "role.delete"
+	AuditParkingEntry       = "parking.entry"
+	AuditParkingExit        = "parking.exit"
+	AuditParkingVoid        = "parking.void"
+	AuditParkingArchive     = "parking.archive"
+	AuditMFAEnable          = "mfa.enable"
+	AuditMFADisable         = "mfa.disable"
+	AuditMFAReset           = "mfa.reset"
+	AuditWebhookCreate      = "webhook.create"
+	AuditWebhookUpdate      = "webhook.update"
+	AuditWebhookDelete      = "webhook.delete"
+	AuditWebhookRedeliver   = "webhook.redeliver"
+	AuditVehicleTypeCreate  = "vehicle_type.create"
+	AuditVehicleTypeUpdate  = "vehicle_type.update"
+	AuditBackupCreate       = "backup.create"
+)
+
+// AuditEntry es una entrada inmutable del registro de auditoría. Cada entrada incluye el hash de
+// la anterior, de modo que modificar o eliminar una entrada rompe la cadena.
+type AuditEntry struct {
+	ID         string          `json:"id"`
+	Seq        int64           `json:"seq"`
+	ActorID    string          `json:"actor_id"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	IP         string          `json:"ip"`
+	RequestID  string          `json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	PrevHash   string          `json:"prev_hash"`
+	Hash       string          `json:"hash"`
+}
+
+// ComputeHash calcula el hash SHA-256 de la entrada encadenado con PrevHash. Los campos se
+// serializan en un orden fijo para que el resultado no dependa de la base de datos.
+func (e AuditEntry) ComputeHash() string {
+	data, _ := json.Marshal([]any{
+		e.Seq,
+		e.ID,
+		e.ActorID,
+		e.Action,
+		e.EntityType,
+		e.EntityID,
+		string(e.Before),
+		string(e.After),
+		e.IP,
+		e.RequestID,
+		e.CreatedAt.UTC().Format(time.RFC3339),
+		e.PrevHash,
+	})
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Link enlaza la entrada a continuación de la entrada con el hash indicado.
+func (e *AuditEntry) Link(seq int64, prevHash string) {
+	e.Seq = seq
+	e.PrevHash = prevHash
+	e.Hash = e.ComputeHash()
+}
+
+// AuditFilter define los criterios de búsqueda del registro de auditoría. Los campos vacíos no
+// filtran. BeforeSeq permite paginar hacia atrás a partir de la última entrada recibida.
+type AuditFilter struct {
+	ActorID    string
+	Action     string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+	BeforeSeq  int64
+	Limit      int
+}
+
+// AuditVerification es el resultado de verificar la cadena de hashes.
+type AuditVerification struct {
+	Valid   bool  `json:"valid"`
+	Entries int64 `json:"entries"`
+	// BrokenAtSeq es la primera entrada cuyo hash o enlace no coincide.
+	BrokenAtSeq int64  `json:"broken_at_seq,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
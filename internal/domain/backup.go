@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+)
+
+// BackupNamePattern es el formato de los nombres de las copias de seguridad. Impide que un nombre
+// recibido por la API apunte fuera del directorio de copias.
+var BackupNamePattern = regexp.MustCompile(`^parking-\d{8}-\d{6}\.db$`)
+
+// BackupPolicy define dónde se guardan las copias de seguridad y cuántas se conservan.
+type BackupPolicy struct {
+	// Directory es la carpeta donde se escriben las copias.
+	Directory string
+	// Interval es el tiempo entre copias automáticas. 0 las desactiva.
+	Interval time.Duration
+	// Keep es la cantidad de copias más recientes que se conservan; las demás se eliminan.
+	Keep int
+}
+
+// Scheduled indica si las copias automáticas están activas.
+func (p BackupPolicy) Scheduled() bool {
+	return p.Interval > 0
+}
+
+// BackupName retorna el nombre de la copia creada en t. El orden alfabético de los nombres es
+// también el orden cronológico.
+func BackupName(t time.Time) string {
+	return "parking-" + t.UTC().Format("20060102-150405") + ".db"
+}
+
+// Backup describe una copia de seguridad de la base de datos.
+type Backup struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -3,9 +3,38 @@ package domain
 import "errors"
 
 var (
-	ErrAdminProtected     = errors.New("no puedes hacer cambios sobre 'admin'")
-	ErrInvalidCredentials = errors.New("credenciales inválidas")
-	ErrUserInactive       = errors.New("usuario bloqueado o inactivo")
+	ErrAdminProtected        = errors.New("no puedes hacer cambios sobre 'admin'")
+	ErrAdminPasswordRequired = errors.New("se requiere una contraseña para crear el usuario 'admin'")
+	ErrInvalidCredentials    = errors.New("credenciales inválidas")
+	ErrUserInactive          = errors.New("usuario bloqueado o inactivo")
+	ErrUserLocked            = errors.New("cuenta bloqueada por demasiados intentos fallidos")
+	ErrTooManyAttempts       = errors.New("demasiados intentos de inicio de sesión, intenta más tarde")
+)
+
+var (
+	ErrPasswordTooShort       = errors.New("la contraseña es demasiado corta")
+	ErrPasswordTooWeak        = errors.New("la contraseña no cumple los requisitos de complejidad")
+	ErrPasswordReused         = errors.New("la contraseña ya fue utilizada anteriormente")
+	ErrCurrentPasswordInvalid = errors.New("la contraseña actual es incorrecta")
+	ErrPasswordChangeRequired = errors.New("debes cambiar tu contraseña antes de continuar")
+)
+
+var (
+	ErrMFANotEnrolled        = errors.New("la autenticación de dos factores no está configurada")
+	ErrMFAAlreadyEnabled     = errors.New("la autenticación de dos factores ya está activa")
+	ErrInvalidMFACode        = errors.New("código de verificación inválido")
+	ErrMFAEnrollmentRequired = errors.New("debes configurar la autenticación de dos factores antes de continuar")
+	ErrMFARequiredByRole     = errors.New("tu rol requiere autenticación de dos factores")
+)
+
+var (
+	ErrSSODisabled           = errors.New("el inicio de sesión único no está configurado")
+	ErrSSOStateInvalid       = errors.New("la solicitud de inicio de sesión único es inválida o expiró")
+	ErrSSOBusy               = errors.New("demasiados inicios de sesión único en curso, intenta más tarde")
+	ErrSSOLoginFailed        = errors.New("el proveedor de identidad rechazó el inicio de sesión")
+	ErrSSONoRole             = errors.New("tu cuenta no pertenece a ningún grupo con acceso al sistema")
+	ErrIdentityNotFound      = errors.New("identidad externa no vinculada")
+	ErrIdentityAlreadyLinked = errors.New("la identidad externa ya está vinculada a un usuario")
 )
 
 var (
@@ -13,8 +42,44 @@ var (
 	ErrVehicleTypeNotFound          = errors.New("tipo de vehículo no encontrado")
 	ErrParkingRecordNotFound        = errors.New("registro de estacionamiento no encontrado")
 	ErrActiveParkingNotFound        = errors.New("no se encontró un registro de entrada activo para esta placa")
+	ErrParkingRecordVoided          = errors.New("el registro de estacionamiento ya fue anulado")
+	ErrInvalidVoidReason            = errors.New("motivo de anulación inválido: use de 1 a 255 caracteres")
 	ErrUsernameAlreadyExists        = errors.New("nombre de usuario ya existe")
+	ErrUserHasRecords               = errors.New("el usuario tiene registros de estacionamiento asociados y no puede eliminarse definitivamente; usa la eliminación lógica o la anonimización")
 	ErrVehicleTypeNameAlreadyExists = errors.New("nombre de tipo de vehículo ya existe")
 	ErrActiveParkingAlreadyExists   = errors.New("ya existe un registro de estacionamiento abierto para esta placa")
 	ErrVehicleTypeInUse             = errors.New("tipo de vehículo está actualmente en uso")
+	ErrInvalidVehicleTypeName       = errors.New("nombre de tipo de vehículo inválido: use de 1 a 50 caracteres")
+	ErrInvalidHourlyRate            = errors.New("la tarifa por hora no puede ser negativa")
+	ErrRoleNotFound                 = errors.New("rol no encontrado")
+	ErrRoleAlreadyExists            = errors.New("el rol ya existe")
+	ErrRoleInUse                    = errors.New("el rol está asignado a uno o más usuarios")
+	ErrRoleProtected                = errors.New("el rol 'admin' no puede modificarse y los roles predefinidos no pueden eliminarse")
+	ErrInvalidRoleName              = errors.New("nombre de rol inválido: use de 3 a 50 caracteres en minúscula, números, '-' o '_'")
+	ErrInvalidPermission            = errors.New("permiso inválido")
+	ErrInvalidRole                  = errors.New("rol de usuario inválido. Consulta los roles disponibles en /admin/roles")
+	ErrRoleNotGrantable             = errors.New("no puedes asignar ni administrar un rol con permisos que tú no tienes")
+)
+
+var (
+	ErrArchiveConflict  = errors.New("el lote de registros cambió durante el archivado; se reintentará en la próxima ejecución")
+	ErrInvalidDateRange = errors.New("rango de fechas inválido: use el formato YYYY-MM-DD y una fecha final mayor o igual a la inicial")
+)
+
+var ErrInvalidEventType = errors.New("tipo de evento inválido: use VehicleEntered, VehicleExited, CapacityChanged o UserDeactivated")
+
+var (
+	ErrWebhookNotFound         = errors.New("suscripción de webhook no encontrada")
+	ErrWebhookDeliveryNotFound = errors.New("entrega de webhook no encontrada")
+	ErrInvalidWebhookURL       = errors.New("la URL del webhook debe ser http o https")
+	ErrInvalidWebhookEventType = errors.New("tipos de evento inválidos: use VehicleEntered o VehicleExited")
+)
+
+var (
+	ErrBackupNotSupported = errors.New("la copia de seguridad integrada solo está disponible para SQLite; use mysqldump o pg_dump")
+	ErrBackupNotFound     = errors.New("copia de seguridad no encontrada")
+	ErrBackupExists       = errors.New("ya existe una copia de seguridad con ese nombre")
+	ErrBackupCorrupt      = errors.New("la copia de seguridad no superó la verificación de integridad")
+	ErrBackupSchemaNewer  = errors.New("la copia de seguridad tiene un esquema más reciente que esta versión de la aplicación")
+	ErrDatabaseInUse      = errors.New("la base de datos está en uso por otro proceso; detenga el servidor antes de restaurar")
 )
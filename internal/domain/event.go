@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// EventType identifica un evento de dominio publicado en tiempo real.
+type EventType = string
+
+const (
+	EventVehicleEntered  EventType = "VehicleEntered"
+	EventVehicleExited   EventType = "VehicleExited"
+	EventParkingVoided   EventType = "ParkingRecordVoided"
+	EventCapacityChanged EventType = "CapacityChanged"
+	EventUserDeactivated EventType = "UserDeactivated"
+)
+
+// eventPermissions indica el permiso necesario para recibir cada tipo de evento.
+var eventPermissions = map[EventType]Permission{
+	EventVehicleEntered:  PermParkingRead,
+	EventVehicleExited:   PermParkingRead,
+	EventParkingVoided:   PermParkingRead,
+	EventCapacityChanged: PermParkingRead,
+	EventUserDeactivated: PermUsersRead,
+}
+
+// EventPermission retorna el permiso necesario para recibir el tipo de evento.
+func EventPermission(eventType EventType) Permission {
+	return eventPermissions[eventType]
+}
+
+// EventTypes retorna los tipos de evento publicados.
+func EventTypes() []EventType {
+	return []EventType{EventVehicleEntered, EventVehicleExited, EventParkingVoided, EventCapacityChanged, EventUserDeactivated}
+}
+
+// Event es un evento de dominio. Data contiene la entidad afectada y se serializa como JSON.
+type Event struct {
+	ID         string    `json:"id"` // ULID, creciente en el tiempo
+	Type       EventType `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// CapacityStatus es el contenido de EventCapacityChanged.
+type CapacityStatus struct {
+	Occupied int `json:"occupied"`
+	// Capacity es la cantidad de espacios configurada; 0 si no hay límite definido.
+	Capacity  int `json:"capacity,omitempty"`
+	Available int `json:"available,omitempty"`
+}
+
+// UserDeactivatedData es el contenido de EventUserDeactivated.
+type UserDeactivatedData struct {
+	UserID string `json:"user_id"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+// HealthStatus es el estado de la aplicación o de una de sus dependencias.
+type HealthStatus string
+
+const (
+	// HealthOK indica que la dependencia responde con normalidad.
+	HealthOK HealthStatus = "ok"
+	// HealthDegraded indica que una dependencia no crítica falla; la aplicación sigue atendiendo
+	// solicitudes.
+	HealthDegraded HealthStatus = "degraded"
+	// HealthUnavailable indica que la aplicación no puede atender solicitudes.
+	HealthUnavailable HealthStatus = "unavailable"
+)
+
+// Health es el resultado de verificar si la aplicación está lista para atender solicitudes.
+type Health struct {
+	Status HealthStatus            `json:"status"`
+	Checks map[string]HealthStatus `json:"checks"`
+}
+
+// Ready indica si la aplicación puede recibir tráfico, aunque alguna dependencia esté degradada.
+func (h Health) Ready() bool {
+	return h.Status != HealthUnavailable
+}
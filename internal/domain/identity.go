@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// UserIdentity vincula un usuario local con su cuenta en un proveedor de identidad externo (OIDC).
+type UserIdentity struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Issuer    string    `json:"issuer"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExternalIdentity contiene los claims verificados del token de identidad del proveedor.
+type ExternalIdentity struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	PreferredUsername string
+	Groups            []string
+}
+
+// GroupRoleMapping asigna un rol a los miembros de un grupo del proveedor de identidad.
+type GroupRoleMapping struct {
+	Group string
+	Role  Role
+}
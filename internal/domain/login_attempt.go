@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// Motivos registrados para cada intento de inicio de sesión.
+const (
+	LoginReasonSuccess         = "success"
+	LoginReasonUnknownUser     = "unknown_user"
+	LoginReasonInvalidPassword = "invalid_password"
+	LoginReasonInactive        = "inactive"
+	LoginReasonLocked          = "locked"
+	LoginReasonThrottled       = "throttled"
+	LoginReasonInvalidMFACode  = "invalid_mfa_code"
+)
+
+type LoginAttempt struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	IP        string    `json:"ip"`
+	Success   bool      `json:"success"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LockoutPolicy define la protección contra ataques de fuerza bruta en el inicio de sesión.
+type LockoutPolicy struct {
+	// MaxFailures es la cantidad de fallos consecutivos que bloquean la cuenta.
+	MaxFailures int
+	// IPMaxFailures es la cantidad de fallos desde una misma IP, dentro de Window,
+	// a partir de la cual se aplica espera exponencial a la IP.
+	IPMaxFailures int
+	// Window es el periodo considerado para contar los fallos por IP.
+	Window time.Duration
+	// BackoffBase es la espera tras el primer fallo; se duplica con cada fallo adicional.
+	BackoffBase time.Duration
+	// BackoffMax es la espera máxima entre intentos.
+	BackoffMax time.Duration
+}
+
+// Backoff calcula la espera requerida después de la cantidad de fallos indicada.
+func (p LockoutPolicy) Backoff(failures int) time.Duration {
+	if failures <= 0 || p.BackoffBase <= 0 {
+		return 0
+	}
+
+	wait := p.BackoffBase
+	for i := 1; i < failures; i++ {
+		wait *= 2
+		if wait >= p.BackoffMax {
+			return p.BackoffMax
+		}
+	}
+
+	return min(wait, p.BackoffMax)
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockoutPolicyBackoff(t *testing.T) {
+	policy := LockoutPolicy{
+		BackoffBase: time.Second,
+		BackoffMax:  time.Minute,
+	}
+
+	tests := []struct {
+		name     string
+		failures int
+		expected time.Duration
+	}{
+		{"Sin fallos", 0, 0},
+		{"Primer fallo", 1, time.Second},
+		{"Tercer fallo", 3, 4 * time.Second},
+		{"Sexto fallo", 6, 32 * time.Second},
+		{"Límite máximo", 20, time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if wait := policy.Backoff(tt.failures); wait != tt.expected {
+				t.Errorf("Espera incorrecta. Esperado: %v, Obtenido: %v", tt.expected, wait)
+			}
+		})
+	}
+}
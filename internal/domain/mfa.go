@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// RecoveryCodeCount es la cantidad de códigos de recuperación generados en cada inscripción.
+const RecoveryCodeCount = 10
+
+// UserMFA representa la configuración de autenticación de dos factores (TOTP) de un usuario.
+type UserMFA struct {
+	UserID string
+	Secret string
+	// EnabledAt es nil mientras la inscripción no haya sido confirmada con un código válido.
+	EnabledAt *time.Time
+	// LastUsedStep es el último periodo TOTP aceptado; impide reutilizar un mismo código.
+	LastUsedStep int64
+	CreatedAt    time.Time
+}
+
+// IsEnabled indica si la inscripción fue confirmada.
+func (m UserMFA) IsEnabled() bool {
+	return m.EnabledAt != nil
+}
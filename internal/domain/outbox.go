@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxMessage es un evento guardado en la misma transacción que el cambio que lo origina. Un
+// proceso en segundo plano lo entrega después a los consumidores durables (ej. webhooks), de modo
+// que solo se notifican cambios confirmados y ninguno se pierde si la aplicación se detiene.
+type OutboxMessage struct {
+	ID          string          `json:"id"` // ULID, coincide con el ID del evento
+	EventType   EventType       `json:"event_type"`
+	Payload     json.RawMessage `json:"payload"` // Event serializado
+	OccurredAt  time.Time       `json:"occurred_at"`
+	ProcessedAt *time.Time      `json:"processed_at"`
+}
+
+// NewOutboxMessage serializa el evento como mensaje pendiente.
+func NewOutboxMessage(event Event) (*OutboxMessage, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxMessage{
+		ID:         event.ID,
+		EventType:  event.Type,
+		Payload:    payload,
+		OccurredAt: event.OccurredAt,
+	}, nil
+}
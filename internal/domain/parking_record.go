@@ -11,4 +11,20 @@ type ParkingRecord struct {
 	ExitTime        *time.Time `json:"exit_time"`
 	TotalCharge     *float64   `json:"total_charge"`
 	CalculatedHours *int       `json:"calculated_hours"`
+
+	// Username es el nombre del operador que registró la entrada, aunque haya sido eliminado.
+	Username string `json:"username,omitempty"`
+
+	// VoidedAt indica que el registro fue anulado; no cuenta en los reportes.
+	VoidedAt   *time.Time `json:"voided_at,omitempty"`
+	VoidedBy   string     `json:"voided_by,omitempty"`
+	VoidReason string     `json:"void_reason,omitempty"`
+}
+
+// MaxVoidReasonLength es la longitud máxima del motivo de anulación.
+const MaxVoidReasonLength = 255
+
+// IsVoided indica si el registro fue anulado.
+func (r ParkingRecord) IsVoided() bool {
+	return r.VoidedAt != nil
 }
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// PasswordPolicy define las reglas que debe cumplir toda contraseña nueva.
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// HistorySize es la cantidad de contraseñas anteriores que no pueden reutilizarse.
+	HistorySize int
+}
+
+// Validate verifica la longitud y complejidad de la contraseña.
+// La verificación contra el historial se realiza en la capa de aplicación.
+func (p PasswordPolicy) Validate(password string) error {
+	if len([]rune(password)) < p.MinLength {
+		return fmt.Errorf("%w: mínimo %d caracteres", ErrPasswordTooShort, p.MinLength)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	if p.RequireUpper && !hasUpper {
+		return fmt.Errorf("%w: debe contener al menos una mayúscula", ErrPasswordTooWeak)
+	}
+
+	if p.RequireLower && !hasLower {
+		return fmt.Errorf("%w: debe contener al menos una minúscula", ErrPasswordTooWeak)
+	}
+
+	if p.RequireDigit && !hasDigit {
+		return fmt.Errorf("%w: debe contener al menos un número", ErrPasswordTooWeak)
+	}
+
+	if p.RequireSymbol && !hasSymbol {
+		return fmt.Errorf("%w: debe contener al menos un símbolo", ErrPasswordTooWeak)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength:    8,
+		RequireUpper: true,
+		RequireLower: true,
+		RequireDigit: true,
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		expected error
+	}{
+		{"Contraseña válida", "Parqueo2024", nil},
+		{"Muy corta", "Pa1", ErrPasswordTooShort},
+		{"Sin mayúsculas", "parqueo2024", ErrPasswordTooWeak},
+		{"Sin minúsculas", "PARQUEO2024", ErrPasswordTooWeak},
+		{"Sin números", "ParqueoSeguro", ErrPasswordTooWeak},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.password)
+
+			if tt.expected == nil && err != nil {
+				t.Errorf("No se esperaba error. Obtenido: %v", err)
+			}
+
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("Error incorrecto. Esperado: %v, Obtenido: %v", tt.expected, err)
+			}
+		})
+	}
+
+	t.Run("Símbolo requerido", func(t *testing.T) {
+		strict := policy
+		strict.RequireSymbol = true
+
+		if err := strict.Validate("Parqueo2024"); !errors.Is(err, ErrPasswordTooWeak) {
+			t.Errorf("Se esperaba %v. Obtenido: %v", ErrPasswordTooWeak, err)
+		}
+
+		if err := strict.Validate("Parqueo-2024"); err != nil {
+			t.Errorf("No se esperaba error. Obtenido: %v", err)
+		}
+	})
+}
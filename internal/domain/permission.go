@@ -0,0 +1,39 @@
+package domain
+
+import "slices"
+
+type Permission = string
+
+const (
+	PermParkingRead      Permission = "parking:read"
+	PermParkingWrite     Permission = "parking:write"
+	PermParkingVoid      Permission = "parking:void"
+	PermReportsRead      Permission = "reports:read"
+	PermVehicleTypesRead Permission = "vehicle_types:read"
+	PermUsersRead        Permission = "users:read"
+	PermUsersManage      Permission = "users:manage"
+	PermRolesManage      Permission = "roles:manage"
+	PermAuditRead        Permission = "audit:read"
+	PermWebhooksManage   Permission = "webhooks:manage"
+	PermBackupsManage    Permission = "backups:manage"
+)
+
+// Permissions es el catálogo de permisos reconocidos por el sistema.
+var Permissions = []Permission{
+	PermParkingRead,
+	PermParkingWrite,
+	PermParkingVoid,
+	PermReportsRead,
+	PermVehicleTypesRead,
+	PermUsersRead,
+	PermUsersManage,
+	PermRolesManage,
+	PermAuditRead,
+	PermWebhooksManage,
+	PermBackupsManage,
+}
+
+// IsValidPermission indica si el permiso pertenece al catálogo.
+func IsValidPermission(p Permission) bool {
+	return slices.Contains(Permissions, p)
+}
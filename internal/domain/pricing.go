@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// PricingPolicy define cómo se convierte el tiempo de estadía en horas cobradas.
+type PricingPolicy struct {
+	// MinimumHours es la cantidad mínima de horas que se cobra por estadía.
+	MinimumHours int
+	// RoundUpAfter es la fracción de hora a partir de la cual se cobra la hora completa
+	// (ej. 30m: 1h 29m = 1h, 1h 30m = 2h).
+	RoundUpAfter time.Duration
+}
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"time"
+)
+
+// Destinos posibles de los registros archivados.
+const (
+	RetentionModeTable = "table"
+	RetentionModeFile  = "file"
+)
+
+// RetentionPolicy define cuánto tiempo se conservan en línea los registros de estacionamiento
+// cerrados y cómo se archivan los más antiguos.
+type RetentionPolicy struct {
+	// Months es la cantidad de meses que se conservan en PARKING_RECORDS. 0 desactiva el archivado.
+	Months int
+	// Mode indica si los registros se mueven a PARKING_RECORDS_ARCHIVE (table) o se exportan a
+	// archivos JSONL comprimidos (file).
+	Mode string
+	// Directory es la carpeta de los archivos exportados en modo file.
+	Directory string
+	// PlateKey, si no está vacía, seudonimiza las placas archivadas con HMAC-SHA256.
+	PlateKey string
+	// BatchSize es la cantidad de registros movidos por transacción.
+	BatchSize int
+	// Interval es el tiempo entre ejecuciones del proceso en segundo plano.
+	Interval time.Duration
+}
+
+// Enabled indica si el archivado está activo.
+func (p RetentionPolicy) Enabled() bool {
+	return p.Months > 0
+}
+
+// Cutoff calcula la fecha a partir de la cual los registros se conservan en línea. Se redondea al
+// inicio del día (UTC) para que un mismo día nunca quede repartido entre línea y archivo.
+func (p RetentionPolicy) Cutoff(now time.Time) time.Time {
+	day := now.UTC().AddDate(0, -p.Months, 0)
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// PseudonymizePlate reemplaza una placa por un identificador estable que no permite recuperarla
+// sin la clave, de modo que siga siendo posible contar visitas de un mismo vehículo.
+func PseudonymizePlate(key, plate string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(plate))
+
+	return "ps-" + hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// DailyParkingSummary agrupa los registros cerrados de un día (según la hora de salida) y tipo
+// de vehículo.
+type DailyParkingSummary struct {
+	Day           string  `json:"day"` // YYYY-MM-DD (UTC)
+	VehicleTypeID string  `json:"vehicle_type_id"`
+	Records       int     `json:"records"`
+	Hours         int     `json:"hours"`
+	Revenue       float64 `json:"revenue"`
+}
+
+// SummarizeDaily agrega los registros cerrados por día de salida y tipo de vehículo. Los registros
+// anulados no se cuentan.
+func SummarizeDaily(records []ParkingRecord) []DailyParkingSummary {
+	type key struct{ day, vehicleTypeID string }
+
+	totals := map[key]*DailyParkingSummary{}
+
+	for _, record := range records {
+		if record.ExitTime == nil || record.IsVoided() {
+			continue
+		}
+
+		k := key{record.ExitTime.UTC().Format(time.DateOnly), record.VehicleTypeID}
+
+		summary, ok := totals[k]
+		if !ok {
+			summary = &DailyParkingSummary{Day: k.day, VehicleTypeID: k.vehicleTypeID}
+			totals[k] = summary
+		}
+
+		summary.Records++
+
+		if record.CalculatedHours != nil {
+			summary.Hours += *record.CalculatedHours
+		}
+
+		if record.TotalCharge != nil {
+			summary.Revenue += *record.TotalCharge
+		}
+	}
+
+	return sortedSummaries(totals)
+}
+
+// MergeDailySummaries combina resúmenes de varias fuentes (registros en línea y archivados)
+// sumando los que corresponden al mismo día y tipo de vehículo.
+func MergeDailySummaries(sources ...[]DailyParkingSummary) []DailyParkingSummary {
+	type key struct{ day, vehicleTypeID string }
+
+	totals := map[key]*DailyParkingSummary{}
+
+	for _, source := range sources {
+		for _, s := range source {
+			k := key{s.Day, s.VehicleTypeID}
+
+			summary, ok := totals[k]
+			if !ok {
+				summary = &DailyParkingSummary{Day: k.day, VehicleTypeID: k.vehicleTypeID}
+				totals[k] = summary
+			}
+
+			summary.Records += s.Records
+			summary.Hours += s.Hours
+			summary.Revenue += s.Revenue
+		}
+	}
+
+	return sortedSummaries(totals)
+}
+
+func sortedSummaries[K comparable](totals map[K]*DailyParkingSummary) []DailyParkingSummary {
+	summaries := make([]DailyParkingSummary, 0, len(totals))
+	for _, summary := range totals {
+		summaries = append(summaries, *summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Day != summaries[j].Day {
+			return summaries[i].Day < summaries[j].Day
+		}
+
+		return summaries[i].VehicleTypeID < summaries[j].VehicleTypeID
+	})
+
+	return summaries
+}
+
+// RetentionResult resume una ejecución del archivado.
+type RetentionResult struct {
+	Cutoff   time.Time `json:"cutoff"`
+	Archived int       `json:"archived"`
+	Batches  int       `json:"batches"`
+}
+
+// RevenueReport resume los ingresos de un rango de días, combinando los registros en línea con
+// los totales de los registros archivados.
+type RevenueReport struct {
+	From    string                `json:"from"`
+	To      string                `json:"to"`
+	Days    []DailyParkingSummary `json:"days"`
+	Records int                   `json:"records"`
+	Hours   int                   `json:"hours"`
+	Revenue float64               `json:"revenue"`
+}
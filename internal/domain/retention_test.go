@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetentionPolicyCutoff(t *testing.T) {
+	policy := RetentionPolicy{Months: 24}
+
+	now := time.Date(2026, 10, 19, 15, 30, 0, 0, time.FixedZone("GMT-6", -6*60*60))
+	want := time.Date(2024, 10, 19, 0, 0, 0, 0, time.UTC)
+
+	if got := policy.Cutoff(now); !got.Equal(want) {
+		t.Errorf("Cutoff() = %v, se esperaba %v", got, want)
+	}
+}
+
+func TestSummarizeAndMergeDaily(t *testing.T) {
+	exit := func(s string) *time.Time {
+		t, _ := time.Parse(time.RFC3339, s)
+		return &t
+	}
+
+	hours := func(h int) *int { return &h }
+	charge := func(c float64) *float64 { return &c }
+
+	records := []ParkingRecord{
+		{VehicleTypeID: "auto", ExitTime: exit("2024-01-01T10:00:00Z"), CalculatedHours: hours(2), TotalCharge: charge(10)},
+		{VehicleTypeID: "auto", ExitTime: exit("2024-01-01T23:59:59Z"), CalculatedHours: hours(1), TotalCharge: charge(5)},
+		{VehicleTypeID: "moto", ExitTime: exit("2024-01-01T12:00:00Z"), CalculatedHours: hours(3), TotalCharge: charge(6)},
+		{VehicleTypeID: "auto", ExitTime: exit("2024-01-02T00:00:00Z"), CalculatedHours: hours(1), TotalCharge: charge(5)},
+		{VehicleTypeID: "auto"}, // Abierto: no se agrega
+		{VehicleTypeID: "moto", ExitTime: exit("2024-01-01T13:00:00Z"), CalculatedHours: hours(1), TotalCharge: charge(2), VoidedAt: exit("2024-01-01T13:05:00Z")}, // Anulado
+	}
+
+	archived := SummarizeDaily(records)
+
+	tests := []struct {
+		name string
+		got  []DailyParkingSummary
+		want []DailyParkingSummary
+	}{
+		{"Agrupa por día y tipo", archived, []DailyParkingSummary{
+			{Day: "2024-01-01", VehicleTypeID: "auto", Records: 2, Hours: 3, Revenue: 15},
+			{Day: "2024-01-01", VehicleTypeID: "moto", Records: 1, Hours: 3, Revenue: 6},
+			{Day: "2024-01-02", VehicleTypeID: "auto", Records: 1, Hours: 1, Revenue: 5},
+		}},
+		{"Combina archivados y en línea", MergeDailySummaries(archived, []DailyParkingSummary{
+			{Day: "2024-01-02", VehicleTypeID: "auto", Records: 1, Hours: 2, Revenue: 10},
+		}), []DailyParkingSummary{
+			{Day: "2024-01-01", VehicleTypeID: "auto", Records: 2, Hours: 3, Revenue: 15},
+			{Day: "2024-01-01", VehicleTypeID: "moto", Records: 1, Hours: 3, Revenue: 6},
+			{Day: "2024-01-02", VehicleTypeID: "auto", Records: 2, Hours: 3, Revenue: 15},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.got) != len(tt.want) {
+				t.Fatalf("se obtuvieron %d resúmenes, se esperaban %d: %+v", len(tt.got), len(tt.want), tt.got)
+			}
+
+			for i := range tt.want {
+				if tt.got[i] != tt.want[i] {
+					t.Errorf("resumen %d = %+v, se esperaba %+v", i, tt.got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
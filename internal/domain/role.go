@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"regexp"
+	"slices"
+	"time"
+)
+
+// RoleNamePattern define el formato válido para el nombre de un rol personalizado.
+var RoleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{2,49}$`)
+
+type RoleDefinition struct {
+	Name        Role         `json:"name"`
+	Description string       `json:"description"`
+	Permissions []Permission `json:"permissions"`
+	// IsSystem indica que el rol es predefinido y no puede eliminarse.
+	IsSystem  bool      `json:"is_system"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Has indica si el rol concede el permiso indicado.
+func (r RoleDefinition) Has(p Permission) bool {
+	return slices.Contains(r.Permissions, p)
+}
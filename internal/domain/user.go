@@ -1,21 +1,85 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Role = string
 
 const AdminUsername = "admin"
 
+// Roles predefinidos. Sus permisos se definen en la tabla ROLE_PERMISSIONS.
 const (
-	RoleAdmin  Role = "admin"
-	RoleCommon Role = "common"
+	RoleAdmin      Role = "admin"
+	RoleCommon     Role = "common"
+	RoleSupervisor Role = "supervisor"
+	RoleCashier    Role = "cashier"
+	RoleAuditor    Role = "auditor"
 )
 
 type User struct {
-	ID        string    `json:"id"`
-	Username  string    `json:"username"`
-	Password  string    `json:"-"`
-	Role      Role      `json:"role"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
+	ID                  string     `json:"id"`
+	Username            string     `json:"username"`
+	Password            string     `json:"-"`
+	Role                Role       `json:"role"`
+	IsActive            bool       `json:"is_active"`
+	MustChangePassword  bool       `json:"must_change_password"`
+	FailedLoginAttempts int        `json:"-"`
+	LastFailedLoginAt   *time.Time `json:"-"`
+	LockedAt            *time.Time `json:"locked_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+	DeletedAt           *time.Time `json:"deleted_at,omitempty"`
+	// TokenVersion se incluye en los tokens emitidos; al incrementarse, los revoca.
+	TokenVersion int `json:"-"`
+}
+
+// UserAuditSnapshot es el estado de un usuario que se guarda en la auditoría. Omite el nombre de
+// usuario, ya que la auditoría es inmutable y no se modifica al anonimizar al usuario.
+type UserAuditSnapshot struct {
+	ID                 string     `json:"id"`
+	Role               Role       `json:"role"`
+	IsActive           bool       `json:"is_active"`
+	MustChangePassword bool       `json:"must_change_password"`
+	LockedAt           *time.Time `json:"locked_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	DeletedAt          *time.Time `json:"deleted_at,omitempty"`
+}
+
+// AuditSnapshot retorna el estado del usuario que se guarda en la auditoría.
+func (u User) AuditSnapshot() UserAuditSnapshot {
+	return UserAuditSnapshot{
+		ID:                 u.ID,
+		Role:               u.Role,
+		IsActive:           u.IsActive,
+		MustChangePassword: u.MustChangePassword,
+		LockedAt:           u.LockedAt,
+		CreatedAt:          u.CreatedAt,
+		DeletedAt:          u.DeletedAt,
+	}
+}
+
+// UserDeleteMode define cómo se elimina un usuario.
+type UserDeleteMode string
+
+const (
+	// UserDeleteSoft marca al usuario como eliminado y libera su nombre de usuario. El historial
+	// conserva su nombre.
+	UserDeleteSoft UserDeleteMode = "soft"
+	// UserDeleteAnonymize elimina lógicamente al usuario y reemplaza sus datos personales
+	// (nombre de usuario, identidades externas, segundo factor e intentos de inicio de sesión).
+	UserDeleteAnonymize UserDeleteMode = "anonymize"
+	// UserDeleteHard elimina al usuario definitivamente. Solo es posible si no tiene registros
+	// de estacionamiento asociados.
+	UserDeleteHard UserDeleteMode = "hard"
+)
+
+// IsValid indica si el modo de eliminación es conocido.
+func (m UserDeleteMode) IsValid() bool {
+	return m == UserDeleteSoft || m == UserDeleteAnonymize || m == UserDeleteHard
+}
+
+// AnonymizedUsername genera el nombre de usuario que reemplaza al original al anonimizar.
+func AnonymizedUsername(id string) string {
+	return "eliminado-" + strings.ToLower(id)
 }
@@ -1,8 +1,36 @@
 package domain
 
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// VehicleTypeNameMaxLength es la longitud máxima del nombre de un tipo de vehículo.
+const VehicleTypeNameMaxLength = 50
+
 type VehicleType struct {
 	ID          string  `json:"id"`
 	Name        string  `json:"name"`
 	HourlyRate  float64 `json:"hourly_rate"`
 	Description string  `json:"description"`
 }
+
+// Validate verifica que el tipo tenga nombre y una tarifa por hora no negativa.
+func (v VehicleType) Validate() error {
+	name := strings.TrimSpace(v.Name)
+	if name == "" || utf8.RuneCountInString(name) > VehicleTypeNameMaxLength {
+		return ErrInvalidVehicleTypeName
+	}
+
+	if v.HourlyRate < 0 {
+		return ErrInvalidHourlyRate
+	}
+
+	return nil
+}
+
+// VehicleTypeImportResult resume una importación de tipos de vehículo.
+type VehicleTypeImportResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
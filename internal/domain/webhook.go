@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Estados de una entrega de webhook.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookEventTypes son los eventos que pueden notificarse por webhook. Llegan a través de la
+// bandeja de salida, por lo que nunca se pierden ni se notifican cambios que no se confirmaron.
+var WebhookEventTypes = []EventType{EventVehicleEntered, EventVehicleExited, EventParkingVoided}
+
+// WebhookSubscription es un destino configurado por un administrador para recibir eventos.
+type WebhookSubscription struct {
+	ID         string      `json:"id"` // ULID
+	URL        string      `json:"url"`
+	EventTypes []EventType `json:"event_types"`
+	// Secret firma las entregas. Solo se muestra al crear la suscripción.
+	Secret    string    `json:"secret,omitempty"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate verifica la URL y los tipos de evento de la suscripción.
+func (s WebhookSubscription) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+
+	if len(s.EventTypes) == 0 {
+		return ErrInvalidWebhookEventType
+	}
+
+	for _, t := range s.EventTypes {
+		if !slices.Contains(WebhookEventTypes, t) {
+			return ErrInvalidWebhookEventType
+		}
+	}
+
+	return nil
+}
+
+// Accepts indica si la suscripción está activa y recibe el tipo de evento.
+func (s WebhookSubscription) Accepts(eventType EventType) bool {
+	return s.IsActive && slices.Contains(s.EventTypes, eventType)
+}
+
+// WebhookDelivery es una notificación pendiente o realizada. Las entregas pendientes forman la
+// cola de reintentos que procesa el despachador.
+type WebhookDelivery struct {
+	ID             string          `json:"id"` // ULID
+	SubscriptionID string          `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      EventType       `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+// WebhookRetryPolicy define los reintentos de las entregas fallidas.
+type WebhookRetryPolicy struct {
+	MaxAttempts int
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+}
+
+// NextAttempt calcula la espera antes del siguiente intento tras attempts intentos fallidos:
+// BackoffBase * 2^(attempts-1), con un máximo de BackoffMax.
+func (p WebhookRetryPolicy) NextAttempt(attempts int) time.Duration {
+	delay := p.BackoffBase
+	for i := 1; i < attempts && delay < p.BackoffMax; i++ {
+		delay *= 2
+	}
+
+	return min(delay, p.BackoffMax)
+}
+
+// SignWebhook calcula la firma de una entrega: HMAC-SHA256 de "<timestamp>.<cuerpo>" con el
+// secreto de la suscripción, en hexadecimal y con el prefijo "sha256=". Incluir el timestamp
+// permite al receptor rechazar entregas repetidas antiguas.
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
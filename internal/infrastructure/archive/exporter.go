@@ -0,0 +1,185 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/JGCaceres97/parking/internal/application/retention"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type fileExporter struct {
+	dir string
+}
+
+// NewFileExporter crea un exportador que escribe los registros en archivos JSONL comprimidos con
+// gzip dentro de dir, uno por día de salida.
+func NewFileExporter(dir string) retention.Exporter {
+	return &fileExporter{dir: dir}
+}
+
+// Export agrega los registros a parking-records-<fecha>.jsonl.gz según su día de salida. Cada
+// archivo conserva una sola copia por ID: si un registro ya estaba exportado se reemplaza, de modo
+// que reintentar un lote (aunque se arme con otros registros) nunca duplica filas. El archivo se
+// reescribe en un temporal y se renombra al terminar, así que nunca queda incompleto.
+func (e *fileExporter) Export(ctx context.Context, records []domain.ParkingRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(e.dir, 0o750); err != nil {
+		return fmt.Errorf("error al crear el directorio de archivo: %w", err)
+	}
+
+	days := []string{}
+	byDay := map[string][]domain.ParkingRecord{}
+
+	for _, record := range records {
+		day := record.ExitTime.UTC().Format("20060102")
+
+		if _, ok := byDay[day]; !ok {
+			days = append(days, day)
+		}
+
+		byDay[day] = append(byDay[day], record)
+	}
+
+	for _, day := range days {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		name := filepath.Join(e.dir, fmt.Sprintf("parking-records-%s.jsonl.gz", day))
+
+		if err := e.merge(name, byDay[day]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportedLine es una línea ya exportada; solo se lee el ID para detectar duplicados.
+type exportedLine struct {
+	id   string
+	data json.RawMessage
+}
+
+// merge reescribe name con las líneas existentes más records, reemplazando las de igual ID.
+func (e *fileExporter) merge(name string, records []domain.ParkingRecord) error {
+	lines, err := readLines(name)
+	if err != nil {
+		return err
+	}
+
+	index := make(map[string]int, len(lines))
+	for i, line := range lines {
+		index[line.id] = i
+	}
+
+	for _, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("error al serializar registro archivado: %w", err)
+		}
+
+		if i, ok := index[record.ID]; ok {
+			lines[i].data = data
+			continue
+		}
+
+		index[record.ID] = len(lines)
+		lines = append(lines, exportedLine{id: record.ID, data: data})
+	}
+
+	tmp, err := os.CreateTemp(e.dir, filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error al crear archivo temporal: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	gz := gzip.NewWriter(tmp)
+	w := bufio.NewWriter(gz)
+
+	for _, line := range lines {
+		if _, err := w.Write(append(line.data, '\n')); err != nil {
+			tmp.Close()
+			return fmt.Errorf("error al escribir registro archivado: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error al escribir registro archivado: %w", err)
+	}
+
+	if err := gz.Close(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error al comprimir archivo: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error al sincronizar archivo: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error al cerrar archivo: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return fmt.Errorf("error al renombrar archivo: %w", err)
+	}
+
+	return nil
+}
+
+// readLines lee un archivo exportado. Si todavía no existe, retorna una lista vacía.
+func readLines(name string) ([]exportedLine, error) {
+	f, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error al abrir archivo exportado: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("error al descomprimir %s: %w", filepath.Base(name), err)
+	}
+	defer gz.Close()
+
+	lines := []exportedLine{}
+	decoder := json.NewDecoder(gz)
+
+	for {
+		var data json.RawMessage
+
+		if err := decoder.Decode(&data); err == io.EOF {
+			return lines, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("error al leer %s: %w", filepath.Base(name), err)
+		}
+
+		var record struct {
+			ID string `json:"id"`
+		}
+
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, fmt.Errorf("error al leer %s: %w", filepath.Base(name), err)
+		}
+
+		lines = append(lines, exportedLine{id: record.ID, data: data})
+	}
+}
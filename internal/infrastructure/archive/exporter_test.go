@@ -0,0 +1,91 @@
+package archive
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+func exportedRecords(t *testing.T, name string) []domain.ParkingRecord {
+	t.Helper()
+
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := []domain.ParkingRecord{}
+	decoder := json.NewDecoder(gz)
+
+	for decoder.More() {
+		var record domain.ParkingRecord
+		if err := decoder.Decode(&record); err != nil {
+			t.Fatal(err)
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestExportIsIdempotentByID(t *testing.T) {
+	dir := t.TempDir()
+	exporter := NewFileExporter(dir)
+
+	exit := time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)
+	nextDay := exit.Add(24 * time.Hour)
+
+	record := func(id, plate string, exitTime time.Time) domain.ParkingRecord {
+		return domain.ParkingRecord{ID: id, LicensePlate: plate, EntryTime: exitTime.Add(-time.Hour), ExitTime: &exitTime}
+	}
+
+	// El reintento llega en un lote armado distinto: repite "b" con otra placa y agrega "c".
+	batches := [][]domain.ParkingRecord{
+		{record("a", "AAA111", exit), record("b", "BBB222", exit)},
+		{record("b", "ps-bbb", exit), record("c", "CCC333", exit), record("d", "DDD444", nextDay)},
+	}
+
+	for _, batch := range batches {
+		if err := exporter.Export(context.Background(), batch); err != nil {
+			t.Fatalf("Export() = %v", err)
+		}
+	}
+
+	records := exportedRecords(t, filepath.Join(dir, "parking-records-20250310.jsonl.gz"))
+
+	var ids, plates []string
+	for _, r := range records {
+		ids = append(ids, r.ID)
+		plates = append(plates, r.LicensePlate)
+	}
+
+	if len(records) != 3 || ids[0] != "a" || ids[1] != "b" || ids[2] != "c" || plates[1] != "ps-bbb" {
+		t.Errorf("archivo del 10/03 = IDs %v con placas %v, se esperaba a, b (ps-bbb), c", ids, plates)
+	}
+
+	if next := exportedRecords(t, filepath.Join(dir, "parking-records-20250311.jsonl.gz")); len(next) != 1 || next[0].ID != "d" {
+		t.Errorf("archivo del 11/03 = %+v, se esperaba solo el registro d", next)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Errorf("el directorio tiene %d archivos, se esperaban 2 sin temporales", len(entries))
+	}
+}
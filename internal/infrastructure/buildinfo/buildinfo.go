@@ -0,0 +1,85 @@
+// Package buildinfo expone la versión del binario. Commit y BuildTime se inyectan al compilar:
+//
+//	go build -ldflags "-X github.com/JGCaceres97/parking/internal/infrastructure/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/JGCaceres97/parking/internal/infrastructure/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Sin ellos, el commit se obtiene de los datos de control de versiones que registra `go build`, si
+// existen. La fecha de compilación no se registra, por lo que se informa "unknown"; en su lugar se
+// informa la fecha del commit.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Valores inyectados con -ldflags -X.
+var (
+	// Commit es el hash del commit compilado.
+	Commit string
+	// BuildTime es la fecha de compilación en formato RFC 3339.
+	BuildTime string
+)
+
+// unknown se informa cuando no se conoce un dato de la compilación.
+const unknown = "unknown"
+
+// Info describe el binario en ejecución.
+type Info struct {
+	Commit string `json:"commit"`
+	// CommitTime es la fecha del commit, según los datos de control de versiones.
+	CommitTime string `json:"commit_time"`
+	BuildTime  string `json:"build_time"`
+	GoVersion  string `json:"go_version"`
+	// Modified indica que el binario se compiló con cambios sin confirmar.
+	Modified bool `json:"modified"`
+}
+
+// Get retorna la información de la compilación.
+func Get() Info {
+	info := Info{Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				// Describe el código, no la compilación: no reemplaza a BuildTime.
+				info.CommitTime = setting.Value
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = unknown
+	}
+
+	if info.CommitTime == "" {
+		info.CommitTime = unknown
+	}
+
+	if info.BuildTime == "" {
+		info.BuildTime = unknown
+	}
+
+	return info
+}
+
+// Short retorna una descripción de una línea, ej. para `parking-system --version`.
+func (i Info) Short() string {
+	commit := i.Commit
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+
+	if i.Modified {
+		commit += "-dirty"
+	}
+
+	return commit + " (" + i.BuildTime + ", " + i.GoVersion + ")"
+}
@@ -1,76 +1,711 @@
 package config
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
-)
 
-const (
-	// HandlerTimeout es el tiempo máximo de espera para todas las operaciones del handler.
-	HandlerTimeout = 60 * time.Second
-	// DBTimeout es el tiempo máximo de espera para cualquier operación de DB.
-	DBTimeout = 10 * time.Second
+	"github.com/JGCaceres97/parking/internal/domain"
 )
 
+// DefaultJWTSecret es el secreto HS256 por defecto; solo se permite en modo desarrollo.
+const DefaultJWTSecret = "secret-key-to-sign-jwt"
+
 type Config struct {
 	AdminPassword string
 	DBDriver      string
 	DBConnString  string
+	// DBAutoMigrate aplica las migraciones pendientes al iniciar el servidor.
+	DBAutoMigrate bool
+	DBTimeout     time.Duration
+	DBPool        DBPoolConfig
+	DevMode       bool
 	JWTSecretKey  string
-	ServerPort    string
-	TokenDuration time.Duration
+	// JWTPrivateKeyFile es la clave privada PEM (RSA o Ed25519) con la que se firman los tokens.
+	// Si está vacía se firma con JWTSecretKey (HS256).
+	JWTPrivateKeyFile string
+	// JWTPublicKeyFiles son claves públicas PEM adicionales aceptadas durante una rotación.
+	JWTPublicKeyFiles []string
+	Server            ServerConfig
+	CORS              CORSConfig
+	Log               LogConfig
+	Metrics           MetricsConfig
+	Tracing           TracingConfig
+	Health            HealthConfig
+	TokenDuration     time.Duration
+	PasswordPolicy    domain.PasswordPolicy
+	LockoutPolicy     domain.LockoutPolicy
+	MFAIssuer         string
+	// MFARequiredRoles son los roles que deben configurar autenticación de dos factores.
+	MFARequiredRoles []domain.Role
+	OIDC             OIDCConfig
+	Pricing          domain.PricingPolicy
+	Retention        domain.RetentionPolicy
+	Backup           domain.BackupPolicy
+	// ParkingCapacity es la cantidad de espacios informada en los eventos de ocupación (0 = sin límite).
+	ParkingCapacity int
+	// EventBufferSize es la cantidad de eventos pendientes por suscriptor antes de desconectarlo.
+	EventBufferSize int
+	// OutboxPollInterval es el tiempo entre revisiones de la bandeja de salida de eventos.
+	OutboxPollInterval time.Duration
+	Webhooks           WebhookConfig
+
+	// values son los valores efectivos de cada opción, para imprimirlos.
+	values values
 }
 
-func GetEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
-	}
+// ServerConfig configura el servidor HTTP.
+type ServerConfig struct {
+	Port string
+	// HandlerTimeout es el tiempo máximo de respuesta de las rutas, salvo los flujos de eventos.
+	HandlerTimeout    time.Duration
+	ReadHeaderTimeout time.Duration
+	IdleTimeout       time.Duration
+	// ShutdownTimeout es la espera máxima de las solicitudes en curso al detener el servidor.
+	ShutdownTimeout time.Duration
+	// ShutdownDelay es el tiempo que /readyz informa unavailable antes de dejar de aceptar
+	// conexiones, para que el balanceador deje de enviar solicitudes.
+	ShutdownDelay time.Duration
+	// TLSCertFile y TLSKeyFile habilitan HTTPS cuando ambos están definidos.
+	TLSCertFile string
+	TLSKeyFile  string
+}
+
+// TLS indica si el servidor atiende por HTTPS.
+func (c ServerConfig) TLS() bool {
+	return c.TLSCertFile != ""
+}
+
+// CORSConfig configura las solicitudes desde otros orígenes. Se habilita al definir
+// AllowedOrigins.
+type CORSConfig struct {
+	// AllowedOrigins son los orígenes permitidos (ej. https://app.example.com) o "*".
+	AllowedOrigins []string
+	// MaxAge es el tiempo que el navegador conserva la respuesta de verificación previa.
+	MaxAge time.Duration
+}
+
+// LogConfig configura el registro de la aplicación.
+type LogConfig struct {
+	Level slog.Level
+	// Format es text o json.
+	Format string
+}
+
+// MetricsConfig configura la ruta /metrics en el formato de Prometheus.
+type MetricsConfig struct {
+	Enabled bool
+	// Token es el token Bearer que se exige para consultar las métricas. Es obligatorio fuera del
+	// modo desarrollo.
+	Token string
+}
 
-	return defaultValue
+// TracingConfig configura el envío de trazas de OpenTelemetry por OTLP/HTTP.
+type TracingConfig struct {
+	Enabled bool
+	// OTLPEndpoint es la URL del colector (ej. http://localhost:4318).
+	OTLPEndpoint string
+	// SampleRatio es la fracción de trazas nuevas que se conservan, entre 0 y 1.
+	SampleRatio float64
 }
 
-func Load() *Config {
+// HealthConfig configura las verificaciones de /readyz.
+type HealthConfig struct {
+	// Timeout es el tiempo máximo de todas las verificaciones de dependencias.
+	Timeout time.Duration
+	// OutboxMaxLag es la antigüedad máxima de un evento pendiente de la bandeja de salida antes
+	// de informar la dependencia como degradada.
+	OutboxMaxLag time.Duration
+}
+
+// DBPoolConfig configura el pool de conexiones de MySQL y PostgreSQL. SQLite utiliza siempre una
+// sola conexión.
+type DBPoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// WebhookConfig configura el envío de webhooks.
+type WebhookConfig struct {
+	Retry domain.WebhookRetryPolicy
+	// Timeout es el tiempo máximo de espera de la respuesta del receptor.
+	Timeout time.Duration
+	// PollInterval es el tiempo entre revisiones de la bandeja de salida.
+	PollInterval time.Duration
+}
+
+// OIDCConfig configura el inicio de sesión único. Se habilita al definir IssuerURL.
+type OIDCConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	GroupsClaim  string
+	// GroupRoles asigna roles según los grupos del usuario; se evalúa en orden.
+	GroupRoles []domain.GroupRoleMapping
+	// DefaultRole se asigna si ningún grupo coincide. Si está vacío, se rechaza el acceso.
+	DefaultRole domain.Role
+	// PostLoginURL es la ruta del frontend a la que se redirige con el token en el fragmento.
+	// Si está vacía, el callback responde el token como JSON.
+	PostLoginURL string
+}
+
+// Options indica los valores que reemplazan a las variables de entorno.
+type Options struct {
+	// File es el archivo YAML de configuración. Si está vacío se usa CONFIG_FILE.
+	File string
+	// Overrides son valores "clave=valor" con prioridad sobre el archivo y el entorno.
+	Overrides []string
+}
+
+// Load obtiene la configuración de, en orden de prioridad, opts.Overrides, las variables de
+// entorno (incluido .env), el archivo de configuración y los valores por defecto. Todas las
+// opciones se validan y los problemas se informan juntos.
+func Load(opts Options) (*Config, error) {
 	if err := godotenv.Load(); err != nil {
-		log.Println("Advertencia: No se pudo cargar el archivo .env. Usando variables de entorno o defaults.")
+		slog.Warn("no se pudo cargar el archivo .env; se usan las variables de entorno y los valores por defecto")
 	}
 
-	duration, err := time.ParseDuration(GetEnv("TOKEN_DURATION_HOURS", "10") + "h")
+	vals, err := resolve(opts)
 	if err != nil {
-		log.Printf("Advertencia: No se pudo parsear TOKEN_DURATION_HOURS. Usando 10h.")
-		duration = 10 * time.Hour
+		return nil, err
 	}
 
-	var dsn string
-	driver := GetEnv("DB_DRIVER", "sqlite")
+	p := &parser{values: vals, failed: map[string]bool{}}
+	cfg := p.config()
+
+	if len(p.problems) > 0 {
+		return nil, invalidConfig(p.problems)
+	}
+
+	return cfg, nil
+}
+
+func invalidConfig(problems []string) error {
+	return errors.New("configuración inválida:\n  - " + strings.Join(problems, "\n  - "))
+}
+
+// parser interpreta y valida los valores efectivos, acumulando los problemas encontrados.
+type parser struct {
+	values   values
+	problems []string
+	// failed son las opciones con un problema ya informado, para no repetirlo en la validación.
+	failed map[string]bool
+}
+
+func (p *parser) value(env string) value {
+	v, ok := p.values[env]
+	if !ok {
+		panic("config: opción no registrada " + env)
+	}
+
+	return v
+}
+
+// fail registra un problema de la opción env, identificada según su origen.
+func (p *parser) fail(env, format string, args ...any) {
+	if p.failed[env] {
+		return
+	}
+
+	p.failed[env] = true
+	p.problems = append(p.problems, p.value(env).name+": "+fmt.Sprintf(format, args...))
+}
+
+func (p *parser) string(env string) string {
+	return p.value(env).raw
+}
+
+func (p *parser) list(env string) []string {
+	return splitList(p.value(env).raw)
+}
+
+func (p *parser) int(env string) int {
+	raw := p.value(env).raw
+
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		p.fail(env, "%q no es un número entero", raw)
+	}
+
+	return n
+}
+
+func (p *parser) bool(env string) bool {
+	raw := p.value(env).raw
+
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		p.fail(env, "%q no es un booleano (use true o false)", raw)
+	}
+
+	return b
+}
+
+func (p *parser) float(env string) float64 {
+	raw := p.value(env).raw
+
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		p.fail(env, "%q no es un número", raw)
+	}
+
+	return f
+}
+
+func (p *parser) duration(env string) time.Duration {
+	raw := p.value(env).raw
+
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		p.fail(env, "%q no es una duración (ej. 30s, 15m, 24h)", raw)
+	}
+
+	return d
+}
+
+// atLeast registra un problema si n es menor que minimum.
+func (p *parser) atLeast(env string, n, minimum int) {
+	if n < minimum {
+		p.fail(env, "debe ser al menos %d", minimum)
+	}
+}
+
+// durationAtLeast registra un problema si d es menor que minimum.
+func (p *parser) durationAtLeast(env string, d, minimum time.Duration) {
+	if d < minimum {
+		p.fail(env, "debe ser al menos %s", minimum)
+	}
+}
+
+// oneOf registra un problema si v no es una de las opciones.
+func (p *parser) oneOf(env, v string, options ...string) {
+	for _, option := range options {
+		if v == option {
+			return
+		}
+	}
+
+	p.fail(env, "%q no es válido, use %s", v, strings.Join(options, ", "))
+}
+
+func (p *parser) config() *Config {
+	devMode := p.bool("DEV_MODE")
+
+	cfg := &Config{
+		AdminPassword:     p.adminPassword(devMode),
+		DBDriver:          p.string("DB_DRIVER"),
+		DBConnString:      p.dsn(devMode),
+		DBAutoMigrate:     p.bool("DB_AUTO_MIGRATE"),
+		DBTimeout:         p.duration("DB_TIMEOUT"),
+		DBPool:            p.dbPool(),
+		DevMode:           devMode,
+		JWTSecretKey:      p.jwtSecret(devMode),
+		JWTPrivateKeyFile: p.string("JWT_PRIVATE_KEY_FILE"),
+		JWTPublicKeyFiles: p.list("JWT_PUBLIC_KEY_FILES"),
+		Server:            p.server(),
+		CORS:              p.cors(),
+		Log:               p.log(),
+		Metrics:           p.metrics(devMode),
+		Tracing:           p.tracing(),
+		Health: HealthConfig{
+			Timeout:      p.duration("HEALTH_TIMEOUT"),
+			OutboxMaxLag: p.duration("HEALTH_OUTBOX_MAX_LAG"),
+		},
+		TokenDuration: time.Duration(p.int("TOKEN_DURATION_HOURS")) * time.Hour,
+		PasswordPolicy: domain.PasswordPolicy{
+			MinLength:     p.int("PASSWORD_MIN_LENGTH"),
+			RequireUpper:  p.bool("PASSWORD_REQUIRE_UPPER"),
+			RequireLower:  p.bool("PASSWORD_REQUIRE_LOWER"),
+			RequireDigit:  p.bool("PASSWORD_REQUIRE_DIGIT"),
+			RequireSymbol: p.bool("PASSWORD_REQUIRE_SYMBOL"),
+			HistorySize:   p.int("PASSWORD_HISTORY_SIZE"),
+		},
+		LockoutPolicy: domain.LockoutPolicy{
+			MaxFailures:   p.int("LOGIN_MAX_FAILURES"),
+			IPMaxFailures: p.int("LOGIN_IP_MAX_FAILURES"),
+			Window:        p.duration("LOGIN_FAILURE_WINDOW"),
+			BackoffBase:   p.duration("LOGIN_BACKOFF_BASE"),
+			BackoffMax:    p.duration("LOGIN_BACKOFF_MAX"),
+		},
+		MFAIssuer:        p.string("MFA_ISSUER"),
+		MFARequiredRoles: p.list("MFA_REQUIRED_ROLES"),
+		OIDC:             p.oidc(),
+		Pricing: domain.PricingPolicy{
+			MinimumHours: p.int("PRICING_MINIMUM_HOURS"),
+			RoundUpAfter: p.duration("PRICING_ROUND_UP_AFTER"),
+		},
+		Retention:          p.retention(),
+		Backup:             p.backup(),
+		ParkingCapacity:    p.int("PARKING_CAPACITY"),
+		EventBufferSize:    p.int("EVENT_BUFFER_SIZE"),
+		OutboxPollInterval: p.duration("OUTBOX_POLL_INTERVAL"),
+		Webhooks: WebhookConfig{
+			Retry: domain.WebhookRetryPolicy{
+				MaxAttempts: p.int("WEBHOOK_MAX_ATTEMPTS"),
+				BackoffBase: p.duration("WEBHOOK_BACKOFF_BASE"),
+				BackoffMax:  p.duration("WEBHOOK_BACKOFF_MAX"),
+			},
+			Timeout:      p.duration("WEBHOOK_TIMEOUT"),
+			PollInterval: p.duration("WEBHOOK_POLL_INTERVAL"),
+		},
+		values: p.values,
+	}
+
+	p.durationAtLeast("DB_TIMEOUT", cfg.DBTimeout, time.Second)
+	p.atLeast("TOKEN_DURATION_HOURS", int(cfg.TokenDuration/time.Hour), 1)
+
+	p.atLeast("PASSWORD_MIN_LENGTH", cfg.PasswordPolicy.MinLength, 1)
+	p.atLeast("PASSWORD_HISTORY_SIZE", cfg.PasswordPolicy.HistorySize, 0)
+
+	p.atLeast("LOGIN_MAX_FAILURES", cfg.LockoutPolicy.MaxFailures, 0)
+	p.atLeast("LOGIN_IP_MAX_FAILURES", cfg.LockoutPolicy.IPMaxFailures, 0)
+	p.durationAtLeast("LOGIN_FAILURE_WINDOW", cfg.LockoutPolicy.Window, time.Second)
+	p.durationAtLeast("LOGIN_BACKOFF_BASE", cfg.LockoutPolicy.BackoffBase, 0)
+	p.durationAtLeast("LOGIN_BACKOFF_MAX", cfg.LockoutPolicy.BackoffMax, cfg.LockoutPolicy.BackoffBase)
+
+	p.atLeast("PRICING_MINIMUM_HOURS", cfg.Pricing.MinimumHours, 0)
+	if cfg.Pricing.RoundUpAfter <= 0 || cfg.Pricing.RoundUpAfter > time.Hour {
+		p.fail("PRICING_ROUND_UP_AFTER", "debe ser mayor que 0 y como máximo 1h")
+	}
+
+	p.atLeast("PARKING_CAPACITY", cfg.ParkingCapacity, 0)
+	p.atLeast("EVENT_BUFFER_SIZE", cfg.EventBufferSize, 1)
+	p.durationAtLeast("OUTBOX_POLL_INTERVAL", cfg.OutboxPollInterval, 100*time.Millisecond)
+
+	p.durationAtLeast("HEALTH_TIMEOUT", cfg.Health.Timeout, 100*time.Millisecond)
+	p.durationAtLeast("HEALTH_OUTBOX_MAX_LAG", cfg.Health.OutboxMaxLag, cfg.OutboxPollInterval)
+
+	p.atLeast("WEBHOOK_MAX_ATTEMPTS", cfg.Webhooks.Retry.MaxAttempts, 1)
+	p.durationAtLeast("WEBHOOK_BACKOFF_BASE", cfg.Webhooks.Retry.BackoffBase, time.Second)
+	p.durationAtLeast("WEBHOOK_BACKOFF_MAX", cfg.Webhooks.Retry.BackoffMax, cfg.Webhooks.Retry.BackoffBase)
+	p.durationAtLeast("WEBHOOK_POLL_INTERVAL", cfg.Webhooks.PollInterval, time.Second)
+	if cfg.Webhooks.Timeout <= 0 || cfg.Webhooks.Timeout > time.Minute {
+		p.fail("WEBHOOK_TIMEOUT", "debe ser mayor que 0 y como máximo 1m")
+	}
+
+	return cfg
+}
+
+// adminPassword obtiene la contraseña con la que se crea el usuario administrador. Solo en modo
+// desarrollo se usa "admin" por defecto; en otro caso el servidor exige definirla para crearlo.
+func (p *parser) adminPassword(devMode bool) string {
+	password := p.string("ADMIN_PASSWORD")
+	if password == "" && devMode {
+		slog.Warn("ADMIN_PASSWORD no está definida; DEV_MODE usa la contraseña 'admin'")
+		return "admin"
+	}
+
+	return password
+}
+
+// jwtSecret obtiene el secreto HS256. El secreto por defecto solo se permite en modo desarrollo,
+// o si los tokens se firman con JWT_PRIVATE_KEY_FILE.
+func (p *parser) jwtSecret(devMode bool) string {
+	secret := p.string("JWT_SECRET")
+	if secret == "" {
+		secret = DefaultJWTSecret
+	}
 
-	switch driver {
+	if secret == DefaultJWTSecret && p.string("JWT_PRIVATE_KEY_FILE") == "" && !devMode {
+		p.fail("JWT_SECRET", "usa el valor por defecto: configure JWT_PRIVATE_KEY_FILE o JWT_SECRET, o active DEV_MODE=true")
+	}
+
+	return secret
+}
+
+func (p *parser) dsn(devMode bool) string {
+	switch driver := p.string("DB_DRIVER"); driver {
 	case "sqlite":
-		dsn = GetEnv("SQLITE_DSN", "file:parking.db?_time_format=sqlite&_pragma=journal_mode(WAL)")
+		return p.string("SQLITE_DSN")
 
 	case "mysql":
-		dsn = fmt.Sprintf(
+		return fmt.Sprintf(
 			"%s:%s@tcp(%s:%s)/%s?parseTime=true&loc=UTC",
-			GetEnv("MYSQL_USER", "root"),
-			GetEnv("MYSQL_PASSWORD", "password"),
-			GetEnv("DB_HOST", "localhost"),
-			GetEnv("DB_PORT", "3306"),
-			GetEnv("MYSQL_DATABASE", "parkingDb"),
+			p.string("MYSQL_USER"),
+			p.dbPassword("MYSQL_PASSWORD", devMode),
+			p.string("DB_HOST"),
+			p.port("3306"),
+			p.string("MYSQL_DATABASE"),
+		)
+
+	case "postgres":
+		return fmt.Sprintf(
+			"postgres://%s:%s@%s:%s/%s?sslmode=%s",
+			p.string("POSTGRES_USER"),
+			p.dbPassword("POSTGRES_PASSWORD", devMode),
+			p.string("DB_HOST"),
+			p.port("5432"),
+			p.string("POSTGRES_DB"),
+			p.string("POSTGRES_SSLMODE"),
 		)
 
 	default:
-		log.Fatalf("unsupported DB_DRIVER: %s", driver)
+		p.fail("DB_DRIVER", "driver %q no soportado, use sqlite, mysql o postgres", driver)
+		return ""
+	}
+}
+
+// dbPassword obtiene la contraseña de la base de datos de env. Solo en modo desarrollo se usa
+// "password" por defecto, que coincide con los contenedores de prueba.
+func (p *parser) dbPassword(env string, devMode bool) string {
+	password := p.string(env)
+	if password != "" {
+		return password
+	}
+
+	if !devMode {
+		p.fail(env, "no está definida: configure la contraseña de la base de datos o active DEV_MODE=true")
+		return ""
+	}
+
+	slog.Warn(env + " no está definida; DEV_MODE usa la contraseña 'password'")
+	return "password"
+}
+
+// port obtiene DB_PORT, o el puerto por defecto del driver si no está definido.
+func (p *parser) port(defaultPort string) string {
+	port := p.string("DB_PORT")
+	if port == "" {
+		return defaultPort
+	}
+
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		p.fail("DB_PORT", "%q no es un puerto válido", port)
+	}
+
+	return port
+}
+
+func (p *parser) dbPool() DBPoolConfig {
+	pool := DBPoolConfig{
+		MaxOpenConns:    p.int("DB_MAX_OPEN_CONNS"),
+		MaxIdleConns:    p.int("DB_MAX_IDLE_CONNS"),
+		ConnMaxLifetime: p.duration("DB_CONN_MAX_LIFETIME"),
+		ConnMaxIdleTime: p.duration("DB_CONN_MAX_IDLE_TIME"),
+	}
+
+	p.atLeast("DB_MAX_OPEN_CONNS", pool.MaxOpenConns, 1)
+	p.atLeast("DB_MAX_IDLE_CONNS", pool.MaxIdleConns, 0)
+	if pool.MaxIdleConns > pool.MaxOpenConns {
+		p.fail("DB_MAX_IDLE_CONNS", "no puede ser mayor que DB_MAX_OPEN_CONNS (%d)", pool.MaxOpenConns)
+	}
+
+	p.durationAtLeast("DB_CONN_MAX_LIFETIME", pool.ConnMaxLifetime, 0)
+	p.durationAtLeast("DB_CONN_MAX_IDLE_TIME", pool.ConnMaxIdleTime, 0)
+
+	return pool
+}
+
+func (p *parser) server() ServerConfig {
+	server := ServerConfig{
+		Port:              p.string("SERVER_PORT"),
+		HandlerTimeout:    p.duration("SERVER_HANDLER_TIMEOUT"),
+		ReadHeaderTimeout: p.duration("SERVER_READ_HEADER_TIMEOUT"),
+		IdleTimeout:       p.duration("SERVER_IDLE_TIMEOUT"),
+		ShutdownTimeout:   p.duration("SERVER_SHUTDOWN_TIMEOUT"),
+		ShutdownDelay:     p.duration("SERVER_SHUTDOWN_DELAY"),
+		TLSCertFile:       p.string("TLS_CERT_FILE"),
+		TLSKeyFile:        p.string("TLS_KEY_FILE"),
+	}
+
+	if n, err := strconv.Atoi(server.Port); err != nil || n < 1 || n > 65535 {
+		p.fail("SERVER_PORT", "%q no es un puerto válido", server.Port)
+	}
+
+	p.durationAtLeast("SERVER_HANDLER_TIMEOUT", server.HandlerTimeout, time.Second)
+	p.durationAtLeast("SERVER_READ_HEADER_TIMEOUT", server.ReadHeaderTimeout, time.Second)
+	p.durationAtLeast("SERVER_IDLE_TIMEOUT", server.IdleTimeout, 0)
+	p.durationAtLeast("SERVER_SHUTDOWN_TIMEOUT", server.ShutdownTimeout, time.Second)
+	p.durationAtLeast("SERVER_SHUTDOWN_DELAY", server.ShutdownDelay, 0)
+
+	switch {
+	case server.TLSCertFile == "" && server.TLSKeyFile != "":
+		p.fail("TLS_CERT_FILE", "es obligatorio si se define TLS_KEY_FILE")
+	case server.TLSCertFile != "" && server.TLSKeyFile == "":
+		p.fail("TLS_KEY_FILE", "es obligatorio si se define TLS_CERT_FILE")
+	case server.TLSCertFile != "":
+		p.fileExists("TLS_CERT_FILE", server.TLSCertFile)
+		p.fileExists("TLS_KEY_FILE", server.TLSKeyFile)
+	}
+
+	return server
+}
+
+// fileExists registra un problema si no se puede acceder al archivo path.
+func (p *parser) fileExists(env, path string) {
+	if _, err := os.Stat(path); err != nil {
+		p.fail(env, "no se puede acceder a %s: %v", path, errors.Unwrap(err))
+	}
+}
+
+func (p *parser) cors() CORSConfig {
+	cors := CORSConfig{
+		AllowedOrigins: []string{},
+		MaxAge:         p.duration("CORS_MAX_AGE"),
+	}
+
+	for _, origin := range p.list("CORS_ALLOWED_ORIGINS") {
+		if origin != "*" {
+			u, err := url.Parse(origin)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || strings.TrimSuffix(u.Path, "/") != "" {
+				p.fail("CORS_ALLOWED_ORIGINS", "%q no es un origen válido (ej. https://app.example.com) ni *", origin)
+				continue
+			}
+
+			origin = u.Scheme + "://" + u.Host
+		}
+
+		cors.AllowedOrigins = append(cors.AllowedOrigins, origin)
+	}
+
+	p.durationAtLeast("CORS_MAX_AGE", cors.MaxAge, 0)
+
+	return cors
+}
+
+func (p *parser) log() LogConfig {
+	logging := LogConfig{Format: p.string("LOG_FORMAT")}
+
+	level := p.string("LOG_LEVEL")
+	if err := logging.Level.UnmarshalText([]byte(level)); err != nil {
+		p.fail("LOG_LEVEL", "%q no es válido, use debug, info, warn o error", level)
+	}
+
+	p.oneOf("LOG_FORMAT", logging.Format, "text", "json")
+
+	return logging
+}
+
+func (p *parser) metrics(devMode bool) MetricsConfig {
+	metrics := MetricsConfig{
+		Enabled: p.bool("METRICS_ENABLED"),
+		Token:   p.string("METRICS_TOKEN"),
+	}
+
+	if metrics.Enabled && metrics.Token == "" && !devMode {
+		p.fail("METRICS_TOKEN", "es obligatorio si se define METRICS_ENABLED fuera de DEV_MODE")
+	}
+
+	return metrics
+}
+
+func (p *parser) tracing() TracingConfig {
+	tracing := TracingConfig{
+		Enabled:      p.bool("TRACING_ENABLED"),
+		OTLPEndpoint: p.string("TRACING_OTLP_ENDPOINT"),
+		SampleRatio:  p.float("TRACING_SAMPLE_RATIO"),
+	}
+
+	if u, err := url.Parse(tracing.OTLPEndpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		p.fail("TRACING_OTLP_ENDPOINT", "%q no es una URL válida (ej. http://localhost:4318)", tracing.OTLPEndpoint)
+	}
+
+	if tracing.SampleRatio < 0 || tracing.SampleRatio > 1 {
+		p.fail("TRACING_SAMPLE_RATIO", "debe estar entre 0 y 1")
+	}
+
+	return tracing
+}
+
+func (p *parser) oidc() OIDCConfig {
+	oidc := OIDCConfig{
+		IssuerURL:    p.string("OIDC_ISSUER_URL"),
+		ClientID:     p.string("OIDC_CLIENT_ID"),
+		ClientSecret: p.string("OIDC_CLIENT_SECRET"),
+		RedirectURL:  p.string("OIDC_REDIRECT_URL"),
+		Scopes:       p.list("OIDC_SCOPES"),
+		GroupsClaim:  p.string("OIDC_GROUPS_CLAIM"),
+		GroupRoles:   p.groupRoles(),
+		DefaultRole:  p.string("OIDC_DEFAULT_ROLE"),
+		PostLoginURL: p.string("OIDC_POST_LOGIN_URL"),
+	}
+
+	if oidc.IssuerURL == "" {
+		return oidc
+	}
+
+	if u, err := url.Parse(oidc.IssuerURL); err != nil || u.Scheme == "" || u.Host == "" {
+		p.fail("OIDC_ISSUER_URL", "%q no es una URL válida", oidc.IssuerURL)
+	}
+
+	if oidc.ClientID == "" {
+		p.fail("OIDC_CLIENT_ID", "es obligatorio si se define OIDC_ISSUER_URL")
+	}
+
+	if u, err := url.Parse(oidc.RedirectURL); err != nil || u.Scheme == "" || u.Host == "" {
+		p.fail("OIDC_REDIRECT_URL", "%q no es una URL válida", oidc.RedirectURL)
+	}
+
+	return oidc
+}
+
+// groupRoles interpreta OIDC_GROUP_ROLES, una lista "grupo=rol,grupo2=rol2", conservando el orden.
+func (p *parser) groupRoles() []domain.GroupRoleMapping {
+	mappings := []domain.GroupRoleMapping{}
+
+	for _, item := range p.list("OIDC_GROUP_ROLES") {
+		group, role, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(group) == "" || strings.TrimSpace(role) == "" {
+			p.fail("OIDC_GROUP_ROLES", "asignación %q inválida, use grupo=rol", item)
+			continue
+		}
+
+		mappings = append(mappings, domain.GroupRoleMapping{
+			Group: strings.TrimSpace(group),
+			Role:  strings.TrimSpace(role),
+		})
+	}
+
+	return mappings
+}
+
+func (p *parser) retention() domain.RetentionPolicy {
+	retention := domain.RetentionPolicy{
+		Months:    p.int("RETENTION_MONTHS"),
+		Mode:      p.string("RETENTION_MODE"),
+		Directory: p.string("RETENTION_ARCHIVE_DIR"),
+		BatchSize: p.int("RETENTION_BATCH_SIZE"),
+		Interval:  p.duration("RETENTION_INTERVAL"),
+	}
+
+	if p.bool("RETENTION_PSEUDONYMIZE_PLATES") {
+		if retention.PlateKey = p.string("RETENTION_PLATE_KEY"); retention.PlateKey == "" {
+			p.fail("RETENTION_PLATE_KEY", "es obligatoria si RETENTION_PSEUDONYMIZE_PLATES está activo")
+		}
+	}
+
+	p.atLeast("RETENTION_MONTHS", retention.Months, 0)
+	p.oneOf("RETENTION_MODE", retention.Mode, domain.RetentionModeTable, domain.RetentionModeFile)
+	p.atLeast("RETENTION_BATCH_SIZE", retention.BatchSize, 1)
+	p.durationAtLeast("RETENTION_INTERVAL", retention.Interval, time.Minute)
+
+	return retention
+}
+
+func (p *parser) backup() domain.BackupPolicy {
+	backup := domain.BackupPolicy{
+		Directory: p.string("BACKUP_DIR"),
+		Interval:  p.duration("BACKUP_INTERVAL"),
+		Keep:      p.int("BACKUP_KEEP"),
 	}
 
-	return &Config{
-		AdminPassword: GetEnv("ADMIN_PASSWORD", "admin"),
-		DBDriver:      driver,
-		DBConnString:  dsn,
-		JWTSecretKey:  GetEnv("JWT_SECRET", "secret-key-to-sign-jwt"),
-		ServerPort:    GetEnv("SERVER_PORT", "3000"),
-		TokenDuration: duration,
+	if backup.Directory == "" {
+		p.fail("BACKUP_DIR", "es obligatorio")
 	}
+
+	p.durationAtLeast("BACKUP_INTERVAL", backup.Interval, 0)
+	p.atLeast("BACKUP_KEEP", backup.Keep, 1)
+
+	return backup
 }
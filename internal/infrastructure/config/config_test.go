@@ -0,0 +1,164 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	content := "dev:\n  mode: true\nserver:\n  port: 8080\n  handler_timeout: 30s\ndb:\n  max_open_conns: 5\n  max_idle_conns: 5\n" +
+		"cors:\n  allowed_origins: [https://app.example.com/, \"*\"]\npricing:\n  minimum_hours: 2\n"
+
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		opts    Options
+		check   func(t *testing.T, cfg *Config)
+		wantErr []string
+	}{
+		{
+			name: "Valores por defecto en modo desarrollo",
+			env:  map[string]string{"DEV_MODE": "true"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.Port != "3000" || cfg.DBTimeout != 10*time.Second || cfg.Pricing.MinimumHours != 1 {
+					t.Errorf("valores por defecto inesperados: %+v", cfg)
+				}
+
+				if cfg.AdminPassword != "admin" || cfg.JWTSecretKey != DefaultJWTSecret {
+					t.Errorf("se esperaban los secretos de desarrollo, se obtuvo %q y %q", cfg.AdminPassword, cfg.JWTSecretKey)
+				}
+			},
+		},
+		{
+			name: "El entorno reemplaza al archivo y --set al entorno",
+			env:  map[string]string{"SERVER_PORT": "9090", "PRICING_MINIMUM_HOURS": "3", "DB_MAX_OPEN_CONNS": ""},
+			opts: Options{File: file, Overrides: []string{"server.port=7070", "SERVER_HANDLER_TIMEOUT=45s"}},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.Port != "7070" || cfg.Server.HandlerTimeout != 45*time.Second || cfg.Pricing.MinimumHours != 3 {
+					t.Errorf("precedencia incorrecta: %+v", cfg.Server)
+				}
+
+				if cfg.DBPool.MaxOpenConns != 5 {
+					t.Errorf("una variable vacía no debe reemplazar el archivo, se obtuvo %d", cfg.DBPool.MaxOpenConns)
+				}
+
+				if got := strings.Join(cfg.CORS.AllowedOrigins, ","); got != "https://app.example.com,*" {
+					t.Errorf("orígenes CORS = %q", got)
+				}
+			},
+		},
+		{
+			name: "Errores acumulados",
+			env: map[string]string{
+				"DB_DRIVER":            "oracle",
+				"BACKUP_KEEP":          "0",
+				"LOG_LEVEL":            "verbose",
+				"WEBHOOK_TIMEOUT":      "diez",
+				"TLS_KEY_FILE":         "server.key",
+				"DB_MAX_IDLE_CONNS":    "50",
+				"OIDC_GROUP_ROLES":     "admins",
+				"TOKEN_DURATION_HOURS": "0",
+				"METRICS_ENABLED":      "true",
+				"TRACING_SAMPLE_RATIO": "1.5",
+			},
+			wantErr: []string{
+				"JWT_SECRET: usa el valor por defecto",
+				"DB_DRIVER: driver \"oracle\" no soportado",
+				"BACKUP_KEEP: debe ser al menos 1",
+				"LOG_LEVEL: \"verbose\" no es válido",
+				"WEBHOOK_TIMEOUT: \"diez\" no es una duración",
+				"TLS_CERT_FILE: es obligatorio si se define TLS_KEY_FILE",
+				"DB_MAX_IDLE_CONNS: no puede ser mayor que DB_MAX_OPEN_CONNS",
+				"OIDC_GROUP_ROLES: asignación \"admins\" inválida",
+				"TOKEN_DURATION_HOURS: debe ser al menos 1",
+				"METRICS_TOKEN: es obligatorio",
+				"TRACING_SAMPLE_RATIO: debe estar entre 0 y 1",
+			},
+		},
+		{
+			name: "Contraseña de DB por defecto solo en modo desarrollo",
+			env:  map[string]string{"DEV_MODE": "true", "DB_DRIVER": "postgres"},
+			check: func(t *testing.T, cfg *Config) {
+				if !strings.HasPrefix(cfg.DBConnString, "postgres://postgres:password@") {
+					t.Errorf("DSN = %q, se esperaba la contraseña de desarrollo", cfg.DBConnString)
+				}
+			},
+		},
+		{
+			name:    "Contraseña de DB obligatoria fuera de desarrollo",
+			env:     map[string]string{"DB_DRIVER": "mysql", "JWT_SECRET": "muy-secreto"},
+			wantErr: []string{"MYSQL_PASSWORD: no está definida"},
+		},
+		{
+			name:    "Opción desconocida",
+			env:     map[string]string{"DEV_MODE": "true"},
+			opts:    Options{Overrides: []string{"server.puerto=1"}},
+			wantErr: []string{"--set \"server.puerto\": opción desconocida"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := Load(tt.opts)
+			if len(tt.wantErr) > 0 {
+				if err == nil {
+					t.Fatal("se esperaba un error de configuración")
+				}
+
+				for _, want := range tt.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("el error no contiene %q:\n%v", want, err)
+					}
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	t.Setenv("DEV_MODE", "true")
+	t.Setenv("JWT_SECRET", "muy-secreto")
+
+	cfg, err := Load(Options{Overrides: []string{"server.port=8080"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := cfg.Print(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	printed := out.String()
+
+	for _, want := range []string{"secret: '******' # JWT_SECRET", "port: \"8080\" # --set", "mode: true # DEV_MODE", "scopes: [openid, profile, email]"} {
+		if !strings.Contains(printed, want) {
+			t.Errorf("la salida no contiene %q:\n%s", want, printed)
+		}
+	}
+
+	if strings.Contains(printed, "muy-secreto") {
+		t.Error("la salida contiene un secreto")
+	}
+}
@@ -0,0 +1,413 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kind es el tipo de valor de una opción; determina cómo se interpreta y cómo se imprime.
+type kind int
+
+const (
+	kindString kind = iota
+	kindInt
+	kindBool
+	kindDuration
+	kindFloat
+	kindList
+)
+
+// Origen de un valor efectivo.
+const (
+	sourceDefault = iota
+	sourceFile
+	sourceEnv
+	sourceFlag
+)
+
+// setting es una opción de configuración. Su clave en el archivo se deriva de la variable de
+// entorno: el primer guion bajo separa la sección (SERVER_PORT se escribe server.port).
+type setting struct {
+	env    string
+	kind   kind
+	def    string
+	secret bool
+}
+
+func (s setting) key() string {
+	return strings.ToLower(strings.Replace(s.env, "_", ".", 1))
+}
+
+// settings son todas las opciones reconocidas, agrupadas por sección en el orden en que se
+// imprimen.
+var settings = []setting{
+	{env: "DEV_MODE", kind: kindBool, def: "false"},
+
+	{env: "SERVER_PORT", kind: kindString, def: "3000"},
+	{env: "SERVER_HANDLER_TIMEOUT", kind: kindDuration, def: "60s"},
+	{env: "SERVER_READ_HEADER_TIMEOUT", kind: kindDuration, def: "10s"},
+	{env: "SERVER_IDLE_TIMEOUT", kind: kindDuration, def: "2m"},
+	{env: "SERVER_SHUTDOWN_TIMEOUT", kind: kindDuration, def: "15s"},
+	{env: "SERVER_SHUTDOWN_DELAY", kind: kindDuration, def: "0s"},
+
+	{env: "TLS_CERT_FILE", kind: kindString},
+	{env: "TLS_KEY_FILE", kind: kindString},
+
+	{env: "CORS_ALLOWED_ORIGINS", kind: kindList},
+	{env: "CORS_MAX_AGE", kind: kindDuration, def: "10m"},
+
+	{env: "LOG_LEVEL", kind: kindString, def: "info"},
+	{env: "LOG_FORMAT", kind: kindString, def: "text"},
+
+	{env: "METRICS_ENABLED", kind: kindBool, def: "false"},
+	{env: "METRICS_TOKEN", kind: kindString, secret: true},
+
+	{env: "TRACING_ENABLED", kind: kindBool, def: "false"},
+	{env: "TRACING_OTLP_ENDPOINT", kind: kindString, def: "http://localhost:4318"},
+	{env: "TRACING_SAMPLE_RATIO", kind: kindFloat, def: "1"},
+
+	{env: "HEALTH_TIMEOUT", kind: kindDuration, def: "2s"},
+	{env: "HEALTH_OUTBOX_MAX_LAG", kind: kindDuration, def: "5m"},
+
+	{env: "DB_DRIVER", kind: kindString, def: "sqlite"},
+	{env: "DB_AUTO_MIGRATE", kind: kindBool, def: "false"},
+	{env: "DB_TIMEOUT", kind: kindDuration, def: "10s"},
+	{env: "DB_HOST", kind: kindString, def: "localhost"},
+	{env: "DB_PORT", kind: kindString},
+	{env: "DB_MAX_OPEN_CONNS", kind: kindInt, def: "20"},
+	{env: "DB_MAX_IDLE_CONNS", kind: kindInt, def: "20"},
+	{env: "DB_CONN_MAX_LIFETIME", kind: kindDuration, def: "2m"},
+	{env: "DB_CONN_MAX_IDLE_TIME", kind: kindDuration, def: "0"},
+
+	{env: "SQLITE_DSN", kind: kindString, def: "file:parking.db?_time_format=sqlite&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)"},
+
+	{env: "MYSQL_USER", kind: kindString, def: "root"},
+	{env: "MYSQL_PASSWORD", kind: kindString, secret: true},
+	{env: "MYSQL_DATABASE", kind: kindString, def: "parkingDb"},
+
+	{env: "POSTGRES_USER", kind: kindString, def: "postgres"},
+	{env: "POSTGRES_PASSWORD", kind: kindString, secret: true},
+	{env: "POSTGRES_DB", kind: kindString, def: "parkingDb"},
+	{env: "POSTGRES_SSLMODE", kind: kindString, def: "disable"},
+
+	{env: "ADMIN_PASSWORD", kind: kindString, secret: true},
+
+	{env: "JWT_SECRET", kind: kindString, secret: true},
+	{env: "JWT_PRIVATE_KEY_FILE", kind: kindString},
+	{env: "JWT_PUBLIC_KEY_FILES", kind: kindList},
+
+	{env: "TOKEN_DURATION_HOURS", kind: kindInt, def: "10"},
+
+	{env: "PASSWORD_MIN_LENGTH", kind: kindInt, def: "8"},
+	{env: "PASSWORD_REQUIRE_UPPER", kind: kindBool, def: "true"},
+	{env: "PASSWORD_REQUIRE_LOWER", kind: kindBool, def: "true"},
+	{env: "PASSWORD_REQUIRE_DIGIT", kind: kindBool, def: "true"},
+	{env: "PASSWORD_REQUIRE_SYMBOL", kind: kindBool, def: "false"},
+	{env: "PASSWORD_HISTORY_SIZE", kind: kindInt, def: "5"},
+
+	{env: "LOGIN_MAX_FAILURES", kind: kindInt, def: "5"},
+	{env: "LOGIN_IP_MAX_FAILURES", kind: kindInt, def: "20"},
+	{env: "LOGIN_FAILURE_WINDOW", kind: kindDuration, def: "15m"},
+	{env: "LOGIN_BACKOFF_BASE", kind: kindDuration, def: "1s"},
+	{env: "LOGIN_BACKOFF_MAX", kind: kindDuration, def: "15m"},
+
+	{env: "MFA_ISSUER", kind: kindString, def: "Parking"},
+	{env: "MFA_REQUIRED_ROLES", kind: kindList},
+
+	{env: "OIDC_ISSUER_URL", kind: kindString},
+	{env: "OIDC_CLIENT_ID", kind: kindString},
+	{env: "OIDC_CLIENT_SECRET", kind: kindString, secret: true},
+	{env: "OIDC_REDIRECT_URL", kind: kindString, def: "http://localhost:3000/api/v1/login/sso/callback"},
+	{env: "OIDC_SCOPES", kind: kindList, def: "openid,profile,email"},
+	{env: "OIDC_GROUPS_CLAIM", kind: kindString, def: "groups"},
+	{env: "OIDC_GROUP_ROLES", kind: kindList},
+	{env: "OIDC_DEFAULT_ROLE", kind: kindString},
+	{env: "OIDC_POST_LOGIN_URL", kind: kindString},
+
+	{env: "PRICING_MINIMUM_HOURS", kind: kindInt, def: "1"},
+	{env: "PRICING_ROUND_UP_AFTER", kind: kindDuration, def: "30m"},
+
+	{env: "PARKING_CAPACITY", kind: kindInt, def: "0"},
+
+	{env: "RETENTION_MONTHS", kind: kindInt, def: "0"},
+	{env: "RETENTION_MODE", kind: kindString, def: "table"},
+	{env: "RETENTION_ARCHIVE_DIR", kind: kindString, def: "archive"},
+	{env: "RETENTION_PSEUDONYMIZE_PLATES", kind: kindBool, def: "false"},
+	{env: "RETENTION_PLATE_KEY", kind: kindString, secret: true},
+	{env: "RETENTION_BATCH_SIZE", kind: kindInt, def: "500"},
+	{env: "RETENTION_INTERVAL", kind: kindDuration, def: "24h"},
+
+	{env: "BACKUP_DIR", kind: kindString, def: "backups"},
+	{env: "BACKUP_INTERVAL", kind: kindDuration, def: "0"},
+	{env: "BACKUP_KEEP", kind: kindInt, def: "7"},
+
+	{env: "EVENT_BUFFER_SIZE", kind: kindInt, def: "64"},
+
+	{env: "OUTBOX_POLL_INTERVAL", kind: kindDuration, def: "1s"},
+
+	{env: "WEBHOOK_MAX_ATTEMPTS", kind: kindInt, def: "10"},
+	{env: "WEBHOOK_BACKOFF_BASE", kind: kindDuration, def: "30s"},
+	{env: "WEBHOOK_BACKOFF_MAX", kind: kindDuration, def: "6h"},
+	{env: "WEBHOOK_TIMEOUT", kind: kindDuration, def: "10s"},
+	{env: "WEBHOOK_POLL_INTERVAL", kind: kindDuration, def: "5s"},
+}
+
+// lookupSetting busca una opción por su clave de archivo (server.port) o su variable de entorno
+// (SERVER_PORT).
+func lookupSetting(name string) (setting, bool) {
+	for _, s := range settings {
+		if s.key() == name || s.env == name {
+			return s, true
+		}
+	}
+
+	return setting{}, false
+}
+
+// value es el valor efectivo de una opción y su origen.
+type value struct {
+	raw    string
+	source int
+	// name identifica dónde se definió el valor en los mensajes de error.
+	name string
+}
+
+// values son los valores efectivos de las opciones, indexados por variable de entorno.
+type values map[string]value
+
+// set reemplaza el valor de s. Un valor vacío no reemplaza al anterior, de modo que las variables
+// definidas sin valor (ej. por docker-compose) no anulan el archivo ni los valores por defecto.
+func (v values) set(s setting, raw string, source int, name string) {
+	if s.kind != kindString {
+		raw = strings.TrimSpace(raw)
+	}
+
+	if strings.TrimSpace(raw) == "" {
+		return
+	}
+
+	v[s.env] = value{raw: raw, source: source, name: name}
+}
+
+// resolve combina, de menor a mayor prioridad, los valores por defecto, el archivo de
+// configuración, las variables de entorno y los valores de opts.Overrides.
+func resolve(opts Options) (values, error) {
+	vals := values{}
+	for _, s := range settings {
+		vals[s.env] = value{raw: s.def, source: sourceDefault, name: s.env}
+	}
+
+	var problems []string
+
+	file := opts.File
+	if file == "" {
+		file = os.Getenv("CONFIG_FILE")
+	}
+
+	if file != "" {
+		entries, err := readFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range sortedKeys(entries) {
+			s, ok := lookupSetting(key)
+			if !ok || s.key() != key {
+				problems = append(problems, fmt.Sprintf("%s: opción desconocida %q", file, key))
+				continue
+			}
+
+			vals.set(s, entries[key], sourceFile, fmt.Sprintf("%s (%s)", key, file))
+		}
+	}
+
+	for _, s := range settings {
+		if raw, ok := os.LookupEnv(s.env); ok {
+			vals.set(s, raw, sourceEnv, s.env)
+		}
+	}
+
+	for _, override := range opts.Overrides {
+		name, raw, ok := strings.Cut(override, "=")
+		if !ok {
+			problems = append(problems, fmt.Sprintf("--set %q: use el formato clave=valor", override))
+			continue
+		}
+
+		s, ok := lookupSetting(strings.TrimSpace(name))
+		if !ok {
+			problems = append(problems, fmt.Sprintf("--set %q: opción desconocida", name))
+			continue
+		}
+
+		vals.set(s, raw, sourceFlag, "--set "+s.key())
+	}
+
+	if len(problems) > 0 {
+		return nil, invalidConfig(problems)
+	}
+
+	return vals, nil
+}
+
+// readFile lee un archivo YAML de secciones con opciones y lo aplana a claves "seccion.opcion".
+func readFile(path string) (map[string]string, error) {
+	if ext := strings.ToLower(filepath.Ext(path)); ext != ".yaml" && ext != ".yml" {
+		return nil, fmt.Errorf("archivo de configuración %s: formato no soportado, use YAML (.yaml o .yml)", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el archivo de configuración: %w", err)
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("archivo de configuración %s inválido: %w", path, err)
+	}
+
+	entries := map[string]string{}
+
+	for section, body := range doc {
+		if body == nil {
+			continue
+		}
+
+		options, ok := body.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("archivo de configuración %s: la sección %q debe contener opciones", path, section)
+		}
+
+		for name, raw := range options {
+			key := section + "." + name
+
+			text, err := scalarText(raw)
+			if err != nil {
+				return nil, fmt.Errorf("archivo de configuración %s: %s: %w", path, key, err)
+			}
+
+			entries[key] = text
+		}
+	}
+
+	return entries, nil
+}
+
+// scalarText convierte un valor del archivo al texto equivalente de su variable de entorno. Las
+// listas se unen con comas.
+func scalarText(raw any) (string, error) {
+	switch v := raw.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case int, bool:
+		return fmt.Sprint(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case time.Time:
+		return v.Format(time.RFC3339), nil
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			text, err := scalarText(item)
+			if err != nil {
+				return "", err
+			}
+
+			if _, nested := item.([]any); nested {
+				return "", fmt.Errorf("no se admiten listas anidadas")
+			}
+
+			items = append(items, text)
+		}
+
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("se esperaba un valor simple o una lista")
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// splitList separa una lista por comas, omitiendo los elementos vacíos.
+func splitList(raw string) []string {
+	list := []string{}
+	for item := range strings.SplitSeq(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+// Print escribe la configuración efectiva como YAML, en el formato del archivo de configuración.
+// Los secretos se ocultan y los valores que no son los predeterminados indican su origen.
+func (c *Config) Print(w io.Writer) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+
+	var section *yaml.Node
+	var current string
+
+	for _, s := range settings {
+		name, option, _ := strings.Cut(s.key(), ".")
+		if name != current {
+			section = &yaml.Node{Kind: yaml.MappingNode}
+			root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, section)
+			current = name
+		}
+
+		v := c.values[s.env]
+
+		node := &yaml.Node{Kind: yaml.ScalarNode, Value: v.raw}
+		switch {
+		case s.secret && v.raw != "":
+			node.Value, node.Tag = "******", "!!str"
+		case s.kind == kindList:
+			node = &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+			for _, item := range splitList(v.raw) {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: item, Tag: "!!str"})
+			}
+		case s.kind == kindString:
+			node.Tag = "!!str"
+		}
+
+		switch v.source {
+		case sourceFile:
+			node.LineComment = "archivo"
+		case sourceEnv:
+			node.LineComment = s.env
+		case sourceFlag:
+			node.LineComment = "--set"
+		}
+
+		section.Content = append(section.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: option}, node)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(root); err != nil {
+		return fmt.Errorf("error al imprimir la configuración: %w", err)
+	}
+
+	return encoder.Close()
+}
@@ -0,0 +1,55 @@
+package httpsender
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/webhook"
+)
+
+// maxResponseBody es la cantidad de bytes de la respuesta que se leen antes de descartarla, para
+// poder reutilizar la conexión.
+const maxResponseBody = 64 << 10
+
+type sender struct {
+	client *http.Client
+}
+
+// New crea un emisor de webhooks por HTTP. No sigue redirecciones: una respuesta 3xx se considera
+// un intento fallido.
+func New(timeout time.Duration) webhook.Sender {
+	return &sender{
+		client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (s *sender) Send(ctx context.Context, url string, headers map[string]string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("petición inválida: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "parking-webhooks/1")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	return resp.StatusCode, nil
+}
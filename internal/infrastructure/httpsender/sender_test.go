@@ -0,0 +1,59 @@
+package httpsender
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSend(t *testing.T) {
+	var gotHeader, gotBody, gotMethod string
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redirect":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+
+		default:
+			body, _ := io.ReadAll(r.Body)
+			gotMethod, gotHeader, gotBody = r.Method, r.Header.Get("X-Parking-Signature"), string(body)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer receiver.Close()
+
+	s := New(5 * time.Second)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{"Entrega las cabeceras y el cuerpo", "/ok", http.StatusNoContent},
+		{"No sigue redirecciones", "/redirect", http.StatusFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotMethod, gotHeader, gotBody = "", "", ""
+
+			status, err := s.Send(context.Background(), receiver.URL+tt.path,
+				map[string]string{"X-Parking-Signature": "sha256=abc"}, []byte(`{"id":"1"}`))
+
+			if err != nil || status != tt.wantStatus {
+				t.Fatalf("Send() = %d, %v; se esperaba %d", status, err, tt.wantStatus)
+			}
+
+			if tt.wantStatus == http.StatusNoContent && (gotMethod != http.MethodPost || gotHeader != "sha256=abc" || gotBody != `{"id":"1"}`) {
+				t.Errorf("el receptor obtuvo %s %q %q", gotMethod, gotHeader, gotBody)
+			}
+
+			if tt.wantStatus == http.StatusFound && gotBody != "" {
+				t.Errorf("se siguió la redirección")
+			}
+		})
+	}
+}
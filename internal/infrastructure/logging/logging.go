@@ -0,0 +1,58 @@
+// Package logging configura el registro estructurado de la aplicación con log/slog.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/JGCaceres97/parking/internal/application/audit"
+)
+
+// New crea un logger en formato texto o JSON que agrega a cada línea el ID de la solicitud, el
+// usuario autenticado y la traza cuando el contexto los contiene (p. ej. slog.InfoContext).
+func New(w io.Writer, level slog.Leveler, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler = slog.NewTextHandler(w, opts)
+	if format == "json" {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+
+	return slog.New(contextHandler{handler})
+}
+
+// contextHandler toma los datos de la solicitud del actor de auditoría, que los middlewares
+// asocian al contexto y los servicios propagan hasta los repositorios.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		actor := audit.ActorFromContext(ctx)
+		if actor.RequestID != "" {
+			record.AddAttrs(slog.String("request_id", actor.RequestID))
+		}
+
+		if actor.UserID != "" {
+			record.AddAttrs(slog.String("user_id", actor.UserID))
+		}
+
+		if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+			record.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+		}
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/JGCaceres97/parking/internal/application/audit"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		level slog.Level
+		want  map[string]string
+	}{
+		{
+			name: "Agrega la solicitud y el usuario",
+			ctx:  audit.WithActor(context.Background(), audit.Actor{UserID: "u1", RequestID: "req-1"}),
+			want: map[string]string{"request_id": "req-1", "user_id": "u1", "msg": "hola", "clave": "valor"},
+		},
+		{
+			name: "Sin actor no agrega campos",
+			ctx:  context.Background(),
+			want: map[string]string{"msg": "hola"},
+		},
+		{
+			name:  "Respeta el nivel",
+			ctx:   context.Background(),
+			level: slog.LevelWarn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			logger := New(&out, tt.level, "json").With("clave", "valor")
+			logger.InfoContext(tt.ctx, "hola")
+
+			if tt.want == nil {
+				if out.Len() > 0 {
+					t.Errorf("se esperaba que no se registrara nada, se obtuvo %s", out.String())
+				}
+
+				return
+			}
+
+			var line map[string]any
+			if err := json.Unmarshal(out.Bytes(), &line); err != nil {
+				t.Fatalf("línea inválida %q: %v", out.String(), err)
+			}
+
+			for key, want := range tt.want {
+				if got, _ := line[key].(string); got != want {
+					t.Errorf("%s = %q, se esperaba %q", key, got, want)
+				}
+			}
+
+			if _, ok := tt.want["request_id"]; !ok && line["request_id"] != nil {
+				t.Errorf("no se esperaba request_id: %s", out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/JGCaceres97/parking/internal/application/auth"
+	"github.com/JGCaceres97/parking/internal/application/events"
+	"github.com/JGCaceres97/parking/internal/application/parking"
+	"github.com/JGCaceres97/parking/internal/application/vehicle_type"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// publisher cuenta las entradas, salidas y el monto cobrado a partir de los eventos de
+// estacionamiento, que se publican una vez confirmada la transacción. Se etiquetan con el ID del
+// tipo de vehículo, que viene en el registro: así no se consulta la DB al publicar y un cambio de
+// nombre no divide la serie. parking_vehicles_parked relaciona cada ID con su nombre.
+type publisher struct {
+	next events.Publisher
+}
+
+// NewPublisher mide los eventos de estacionamiento antes de entregarlos a next.
+func NewPublisher(next events.Publisher) events.Publisher {
+	return &publisher{next: next}
+}
+
+func (p *publisher) Publish(eventType domain.EventType, data any) {
+	if record, ok := data.(domain.ParkingRecord); ok {
+		switch eventType {
+		case domain.EventVehicleEntered:
+			entries.WithLabelValues(record.VehicleTypeID).Inc()
+
+		case domain.EventVehicleExited:
+			exits.WithLabelValues(record.VehicleTypeID).Inc()
+
+			if record.TotalCharge != nil {
+				revenue.WithLabelValues(record.VehicleTypeID).Add(*record.TotalCharge)
+			}
+		}
+	}
+
+	p.next.Publish(eventType, data)
+}
+
+// loginAttemptRepository cuenta los intentos de inicio de sesión al registrarlos.
+type loginAttemptRepository struct {
+	auth.LoginAttemptRepository
+}
+
+// NewLoginAttemptRepository mide los intentos de inicio de sesión que se registran en repo.
+func NewLoginAttemptRepository(repo auth.LoginAttemptRepository) auth.LoginAttemptRepository {
+	return &loginAttemptRepository{repo}
+}
+
+func (r *loginAttemptRepository) Create(ctx context.Context, attempt *domain.LoginAttempt) error {
+	result := "failure"
+	if attempt.Success {
+		result = "success"
+	}
+
+	logins.WithLabelValues(result, attempt.Reason).Inc()
+
+	return r.LoginAttemptRepository.Create(ctx, attempt)
+}
+
+// parkedCollector informa los vehículos estacionados por tipo al momento de la consulta, de modo
+// que el valor coincide con la DB aunque haya varias instancias.
+type parkedCollector struct {
+	parking      parking.Repository
+	vehicleTypes vehicle_type.Repository
+	desc         *prometheus.Desc
+}
+
+// RegisterParked expone la cantidad de vehículos estacionados por tipo (parking_vehicles_parked),
+// con el ID y el nombre de cada tipo.
+func RegisterParked(parkingRepo parking.Repository, vehicleTypes vehicle_type.Repository) error {
+	return registry.Register(&parkedCollector{
+		parking:      parkingRepo,
+		vehicleTypes: vehicleTypes,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "vehicles_parked"),
+			"Vehículos estacionados actualmente por tipo de vehículo.",
+			[]string{"vehicle_type_id", "vehicle_type"}, nil),
+	})
+}
+
+func (c *parkedCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *parkedCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
+	vehicleTypes, err := c.vehicleTypes.ListAll(ctx)
+	if err != nil {
+		slog.Warn("no se pudieron obtener los tipos de vehículo para las métricas", "error", err)
+		return
+	}
+
+	records, err := c.parking.ListCurrent(ctx)
+	if err != nil {
+		slog.Warn("no se pudieron obtener los vehículos estacionados para las métricas", "error", err)
+		return
+	}
+
+	parked := make(map[string]int, len(vehicleTypes))
+	for _, record := range records {
+		parked[record.VehicleTypeID]++
+	}
+
+	for _, vehicleType := range vehicleTypes {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(parked[vehicleType.ID]), vehicleType.ID, vehicleType.Name)
+	}
+}
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"database/sql"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// RegisterDB expone las estadísticas del pool de conexiones (go_sql_*).
+func RegisterDB(db *sql.DB) error {
+	return registry.Register(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// queryCallers guarda la operación que corresponde a cada método de repositorio, por dirección
+// de código.
+var queryCallers sync.Map
+
+// Query identifica una operación de DB por su repositorio y método.
+type Query struct {
+	Repository string
+	Method     string
+}
+
+// String retorna la operación como repositorio.Método (ej. vehicle_type.FindByID).
+func (q Query) String() string {
+	return q.Repository + "." + q.Method
+}
+
+// QueryCaller identifica el método de repositorio que llama a la función que invoca a
+// QueryCaller (p. ej. (*vehicleTypeRepository).FindByID es vehicle_type y FindByID).
+func QueryCaller() Query {
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+
+	query, ok := queryCallers.Load(pcs[0])
+	if !ok {
+		frame, _ := runtime.CallersFrames(pcs[:]).Next()
+		query, _ = queryCallers.LoadOrStore(pcs[0], parseQuery(frame.Function))
+	}
+
+	return query.(Query)
+}
+
+// StartQuery inicia la medición de una operación de DB y retorna la función que la registra.
+func StartQuery(query Query) func() {
+	start := time.Now()
+
+	return func() {
+		dbQueryDuration.WithLabelValues(query.Repository, query.Method).Observe(time.Since(start).Seconds())
+	}
+}
+
+// parseQuery convierte el nombre completo de un método de repositorio en su operación.
+func parseQuery(function string) Query {
+	function = function[strings.LastIndex(function, "/")+1:]
+
+	parts := strings.Split(function, ".")
+	if len(parts) < 3 {
+		return Query{Repository: "unknown", Method: parts[len(parts)-1]}
+	}
+
+	receiver := strings.Trim(parts[1], "(*)")
+	receiver = strings.TrimSuffix(receiver, "Repository")
+
+	return Query{Repository: snakeCase(receiver), Method: parts[2]}
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+
+			r = unicode.ToLower(r)
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
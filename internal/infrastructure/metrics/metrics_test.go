@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		function string
+		want     Query
+	}{
+		{
+			name:     "Método de repositorio",
+			function: "github.com/JGCaceres97/parking/internal/infrastructure/persistence/sqlite.(*vehicleTypeRepository).FindByID",
+			want:     Query{Repository: "vehicle_type", Method: "FindByID"},
+		},
+		{
+			name:     "Función anónima dentro del método",
+			function: "github.com/JGCaceres97/parking/internal/infrastructure/persistence/mysql.(*loginAttemptRepository).List.func1",
+			want:     Query{Repository: "login_attempt", Method: "List"},
+		},
+		{
+			name:     "Función sin receptor",
+			function: "github.com/JGCaceres97/parking/internal/infrastructure/persistence/postgres.NewConnection",
+			want:     Query{Repository: "unknown", Method: "NewConnection"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseQuery(tt.function); got != tt.want {
+				t.Errorf("parseQuery() = %+v, se esperaba %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+type userRepository struct{}
+
+func withTimeout() func() {
+	return StartQuery(QueryCaller())
+}
+
+func (userRepository) FindByUsername() {
+	defer withTimeout()()
+}
+
+func TestQueryCaller(t *testing.T) {
+	userRepository{}.FindByUsername()
+
+	if got := testutil.CollectAndCount(dbQueryDuration, "parking_db_query_duration_seconds"); got != 1 {
+		t.Fatalf("se esperaba 1 serie, se obtuvieron %d", got)
+	}
+
+	// Obtener la serie con las etiquetas esperadas no debe crear una nueva.
+	dbQueryDuration.WithLabelValues("user", "FindByUsername")
+	if got := testutil.CollectAndCount(dbQueryDuration); got != 1 {
+		t.Errorf("las etiquetas no son repository=user y method=FindByUsername (%d series)", got)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		want   int
+	}{
+		{name: "Sin token configurado", want: http.StatusOK},
+		{name: "Token correcto", token: "secreto", header: "Bearer secreto", want: http.StatusOK},
+		{name: "Token incorrecto", token: "secreto", header: "Bearer otro", want: http.StatusUnauthorized},
+		{name: "Sin cabecera", token: "secreto", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			rec := httptest.NewRecorder()
+			Handler(tt.token).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("estado = %d, se esperaba %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+type memoryPublisher struct {
+	published []domain.EventType
+}
+
+func (p *memoryPublisher) Publish(eventType domain.EventType, data any) {
+	p.published = append(p.published, eventType)
+}
+
+func TestPublisherLabelsByVehicleTypeID(t *testing.T) {
+	next := &memoryPublisher{}
+	publisher := NewPublisher(next)
+
+	charge := 25.5
+	record := domain.ParkingRecord{ID: "registro", VehicleTypeID: "tipo-metricas", TotalCharge: &charge}
+
+	publisher.Publish(domain.EventVehicleEntered, record)
+	publisher.Publish(domain.EventVehicleExited, record)
+
+	if got := testutil.ToFloat64(entries.WithLabelValues("tipo-metricas")); got != 1 {
+		t.Errorf("entradas = %v, se esperaba 1", got)
+	}
+
+	if got := testutil.ToFloat64(exits.WithLabelValues("tipo-metricas")); got != 1 {
+		t.Errorf("salidas = %v, se esperaba 1", got)
+	}
+
+	if got := testutil.ToFloat64(revenue.WithLabelValues("tipo-metricas")); got != charge {
+		t.Errorf("monto = %v, se esperaba %v", got, charge)
+	}
+
+	if len(next.published) != 2 {
+		t.Errorf("eventos entregados = %v, se esperaban los 2 publicados", next.published)
+	}
+}
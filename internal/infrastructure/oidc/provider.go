@@ -0,0 +1,127 @@
+// Package oidc implementa sso.Provider sobre un proveedor de identidad OpenID Connect.
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/JGCaceres97/parking/internal/application/sso"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	// GroupsClaim es el claim del token de identidad que contiene los grupos del usuario.
+	GroupsClaim string
+}
+
+type provider struct {
+	oauth       oauth2.Config
+	verifier    *gooidc.IDTokenVerifier
+	groupsClaim string
+}
+
+// New obtiene la configuración del proveedor mediante discovery
+// (/.well-known/openid-configuration).
+func New(ctx context.Context, cfg Config) (sso.Provider, error) {
+	p, err := gooidc.NewProvider(ctx, cfg.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener configuración del proveedor OIDC: %w", err)
+	}
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{gooidc.ScopeOpenID, "profile", "email"}
+	}
+
+	return &provider{
+		oauth: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     p.Endpoint(),
+			Scopes:       scopes,
+		},
+		verifier:    p.Verifier(&gooidc.Config{ClientID: cfg.ClientID}),
+		groupsClaim: cfg.GroupsClaim,
+	}, nil
+}
+
+func (p *provider) AuthCodeURL(state, nonce, verifier string) string {
+	return p.oauth.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+}
+
+func (p *provider) Exchange(ctx context.Context, code, verifier, nonce string) (*domain.ExternalIdentity, error) {
+	token, err := p.oauth.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrSSOLoginFailed, retrieveErr.ErrorCode)
+		}
+
+		return nil, fmt.Errorf("error al canjear código de autorización: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: la respuesta no incluye id_token", domain.ErrSSOLoginFailed)
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: token de identidad inválido: %v", domain.ErrSSOLoginFailed, err)
+	}
+
+	if idToken.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce inválido", domain.ErrSSOLoginFailed)
+	}
+
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("error al leer claims del token de identidad: %w", err)
+	}
+
+	identity := &domain.ExternalIdentity{
+		Issuer:            idToken.Issuer,
+		Subject:           idToken.Subject,
+		Email:             stringClaim(claims, "email"),
+		PreferredUsername: stringClaim(claims, "preferred_username"),
+		Groups:            stringsClaim(claims, p.groupsClaim),
+	}
+
+	return identity, nil
+}
+
+func stringClaim(claims map[string]any, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// stringsClaim acepta el claim como lista de cadenas o como una sola cadena.
+func stringsClaim(claims map[string]any, name string) []string {
+	switch value := claims[name].(type) {
+	case string:
+		return []string{value}
+
+	case []any:
+		list := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+
+		return list
+
+	default:
+		return []string{}
+	}
+}
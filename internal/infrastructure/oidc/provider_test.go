@@ -0,0 +1,262 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+const (
+	testClientID     = "parking"
+	testClientSecret = "secreto"
+	testKeyID        = "test-key"
+)
+
+type authorization struct {
+	challenge string
+	nonce     string
+	claims    jwt.MapClaims
+}
+
+// mockServer es un proveedor OIDC mínimo: discovery, JWKS y token endpoint con PKCE (S256).
+type mockServer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]authorization
+}
+
+func newMockServer(t *testing.T) *mockServer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error al generar clave RSA: %v", err)
+	}
+
+	m := &mockServer{key: key, codes: map[string]authorization{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", m.discovery)
+	mux.HandleFunc("GET /jwks", m.jwks)
+	mux.HandleFunc("POST /token", m.token)
+
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+
+	return m
+}
+
+// authorize simula la autenticación del usuario en el proveedor y retorna el código de autorización.
+func (m *mockServer) authorize(authURL string, claims jwt.MapClaims) (code, state string) {
+	u, _ := url.Parse(authURL)
+	q := u.Query()
+
+	code = base64.RawURLEncoding.EncodeToString([]byte(q.Get("state")))
+
+	m.mu.Lock()
+	m.codes[code] = authorization{challenge: q.Get("code_challenge"), nonce: q.Get("nonce"), claims: claims}
+	m.mu.Unlock()
+
+	return code, q.Get("state")
+}
+
+func (m *mockServer) discovery(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]any{
+		"issuer":                                m.URL,
+		"authorization_endpoint":                m.URL + "/authorize",
+		"token_endpoint":                        m.URL + "/token",
+		"jwks_uri":                              m.URL + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (m *mockServer) jwks(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testKeyID,
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (m *mockServer) token(w http.ResponseWriter, r *http.Request) {
+	fail := func(code string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": code})
+	}
+
+	if err := r.ParseForm(); err != nil {
+		fail("invalid_request")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+
+	if clientID != testClientID || clientSecret != testClientSecret {
+		fail("invalid_client")
+		return
+	}
+
+	m.mu.Lock()
+	auth, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != auth.challenge {
+		fail("invalid_grant")
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   m.URL,
+		"aud":   testClientID,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": auth.nonce,
+	}
+
+	for k, v := range auth.claims {
+		claims[k] = v
+	}
+
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	idToken.Header["kid"] = testKeyID
+	signed, _ := idToken.SignedString(m.key)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     signed,
+	})
+}
+
+func newTestProvider(t *testing.T, m *mockServer) *provider {
+	t.Helper()
+
+	p, err := New(context.Background(), Config{
+		IssuerURL:    m.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  "http://localhost/api/v1/login/sso/callback",
+		GroupsClaim:  "groups",
+	})
+
+	if err != nil {
+		t.Fatalf("error al crear el proveedor: %v", err)
+	}
+
+	return p.(*provider)
+}
+
+func TestProviderAuthCodeURL(t *testing.T) {
+	m := newMockServer(t)
+	p := newTestProvider(t, m)
+
+	authURL, _ := url.Parse(p.AuthCodeURL("estado", "nonce", "verificador-de-prueba-con-longitud-suficiente-123"))
+	q := authURL.Query()
+
+	sum := sha256.Sum256([]byte("verificador-de-prueba-con-longitud-suficiente-123"))
+
+	checks := map[string]string{
+		"response_type":         "code",
+		"client_id":             testClientID,
+		"state":                 "estado",
+		"nonce":                 "nonce",
+		"code_challenge_method": "S256",
+		"code_challenge":        base64.RawURLEncoding.EncodeToString(sum[:]),
+	}
+
+	for param, want := range checks {
+		if got := q.Get(param); got != want {
+			t.Errorf("%s = %q, se esperaba %q", param, got, want)
+		}
+	}
+}
+
+func TestProviderExchange(t *testing.T) {
+	m := newMockServer(t)
+	p := newTestProvider(t, m)
+
+	const verifier = "verificador-de-prueba-con-longitud-suficiente-123"
+
+	claims := jwt.MapClaims{
+		"sub":                "empleado-42",
+		"email":              "ana@example.com",
+		"preferred_username": "ana",
+		"groups":             []string{"parking-staff", "parking-admins"},
+	}
+
+	tests := []struct {
+		name     string
+		verifier string
+		nonce    string
+		claims   jwt.MapClaims
+		wantErr  bool
+	}{
+		{"Flujo válido", verifier, "nonce-1", claims, false},
+		{"Verifier PKCE incorrecto", "otro-verificador-de-prueba-con-longitud-suficiente", "nonce-1", claims, true},
+		{"Nonce distinto", verifier, "nonce-2", claims, true},
+		{"Audiencia distinta", verifier, "nonce-1", jwt.MapClaims{"sub": "x", "aud": "otro-cliente"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authURL := p.AuthCodeURL("estado", "nonce-1", verifier)
+			code, _ := m.authorize(authURL, tt.claims)
+
+			identity, err := p.Exchange(context.Background(), code, tt.verifier, tt.nonce)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrSSOLoginFailed) {
+					t.Fatalf("se esperaba ErrSSOLoginFailed, se obtuvo %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+
+			if identity.Issuer != m.URL || identity.Subject != "empleado-42" {
+				t.Errorf("identidad inesperada: %+v", identity)
+			}
+
+			if identity.PreferredUsername != "ana" || identity.Email != "ana@example.com" {
+				t.Errorf("claims de perfil inesperados: %+v", identity)
+			}
+
+			if !slices.Equal(identity.Groups, []string{"parking-staff", "parking-admins"}) {
+				t.Errorf("grupos inesperados: %v", identity.Groups)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/JGCaceres97/parking/internal/application/backup"
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/internal/infrastructure/persistence/sqlite"
+)
+
+// NewBackupStorage crea el almacenamiento de copias de seguridad en dir. Retorna nil si el driver
+// no permite copias en caliente.
+func NewBackupStorage(db *sql.DB, driver, dir string) backup.Storage {
+	if driver != "sqlite" {
+		return nil
+	}
+
+	return sqlite.NewBackupStorage(db, dir)
+}
+
+// Backup escribe una copia consistente de la DB en path sin detener el servidor.
+func Backup(ctx context.Context, db *sql.DB, driver, path string) error {
+	if driver != "sqlite" {
+		return domain.ErrBackupNotSupported
+	}
+
+	return sqlite.Backup(ctx, db, path)
+}
+
+// Restore reemplaza la DB de dsn por la copia src después de verificar su integridad y que su
+// esquema no sea más reciente que las migraciones del binario. Si es más antiguo, las migraciones
+// pendientes se aplican después con `migrate up`. Retorna la ruta donde se conservó la DB
+// reemplazada, o "" si no existía. El servidor debe estar detenido.
+func Restore(ctx context.Context, driver, dsn, src string) (string, error) {
+	if driver != "sqlite" {
+		return "", domain.ErrBackupNotSupported
+	}
+
+	return sqlite.Restore(ctx, dsn, src, func(ctx context.Context, db *sql.DB) error {
+		migrator, err := NewMigrator(db, driver)
+		if err != nil {
+			return err
+		}
+
+		current, target, err := migrator.Versions(ctx)
+		if err != nil {
+			return err
+		}
+
+		if current == 0 {
+			return errors.New("la copia de seguridad no contiene el esquema de la aplicación")
+		}
+
+		if current > target {
+			return fmt.Errorf("%w: versión %d, esta versión admite hasta la %d", domain.ErrBackupSchemaNewer, current, target)
+		}
+
+		return nil
+	})
+}
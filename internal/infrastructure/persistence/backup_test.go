@@ -0,0 +1,149 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	dsn := "file:" + filepath.Join(dir, "parking.db") + "?_time_format=sqlite&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)"
+
+	db, err := NewConnection(ctx, "sqlite", dsn, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrator, err := NewMigrator(db, "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up() = %v", err)
+	}
+
+	valid := filepath.Join(dir, "valid.db")
+	if err := Backup(ctx, db, "sqlite", valid); err != nil {
+		t.Fatalf("Backup() = %v", err)
+	}
+
+	// Una copia de una versión posterior de la aplicación.
+	if _, err := db.ExecContext(ctx, "INSERT INTO goose_db_version (version_id, is_applied) VALUES (9999, 1);"); err != nil {
+		t.Fatal(err)
+	}
+
+	newer := filepath.Join(dir, "newer.db")
+	if err := Backup(ctx, db, "sqlite", newer); err != nil {
+		t.Fatalf("Backup() = %v", err)
+	}
+
+	if _, err := db.ExecContext(ctx, "DELETE FROM goose_db_version WHERE version_id = 9999;"); err != nil {
+		t.Fatal(err)
+	}
+
+	db.Close()
+
+	corrupt := filepath.Join(dir, "corrupt.db")
+	if err := os.WriteFile(corrupt, []byte("no es una base de datos"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		src     string
+		wantErr error
+	}{
+		{"Copia dañada", corrupt, domain.ErrBackupCorrupt},
+		{"Esquema más reciente", newer, domain.ErrBackupSchemaNewer},
+		{"Copia válida", valid, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous, err := Restore(ctx, "sqlite", dsn, tt.src)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Restore() = %v, se esperaba %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Restore() = %v", err)
+			}
+
+			if _, err := os.Stat(previous); err != nil {
+				t.Errorf("no se conservó la DB reemplazada: %v", err)
+			}
+
+			restored, err := NewConnection(ctx, "sqlite", dsn, time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer restored.Close()
+
+			migrator, err := NewMigrator(restored, "sqlite")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := migrator.CheckVersion(ctx); err != nil {
+				t.Errorf("CheckVersion() tras restaurar = %v", err)
+			}
+		})
+	}
+
+	t.Run("Conexión inactiva", func(t *testing.T) {
+		db, err := NewConnection(ctx, "sqlite", dsn, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+
+		// Un servidor conectado que no está atendiendo solicitudes.
+		var version int64
+		if err := db.QueryRowContext(ctx, "SELECT MAX(version_id) FROM goose_db_version;").Scan(&version); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Restore(ctx, "sqlite", dsn, valid); !errors.Is(err, domain.ErrDatabaseInUse) {
+			t.Errorf("Restore() = %v, se esperaba %v", err, domain.ErrDatabaseInUse)
+		}
+
+		if err := db.QueryRowContext(ctx, "SELECT MAX(version_id) FROM goose_db_version;").Scan(&version); err != nil {
+			t.Errorf("la conexión abierta no puede leer la DB tras el rechazo: %v", err)
+		}
+	})
+
+	t.Run("Escritura en curso", func(t *testing.T) {
+		db, err := NewConnection(ctx, "sqlite", dsn, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+
+		// Una escritura en curso del servidor.
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM goose_db_version WHERE version_id = 9999;"); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Restore(ctx, "sqlite", dsn, valid); !errors.Is(err, domain.ErrDatabaseInUse) {
+			t.Errorf("Restore() = %v, se esperaba %v", err, domain.ErrDatabaseInUse)
+		}
+	})
+}
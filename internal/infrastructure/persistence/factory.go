@@ -6,17 +6,39 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/JGCaceres97/parking/internal/application/audit"
+	"github.com/JGCaceres97/parking/internal/application/auth"
+	"github.com/JGCaceres97/parking/internal/application/mfa"
+	"github.com/JGCaceres97/parking/internal/application/outbox"
 	"github.com/JGCaceres97/parking/internal/application/parking"
+	"github.com/JGCaceres97/parking/internal/application/report"
+	"github.com/JGCaceres97/parking/internal/application/retention"
+	"github.com/JGCaceres97/parking/internal/application/role"
+	"github.com/JGCaceres97/parking/internal/application/sso"
+	"github.com/JGCaceres97/parking/internal/application/transaction"
 	"github.com/JGCaceres97/parking/internal/application/user"
 	"github.com/JGCaceres97/parking/internal/application/vehicle_type"
+	"github.com/JGCaceres97/parking/internal/application/webhook"
 	"github.com/JGCaceres97/parking/internal/infrastructure/persistence/mysql"
+	"github.com/JGCaceres97/parking/internal/infrastructure/persistence/postgres"
 	"github.com/JGCaceres97/parking/internal/infrastructure/persistence/sqlite"
 )
 
-type repositories struct {
-	Parking     parking.Repository
-	User        user.Repository
-	VehicleType vehicle_type.Repository
+// Repositories agrupa las implementaciones de los repositorios para un driver de DB.
+type Repositories struct {
+	Audit        audit.Repository
+	Identity     sso.IdentityRepository
+	LoginAttempt auth.LoginAttemptRepository
+	MFA          mfa.Repository
+	Outbox       outbox.Repository
+	Parking      parking.Repository
+	Report       report.Repository
+	Retention    retention.Repository
+	Role         role.Repository
+	UnitOfWork   transaction.UnitOfWork
+	User         user.Repository
+	VehicleType  vehicle_type.Repository
+	Webhook      webhook.Repository
 }
 
 func NewConnection(ctx context.Context, driver, dsn string, timeout time.Duration) (*sql.DB, error) {
@@ -27,18 +49,87 @@ func NewConnection(ctx context.Context, driver, dsn string, timeout time.Duratio
 	case "mysql":
 		return mysql.NewConnection(ctx, dsn, timeout)
 
+	case "postgres":
+		return postgres.NewConnection(ctx, dsn, timeout)
+
 	default:
 		return nil, fmt.Errorf("driver de DB no soportado: %s", driver)
 	}
 }
 
-func NewRepositories(db *sql.DB, driver string) *repositories {
+// PoolConfig ajusta el pool de conexiones de MySQL y PostgreSQL.
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// ConfigurePool aplica pool a la conexión. SQLite conserva una sola conexión, ya que no admite
+// escrituras concurrentes y una DB en memoria no se comparte entre conexiones.
+func ConfigurePool(db *sql.DB, driver string, pool PoolConfig) {
+	if driver == "sqlite" {
+		return
+	}
+
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+}
+
+// NewRepositories crea los repositorios de driver. timeout limita cada operación (DB_TIMEOUT).
+func NewRepositories(db *sql.DB, driver string, timeout time.Duration) *Repositories {
 	switch driver {
-	case "sqlite", "mysql":
-		return &repositories{
-			Parking:     mysql.NewParkingRepository(db),
-			User:        mysql.NewUserRepository(db),
-			VehicleType: mysql.NewVehicleTypeRepository(db),
+	case "sqlite":
+		return &Repositories{
+			Audit:        sqlite.NewAuditRepository(db, timeout),
+			Identity:     sqlite.NewIdentityRepository(db, timeout),
+			LoginAttempt: sqlite.NewLoginAttemptRepository(db, timeout),
+			MFA:          sqlite.NewMFARepository(db, timeout),
+			Outbox:       sqlite.NewOutboxRepository(db, timeout),
+			Parking:      sqlite.NewParkingRepository(db, timeout),
+			Report:       sqlite.NewReportRepository(db, timeout),
+			Retention:    sqlite.NewRetentionRepository(db, timeout),
+			Role:         sqlite.NewRoleRepository(db, timeout),
+			UnitOfWork:   sqlite.NewUnitOfWork(db),
+			User:         sqlite.NewUserRepository(db, timeout),
+			VehicleType:  sqlite.NewVehicleTypeRepository(db, timeout),
+			Webhook:      sqlite.NewWebhookRepository(db, timeout),
+		}
+
+	case "mysql":
+		return &Repositories{
+			Audit:        mysql.NewAuditRepository(db, timeout),
+			Identity:     mysql.NewIdentityRepository(db, timeout),
+			LoginAttempt: mysql.NewLoginAttemptRepository(db, timeout),
+			MFA:          mysql.NewMFARepository(db, timeout),
+			Outbox:       mysql.NewOutboxRepository(db, timeout),
+			Parking:      mysql.NewParkingRepository(db, timeout),
+			Report:       mysql.NewReportRepository(db, timeout),
+			Retention:    mysql.NewRetentionRepository(db, timeout),
+			Role:         mysql.NewRoleRepository(db, timeout),
+			UnitOfWork:   mysql.NewUnitOfWork(db),
+			User:         mysql.NewUserRepository(db, timeout),
+			VehicleType:  mysql.NewVehicleTypeRepository(db, timeout),
+			Webhook:      mysql.NewWebhookRepository(db, timeout),
+		}
+
+	case "postgres":
+		return &Repositories{
+			Audit:        postgres.NewAuditRepository(db, timeout),
+			Identity:     postgres.NewIdentityRepository(db, timeout),
+			LoginAttempt: postgres.NewLoginAttemptRepository(db, timeout),
+			MFA:          postgres.NewMFARepository(db, timeout),
+			Outbox:       postgres.NewOutboxRepository(db, timeout),
+			Parking:      postgres.NewParkingRepository(db, timeout),
+			Report:       postgres.NewReportRepository(db, timeout),
+			Retention:    postgres.NewRetentionRepository(db, timeout),
+			Role:         postgres.NewRoleRepository(db, timeout),
+			UnitOfWork:   postgres.NewUnitOfWork(db),
+			User:         postgres.NewUserRepository(db, timeout),
+			VehicleType:  postgres.NewVehicleTypeRepository(db, timeout),
+			Webhook:      postgres.NewWebhookRepository(db, timeout),
 		}
 
 	default:
@@ -0,0 +1,172 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+
+	"github.com/JGCaceres97/parking/migrations"
+)
+
+// ErrSchemaOutdated indica que la DB no tiene aplicadas todas las migraciones que espera el código.
+var ErrSchemaOutdated = errors.New("el esquema de la DB está desactualizado")
+
+// Migrator aplica las migraciones incluidas en el binario sobre la DB del driver configurado.
+type Migrator struct {
+	db       *sql.DB
+	driver   string
+	provider *goose.Provider
+}
+
+func NewMigrator(db *sql.DB, driver string) (*Migrator, error) {
+	var dialect goose.Dialect
+
+	switch driver {
+	case "sqlite":
+		dialect = goose.DialectSQLite3
+
+	case "mysql":
+		dialect = goose.DialectMySQL
+
+	case "postgres":
+		dialect = goose.DialectPostgres
+
+	default:
+		return nil, fmt.Errorf("driver de DB no soportado: %s", driver)
+	}
+
+	fsys, err := migrations.FS(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := goose.NewProvider(dialect, db, fsys)
+	if err != nil {
+		return nil, fmt.Errorf("error al preparar migraciones: %w", err)
+	}
+
+	return &Migrator{db: db, driver: driver, provider: provider}, nil
+}
+
+// Up aplica todas las migraciones pendientes.
+func (m *Migrator) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	var results []*goose.MigrationResult
+
+	err := m.withoutForeignKeys(ctx, func() (err error) {
+		results, err = m.provider.Up(ctx)
+		return err
+	})
+	if err != nil {
+		return results, fmt.Errorf("error al aplicar migraciones: %w", err)
+	}
+
+	return results, nil
+}
+
+// Down revierte la última migración aplicada.
+func (m *Migrator) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	var result *goose.MigrationResult
+
+	err := m.withoutForeignKeys(ctx, func() (err error) {
+		result, err = m.provider.Down(ctx)
+		return err
+	})
+	if err != nil {
+		return result, fmt.Errorf("error al revertir migración: %w", err)
+	}
+
+	return result, nil
+}
+
+// withoutForeignKeys ejecuta fn con las claves foráneas de SQLite desactivadas, ya que algunas
+// migraciones reconstruyen tablas referenciadas y el DROP TABLE fallaría con ellas activas. El
+// PRAGMA no tiene efecto dentro de una transacción, por lo que se aplica sobre la única conexión
+// del pool antes de que goose abra las suyas. Al terminar se restablece el valor anterior y se
+// verifica que ninguna referencia haya quedado rota.
+func (m *Migrator) withoutForeignKeys(ctx context.Context, fn func() error) (err error) {
+	if m.driver != "sqlite" {
+		return fn()
+	}
+
+	var enabled bool
+	if err := m.db.QueryRowContext(ctx, "PRAGMA foreign_keys;").Scan(&enabled); err != nil {
+		return fmt.Errorf("error al consultar las claves foráneas: %w", err)
+	}
+
+	if !enabled {
+		return fn()
+	}
+
+	if _, err := m.db.ExecContext(ctx, "PRAGMA foreign_keys = OFF;"); err != nil {
+		return fmt.Errorf("error al desactivar las claves foráneas: %w", err)
+	}
+
+	defer func() {
+		if _, restoreErr := m.db.ExecContext(context.WithoutCancel(ctx), "PRAGMA foreign_keys = ON;"); restoreErr != nil && err == nil {
+			err = fmt.Errorf("error al reactivar las claves foráneas: %w", restoreErr)
+		}
+	}()
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	rows, err := m.db.QueryContext(ctx, "PRAGMA foreign_key_check;")
+	if err != nil {
+		return fmt.Errorf("error al verificar las claves foráneas: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var table string
+		if err := rows.Scan(&table, new(sql.NullInt64), new(sql.NullString), new(sql.NullInt64)); err != nil {
+			return fmt.Errorf("error al verificar las claves foráneas: %w", err)
+		}
+
+		return fmt.Errorf("la migración dejó referencias rotas en la tabla %s", table)
+	}
+
+	return rows.Err()
+}
+
+// Status retorna el estado de cada migración, ordenadas por versión.
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error al consultar el estado de las migraciones: %w", err)
+	}
+
+	return statuses, nil
+}
+
+// CheckVersion retorna ErrSchemaOutdated si hay migraciones sin aplicar.
+func (m *Migrator) CheckVersion(ctx context.Context) error {
+	pending, err := m.provider.HasPending(ctx)
+	if err != nil {
+		return fmt.Errorf("error al verificar la versión del esquema: %w", err)
+	}
+
+	if !pending {
+		return nil
+	}
+
+	current, target, err := m.Versions(ctx)
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: versión %d, se esperaba %d", ErrSchemaOutdated, current, target)
+}
+
+// Versions retorna la versión aplicada en la DB y la última incluida en el binario.
+func (m *Migrator) Versions(ctx context.Context) (current, target int64, err error) {
+	current, target, err = m.provider.GetVersions(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error al verificar la versión del esquema: %w", err)
+	}
+
+	return current, target, nil
+}
@@ -0,0 +1,91 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMigratorCheckVersion(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := NewConnection(ctx, "sqlite", "file::memory:?_time_format=sqlite", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	migrator, err := NewMigrator(db, "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migrator.CheckVersion(ctx); !errors.Is(err, ErrSchemaOutdated) {
+		t.Fatalf("CheckVersion() en una DB vacía = %v, se esperaba %v", err, ErrSchemaOutdated)
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up() = %v", err)
+	}
+
+	if err := migrator.CheckVersion(ctx); err != nil {
+		t.Fatalf("CheckVersion() tras migrar = %v", err)
+	}
+
+	if _, err := migrator.Down(ctx); err != nil {
+		t.Fatalf("Down() = %v", err)
+	}
+
+	if err := migrator.CheckVersion(ctx); !errors.Is(err, ErrSchemaOutdated) {
+		t.Errorf("CheckVersion() tras revertir = %v, se esperaba %v", err, ErrSchemaOutdated)
+	}
+}
+
+func TestMigratorUpWithForeignKeys(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := NewConnection(ctx, "sqlite", "file::memory:?_time_format=sqlite&_pragma=foreign_keys(1)", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	migrator, err := NewMigrator(db, "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// La migración 6 reconstruye USERS, que ya está referenciada por PARKING_RECORDS.
+	if _, err := migrator.provider.UpTo(ctx, 5); err != nil {
+		t.Fatalf("UpTo(5) = %v", err)
+	}
+
+	statements := []string{
+		"INSERT INTO USERS (id, username, password_hash, role) VALUES ('U1', 'cajero', 'hash', 'common');",
+		"INSERT INTO PARKING_RECORDS (id, user_id, vehicle_type_id, license_plate, entry_time) SELECT 'P1', 'U1', id, 'ABC123', CURRENT_TIMESTAMP FROM VEHICLE_TYPES LIMIT 1;",
+	}
+
+	for _, stmt := range statements {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up() = %v", err)
+	}
+
+	var enabled bool
+	if err := db.QueryRowContext(ctx, "PRAGMA foreign_keys;").Scan(&enabled); err != nil {
+		t.Fatal(err)
+	}
+
+	if !enabled {
+		t.Error("las claves foráneas quedaron desactivadas tras migrar")
+	}
+
+	if _, err := db.ExecContext(ctx, "DELETE FROM USERS WHERE id = 'U1';"); err == nil {
+		t.Error("se eliminó un usuario con registros de estacionamiento, se esperaba una violación de clave foránea")
+	}
+}
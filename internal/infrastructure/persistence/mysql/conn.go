@@ -6,15 +6,27 @@ import (
 	"fmt"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
 
 func NewConnection(ctx context.Context, dsn string, timeout time.Duration) (*sql.DB, error) {
-	conn, err := sql.Open("mysql", dsn)
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error al interpretar el DSN de MySQL: %w", err)
+	}
+
+	// Los repositorios usan RowsAffected para detectar registros inexistentes. Sin esta opción,
+	// MySQL informa las filas modificadas en lugar de las encontradas, y un UPDATE que no cambia
+	// ningún valor se confundiría con uno que no encontró el registro.
+	cfg.ClientFoundRows = true
+
+	connector, err := mysql.NewConnector(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("error al abrir la conexión con MySQL: %w", err)
 	}
 
+	conn := sql.OpenDB(connector)
+
 	conn.SetMaxOpenConns(20)
 	conn.SetMaxIdleConns(20)
 	conn.SetConnMaxLifetime(2 * time.Minute)
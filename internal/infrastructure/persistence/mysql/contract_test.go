@@ -0,0 +1,58 @@
+package mysql
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pressly/goose/v3"
+
+	"github.com/JGCaceres97/parking/internal/infrastructure/persistence/persistencetest"
+)
+
+// TestRepositoryContract se ejecuta contra el servidor indicado en MYSQL_TEST_DSN (ej.
+// "root:password@tcp(localhost:3306)/parking_test?parseTime=true&loc=UTC").
+func TestRepositoryContract(t *testing.T) {
+	dsn := os.Getenv("MYSQL_TEST_DSN")
+	if dsn == "" {
+		t.Skip("MYSQL_TEST_DSN no está definido")
+	}
+
+	db, err := NewConnection(context.Background(), dsn, 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	persistencetest.Migrate(t, db, goose.DialectMySQL, "mysql")
+
+	repos := persistencetest.Repositories{
+		UnitOfWork:   NewUnitOfWork(db),
+		Audit:        NewAuditRepository(db, time.Second),
+		Identity:     NewIdentityRepository(db, time.Second),
+		LoginAttempt: NewLoginAttemptRepository(db, time.Second),
+		MFA:          NewMFARepository(db, time.Second),
+		Outbox:       NewOutboxRepository(db, time.Second),
+		Parking:      NewParkingRepository(db, time.Second),
+		Report:       NewReportRepository(db, time.Second),
+		Retention:    NewRetentionRepository(db, time.Second),
+		Role:         NewRoleRepository(db, time.Second),
+		User:         NewUserRepository(db, time.Second),
+		VehicleType:  NewVehicleTypeRepository(db, time.Second),
+		Webhook:      NewWebhookRepository(db, time.Second),
+	}
+
+	t.Run("UnitOfWork", func(t *testing.T) { persistencetest.RunUnitOfWork(t, repos) })
+	t.Run("Audit", func(t *testing.T) { persistencetest.RunAuditRepository(t, repos) })
+	t.Run("Identity", func(t *testing.T) { persistencetest.RunIdentityRepository(t, repos) })
+	t.Run("LoginAttempt", func(t *testing.T) { persistencetest.RunLoginAttemptRepository(t, repos) })
+	t.Run("MFA", func(t *testing.T) { persistencetest.RunMFARepository(t, repos) })
+	t.Run("Outbox", func(t *testing.T) { persistencetest.RunOutboxRepository(t, repos) })
+	t.Run("Parking", func(t *testing.T) { persistencetest.RunParkingRepository(t, repos) })
+	t.Run("Retention", func(t *testing.T) { persistencetest.RunRetentionRepository(t, repos) })
+	t.Run("Role", func(t *testing.T) { persistencetest.RunRoleRepository(t, repos) })
+	t.Run("User", func(t *testing.T) { persistencetest.RunUserRepository(t, repos) })
+	t.Run("VehicleType", func(t *testing.T) { persistencetest.RunVehicleTypeRepository(t, repos) })
+	t.Run("Webhook", func(t *testing.T) { persistencetest.RunWebhookRepository(t, repos) })
+}
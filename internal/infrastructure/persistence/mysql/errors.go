@@ -0,0 +1,28 @@
+package mysql
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	// erDupEntry es el código de MySQL para una clave única o primaria duplicada.
+	erDupEntry = 1062
+
+	// erRowIsReferenced es el código de MySQL al eliminar una fila referenciada por una clave
+	// foránea sin ON DELETE CASCADE.
+	erRowIsReferenced = 1451
+)
+
+// isUniqueViolation indica si err se debe a un índice único o una clave primaria duplicada.
+func isUniqueViolation(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == erDupEntry
+}
+
+// isForeignKeyViolation indica si err se debe a que la fila eliminada sigue referenciada.
+func isForeignKeyViolation(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == erRowIsReferenced
+}
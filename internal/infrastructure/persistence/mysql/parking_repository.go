@@ -4,22 +4,23 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/JGCaceres97/parking/internal/application/parking"
 	"github.com/JGCaceres97/parking/internal/domain"
-	"github.com/JGCaceres97/parking/internal/infrastructure/config"
 )
 
 type parkingRepository struct {
-	DB *sql.DB
+	DB      *sql.DB
+	Timeout time.Duration
 }
 
-func NewParkingRepository(db *sql.DB) parking.Repository {
-	return &parkingRepository{DB: db}
+func NewParkingRepository(db *sql.DB, timeout time.Duration) parking.Repository {
+	return &parkingRepository{DB: db, Timeout: timeout}
 }
 
 func (r *parkingRepository) CreateEntry(ctx context.Context, record *domain.ParkingRecord) error {
-	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	ctx, cancel := withTimeout(ctx, r.Timeout)
 	defer cancel()
 
 	query := `
@@ -27,7 +28,7 @@ func (r *parkingRepository) CreateEntry(ctx context.Context, record *domain.Park
 		(id, user_id, vehicle_type_id, license_plate, entry_time)
 		VALUES (?, ?, ?, ?, ?);`
 
-	_, err := r.DB.ExecContext(
+	_, err := conn(ctx, r.DB).ExecContext(
 		ctx,
 		query,
 		record.ID,
@@ -39,7 +40,11 @@ func (r *parkingRepository) CreateEntry(ctx context.Context, record *domain.Park
 
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
-			return fmt.Errorf("timeout de DB excedido al buscar tipo de vehículo: %w", ctx.Err())
+			return fmt.Errorf("timeout de DB excedido al crear registro de entrada: %w", ctx.Err())
+		}
+
+		if isUniqueViolation(err) {
+			return domain.ErrActiveParkingAlreadyExists
 		}
 
 		return fmt.Errorf("error al crear registro de entrada: %w", err)
@@ -49,21 +54,26 @@ func (r *parkingRepository) CreateEntry(ctx context.Context, record *domain.Park
 }
 
 func (r *parkingRepository) FindByID(ctx context.Context, id string) (*domain.ParkingRecord, error) {
-	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	ctx, cancel := withTimeout(ctx, r.Timeout)
 	defer cancel()
 
 	query := `
-		SELECT id, user_id, vehicle_type_id, license_plate, entry_time, exit_time, total_charge, calculated_hours
-		FROM PARKING_RECORDS
-		WHERE id = ?;`
+		SELECT p.id, p.user_id, p.vehicle_type_id, p.license_plate, p.entry_time, p.exit_time,
+			p.total_charge, p.calculated_hours, COALESCE(u.username, ''),
+			p.voided_at, p.voided_by, p.void_reason
+		FROM PARKING_RECORDS p
+		LEFT JOIN USERS u ON u.id = p.user_id
+		WHERE p.id = ?;`
 
 	var record domain.ParkingRecord
 
-	row := r.DB.QueryRowContext(ctx, query, id)
+	row := conn(ctx, r.DB).QueryRowContext(ctx, query, id)
 
 	var exitTime sql.NullTime
 	var totalCharge sql.NullFloat64
 	var calculatedHours sql.NullInt32
+	var voidedAt sql.NullTime
+	var voidedBy, voidReason sql.NullString
 
 	err := row.Scan(
 		&record.ID,
@@ -74,6 +84,10 @@ func (r *parkingRepository) FindByID(ctx context.Context, id string) (*domain.Pa
 		&exitTime,
 		&totalCharge,
 		&calculatedHours,
+		&record.Username,
+		&voidedAt,
+		&voidedBy,
+		&voidReason,
 	)
 
 	if err != nil {
@@ -101,11 +115,17 @@ func (r *parkingRepository) FindByID(ctx context.Context, id string) (*domain.Pa
 		record.CalculatedHours = &h
 	}
 
+	if voidedAt.Valid {
+		record.VoidedAt = &voidedAt.Time
+		record.VoidedBy = voidedBy.String
+		record.VoidReason = voidReason.String
+	}
+
 	return &record, nil
 }
 
 func (r *parkingRepository) FindOpenByLicensePlate(ctx context.Context, licensePlate string) (*domain.ParkingRecord, error) {
-	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	ctx, cancel := withTimeout(ctx, r.Timeout)
 	defer cancel()
 
 	query := `
@@ -115,7 +135,7 @@ func (r *parkingRepository) FindOpenByLicensePlate(ctx context.Context, licenseP
 
 	var record domain.ParkingRecord
 
-	row := r.DB.QueryRowContext(ctx, query, licensePlate)
+	row := conn(ctx, r.DB).QueryRowContext(ctx, query, licensePlate)
 
 	err := row.Scan(
 		&record.ID,
@@ -141,15 +161,15 @@ func (r *parkingRepository) FindOpenByLicensePlate(ctx context.Context, licenseP
 }
 
 func (r *parkingRepository) UpdateExit(ctx context.Context, record *domain.ParkingRecord) error {
-	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	ctx, cancel := withTimeout(ctx, r.Timeout)
 	defer cancel()
 
 	query := `
 		UPDATE PARKING_RECORDS
 		SET exit_time = ?, total_charge = ?, calculated_hours = ?
-		WHERE id = ?;`
+		WHERE id = ? AND exit_time IS NULL;`
 
-	result, err := r.DB.ExecContext(
+	result, err := conn(ctx, r.DB).ExecContext(
 		ctx,
 		query,
 		record.ExitTime,
@@ -174,17 +194,54 @@ func (r *parkingRepository) UpdateExit(ctx context.Context, record *domain.Parki
 	return nil
 }
 
+func (r *parkingRepository) Void(ctx context.Context, record *domain.ParkingRecord) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	// Si el registro se cerró mientras tanto, se conserva su hora de salida.
+	query := `
+		UPDATE PARKING_RECORDS
+		SET exit_time = COALESCE(exit_time, ?), voided_at = ?, voided_by = ?, void_reason = ?
+		WHERE id = ? AND voided_at IS NULL;`
+
+	result, err := conn(ctx, r.DB).ExecContext(
+		ctx,
+		query,
+		record.ExitTime,
+		record.VoidedAt,
+		record.VoidedBy,
+		record.VoidReason,
+		record.ID,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al anular registro: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al anular registro: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return domain.ErrParkingRecordNotFound
+	}
+
+	return nil
+}
+
 func (r *parkingRepository) ListCurrent(ctx context.Context) ([]domain.ParkingRecord, error) {
-	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	ctx, cancel := withTimeout(ctx, r.Timeout)
 	defer cancel()
 
 	query := `
-		SELECT id, user_id, vehicle_type_id, license_plate, entry_time
-		FROM PARKING_RECORDS
-		WHERE exit_time IS NULL
-		ORDER BY entry_time DESC;`
+		SELECT p.id, p.user_id, p.vehicle_type_id, p.license_plate, p.entry_time, COALESCE(u.username, '')
+		FROM PARKING_RECORDS p
+		LEFT JOIN USERS u ON u.id = p.user_id
+		WHERE p.exit_time IS NULL
+		ORDER BY p.entry_time DESC;`
 
-	rows, err := r.DB.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query)
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return nil, fmt.Errorf("timeout de DB excedido al listar vehículos actuales: %w", ctx.Err())
@@ -205,6 +262,7 @@ func (r *parkingRepository) ListCurrent(ctx context.Context) ([]domain.ParkingRe
 			&record.VehicleTypeID,
 			&record.LicensePlate,
 			&record.EntryTime,
+			&record.Username,
 		)
 
 		if err != nil {
@@ -222,16 +280,19 @@ func (r *parkingRepository) ListCurrent(ctx context.Context) ([]domain.ParkingRe
 }
 
 func (r *parkingRepository) ListHistory(ctx context.Context) ([]domain.ParkingRecord, error) {
-	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	ctx, cancel := withTimeout(ctx, r.Timeout)
 	defer cancel()
 
 	query := `
-		SELECT id, user_id, vehicle_type_id, license_plate, entry_time, exit_time, total_charge, calculated_hours
-		FROM PARKING_RECORDS
-		WHERE exit_time IS NOT NULL
-		ORDER BY exit_time DESC;`
-
-	rows, err := r.DB.QueryContext(ctx, query)
+		SELECT p.id, p.user_id, p.vehicle_type_id, p.license_plate, p.entry_time, p.exit_time,
+			p.total_charge, p.calculated_hours, COALESCE(u.username, ''),
+			p.voided_at, p.voided_by, p.void_reason
+		FROM PARKING_RECORDS p
+		LEFT JOIN USERS u ON u.id = p.user_id
+		WHERE p.exit_time IS NOT NULL
+		ORDER BY p.exit_time DESC;`
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query)
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return nil, fmt.Errorf("timeout de DB excedido al listar historial: %w", ctx.Err())
@@ -249,6 +310,8 @@ func (r *parkingRepository) ListHistory(ctx context.Context) ([]domain.ParkingRe
 		var exitTime sql.NullTime
 		var totalCharge sql.NullFloat64
 		var calculatedHours sql.NullInt32
+		var voidedAt sql.NullTime
+		var voidedBy, voidReason sql.NullString
 
 		err := rows.Scan(
 			&record.ID,
@@ -259,6 +322,10 @@ func (r *parkingRepository) ListHistory(ctx context.Context) ([]domain.ParkingRe
 			&exitTime,
 			&totalCharge,
 			&calculatedHours,
+			&record.Username,
+			&voidedAt,
+			&voidedBy,
+			&voidReason,
 		)
 
 		if err != nil {
@@ -278,6 +345,12 @@ func (r *parkingRepository) ListHistory(ctx context.Context) ([]domain.ParkingRe
 			record.CalculatedHours = &h
 		}
 
+		if voidedAt.Valid {
+			record.VoidedAt = &voidedAt.Time
+			record.VoidedBy = voidedBy.String
+			record.VoidReason = voidReason.String
+		}
+
 		records = append(records, record)
 	}
 
@@ -287,3 +360,21 @@ func (r *parkingRepository) ListHistory(ctx context.Context) ([]domain.ParkingRe
 
 	return records, nil
 }
+
+func (r *parkingRepository) CountCurrent(ctx context.Context) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	var count int
+	err := conn(ctx, r.DB).QueryRowContext(ctx, "SELECT COUNT(*) FROM PARKING_RECORDS WHERE exit_time IS NULL;").Scan(&count)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return 0, fmt.Errorf("timeout de DB excedido al contar vehículos actuales: %w", ctx.Err())
+		}
+
+		return 0, fmt.Errorf("error al contar vehículos actuales: %w", err)
+	}
+
+	return count, nil
+}
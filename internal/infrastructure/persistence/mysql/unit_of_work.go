@@ -0,0 +1,120 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/transaction"
+	"github.com/JGCaceres97/parking/internal/infrastructure/metrics"
+	"github.com/JGCaceres97/parking/internal/infrastructure/tracing"
+)
+
+// dbSystem identifica al motor en las trazas.
+const dbSystem = "mysql"
+
+// txKey es la clave del contexto bajo la que viaja la transacción de una unidad de trabajo.
+type txKey struct{}
+
+// dbtx abstrae *sql.DB y *sql.Tx para ejecutar consultas dentro o fuera de una transacción.
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// conn retorna la transacción de la unidad de trabajo en curso o, si no hay una, la conexión.
+// Todas las consultas deben pasar por aquí para formar parte de la unidad de trabajo.
+func conn(ctx context.Context, db *sql.DB) dbtx {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tracing.WrapDB(tx, dbSystem)
+	}
+
+	return tracing.WrapDB(db, dbSystem)
+}
+
+// withTimeout limita la operación del repositorio a timeout (DB_TIMEOUT) y la registra en las
+// métricas y en un span con el nombre del método que la llamó. La duración se mide hasta que se
+// cancela el contexto.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	query := metrics.QueryCaller()
+	observe := metrics.StartQuery(query)
+
+	ctx, span := tracing.StartQuery(ctx, dbSystem, query.String())
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+
+	return ctx, func() {
+		cancel()
+		observe()
+		span.End()
+	}
+}
+
+// txScope es una transacción iniciada por un repositorio. Si el repositorio se llama dentro de
+// una unidad de trabajo, se reutiliza su transacción y Commit y Rollback quedan a cargo de ella.
+type txScope struct {
+	dbtx
+	tx    *sql.Tx
+	owned bool
+}
+
+func (t *txScope) Commit() error {
+	if !t.owned {
+		return nil
+	}
+
+	return t.tx.Commit()
+}
+
+func (t *txScope) Rollback() error {
+	if !t.owned {
+		return nil
+	}
+
+	return t.tx.Rollback()
+}
+
+// beginTx inicia una transacción o se une a la de la unidad de trabajo en curso.
+func beginTx(ctx context.Context, db *sql.DB) (*txScope, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return &txScope{dbtx: tracing.WrapDB(tx, dbSystem), tx: tx}, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &txScope{dbtx: tracing.WrapDB(tx, dbSystem), tx: tx, owned: true}, nil
+}
+
+type unitOfWork struct {
+	DB *sql.DB
+}
+
+func NewUnitOfWork(db *sql.DB) transaction.UnitOfWork {
+	return &unitOfWork{DB: db}
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := u.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar transacción: %w", err)
+	}
+
+	return nil
+}
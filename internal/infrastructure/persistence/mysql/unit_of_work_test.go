@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/internal/infrastructure/persistence/sqlite"
+)
+
+func TestUnitOfWork(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlite.NewConnection(ctx, "file::memory:?_time_format=sqlite", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	schema := `
+		CREATE TABLE OUTBOX (id TEXT PRIMARY KEY, event_type TEXT, payload TEXT, occurred_at DATETIME, processed_at DATETIME);
+		CREATE TABLE WEBHOOK_DELIVERIES (id TEXT PRIMARY KEY, subscription_id TEXT, event_id TEXT, event_type TEXT,
+			payload TEXT, status TEXT, attempts INTEGER, next_attempt_at DATETIME, created_at DATETIME);`
+
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		t.Fatal(err)
+	}
+
+	uow := NewUnitOfWork(db)
+	outbox := NewOutboxRepository(db, time.Second)
+	webhooks := NewWebhookRepository(db, time.Second)
+	errAbort := errors.New("abortar")
+
+	// write guarda un mensaje y, dentro de la misma unidad de trabajo, una entrega que a su vez
+	// abre su propia transacción.
+	write := func(id string, fail error) error {
+		return uow.Do(ctx, func(ctx context.Context) error {
+			message := &domain.OutboxMessage{ID: id, EventType: domain.EventVehicleEntered, Payload: []byte("{}"), OccurredAt: time.Now()}
+			if err := outbox.Insert(ctx, message); err != nil {
+				return err
+			}
+
+			return uow.Do(ctx, func(ctx context.Context) error {
+				err := webhooks.InsertDeliveries(ctx, []domain.WebhookDelivery{{ID: id, EventID: id, Payload: []byte("{}")}})
+				if err != nil {
+					return err
+				}
+
+				return fail
+			})
+		})
+	}
+
+	count := func(table, id string) int {
+		var n int
+		db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table+" WHERE id = ?", id).Scan(&n)
+		return n
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		fail error
+		want int
+	}{
+		{"Confirma todas las operaciones", "ok", nil, 1},
+		{"Revierte todas las operaciones ante un error", "error", errAbort, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := write(tt.id, tt.fail); !errors.Is(err, tt.fail) {
+				t.Fatalf("Do() = %v, se esperaba %v", err, tt.fail)
+			}
+
+			if got := count("OUTBOX", tt.id); got != tt.want {
+				t.Errorf("mensajes = %d, se esperaba %d", got, tt.want)
+			}
+
+			if got := count("WEBHOOK_DELIVERIES", tt.id); got != tt.want {
+				t.Errorf("entregas = %d, se esperaba %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,29 +6,41 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/JGCaceres97/parking/internal/application/user"
 	"github.com/JGCaceres97/parking/internal/domain"
-	"github.com/JGCaceres97/parking/internal/infrastructure/config"
+	"github.com/JGCaceres97/parking/pkg/ulid"
 )
 
+// userColumns es el listado de columnas que espera scanUser.
+const userColumns = `id, username, password_hash, role, is_active, must_change_password,
+		failed_login_attempts, last_failed_login_at, locked_at, created_at, deleted_at, token_version`
+
 type userRepository struct {
-	DB *sql.DB
+	DB      *sql.DB
+	Timeout time.Duration
 }
 
-func NewUserRepository(db *sql.DB) user.Repository {
-	return &userRepository{DB: db}
+func NewUserRepository(db *sql.DB, timeout time.Duration) user.Repository {
+	return &userRepository{DB: db, Timeout: timeout}
 }
 
 func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
-	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	ctx, cancel := withTimeout(ctx, r.Timeout)
 	defer cancel()
 
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO USERS (id, username, password_hash, role, is_active, created_at)
-		VALUES (?, ?, ?, ?, ?, ?);`
+		INSERT INTO USERS (id, username, password_hash, role, is_active, must_change_password, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?);`
 
-	_, err := r.DB.ExecContext(
+	_, err = tx.ExecContext(
 		ctx,
 		query,
 		user.ID,
@@ -36,6 +48,7 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 		user.Password,
 		user.Role,
 		user.IsActive,
+		user.MustChangePassword,
 		user.CreatedAt,
 	)
 
@@ -44,32 +57,60 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 			return fmt.Errorf("timeout de DB excedido al crear usuario: %w", ctx.Err())
 		}
 
+		if isUniqueViolation(err) {
+			return domain.ErrUsernameAlreadyExists
+		}
+
 		return fmt.Errorf("error al crear usuario: %w", err)
 	}
 
+	if err := insertPasswordHistory(ctx, tx, user.ID, user.Password, user.CreatedAt); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar creación de usuario: %w", err)
+	}
+
 	return nil
 }
 
 func (r *userRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
-	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	ctx, cancel := withTimeout(ctx, r.Timeout)
 	defer cancel()
 
 	query := `
-		SELECT id, username, role, is_active, created_at
+		SELECT ` + userColumns + `
 		FROM USERS
-		WHERE id = ?;`
+		WHERE id = ? AND deleted_at IS NULL;`
 
-	var user domain.User
+	user, err := scanUser(conn(ctx, r.DB).QueryRowContext(ctx, query, id))
 
-	row := r.DB.QueryRowContext(ctx, query, id)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al buscar usuario: %w", ctx.Err())
+		}
 
-	err := row.Scan(
-		&user.ID,
-		&user.Username,
-		&user.Role,
-		&user.IsActive,
-		&user.CreatedAt,
-	)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+
+		return nil, fmt.Errorf("error al buscar usuario: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *userRepository) FindByIDWithDeleted(ctx context.Context, id string) (*domain.User, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + userColumns + `
+		FROM USERS
+		WHERE id = ?;`
+
+	user, err := scanUser(conn(ctx, r.DB).QueryRowContext(ctx, query, id))
 
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
@@ -83,30 +124,19 @@ func (r *userRepository) FindByID(ctx context.Context, id string) (*domain.User,
 		return nil, fmt.Errorf("error al buscar usuario: %w", err)
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 func (r *userRepository) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
-	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	ctx, cancel := withTimeout(ctx, r.Timeout)
 	defer cancel()
 
 	query := `
-		SELECT id, username, password_hash, role, is_active, created_at
+		SELECT ` + userColumns + `
 		FROM USERS
-		WHERE username = ?;`
+		WHERE username = ? AND deleted_at IS NULL;`
 
-	var user domain.User
-
-	row := r.DB.QueryRowContext(ctx, query, username)
-
-	err := row.Scan(
-		&user.ID,
-		&user.Username,
-		&user.Password,
-		&user.Role,
-		&user.IsActive,
-		&user.CreatedAt,
-	)
+	user, err := scanUser(conn(ctx, r.DB).QueryRowContext(ctx, query, username))
 
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
@@ -120,17 +150,17 @@ func (r *userRepository) FindByUsername(ctx context.Context, username string) (*
 		return nil, fmt.Errorf("error al buscar usuario: %w", err)
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 func (r *userRepository) ExistsUsername(ctx context.Context, username string) bool {
-	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	ctx, cancel := withTimeout(ctx, r.Timeout)
 	defer cancel()
 
 	var exists bool
-	query := "SELECT EXISTS(SELECT 1 FROM USERS WHERE username = ?);"
+	query := "SELECT EXISTS(SELECT 1 FROM USERS WHERE username = ? AND deleted_at IS NULL);"
 
-	err := r.DB.QueryRowContext(ctx, query, username).Scan(&exists)
+	err := conn(ctx, r.DB).QueryRowContext(ctx, query, username).Scan(&exists)
 	if err != nil {
 		return false
 	}
@@ -139,13 +169,13 @@ func (r *userRepository) ExistsUsername(ctx context.Context, username string) bo
 }
 
 func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
-	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	ctx, cancel := withTimeout(ctx, r.Timeout)
 	defer cancel()
 
 	var exists bool
-	checkQuery := "SELECT EXISTS(SELECT 1 FROM USERS WHERE id = ?);"
+	checkQuery := "SELECT EXISTS(SELECT 1 FROM USERS WHERE id = ? AND deleted_at IS NULL);"
 
-	err := r.DB.QueryRowContext(ctx, checkQuery, user.ID).Scan(&exists)
+	err := conn(ctx, r.DB).QueryRowContext(ctx, checkQuery, user.ID).Scan(&exists)
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return fmt.Errorf("timeout de DB excedido al verificar existencia de usuario: %w", err)
@@ -163,7 +193,7 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 		SET username = ?, role = ?, is_active = ?
 		WHERE id = ?;`
 
-	result, err := r.DB.ExecContext(
+	result, err := conn(ctx, r.DB).ExecContext(
 		ctx,
 		updateQuery,
 		user.Username,
@@ -177,6 +207,10 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 			return fmt.Errorf("timeout de DB excedido al actualizar usuario: %w", err)
 		}
 
+		if isUniqueViolation(err) {
+			return domain.ErrUsernameAlreadyExists
+		}
+
 		return fmt.Errorf("error al actualizar usuario: %w", err)
 	}
 
@@ -188,35 +222,201 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 	return nil
 }
 
-func (r *userRepository) Delete(ctx context.Context, id string) error {
-	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+func (r *userRepository) UpdateLoginState(ctx context.Context, user *domain.User) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
 	defer cancel()
 
-	var exists bool
-	checkQuery := `SELECT EXISTS(SELECT 1 FROM USERS WHERE id = ?);`
+	query := `
+		UPDATE USERS
+		SET failed_login_attempts = ?, last_failed_login_at = ?, locked_at = ?
+		WHERE id = ?;`
+
+	result, err := conn(ctx, r.DB).ExecContext(
+		ctx,
+		query,
+		user.FailedLoginAttempts,
+		user.LastFailedLoginAt,
+		user.LockedAt,
+		user.ID,
+	)
 
-	err := r.DB.QueryRowContext(ctx, checkQuery, id).Scan(&exists)
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
-			return fmt.Errorf("timeout de DB excedido al verificar existencia de usuario: %w", err)
+			return fmt.Errorf("timeout de DB excedido al actualizar estado de inicio de sesión: %w", ctx.Err())
 		}
 
-		return fmt.Errorf("error al verificar existencia de usuario: %w", err)
+		return fmt.Errorf("error al actualizar estado de inicio de sesión: %w", err)
 	}
 
-	if !exists {
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
 		return domain.ErrUserNotFound
 	}
 
-	deleteQuery := `
-		DELETE FROM USERS
+	return nil
+}
+
+// RegisterLoginFailure incrementa los fallos consecutivos en la misma sentencia, para que los
+// intentos concurrentes no se pierdan, y bloquea la cuenta al alcanzar maxFailures.
+func (r *userRepository) RegisterLoginFailure(ctx context.Context, id string, at time.Time, maxFailures int) (int, bool, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	// MySQL evalúa las asignaciones en orden, por lo que locked_at se calcula con el contador
+	// anterior al incremento.
+	query := `
+		UPDATE USERS
+		SET locked_at = CASE
+				WHEN locked_at IS NULL AND ? > 0 AND failed_login_attempts + 1 >= ? THEN ?
+				ELSE locked_at
+			END,
+			failed_login_attempts = failed_login_attempts + 1,
+			last_failed_login_at = ?
+		WHERE id = ?;`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, query, maxFailures, maxFailures, at, at, id)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return 0, false, fmt.Errorf("timeout de DB excedido al registrar intento fallido: %w", ctx.Err())
+		}
+
+		return 0, false, fmt.Errorf("error al registrar intento fallido: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return 0, false, domain.ErrUserNotFound
+	}
+
+	var failures int
+	var lockedAt sql.NullTime
+
+	err = conn(ctx, r.DB).
+		QueryRowContext(ctx, `SELECT failed_login_attempts, locked_at FROM USERS WHERE id = ?;`, id).
+		Scan(&failures, &lockedAt)
+
+	if err != nil {
+		return 0, false, fmt.Errorf("error al leer intentos fallidos: %w", err)
+	}
+
+	return failures, lockedAt.Valid, nil
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, id, passwordHash string, mustChange bool) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE USERS
+		SET password_hash = ?, must_change_password = ?
 		WHERE id = ?;`
 
-	result, err := r.DB.ExecContext(ctx, deleteQuery, id)
+	result, err := tx.ExecContext(ctx, query, passwordHash, mustChange, id)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al actualizar contraseña: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al actualizar contraseña: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	if err := insertPasswordHistory(ctx, tx, id, passwordHash, time.Now().UTC().Truncate(time.Second)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar cambio de contraseña: %w", err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) RevokeTokens(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `UPDATE USERS SET token_version = token_version + 1 WHERE id = ?;`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, query, id)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al revocar tokens: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al revocar tokens: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *userRepository) ListPasswordHistory(ctx context.Context, id string, limit int) ([]string, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT password_hash
+		FROM PASSWORD_HISTORY
+		WHERE user_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?;`
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, id, limit)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al listar historial de contraseñas: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("error al listar historial de contraseñas: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := []string{}
+
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("error al escanear fila de historial de contraseñas: %w", err)
+		}
+
+		hashes = append(hashes, hash)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar sobre historial de contraseñas: %w", err)
+	}
+
+	return hashes, nil
+}
 
+func (r *userRepository) SoftDelete(ctx context.Context, id string, deletedAt time.Time) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		UPDATE USERS
+		SET is_active = FALSE, deleted_at = ?
+		WHERE id = ? AND deleted_at IS NULL;`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, query, deletedAt, id)
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
-			return fmt.Errorf("timeout de DB excedido al eliminar usuario: %w", err)
+			return fmt.Errorf("timeout de DB excedido al eliminar usuario: %w", ctx.Err())
 		}
 
 		return fmt.Errorf("error al eliminar usuario: %w", err)
@@ -224,22 +424,128 @@ func (r *userRepository) Delete(ctx context.Context, id string) error {
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return nil
+		return domain.ErrUserNotFound
 	}
 
 	return nil
 }
 
-func (r *userRepository) ListAll(ctx context.Context, id string) ([]domain.User, error) {
-	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+func (r *userRepository) Anonymize(ctx context.Context, id, username string, deletedAt time.Time) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
 	defer cancel()
 
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previousUsername string
+	if err := tx.QueryRowContext(ctx, `SELECT username FROM USERS WHERE id = ?;`, id).Scan(&previousUsername); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+
+		return fmt.Errorf("error al buscar usuario: %w", err)
+	}
+
+	// La contraseña se reemplaza por un valor que ningún hash bcrypt puede igualar.
 	query := `
-		SELECT id, username, role, is_active, created_at
+		UPDATE USERS
+		SET username = ?, password_hash = '!', is_active = FALSE, must_change_password = FALSE,
+			failed_login_attempts = 0, last_failed_login_at = NULL, locked_at = NULL,
+			deleted_at = COALESCE(deleted_at, ?)
+		WHERE id = ?;`
+
+	if _, err := tx.ExecContext(ctx, query, username, deletedAt, id); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al anonimizar usuario: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al anonimizar usuario: %w", err)
+	}
+
+	if err := deleteUserData(ctx, tx, id); err != nil {
+		return err
+	}
+
+	// Solo los intentos anteriores a la eliminación pertenecen al usuario; su nombre pudo
+	// reutilizarse después.
+	attemptsQuery := `
+		UPDATE LOGIN_ATTEMPTS
+		SET username = ?
+		WHERE username = ? AND created_at <= ?;`
+
+	if _, err := tx.ExecContext(ctx, attemptsQuery, username, previousUsername, deletedAt); err != nil {
+		return fmt.Errorf("error al anonimizar intentos de inicio de sesión: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar anonimización de usuario: %w", err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists, hasRecords bool
+	checkQuery := `
+		SELECT
+			EXISTS(SELECT 1 FROM USERS WHERE id = ?),
+			EXISTS(SELECT 1 FROM PARKING_RECORDS WHERE user_id = ?)
+				OR EXISTS(SELECT 1 FROM PARKING_RECORDS_ARCHIVE WHERE user_id = ?);`
+
+	if err := tx.QueryRowContext(ctx, checkQuery, id, id, id).Scan(&exists, &hasRecords); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al verificar existencia de usuario: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al verificar existencia de usuario: %w", err)
+	}
+
+	if !exists {
+		return domain.ErrUserNotFound
+	}
+
+	if hasRecords {
+		return domain.ErrUserHasRecords
+	}
+
+	// El historial de contraseñas, el segundo factor y las identidades se eliminan en cascada.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM USERS WHERE id = ?;`, id); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al eliminar usuario: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al eliminar usuario: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar eliminación de usuario: %w", err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) ListAll(ctx context.Context, id string, includeDeleted bool) ([]domain.User, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + userColumns + `
 		FROM USERS
-		WHERE id != ?;`
+		WHERE id != ? AND (? OR deleted_at IS NULL);`
 
-	rows, err := r.DB.QueryContext(ctx, query, id)
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, id, includeDeleted)
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return nil, fmt.Errorf("timeout de DB excedido al listar usuarios: %w", ctx.Err())
@@ -252,21 +558,13 @@ func (r *userRepository) ListAll(ctx context.Context, id string) ([]domain.User,
 	users := []domain.User{}
 
 	for rows.Next() {
-		var user domain.User
-
-		err := rows.Scan(
-			&user.ID,
-			&user.Username,
-			&user.Role,
-			&user.IsActive,
-			&user.CreatedAt,
-		)
-
+		user, err := scanUser(rows)
 		if err != nil {
 			return nil, fmt.Errorf("error al escanear fila de usuario: %w", err)
 		}
 
-		users = append(users, user)
+		user.Password = ""
+		users = append(users, *user)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -275,3 +573,88 @@ func (r *userRepository) ListAll(ctx context.Context, id string) ([]domain.User,
 
 	return users, nil
 }
+
+func insertPasswordHistory(ctx context.Context, tx dbtx, userID, passwordHash string, createdAt time.Time) error {
+	query := `
+		INSERT INTO PASSWORD_HISTORY (id, user_id, password_hash, created_at)
+		VALUES (?, ?, ?, ?);`
+
+	if _, err := tx.ExecContext(ctx, query, ulid.GenerateNewULID(), userID, passwordHash, createdAt); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al guardar historial de contraseñas: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al guardar historial de contraseñas: %w", err)
+	}
+
+	return nil
+}
+
+// deleteUserData elimina las credenciales y vínculos del usuario: historial de contraseñas,
+// segundo factor e identidades externas.
+func deleteUserData(ctx context.Context, tx dbtx, id string) error {
+	queries := []string{
+		`DELETE FROM PASSWORD_HISTORY WHERE user_id = ?;`,
+		`DELETE FROM MFA_RECOVERY_CODES WHERE user_id = ?;`,
+		`DELETE FROM USER_MFA WHERE user_id = ?;`,
+		`DELETE FROM USER_IDENTITIES WHERE user_id = ?;`,
+	}
+
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("timeout de DB excedido al eliminar datos del usuario: %w", ctx.Err())
+			}
+
+			return fmt.Errorf("error al eliminar datos del usuario: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// scanner abstrae *sql.Row y *sql.Rows para reutilizar la lectura de filas.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(row scanner) (*domain.User, error) {
+	var user domain.User
+
+	var lastFailedLoginAt sql.NullTime
+	var lockedAt sql.NullTime
+	var deletedAt sql.NullTime
+
+	err := row.Scan(
+		&user.ID,
+		&user.Username,
+		&user.Password,
+		&user.Role,
+		&user.IsActive,
+		&user.MustChangePassword,
+		&user.FailedLoginAttempts,
+		&lastFailedLoginAt,
+		&lockedAt,
+		&user.CreatedAt,
+		&deletedAt,
+		&user.TokenVersion,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if lastFailedLoginAt.Valid {
+		user.LastFailedLoginAt = &lastFailedLoginAt.Time
+	}
+
+	if lockedAt.Valid {
+		user.LockedAt = &lockedAt.Time
+	}
+
+	if deletedAt.Valid {
+		user.DeletedAt = &deletedAt.Time
+	}
+
+	return &user, nil
+}
@@ -4,22 +4,23 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/JGCaceres97/parking/internal/application/vehicle_type"
 	"github.com/JGCaceres97/parking/internal/domain"
-	"github.com/JGCaceres97/parking/internal/infrastructure/config"
 )
 
 type vehicleTypeRepository struct {
-	DB *sql.DB
+	DB      *sql.DB
+	Timeout time.Duration
 }
 
-func NewVehicleTypeRepository(db *sql.DB) vehicle_type.Repository {
-	return &vehicleTypeRepository{DB: db}
+func NewVehicleTypeRepository(db *sql.DB, timeout time.Duration) vehicle_type.Repository {
+	return &vehicleTypeRepository{DB: db, Timeout: timeout}
 }
 
 func (r *vehicleTypeRepository) FindByID(ctx context.Context, id string) (*domain.VehicleType, error) {
-	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	ctx, cancel := withTimeout(ctx, r.Timeout)
 	defer cancel()
 
 	query := `
@@ -29,7 +30,7 @@ func (r *vehicleTypeRepository) FindByID(ctx context.Context, id string) (*domai
 
 	var record domain.VehicleType
 
-	row := r.DB.QueryRowContext(ctx, query, id)
+	row := conn(ctx, r.DB).QueryRowContext(ctx, query, id)
 
 	err := row.Scan(
 		&record.ID,
@@ -54,7 +55,7 @@ func (r *vehicleTypeRepository) FindByID(ctx context.Context, id string) (*domai
 }
 
 func (r *vehicleTypeRepository) ListAll(ctx context.Context) ([]domain.VehicleType, error) {
-	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	ctx, cancel := withTimeout(ctx, r.Timeout)
 	defer cancel()
 
 	query := `
@@ -62,7 +63,7 @@ func (r *vehicleTypeRepository) ListAll(ctx context.Context) ([]domain.VehicleTy
 		FROM VEHICLE_TYPES
 		ORDER BY name;`
 
-	rows, err := r.DB.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query)
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return nil, fmt.Errorf("timeout de DB excedido al listar tipos de vehículo: %w", ctx.Err())
@@ -97,3 +98,119 @@ func (r *vehicleTypeRepository) ListAll(ctx context.Context) ([]domain.VehicleTy
 
 	return vehicleTypes, nil
 }
+
+func (r *vehicleTypeRepository) FindByName(ctx context.Context, name string) (*domain.VehicleType, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT id, name, hourly_rate, description
+		FROM VEHICLE_TYPES
+		WHERE name = ?;`
+
+	var record domain.VehicleType
+
+	row := conn(ctx, r.DB).QueryRowContext(ctx, query, name)
+
+	err := row.Scan(
+		&record.ID,
+		&record.Name,
+		&record.HourlyRate,
+		&record.Description,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al buscar tipo de vehículo: %w", ctx.Err())
+		}
+
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrVehicleTypeNotFound
+		}
+
+		return nil, fmt.Errorf("error al buscar tipo de vehículo: %w", err)
+	}
+
+	return &record, nil
+}
+
+func (r *vehicleTypeRepository) Create(ctx context.Context, vehicleType *domain.VehicleType) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO VEHICLE_TYPES (id, name, hourly_rate, description)
+		VALUES (?, ?, ?, ?);`
+
+	_, err := conn(ctx, r.DB).ExecContext(
+		ctx,
+		query,
+		vehicleType.ID,
+		vehicleType.Name,
+		vehicleType.HourlyRate,
+		vehicleType.Description,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al crear tipo de vehículo: %w", ctx.Err())
+		}
+
+		if isUniqueViolation(err) {
+			return domain.ErrVehicleTypeNameAlreadyExists
+		}
+
+		return fmt.Errorf("error al crear tipo de vehículo: %w", err)
+	}
+
+	return nil
+}
+
+func (r *vehicleTypeRepository) Update(ctx context.Context, vehicleType *domain.VehicleType) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	var exists bool
+	checkQuery := "SELECT EXISTS(SELECT 1 FROM VEHICLE_TYPES WHERE id = ?);"
+
+	err := conn(ctx, r.DB).QueryRowContext(ctx, checkQuery, vehicleType.ID).Scan(&exists)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al verificar existencia de tipo de vehículo: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al verificar existencia de tipo de vehículo: %w", err)
+	}
+
+	if !exists {
+		return domain.ErrVehicleTypeNotFound
+	}
+
+	query := `
+		UPDATE VEHICLE_TYPES
+		SET name = ?, hourly_rate = ?, description = ?
+		WHERE id = ?;`
+
+	_, err = conn(ctx, r.DB).ExecContext(
+		ctx,
+		query,
+		vehicleType.Name,
+		vehicleType.HourlyRate,
+		vehicleType.Description,
+		vehicleType.ID,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al actualizar tipo de vehículo: %w", ctx.Err())
+		}
+
+		if isUniqueViolation(err) {
+			return domain.ErrVehicleTypeNameAlreadyExists
+		}
+
+		return fmt.Errorf("error al actualizar tipo de vehículo: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+package persistencetest
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/ulid"
+)
+
+// RunAuditRepository verifica el contrato de audit.Repository, incluida la cadena de hashes.
+func RunAuditRepository(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	t.Run("Encadena las entradas", func(t *testing.T) {
+		headSeq, headHash, err := repos.Audit.Head(ctx)
+		if err != nil {
+			t.Fatalf("Head() = %v", err)
+		}
+
+		if headSeq == 0 && headHash != domain.AuditGenesisHash {
+			t.Errorf("Head() de una cadena vacía = %q, se esperaba %q", headHash, domain.AuditGenesisHash)
+		}
+
+		entityID := ulid.GenerateNewULID()
+		entries := []*domain.AuditEntry{
+			newAuditEntry(domain.AuditUserCreate, entityID, nil, json.RawMessage(`{"username": "ana", "roles": ["common"]}`)),
+			newAuditEntry(domain.AuditUserUpdate, entityID, json.RawMessage(`{"username": "ana"}`), json.RawMessage(`{"username":"ana.b"}`)),
+			newAuditEntry(domain.AuditUserDelete, entityID, json.RawMessage(`{"username":"ana.b"}`), nil),
+		}
+
+		prevHash := headHash
+		for i, entry := range entries {
+			if err := repos.Audit.Append(ctx, entry); err != nil {
+				t.Fatalf("Append() = %v", err)
+			}
+
+			if want := headSeq + int64(i) + 1; entry.Seq != want {
+				t.Errorf("Seq = %d, se esperaba %d", entry.Seq, want)
+			}
+
+			if entry.PrevHash != prevHash || entry.Hash != entry.ComputeHash() {
+				t.Errorf("entrada %d mal enlazada: PrevHash = %q, Hash = %q", entry.Seq, entry.PrevHash, entry.Hash)
+			}
+
+			prevHash = entry.Hash
+		}
+
+		seq, hash, err := repos.Audit.Head(ctx)
+		if err != nil {
+			t.Fatalf("Head() = %v", err)
+		}
+
+		if last := entries[len(entries)-1]; seq != last.Seq || hash != last.Hash {
+			t.Errorf("Head() = %d, %q; se esperaba %d, %q", seq, hash, last.Seq, last.Hash)
+		}
+
+		// El hash se recalcula sobre lo leído, por lo que la base debe conservar los bytes del
+		// JSON y la fecha exactos.
+		stored, err := repos.Audit.ListAfter(ctx, headSeq, len(entries))
+		if err != nil {
+			t.Fatalf("ListAfter() = %v", err)
+		}
+
+		if len(stored) != len(entries) {
+			t.Fatalf("ListAfter() = %d entradas, se esperaban %d", len(stored), len(entries))
+		}
+
+		for i, got := range stored {
+			want := entries[i]
+
+			if got.ID != want.ID || got.Seq != want.Seq || got.Hash != want.Hash {
+				t.Errorf("ListAfter()[%d] = %s (%d), se esperaba %s (%d)", i, got.ID, got.Seq, want.ID, want.Seq)
+			}
+
+			if string(got.Before) != string(want.Before) || string(got.After) != string(want.After) {
+				t.Errorf("entrada %d = %s -> %s, se esperaba %s -> %s", got.Seq, got.Before, got.After, want.Before, want.After)
+			}
+
+			if got.ComputeHash() != got.Hash {
+				t.Errorf("el hash de la entrada %d no coincide con su contenido leído", got.Seq)
+			}
+		}
+	})
+
+	t.Run("Inserciones concurrentes", func(t *testing.T) {
+		headSeq, headHash, err := repos.Audit.Head(ctx)
+		if err != nil {
+			t.Fatalf("Head() = %v", err)
+		}
+
+		entityID := ulid.GenerateNewULID()
+
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Go(func() {
+				if err := repos.Audit.Append(ctx, newAuditEntry(domain.AuditUserUpdate, entityID, nil, nil)); err != nil {
+					t.Errorf("Append() = %v", err)
+				}
+			})
+		}
+		wg.Wait()
+
+		entries, err := repos.Audit.ListAfter(ctx, headSeq, 100)
+		if err != nil {
+			t.Fatalf("ListAfter() = %v", err)
+		}
+
+		if len(entries) < 4 {
+			t.Fatalf("ListAfter() = %d entradas, se esperaban al menos 4", len(entries))
+		}
+
+		// Ninguna inserción puede enlazarse a la misma entrada que otra.
+		seq, prevHash := headSeq, headHash
+		for _, entry := range entries {
+			if entry.Seq != seq+1 || entry.PrevHash != prevHash {
+				t.Fatalf("entrada %d enlazada a %q, se esperaba la entrada %d con %q", entry.Seq, entry.PrevHash, seq+1, prevHash)
+			}
+
+			seq, prevHash = entry.Seq, entry.Hash
+		}
+	})
+
+	t.Run("Filtros y paginación", func(t *testing.T) {
+		entityID := ulid.GenerateNewULID()
+		actorID := ulid.GenerateNewULID()
+		ids := []string{}
+
+		for _, action := range []string{domain.AuditRoleCreate, domain.AuditRoleUpdate, domain.AuditRoleUpdate, domain.AuditRoleDelete} {
+			entry := newAuditEntry(action, entityID, nil, nil)
+			entry.EntityType = domain.AuditEntityRole
+			entry.ActorID = actorID
+
+			if err := repos.Audit.Append(ctx, entry); err != nil {
+				t.Fatalf("Append() = %v", err)
+			}
+
+			ids = append(ids, entry.ID)
+		}
+
+		yesterday := time.Now().UTC().Add(-24 * time.Hour)
+
+		tests := []struct {
+			name   string
+			filter domain.AuditFilter
+			want   []string
+		}{
+			{
+				name:   "Por entidad, de la más reciente a la más antigua",
+				filter: domain.AuditFilter{EntityType: domain.AuditEntityRole, EntityID: entityID, Limit: 10},
+				want:   []string{ids[3], ids[2], ids[1], ids[0]},
+			},
+			{
+				name:   "Por actor y acción",
+				filter: domain.AuditFilter{ActorID: actorID, Action: domain.AuditRoleUpdate, Limit: 10},
+				want:   []string{ids[2], ids[1]},
+			},
+			{
+				name:   "Por rango de fechas",
+				filter: domain.AuditFilter{EntityID: entityID, From: &yesterday, Limit: 10},
+				want:   []string{ids[3], ids[2], ids[1], ids[0]},
+			},
+			{
+				name:   "Fuera del rango de fechas",
+				filter: domain.AuditFilter{EntityID: entityID, To: &yesterday, Limit: 10},
+				want:   []string{},
+			},
+			{
+				name:   "Con límite",
+				filter: domain.AuditFilter{EntityID: entityID, Limit: 2},
+				want:   []string{ids[3], ids[2]},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				entries, err := repos.Audit.List(ctx, tt.filter)
+				if err != nil {
+					t.Fatalf("List() = %v", err)
+				}
+
+				if got := auditIDs(entries); !slices.Equal(got, tt.want) {
+					t.Errorf("List() = %v, se esperaba %v", got, tt.want)
+				}
+			})
+		}
+
+		t.Run("Página siguiente", func(t *testing.T) {
+			first, err := repos.Audit.List(ctx, domain.AuditFilter{EntityID: entityID, Limit: 2})
+			if err != nil {
+				t.Fatalf("List() = %v", err)
+			}
+
+			next, err := repos.Audit.List(ctx, domain.AuditFilter{EntityID: entityID, BeforeSeq: first[len(first)-1].Seq, Limit: 2})
+			if err != nil {
+				t.Fatalf("List() = %v", err)
+			}
+
+			if got, want := auditIDs(next), []string{ids[1], ids[0]}; !slices.Equal(got, want) {
+				t.Errorf("List() = %v, se esperaba %v", got, want)
+			}
+		})
+	})
+
+	t.Run("Plazo vencido", func(t *testing.T) {
+		ctx := expiredContext()
+
+		err := repos.Audit.Append(ctx, newAuditEntry(domain.AuditUserCreate, ulid.GenerateNewULID(), nil, nil))
+		assertTimeout(t, "Append()", err)
+
+		_, err = repos.Audit.List(ctx, domain.AuditFilter{Limit: 1})
+		assertTimeout(t, "List()", err)
+
+		_, err = repos.Audit.ListAfter(ctx, 0, 1)
+		assertTimeout(t, "ListAfter()", err)
+
+		_, _, err = repos.Audit.Head(ctx)
+		assertTimeout(t, "Head()", err)
+	})
+}
+
+func newAuditEntry(action, entityID string, before, after json.RawMessage) *domain.AuditEntry {
+	return &domain.AuditEntry{
+		ID:         ulid.GenerateNewULID(),
+		Action:     action,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   entityID,
+		Before:     before,
+		After:      after,
+		IP:         "127.0.0.1",
+		RequestID:  ulid.GenerateNewULID(),
+		CreatedAt:  time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func auditIDs(entries []domain.AuditEntry) []string {
+	ids := []string{}
+
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+
+	return ids
+}
@@ -0,0 +1,51 @@
+package persistencetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/ulid"
+)
+
+// RunIdentityRepository verifica el contrato de sso.IdentityRepository.
+func RunIdentityRepository(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	u := newUser(t, repos)
+	identity := &domain.UserIdentity{
+		ID:        ulid.GenerateNewULID(),
+		UserID:    u.ID,
+		Issuer:    "https://idp.example.com",
+		Subject:   ulid.GenerateNewULID(),
+		Email:     "cajero@example.com",
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+
+	if _, err := repos.Identity.FindBySubject(ctx, identity.Issuer, identity.Subject); !errors.Is(err, domain.ErrIdentityNotFound) {
+		t.Errorf("FindBySubject() sin vincular = %v, se esperaba %v", err, domain.ErrIdentityNotFound)
+	}
+
+	if err := repos.Identity.Create(ctx, identity); err != nil {
+		t.Fatalf("Create() = %v", err)
+	}
+
+	stored, err := repos.Identity.FindBySubject(ctx, identity.Issuer, identity.Subject)
+	if err != nil {
+		t.Fatalf("FindBySubject() = %v", err)
+	}
+
+	if stored.UserID != u.ID || stored.Email != identity.Email || !stored.CreatedAt.Equal(identity.CreatedAt) {
+		t.Errorf("FindBySubject() = %+v, se esperaba %+v", stored, identity)
+	}
+
+	duplicate := *identity
+	duplicate.ID = ulid.GenerateNewULID()
+	duplicate.UserID = newUser(t, repos).ID
+
+	if err := repos.Identity.Create(ctx, &duplicate); !errors.Is(err, domain.ErrIdentityAlreadyLinked) {
+		t.Errorf("Create() con un sujeto vinculado = %v, se esperaba %v", err, domain.ErrIdentityAlreadyLinked)
+	}
+}
@@ -0,0 +1,74 @@
+package persistencetest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/ulid"
+)
+
+// RunLoginAttemptRepository verifica el contrato de auth.LoginAttemptRepository.
+func RunLoginAttemptRepository(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	username := "contract-" + strings.ToLower(ulid.GenerateNewULID())
+	ip := "10." + ulid.GenerateNewULID()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	attempts := []domain.LoginAttempt{
+		{Reason: domain.LoginReasonInvalidPassword, CreatedAt: now.Add(-2 * time.Minute)},
+		{Reason: domain.LoginReasonInvalidPassword, CreatedAt: now.Add(-time.Minute)},
+		{Reason: domain.LoginReasonThrottled, CreatedAt: now},
+		{Reason: domain.LoginReasonSuccess, Success: true, CreatedAt: now},
+		{Reason: domain.LoginReasonInvalidPassword, CreatedAt: now.Add(-time.Hour)},
+	}
+
+	for _, attempt := range attempts {
+		attempt.ID = ulid.GenerateNewULID()
+		attempt.Username = username
+		attempt.IP = ip
+
+		if err := repos.LoginAttempt.Create(ctx, &attempt); err != nil {
+			t.Fatalf("Create() = %v", err)
+		}
+	}
+
+	t.Run("Fallos por IP", func(t *testing.T) {
+		count, last, err := repos.LoginAttempt.FailuresByIP(ctx, ip, now.Add(-10*time.Minute))
+		if err != nil {
+			t.Fatalf("FailuresByIP() = %v", err)
+		}
+
+		// Los intentos rechazados por espera, los exitosos y los anteriores a since no cuentan.
+		want := now.Add(-time.Minute)
+		if count != 2 || last == nil || !last.Equal(want) {
+			t.Errorf("FailuresByIP() = %d, %v; se esperaba 2, %v", count, last, want)
+		}
+
+		count, last, err = repos.LoginAttempt.FailuresByIP(ctx, "sin-intentos-"+ip, now.Add(-10*time.Minute))
+		if err != nil || count != 0 || last != nil {
+			t.Errorf("FailuresByIP() sin intentos = %d, %v, %v; se esperaba 0, nil, nil", count, last, err)
+		}
+	})
+
+	t.Run("Listado por usuario sin distinguir mayúsculas", func(t *testing.T) {
+		got, err := repos.LoginAttempt.List(ctx, strings.ToUpper(username), 10)
+		if err != nil {
+			t.Fatalf("List() = %v", err)
+		}
+
+		if len(got) != len(attempts) {
+			t.Fatalf("List() = %d intentos, se esperaban %d", len(got), len(attempts))
+		}
+
+		for i := 1; i < len(got); i++ {
+			if got[i].CreatedAt.After(got[i-1].CreatedAt) {
+				t.Errorf("List() = %+v, se esperaba ordenado del más reciente al más antiguo", got)
+				break
+			}
+		}
+	})
+}
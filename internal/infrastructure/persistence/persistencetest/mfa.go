@@ -0,0 +1,101 @@
+package persistencetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// RunMFARepository verifica el contrato de mfa.Repository.
+func RunMFARepository(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	t.Run("Inscripción inexistente", func(t *testing.T) {
+		u := newUser(t, repos)
+
+		if _, err := repos.MFA.Find(ctx, u.ID); !errors.Is(err, domain.ErrMFANotEnrolled) {
+			t.Errorf("Find() = %v, se esperaba %v", err, domain.ErrMFANotEnrolled)
+		}
+
+		if err := repos.MFA.Enable(ctx, u.ID, time.Now().UTC()); !errors.Is(err, domain.ErrMFANotEnrolled) {
+			t.Errorf("Enable() = %v, se esperaba %v", err, domain.ErrMFANotEnrolled)
+		}
+	})
+
+	t.Run("Códigos de un solo uso", func(t *testing.T) {
+		u := newUser(t, repos)
+		now := time.Now().UTC().Truncate(time.Second)
+
+		enrollment := &domain.UserMFA{UserID: u.ID, Secret: "secreto", CreatedAt: now}
+		if err := repos.MFA.Save(ctx, enrollment, []string{"hash-1", "hash-2"}); err != nil {
+			t.Fatalf("Save() = %v", err)
+		}
+
+		if err := repos.MFA.Enable(ctx, u.ID, now); err != nil {
+			t.Fatalf("Enable() = %v", err)
+		}
+
+		stored, err := repos.MFA.Find(ctx, u.ID)
+		if err != nil {
+			t.Fatalf("Find() = %v", err)
+		}
+
+		if stored.EnabledAt == nil || !stored.EnabledAt.Equal(now) {
+			t.Errorf("EnabledAt = %v, se esperaba %v", stored.EnabledAt, now)
+		}
+
+		// Los casos se ejecutan en orden, ya que cada código aceptado se consume.
+		steps := []struct {
+			step int64
+			want bool
+		}{
+			{step: 100, want: true},
+			{step: 100},
+			{step: 99},
+			{step: 101, want: true},
+		}
+
+		for _, tt := range steps {
+			got, err := repos.MFA.ConsumeStep(ctx, u.ID, tt.step)
+			if err != nil {
+				t.Fatalf("ConsumeStep(%d) = %v", tt.step, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("ConsumeStep(%d) = %v, se esperaba %v", tt.step, got, tt.want)
+			}
+		}
+
+		codes := []struct {
+			hash string
+			want bool
+		}{
+			{hash: "hash-1", want: true},
+			{hash: "hash-1"},
+			{hash: "hash-3"},
+			{hash: "hash-2", want: true},
+		}
+
+		for _, tt := range codes {
+			got, err := repos.MFA.UseRecoveryCode(ctx, u.ID, tt.hash, now)
+			if err != nil {
+				t.Fatalf("UseRecoveryCode(%q) = %v", tt.hash, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("UseRecoveryCode(%q) = %v, se esperaba %v", tt.hash, got, tt.want)
+			}
+		}
+
+		if err := repos.MFA.Delete(ctx, u.ID); err != nil {
+			t.Fatalf("Delete() = %v", err)
+		}
+
+		if _, err := repos.MFA.Find(ctx, u.ID); !errors.Is(err, domain.ErrMFANotEnrolled) {
+			t.Errorf("Find() tras eliminar = %v, se esperaba %v", err, domain.ErrMFANotEnrolled)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package persistencetest
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/ulid"
+)
+
+// RunOutboxRepository verifica el contrato de outbox.Repository.
+func RunOutboxRepository(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	t.Run("Mensajes pendientes y procesados", func(t *testing.T) {
+		// Los identificadores se generan antes de insertar, en desorden, para comprobar que los
+		// pendientes se listan por identificador y no por orden de inserción.
+		ids := []string{ulid.GenerateNewULID(), ulid.GenerateNewULID(), ulid.GenerateNewULID()}
+		slices.Sort(ids)
+
+		for _, id := range []string{ids[2], ids[0], ids[1]} {
+			message, err := domain.NewOutboxMessage(domain.Event{
+				ID:         id,
+				Type:       domain.EventVehicleEntered,
+				OccurredAt: time.Now().UTC().Truncate(time.Second),
+				Data:       map[string]string{"id": id},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := repos.Outbox.Insert(ctx, message); err != nil {
+				t.Fatalf("Insert() = %v", err)
+			}
+		}
+
+		pending, err := repos.Outbox.ListPending(ctx, 1000)
+		if err != nil {
+			t.Fatalf("ListPending() = %v", err)
+		}
+
+		if got := filterIDs(outboxIDs(pending), ids...); !slices.Equal(got, ids) {
+			t.Errorf("ListPending() = %v, se esperaba %v", got, ids)
+		}
+
+		for _, message := range pending {
+			if message.ID == ids[0] && (message.EventType != domain.EventVehicleEntered || len(message.Payload) == 0) {
+				t.Errorf("ListPending() = %+v, se esperaba el evento completo", message)
+			}
+		}
+
+		// Una fecha antigua permite depurar solo los mensajes de esta prueba en una base compartida.
+		processedAt := time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+		for _, id := range ids[:2] {
+			marked, err := repos.Outbox.MarkProcessed(ctx, id, processedAt)
+			if err != nil || !marked {
+				t.Errorf("MarkProcessed(%s) = %v, %v; se esperaba true", id, marked, err)
+			}
+		}
+
+		if marked, err := repos.Outbox.MarkProcessed(ctx, ids[0], processedAt); err != nil || marked {
+			t.Errorf("MarkProcessed() de un mensaje procesado = %v, %v; se esperaba false", marked, err)
+		}
+
+		pending, err = repos.Outbox.ListPending(ctx, 1000)
+		if err != nil {
+			t.Fatalf("ListPending() = %v", err)
+		}
+
+		if got, want := filterIDs(outboxIDs(pending), ids...), ids[2:]; !slices.Equal(got, want) {
+			t.Errorf("ListPending() tras procesar = %v, se esperaba %v", got, want)
+		}
+
+		deleted, err := repos.Outbox.DeleteProcessed(ctx, processedAt.Add(time.Hour))
+		if err != nil {
+			t.Fatalf("DeleteProcessed() = %v", err)
+		}
+
+		if deleted != 2 {
+			t.Errorf("DeleteProcessed() = %d, se esperaba 2", deleted)
+		}
+
+		// El mensaje pendiente no se depura y sigue disponible para procesarse.
+		if marked, err := repos.Outbox.MarkProcessed(ctx, ids[2], time.Now().UTC().Truncate(time.Second)); err != nil || !marked {
+			t.Errorf("MarkProcessed() del mensaje pendiente = %v, %v; se esperaba true", marked, err)
+		}
+	})
+
+	t.Run("Plazo vencido", func(t *testing.T) {
+		ctx := expiredContext()
+
+		message, _ := domain.NewOutboxMessage(domain.Event{ID: ulid.GenerateNewULID(), Type: domain.EventVehicleEntered})
+		assertTimeout(t, "Insert()", repos.Outbox.Insert(ctx, message))
+
+		_, err := repos.Outbox.ListPending(ctx, 1)
+		assertTimeout(t, "ListPending()", err)
+
+		_, err = repos.Outbox.MarkProcessed(ctx, message.ID, time.Now())
+		assertTimeout(t, "MarkProcessed()", err)
+
+		_, err = repos.Outbox.DeleteProcessed(ctx, time.Now())
+		assertTimeout(t, "DeleteProcessed()", err)
+	})
+}
+
+func outboxIDs(messages []domain.OutboxMessage) []string {
+	ids := []string{}
+
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+
+	return ids
+}
@@ -0,0 +1,306 @@
+package persistencetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/ulid"
+)
+
+// RunParkingRepository verifica el contrato de parking.Repository.
+func RunParkingRepository(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	u := newUser(t, repos)
+
+	t.Run("Registro inexistente", func(t *testing.T) {
+		if _, err := repos.Parking.FindByID(ctx, ulid.GenerateNewULID()); !errors.Is(err, domain.ErrParkingRecordNotFound) {
+			t.Errorf("FindByID() = %v, se esperaba %v", err, domain.ErrParkingRecordNotFound)
+		}
+
+		if _, err := repos.Parking.FindOpenByLicensePlate(ctx, newPlate()); !errors.Is(err, domain.ErrParkingRecordNotFound) {
+			t.Errorf("FindOpenByLicensePlate() = %v, se esperaba %v", err, domain.ErrParkingRecordNotFound)
+		}
+
+		record := newEntry(u.ID, newPlate(), time.Now())
+		closeRecord(record, time.Now(), 1, 15)
+
+		if err := repos.Parking.UpdateExit(ctx, record); !errors.Is(err, domain.ErrParkingRecordNotFound) {
+			t.Errorf("UpdateExit() = %v, se esperaba %v", err, domain.ErrParkingRecordNotFound)
+		}
+	})
+
+	t.Run("La placa no distingue mayúsculas", func(t *testing.T) {
+		plate := newPlate()
+		record := newEntry(u.ID, plate, time.Now())
+
+		if err := repos.Parking.CreateEntry(ctx, record); err != nil {
+			t.Fatalf("CreateEntry() = %v", err)
+		}
+
+		found, err := repos.Parking.FindOpenByLicensePlate(ctx, strings.ToUpper(plate))
+		if err != nil || found.ID != record.ID {
+			t.Fatalf("FindOpenByLicensePlate() = %+v, %v; se esperaba %s", found, err, record.ID)
+		}
+
+		duplicate := newEntry(u.ID, strings.ToUpper(plate), time.Now())
+		if err := repos.Parking.CreateEntry(ctx, duplicate); !errors.Is(err, domain.ErrActiveParkingAlreadyExists) {
+			t.Errorf("CreateEntry() con la placa activa = %v, se esperaba %v", err, domain.ErrActiveParkingAlreadyExists)
+		}
+	})
+
+	t.Run("Cerrar un registro libera la placa", func(t *testing.T) {
+		plate := newPlate()
+		record := newEntry(u.ID, plate, time.Now().Add(-2*time.Hour))
+
+		if err := repos.Parking.CreateEntry(ctx, record); err != nil {
+			t.Fatalf("CreateEntry() = %v", err)
+		}
+
+		closeRecord(record, time.Now(), 2, 30)
+		if err := repos.Parking.UpdateExit(ctx, record); err != nil {
+			t.Fatalf("UpdateExit() = %v", err)
+		}
+
+		stored, err := repos.Parking.FindByID(ctx, record.ID)
+		if err != nil {
+			t.Fatalf("FindByID() = %v", err)
+		}
+
+		if stored.ExitTime == nil || !stored.ExitTime.Equal(*record.ExitTime) || *stored.TotalCharge != 30 || *stored.CalculatedHours != 2 {
+			t.Errorf("registro cerrado = %+v, se esperaba salida %v con 2 horas y 30 de cobro", stored, record.ExitTime)
+		}
+
+		if _, err := repos.Parking.FindOpenByLicensePlate(ctx, plate); !errors.Is(err, domain.ErrParkingRecordNotFound) {
+			t.Errorf("FindOpenByLicensePlate() tras la salida = %v, se esperaba %v", err, domain.ErrParkingRecordNotFound)
+		}
+
+		if err := repos.Parking.CreateEntry(ctx, newEntry(u.ID, plate, time.Now())); err != nil {
+			t.Errorf("CreateEntry() tras la salida = %v", err)
+		}
+	})
+
+	t.Run("Un registro cerrado no se vuelve a cerrar", func(t *testing.T) {
+		record := newEntry(u.ID, newPlate(), time.Now().Add(-3*time.Hour))
+
+		if err := repos.Parking.CreateEntry(ctx, record); err != nil {
+			t.Fatalf("CreateEntry() = %v", err)
+		}
+
+		closeRecord(record, time.Now().Add(-time.Hour), 2, 30)
+		if err := repos.Parking.UpdateExit(ctx, record); err != nil {
+			t.Fatalf("UpdateExit() = %v", err)
+		}
+
+		first := *record.ExitTime
+
+		closeRecord(record, time.Now(), 3, 45)
+		if err := repos.Parking.UpdateExit(ctx, record); !errors.Is(err, domain.ErrParkingRecordNotFound) {
+			t.Fatalf("UpdateExit() repetido = %v, se esperaba %v", err, domain.ErrParkingRecordNotFound)
+		}
+
+		stored, err := repos.Parking.FindByID(ctx, record.ID)
+		if err != nil {
+			t.Fatalf("FindByID() = %v", err)
+		}
+
+		if !stored.ExitTime.Equal(first) || *stored.TotalCharge != 30 || *stored.CalculatedHours != 2 {
+			t.Errorf("registro = %+v, se esperaba la primera salida %v con 2 horas y 30 de cobro", stored, first)
+		}
+	})
+
+	t.Run("Anular un registro abierto libera la placa", func(t *testing.T) {
+		plate := newPlate()
+		record := newEntry(u.ID, plate, time.Now().Add(-time.Hour))
+
+		if err := repos.Parking.CreateEntry(ctx, record); err != nil {
+			t.Fatalf("CreateEntry() = %v", err)
+		}
+
+		now := time.Now().UTC().Truncate(time.Second)
+		record.ExitTime = &now
+		record.VoidedAt = &now
+		record.VoidedBy = u.ID
+		record.VoidReason = "entrada duplicada"
+
+		if err := repos.Parking.Void(ctx, record); err != nil {
+			t.Fatalf("Void() = %v", err)
+		}
+
+		if err := repos.Parking.Void(ctx, record); !errors.Is(err, domain.ErrParkingRecordNotFound) {
+			t.Errorf("Void() repetido = %v, se esperaba %v", err, domain.ErrParkingRecordNotFound)
+		}
+
+		stored, err := repos.Parking.FindByID(ctx, record.ID)
+		if err != nil {
+			t.Fatalf("FindByID() = %v", err)
+		}
+
+		if !stored.IsVoided() || !stored.VoidedAt.Equal(now) || stored.VoidedBy != u.ID || stored.VoidReason != "entrada duplicada" {
+			t.Errorf("registro anulado = %+v", stored)
+		}
+
+		if stored.ExitTime == nil || stored.TotalCharge != nil {
+			t.Errorf("registro anulado: salida = %v, cobro = %v, se esperaba salida sin cobro", stored.ExitTime, stored.TotalCharge)
+		}
+
+		if _, err := repos.Parking.FindOpenByLicensePlate(ctx, plate); !errors.Is(err, domain.ErrParkingRecordNotFound) {
+			t.Errorf("FindOpenByLicensePlate() tras anular = %v, se esperaba %v", err, domain.ErrParkingRecordNotFound)
+		}
+	})
+
+	t.Run("Listados ordenados", func(t *testing.T) {
+		now := time.Now()
+
+		before, err := repos.Parking.CountCurrent(ctx)
+		if err != nil {
+			t.Fatalf("CountCurrent() = %v", err)
+		}
+
+		older := newEntry(u.ID, newPlate(), now.Add(-3*time.Hour))
+		newer := newEntry(u.ID, newPlate(), now.Add(-2*time.Hour))
+		earlyExit := newEntry(u.ID, newPlate(), now.Add(-5*time.Hour))
+		lateExit := newEntry(u.ID, newPlate(), now.Add(-5*time.Hour))
+
+		for _, record := range []*domain.ParkingRecord{older, newer, earlyExit, lateExit} {
+			if err := repos.Parking.CreateEntry(ctx, record); err != nil {
+				t.Fatalf("CreateEntry() = %v", err)
+			}
+		}
+
+		closeRecord(earlyExit, now.Add(-4*time.Hour), 1, 15)
+		closeRecord(lateExit, now.Add(-time.Hour), 4, 60)
+
+		for _, record := range []*domain.ParkingRecord{lateExit, earlyExit} {
+			if err := repos.Parking.UpdateExit(ctx, record); err != nil {
+				t.Fatalf("UpdateExit() = %v", err)
+			}
+		}
+
+		if after, err := repos.Parking.CountCurrent(ctx); err != nil || after-before != 2 {
+			t.Errorf("CountCurrent() = %d, %v; se esperaban %d", after, err, before+2)
+		}
+
+		current, err := repos.Parking.ListCurrent(ctx)
+		if err != nil {
+			t.Fatalf("ListCurrent() = %v", err)
+		}
+
+		ids := []string{}
+		for _, record := range current {
+			ids = append(ids, record.ID)
+
+			if record.ID == older.ID && record.Username != u.Username {
+				t.Errorf("ListCurrent() usuario = %q, se esperaba %q", record.Username, u.Username)
+			}
+		}
+
+		// Los vehículos actuales se ordenan de la entrada más reciente a la más antigua.
+		if got := filterIDs(ids, older.ID, newer.ID, earlyExit.ID); !slices.Equal(got, []string{newer.ID, older.ID}) {
+			t.Errorf("ListCurrent() = %v, se esperaba %v", got, []string{newer.ID, older.ID})
+		}
+
+		history, err := repos.Parking.ListHistory(ctx)
+		if err != nil {
+			t.Fatalf("ListHistory() = %v", err)
+		}
+
+		ids = []string{}
+		for _, record := range history {
+			ids = append(ids, record.ID)
+		}
+
+		// El historial se ordena de la salida más reciente a la más antigua y excluye los abiertos.
+		if got := filterIDs(ids, older.ID, earlyExit.ID, lateExit.ID); !slices.Equal(got, []string{lateExit.ID, earlyExit.ID}) {
+			t.Errorf("ListHistory() = %v, se esperaba %v", got, []string{lateExit.ID, earlyExit.ID})
+		}
+	})
+
+	t.Run("Plazo vencido", func(t *testing.T) {
+		ctx := expiredContext()
+
+		_, err := repos.Parking.FindByID(ctx, ulid.GenerateNewULID())
+		assertTimeout(t, "FindByID()", err)
+
+		_, err = repos.Parking.FindOpenByLicensePlate(ctx, newPlate())
+		assertTimeout(t, "FindOpenByLicensePlate()", err)
+
+		assertTimeout(t, "CreateEntry()", repos.Parking.CreateEntry(ctx, newEntry(u.ID, newPlate(), time.Now())))
+
+		record := newEntry(u.ID, newPlate(), time.Now())
+		closeRecord(record, time.Now(), 1, 15)
+		assertTimeout(t, "UpdateExit()", repos.Parking.UpdateExit(ctx, record))
+
+		_, err = repos.Parking.ListCurrent(ctx)
+		assertTimeout(t, "ListCurrent()", err)
+
+		_, err = repos.Parking.ListHistory(ctx)
+		assertTimeout(t, "ListHistory()", err)
+
+		_, err = repos.Parking.CountCurrent(ctx)
+		assertTimeout(t, "CountCurrent()", err)
+	})
+}
+
+// RunUnitOfWork verifica que las operaciones de varios repositorios se confirmen o reviertan en
+// conjunto.
+func RunUnitOfWork(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	u := newUser(t, repos)
+	errAbort := errors.New("abortar")
+
+	tests := []struct {
+		name string
+		fail error
+	}{
+		{"Confirma todas las operaciones", nil},
+		{"Revierte todas las operaciones ante un error", errAbort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := newEntry(u.ID, newPlate(), time.Now())
+			message, _ := domain.NewOutboxMessage(domain.Event{
+				ID:         ulid.GenerateNewULID(),
+				Type:       domain.EventVehicleEntered,
+				OccurredAt: record.EntryTime,
+				Data:       record,
+			})
+
+			err := repos.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+				if err := repos.Parking.CreateEntry(ctx, record); err != nil {
+					return err
+				}
+
+				// Una unidad de trabajo anidada se une a la transacción en curso.
+				return repos.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+					if err := repos.Outbox.Insert(ctx, message); err != nil {
+						return err
+					}
+
+					return tt.fail
+				})
+			})
+
+			if !errors.Is(err, tt.fail) {
+				t.Fatalf("Do() = %v, se esperaba %v", err, tt.fail)
+			}
+
+			_, err = repos.Parking.FindByID(ctx, record.ID)
+			if committed := err == nil; committed != (tt.fail == nil) {
+				t.Errorf("registro guardado = %t (%v), se esperaba %t", committed, err, tt.fail == nil)
+			}
+
+			// Marcar el mensaje solo es posible si se guardó; además evita que otras pruebas lo
+			// procesen.
+			marked, err := repos.Outbox.MarkProcessed(ctx, message.ID, time.Now().UTC().Truncate(time.Second))
+			if err != nil || marked != (tt.fail == nil) {
+				t.Errorf("mensaje guardado = %t (%v), se esperaba %t", marked, err, tt.fail == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Package persistencetest contiene la suite de contrato que deben cumplir todas las
+// implementaciones de los repositorios. Cada backend la ejecuta desde sus propias pruebas sobre
+// una base de datos con las migraciones aplicadas.
+package persistencetest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pressly/goose/v3"
+
+	"github.com/JGCaceres97/parking/internal/application/audit"
+	"github.com/JGCaceres97/parking/internal/application/auth"
+	"github.com/JGCaceres97/parking/internal/application/mfa"
+	"github.com/JGCaceres97/parking/internal/application/outbox"
+	"github.com/JGCaceres97/parking/internal/application/parking"
+	"github.com/JGCaceres97/parking/internal/application/report"
+	"github.com/JGCaceres97/parking/internal/application/retention"
+	"github.com/JGCaceres97/parking/internal/application/role"
+	"github.com/JGCaceres97/parking/internal/application/sso"
+	"github.com/JGCaceres97/parking/internal/application/transaction"
+	"github.com/JGCaceres97/parking/internal/application/user"
+	"github.com/JGCaceres97/parking/internal/application/vehicle_type"
+	"github.com/JGCaceres97/parking/internal/application/webhook"
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/migrations"
+	"github.com/JGCaceres97/parking/pkg/ulid"
+)
+
+// VehicleTypeNormalID es el tipo de vehículo que crean las migraciones.
+const VehicleTypeNormalID = "01K8M9658PVKMJEBR2GD218M87"
+
+// Repositories agrupa los repositorios de un backend bajo prueba.
+type Repositories struct {
+	UnitOfWork   transaction.UnitOfWork
+	Audit        audit.Repository
+	Identity     sso.IdentityRepository
+	LoginAttempt auth.LoginAttemptRepository
+	MFA          mfa.Repository
+	Outbox       outbox.Repository
+	Parking      parking.Repository
+	Report       report.Repository
+	Retention    retention.Repository
+	Role         role.Repository
+	User         user.Repository
+	VehicleType  vehicle_type.Repository
+	Webhook      webhook.Repository
+}
+
+// Migrate aplica las migraciones incluidas en el binario para driver.
+func Migrate(t testing.TB, db *sql.DB, dialect goose.Dialect, driver string) {
+	t.Helper()
+
+	fsys, err := migrations.FS(driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := goose.NewProvider(dialect, db, fsys)
+	if err != nil {
+		t.Fatalf("error al preparar migraciones: %v", err)
+	}
+
+	if _, err := provider.Up(context.Background()); err != nil {
+		t.Fatalf("error al aplicar migraciones: %v", err)
+	}
+}
+
+// expiredContext retorna un contexto cuyo plazo ya venció, para verificar que los repositorios
+// reporten el timeout en lugar de un error de dominio.
+func expiredContext() context.Context {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	cancel()
+
+	return ctx
+}
+
+func assertTimeout(t *testing.T, operation string, err error) {
+	t.Helper()
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("%s con el plazo vencido = %v, se esperaba %v", operation, err, context.DeadlineExceeded)
+	}
+}
+
+// filterIDs conserva, en el orden recibido, los identificadores que pertenecen a want.
+func filterIDs(ids []string, want ...string) []string {
+	filtered := []string{}
+
+	for _, id := range ids {
+		if slices.Contains(want, id) {
+			filtered = append(filtered, id)
+		}
+	}
+
+	return filtered
+}
+
+// Las pruebas pueden ejecutarse sobre una base compartida (ej. MySQL), por lo que cada una crea
+// sus propios datos con identificadores únicos y solo compara lo que creó.
+
+func newPlate() string {
+	id := ulid.GenerateNewULID()
+	return "T" + strings.ToLower(id[len(id)-7:])
+}
+
+func newUser(t *testing.T, repos Repositories) *domain.User {
+	t.Helper()
+
+	u := &domain.User{
+		ID:        ulid.GenerateNewULID(),
+		Username:  "contract-" + strings.ToLower(ulid.GenerateNewULID()),
+		Password:  "hash",
+		Role:      "common",
+		IsActive:  true,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+
+	if err := repos.User.Create(context.Background(), u); err != nil {
+		t.Fatalf("error al crear usuario de prueba: %v", err)
+	}
+
+	return u
+}
+
+func newEntry(userID, plate string, entryTime time.Time) *domain.ParkingRecord {
+	return &domain.ParkingRecord{
+		ID:            ulid.GenerateNewULID(),
+		UserID:        userID,
+		VehicleTypeID: VehicleTypeNormalID,
+		LicensePlate:  plate,
+		EntryTime:     entryTime.UTC().Truncate(time.Second),
+	}
+}
+
+func closeRecord(record *domain.ParkingRecord, exitTime time.Time, hours int, charge float64) {
+	exit := exitTime.UTC().Truncate(time.Second)
+	record.ExitTime = &exit
+	record.CalculatedHours = &hours
+	record.TotalCharge = &charge
+}
@@ -0,0 +1,103 @@
+package persistencetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/ulid"
+)
+
+// RunRetentionRepository verifica el contrato de retention.Repository.
+func RunRetentionRepository(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	u := newUser(t, repos)
+
+	// Un día antiguo y propio de la prueba, para no mezclar totales con otras ejecuciones.
+	exit := time.Date(2001, 1, 1, 12, 0, 0, 0, time.UTC).AddDate(0, 0, int(time.Now().UnixNano()%3650))
+	day := exit.Format(time.DateOnly)
+
+	closed := func() domain.ParkingRecord {
+		t.Helper()
+
+		record := newEntry(u.ID, newPlate(), exit.Add(-2*time.Hour))
+		if err := repos.Parking.CreateEntry(ctx, record); err != nil {
+			t.Fatalf("CreateEntry() = %v", err)
+		}
+
+		closeRecord(record, exit, 2, 30)
+		if err := repos.Parking.UpdateExit(ctx, record); err != nil {
+			t.Fatalf("UpdateExit() = %v", err)
+		}
+
+		return *record
+	}
+
+	archivedRecords := func() int {
+		t.Helper()
+
+		summaries, err := repos.Report.ArchivedDailySummaries(ctx, day, day)
+		if err != nil {
+			t.Fatalf("ArchivedDailySummaries() = %v", err)
+		}
+
+		total := 0
+		for _, s := range summaries {
+			total += s.Records
+		}
+
+		return total
+	}
+
+	t.Run("Un conflicto revierte el lote completo", func(t *testing.T) {
+		record := closed()
+		missing := record
+		missing.ID = ulid.GenerateNewULID()
+
+		batch := []domain.ParkingRecord{record, missing}
+		before := archivedRecords()
+
+		err := repos.Retention.Archive(ctx, batch, domain.SummarizeDaily(batch), true, time.Now().UTC())
+		if !errors.Is(err, domain.ErrArchiveConflict) {
+			t.Fatalf("Archive() con un registro inexistente = %v, se esperaba %v", err, domain.ErrArchiveConflict)
+		}
+
+		if _, err := repos.Parking.FindByID(ctx, record.ID); err != nil {
+			t.Errorf("FindByID() tras el conflicto = %v, el registro debía conservarse", err)
+		}
+
+		if after := archivedRecords(); after != before {
+			t.Errorf("totales archivados tras el conflicto = %d, se esperaba %d", after, before)
+		}
+	})
+
+	t.Run("Archivar mueve los registros y suma los totales una sola vez", func(t *testing.T) {
+		batch := []domain.ParkingRecord{closed(), closed()}
+		before := archivedRecords()
+
+		if err := repos.Retention.Archive(ctx, batch, domain.SummarizeDaily(batch), true, time.Now().UTC()); err != nil {
+			t.Fatalf("Archive() = %v", err)
+		}
+
+		for _, record := range batch {
+			if _, err := repos.Parking.FindByID(ctx, record.ID); !errors.Is(err, domain.ErrParkingRecordNotFound) {
+				t.Errorf("FindByID(%s) tras archivar = %v, se esperaba %v", record.ID, err, domain.ErrParkingRecordNotFound)
+			}
+		}
+
+		if after := archivedRecords(); after != before+len(batch) {
+			t.Errorf("totales archivados = %d, se esperaba %d", after, before+len(batch))
+		}
+
+		err := repos.Retention.Archive(ctx, batch, domain.SummarizeDaily(batch), true, time.Now().UTC())
+		if !errors.Is(err, domain.ErrArchiveConflict) {
+			t.Errorf("Archive() repetido = %v, se esperaba %v", err, domain.ErrArchiveConflict)
+		}
+
+		if after := archivedRecords(); after != before+len(batch) {
+			t.Errorf("totales archivados tras repetir = %d, se esperaba %d", after, before+len(batch))
+		}
+	})
+}
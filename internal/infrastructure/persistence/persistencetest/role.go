@@ -0,0 +1,178 @@
+package persistencetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/ulid"
+)
+
+func newRoleName() domain.Role {
+	return "contract-" + strings.ToLower(ulid.GenerateNewULID())
+}
+
+// RunRoleRepository verifica el contrato de role.Repository a partir de los roles que crean las
+// migraciones.
+func RunRoleRepository(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	t.Run("Rol inexistente", func(t *testing.T) {
+		if _, err := repos.Role.FindByName(ctx, newRoleName()); !errors.Is(err, domain.ErrRoleNotFound) {
+			t.Errorf("FindByName() = %v, se esperaba %v", err, domain.ErrRoleNotFound)
+		}
+	})
+
+	t.Run("Roles del sistema", func(t *testing.T) {
+		tests := []struct {
+			name domain.Role
+			want []domain.Permission
+		}{
+			{name: domain.RoleAdmin, want: domain.Permissions},
+			{name: domain.RoleSupervisor, want: []domain.Permission{domain.PermParkingRead, domain.PermParkingWrite, domain.PermParkingVoid, domain.PermReportsRead, domain.PermVehicleTypesRead}},
+		}
+
+		for _, tt := range tests {
+			role, err := repos.Role.FindByName(ctx, tt.name)
+			if err != nil {
+				t.Fatalf("FindByName(%q) = %v", tt.name, err)
+			}
+
+			if !role.IsSystem {
+				t.Errorf("FindByName(%q) no es un rol del sistema", tt.name)
+			}
+
+			got := slices.Sorted(slices.Values(role.Permissions))
+			want := slices.Sorted(slices.Values(tt.want))
+
+			if !slices.Equal(got, want) {
+				t.Errorf("permisos de %q = %v, se esperaba %v", tt.name, got, want)
+			}
+		}
+	})
+
+	t.Run("Crear, actualizar y eliminar", func(t *testing.T) {
+		role := &domain.RoleDefinition{
+			Name:        newRoleName(),
+			Description: "Rol de prueba",
+			Permissions: []domain.Permission{domain.PermParkingRead, domain.PermReportsRead},
+			CreatedAt:   time.Now().UTC().Truncate(time.Second),
+		}
+
+		if err := repos.Role.Create(ctx, role); err != nil {
+			t.Fatalf("Create() = %v", err)
+		}
+
+		if err := repos.Role.Create(ctx, role); !errors.Is(err, domain.ErrRoleAlreadyExists) {
+			t.Errorf("Create() con nombre repetido = %v, se esperaba %v", err, domain.ErrRoleAlreadyExists)
+		}
+
+		role.Description = "Rol actualizado"
+		role.Permissions = []domain.Permission{domain.PermParkingWrite}
+
+		if err := repos.Role.Update(ctx, role); err != nil {
+			t.Fatalf("Update() = %v", err)
+		}
+
+		stored, err := repos.Role.FindByName(ctx, role.Name)
+		if err != nil {
+			t.Fatalf("FindByName() = %v", err)
+		}
+
+		if stored.Description != role.Description || stored.IsSystem || !slices.Equal(stored.Permissions, role.Permissions) {
+			t.Errorf("FindByName() = %+v, se esperaba %+v", stored, role)
+		}
+
+		missing := *role
+		missing.Name = newRoleName()
+
+		if err := repos.Role.Update(ctx, &missing); !errors.Is(err, domain.ErrRoleNotFound) {
+			t.Errorf("Update() de un rol inexistente = %v, se esperaba %v", err, domain.ErrRoleNotFound)
+		}
+
+		if err := repos.Role.Delete(ctx, role.Name); err != nil {
+			t.Fatalf("Delete() = %v", err)
+		}
+
+		if _, err := repos.Role.FindByName(ctx, role.Name); !errors.Is(err, domain.ErrRoleNotFound) {
+			t.Errorf("FindByName() tras eliminar = %v, se esperaba %v", err, domain.ErrRoleNotFound)
+		}
+
+		if err := repos.Role.Delete(ctx, role.Name); !errors.Is(err, domain.ErrRoleNotFound) {
+			t.Errorf("Delete() de un rol inexistente = %v, se esperaba %v", err, domain.ErrRoleNotFound)
+		}
+	})
+
+	t.Run("Rol en uso", func(t *testing.T) {
+		role := &domain.RoleDefinition{
+			Name:      newRoleName(),
+			CreatedAt: time.Now().UTC().Truncate(time.Second),
+		}
+
+		if err := repos.Role.Create(ctx, role); err != nil {
+			t.Fatalf("Create() = %v", err)
+		}
+
+		inUse, err := repos.Role.IsInUse(ctx, role.Name)
+		if err != nil {
+			t.Fatalf("IsInUse() = %v", err)
+		}
+
+		if inUse {
+			t.Error("IsInUse() = true para un rol sin usuarios")
+		}
+
+		u := newUser(t, repos)
+		u.Role = role.Name
+
+		if err := repos.User.Update(ctx, u); err != nil {
+			t.Fatalf("error al asignar el rol: %v", err)
+		}
+
+		inUse, err = repos.Role.IsInUse(ctx, role.Name)
+		if err != nil {
+			t.Fatalf("IsInUse() = %v", err)
+		}
+
+		if !inUse {
+			t.Error("IsInUse() = false para un rol asignado")
+		}
+	})
+
+	t.Run("Listado ordenado por nombre", func(t *testing.T) {
+		roles, err := repos.Role.ListAll(ctx)
+		if err != nil {
+			t.Fatalf("ListAll() = %v", err)
+		}
+
+		if len(roles) < 5 {
+			t.Fatalf("ListAll() = %d roles, se esperaban al menos los 5 de las migraciones", len(roles))
+		}
+
+		sorted := sort.SliceIsSorted(roles, func(i, j int) bool {
+			return roles[i].Name < roles[j].Name
+		})
+
+		if !sorted {
+			t.Errorf("ListAll() = %+v, se esperaba ordenado por nombre", roles)
+		}
+	})
+
+	t.Run("Plazo vencido", func(t *testing.T) {
+		ctx := expiredContext()
+
+		_, err := repos.Role.FindByName(ctx, domain.RoleAdmin)
+		assertTimeout(t, "FindByName()", err)
+
+		_, err = repos.Role.ListAll(ctx)
+		assertTimeout(t, "ListAll()", err)
+
+		_, err = repos.Role.IsInUse(ctx, domain.RoleAdmin)
+		assertTimeout(t, "IsInUse()", err)
+	})
+}
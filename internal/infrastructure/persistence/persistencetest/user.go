@@ -0,0 +1,310 @@
+package persistencetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/ulid"
+)
+
+// RunUserRepository verifica el contrato de user.Repository.
+func RunUserRepository(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	t.Run("Usuario inexistente", func(t *testing.T) {
+		id := ulid.GenerateNewULID()
+		missing := &domain.User{ID: id, Username: "contract-" + strings.ToLower(id), Role: "common"}
+		now := time.Now().UTC().Truncate(time.Second)
+
+		lookups := []struct {
+			name string
+			find func() (*domain.User, error)
+		}{
+			{"FindByID()", func() (*domain.User, error) { return repos.User.FindByID(ctx, id) }},
+			{"FindByIDWithDeleted()", func() (*domain.User, error) { return repos.User.FindByIDWithDeleted(ctx, id) }},
+			{"FindByUsername()", func() (*domain.User, error) { return repos.User.FindByUsername(ctx, missing.Username) }},
+		}
+
+		for _, lookup := range lookups {
+			if _, err := lookup.find(); !errors.Is(err, domain.ErrUserNotFound) {
+				t.Errorf("%s = %v, se esperaba %v", lookup.name, err, domain.ErrUserNotFound)
+			}
+		}
+
+		updates := []struct {
+			name string
+			err  error
+		}{
+			{"Update()", repos.User.Update(ctx, missing)},
+			{"UpdateLoginState()", repos.User.UpdateLoginState(ctx, missing)},
+			{"UpdatePassword()", repos.User.UpdatePassword(ctx, id, "hash", false)},
+			{"SoftDelete()", repos.User.SoftDelete(ctx, id, now)},
+			{"Anonymize()", repos.User.Anonymize(ctx, id, "anonimo-"+id, now)},
+			{"Delete()", repos.User.Delete(ctx, id)},
+		}
+
+		for _, update := range updates {
+			if !errors.Is(update.err, domain.ErrUserNotFound) {
+				t.Errorf("%s = %v, se esperaba %v", update.name, update.err, domain.ErrUserNotFound)
+			}
+		}
+
+		if repos.User.ExistsUsername(ctx, missing.Username) {
+			t.Errorf("ExistsUsername(%q) = true, se esperaba false", missing.Username)
+		}
+	})
+
+	t.Run("Desbloquear un usuario no bloqueado", func(t *testing.T) {
+		u := newUser(t, repos)
+
+		// Las actualizaciones que no modifican ningún valor deben tratarse como exitosas y no como
+		// un usuario inexistente.
+		updates := []struct {
+			name string
+			err  error
+		}{
+			{"UpdateLoginState()", repos.User.UpdateLoginState(ctx, u)},
+			{"Update()", repos.User.Update(ctx, u)},
+			{"UpdatePassword()", repos.User.UpdatePassword(ctx, u.ID, u.Password, u.MustChangePassword)},
+		}
+
+		for _, update := range updates {
+			if update.err != nil {
+				t.Errorf("%s sin cambios = %v, se esperaba nil", update.name, update.err)
+			}
+		}
+	})
+
+	t.Run("El nombre de usuario no distingue mayúsculas", func(t *testing.T) {
+		u := newUser(t, repos)
+		upper := strings.ToUpper(u.Username)
+
+		found, err := repos.User.FindByUsername(ctx, upper)
+		if err != nil || found.ID != u.ID {
+			t.Fatalf("FindByUsername(%q) = %+v, %v; se esperaba %s", upper, found, err, u.ID)
+		}
+
+		if !repos.User.ExistsUsername(ctx, upper) {
+			t.Errorf("ExistsUsername(%q) = false, se esperaba true", upper)
+		}
+
+		duplicate := &domain.User{
+			ID:        ulid.GenerateNewULID(),
+			Username:  upper,
+			Password:  "hash",
+			Role:      "common",
+			IsActive:  true,
+			CreatedAt: time.Now().UTC().Truncate(time.Second),
+		}
+
+		if err := repos.User.Create(ctx, duplicate); !errors.Is(err, domain.ErrUsernameAlreadyExists) {
+			t.Errorf("Create() con un nombre existente = %v, se esperaba %v", err, domain.ErrUsernameAlreadyExists)
+		}
+
+		other := newUser(t, repos)
+		other.Username = upper
+
+		if err := repos.User.Update(ctx, other); !errors.Is(err, domain.ErrUsernameAlreadyExists) {
+			t.Errorf("Update() con un nombre existente = %v, se esperaba %v", err, domain.ErrUsernameAlreadyExists)
+		}
+	})
+
+	t.Run("La eliminación lógica libera el nombre de usuario", func(t *testing.T) {
+		u := newUser(t, repos)
+		viewer := newUser(t, repos)
+		deletedAt := time.Now().UTC().Truncate(time.Second)
+
+		if err := repos.User.SoftDelete(ctx, u.ID, deletedAt); err != nil {
+			t.Fatalf("SoftDelete() = %v", err)
+		}
+
+		if _, err := repos.User.FindByID(ctx, u.ID); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Errorf("FindByID() tras eliminar = %v, se esperaba %v", err, domain.ErrUserNotFound)
+		}
+
+		deleted, err := repos.User.FindByIDWithDeleted(ctx, u.ID)
+		if err != nil || deleted.DeletedAt == nil || !deleted.DeletedAt.Equal(deletedAt) || deleted.IsActive {
+			t.Fatalf("FindByIDWithDeleted() = %+v, %v; se esperaba inactivo y eliminado en %v", deleted, err, deletedAt)
+		}
+
+		if err := repos.User.SoftDelete(ctx, u.ID, deletedAt); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Errorf("SoftDelete() repetido = %v, se esperaba %v", err, domain.ErrUserNotFound)
+		}
+
+		listed := func(includeDeleted bool) bool {
+			users, err := repos.User.ListAll(ctx, viewer.ID, includeDeleted)
+			if err != nil {
+				t.Fatalf("ListAll() = %v", err)
+			}
+
+			return slices.ContainsFunc(users, func(listed domain.User) bool { return listed.ID == u.ID })
+		}
+
+		if listed(false) {
+			t.Error("ListAll() sin eliminados incluye al usuario eliminado")
+		}
+
+		if !listed(true) {
+			t.Error("ListAll() con eliminados no incluye al usuario eliminado")
+		}
+
+		reused := &domain.User{
+			ID:        ulid.GenerateNewULID(),
+			Username:  u.Username,
+			Password:  "hash",
+			Role:      "common",
+			IsActive:  true,
+			CreatedAt: time.Now().UTC().Truncate(time.Second),
+		}
+
+		if err := repos.User.Create(ctx, reused); err != nil {
+			t.Errorf("Create() con el nombre de un usuario eliminado = %v", err)
+		}
+	})
+
+	t.Run("Listado sin el usuario actual", func(t *testing.T) {
+		u := newUser(t, repos)
+
+		users, err := repos.User.ListAll(ctx, u.ID, true)
+		if err != nil {
+			t.Fatalf("ListAll() = %v", err)
+		}
+
+		for _, listed := range users {
+			if listed.ID == u.ID {
+				t.Error("ListAll() incluye al usuario que consulta")
+			}
+
+			if listed.Password != "" {
+				t.Errorf("ListAll() retorna el hash de contraseña de %s", listed.ID)
+			}
+		}
+	})
+
+	t.Run("Historial de contraseñas del más reciente al más antiguo", func(t *testing.T) {
+		u := newUser(t, repos)
+
+		for _, hash := range []string{"hash-2", "hash-3"} {
+			// Los cambios deben caer en milisegundos distintos para que su orden esté definido.
+			time.Sleep(2 * time.Millisecond)
+
+			if err := repos.User.UpdatePassword(ctx, u.ID, hash, false); err != nil {
+				t.Fatalf("UpdatePassword() = %v", err)
+			}
+		}
+
+		history, err := repos.User.ListPasswordHistory(ctx, u.ID, 2)
+		if err != nil {
+			t.Fatalf("ListPasswordHistory() = %v", err)
+		}
+
+		if want := []string{"hash-3", "hash-2"}; !slices.Equal(history, want) {
+			t.Errorf("ListPasswordHistory() = %v, se esperaba %v", history, want)
+		}
+	})
+
+	t.Run("Revocar tokens incrementa la versión", func(t *testing.T) {
+		u := newUser(t, repos)
+
+		if err := repos.User.RevokeTokens(ctx, ulid.GenerateNewULID()); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Errorf("RevokeTokens() de usuario inexistente = %v, se esperaba %v", err, domain.ErrUserNotFound)
+		}
+
+		for range 2 {
+			if err := repos.User.RevokeTokens(ctx, u.ID); err != nil {
+				t.Fatalf("RevokeTokens() = %v", err)
+			}
+		}
+
+		stored, err := repos.User.FindByID(ctx, u.ID)
+		if err != nil {
+			t.Fatalf("FindByID() = %v", err)
+		}
+
+		if stored.TokenVersion != u.TokenVersion+2 {
+			t.Errorf("TokenVersion = %d, se esperaba %d", stored.TokenVersion, u.TokenVersion+2)
+		}
+	})
+
+	t.Run("No se elimina un usuario con registros", func(t *testing.T) {
+		u := newUser(t, repos)
+
+		if err := repos.Parking.CreateEntry(ctx, newEntry(u.ID, newPlate(), time.Now())); err != nil {
+			t.Fatalf("CreateEntry() = %v", err)
+		}
+
+		if err := repos.User.Delete(ctx, u.ID); !errors.Is(err, domain.ErrUserHasRecords) {
+			t.Errorf("Delete() = %v, se esperaba %v", err, domain.ErrUserHasRecords)
+		}
+
+		other := newUser(t, repos)
+		if err := repos.User.Delete(ctx, other.ID); err != nil {
+			t.Fatalf("Delete() = %v", err)
+		}
+
+		if _, err := repos.User.FindByIDWithDeleted(ctx, other.ID); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Errorf("FindByIDWithDeleted() tras eliminar definitivamente = %v, se esperaba %v", err, domain.ErrUserNotFound)
+		}
+	})
+
+	t.Run("Los intentos fallidos concurrentes se cuentan todos", func(t *testing.T) {
+		u := newUser(t, repos)
+		now := time.Now().UTC().Truncate(time.Second)
+
+		if _, _, err := repos.User.RegisterLoginFailure(ctx, ulid.GenerateNewULID(), now, 0); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Errorf("RegisterLoginFailure() de un usuario inexistente = %v, se esperaba %v", err, domain.ErrUserNotFound)
+		}
+
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Go(func() {
+				if _, _, err := repos.User.RegisterLoginFailure(ctx, u.ID, now, 0); err != nil {
+					t.Errorf("RegisterLoginFailure() = %v", err)
+				}
+			})
+		}
+		wg.Wait()
+
+		failures, locked, err := repos.User.RegisterLoginFailure(ctx, u.ID, now, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if failures != 5 || !locked {
+			t.Errorf("RegisterLoginFailure() = %d, %v; se esperaba 5, true", failures, locked)
+		}
+
+		got, err := repos.User.FindByID(ctx, u.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.FailedLoginAttempts != 5 || got.LockedAt == nil || got.LastFailedLoginAt == nil {
+			t.Errorf("estado = %d intentos, bloqueado en %v, último fallo en %v",
+				got.FailedLoginAttempts, got.LockedAt, got.LastFailedLoginAt)
+		}
+	})
+
+	t.Run("Plazo vencido", func(t *testing.T) {
+		ctx := expiredContext()
+		u := &domain.User{ID: ulid.GenerateNewULID(), Username: "contract-timeout", Role: "common"}
+
+		_, err := repos.User.FindByID(ctx, u.ID)
+		assertTimeout(t, "FindByID()", err)
+
+		_, err = repos.User.FindByUsername(ctx, u.Username)
+		assertTimeout(t, "FindByUsername()", err)
+
+		assertTimeout(t, "Update()", repos.User.Update(ctx, u))
+		assertTimeout(t, "SoftDelete()", repos.User.SoftDelete(ctx, u.ID, time.Now()))
+
+		_, err = repos.User.ListAll(ctx, u.ID, false)
+		assertTimeout(t, "ListAll()", err)
+	})
+}
@@ -0,0 +1,134 @@
+package persistencetest
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/ulid"
+)
+
+// RunVehicleTypeRepository verifica el contrato de vehicle_type.Repository a partir de los tipos
+// que crean las migraciones.
+func RunVehicleTypeRepository(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	t.Run("Tipo inexistente", func(t *testing.T) {
+		if _, err := repos.VehicleType.FindByID(ctx, ulid.GenerateNewULID()); !errors.Is(err, domain.ErrVehicleTypeNotFound) {
+			t.Errorf("FindByID() = %v, se esperaba %v", err, domain.ErrVehicleTypeNotFound)
+		}
+	})
+
+	t.Run("Buscar por ID", func(t *testing.T) {
+		vehicleType, err := repos.VehicleType.FindByID(ctx, VehicleTypeNormalID)
+		if err != nil {
+			t.Fatalf("FindByID() = %v", err)
+		}
+
+		if vehicleType.Name != "Normal" || vehicleType.HourlyRate != 15 {
+			t.Errorf("FindByID() = %+v, se esperaba Normal con tarifa 15", vehicleType)
+		}
+	})
+
+	t.Run("Buscar por nombre", func(t *testing.T) {
+		vehicleType, err := repos.VehicleType.FindByName(ctx, "NORMAL")
+		if err != nil {
+			t.Fatalf("FindByName() = %v", err)
+		}
+
+		if vehicleType.ID != VehicleTypeNormalID {
+			t.Errorf("FindByName() = %+v, se esperaba el tipo Normal sin distinguir mayúsculas", vehicleType)
+		}
+
+		if _, err := repos.VehicleType.FindByName(ctx, "Inexistente "+ulid.GenerateNewULID()); !errors.Is(err, domain.ErrVehicleTypeNotFound) {
+			t.Errorf("FindByName() = %v, se esperaba %v", err, domain.ErrVehicleTypeNotFound)
+		}
+	})
+
+	t.Run("Crear y actualizar", func(t *testing.T) {
+		vehicleType := &domain.VehicleType{
+			ID:          ulid.GenerateNewULID(),
+			Name:        "Tipo " + ulid.GenerateNewULID(),
+			HourlyRate:  7.5,
+			Description: "Tarifa de prueba",
+		}
+
+		if err := repos.VehicleType.Create(ctx, vehicleType); err != nil {
+			t.Fatalf("Create() = %v", err)
+		}
+
+		duplicate := *vehicleType
+		duplicate.ID = ulid.GenerateNewULID()
+		duplicate.Name = strings.ToUpper(vehicleType.Name)
+
+		if err := repos.VehicleType.Create(ctx, &duplicate); !errors.Is(err, domain.ErrVehicleTypeNameAlreadyExists) {
+			t.Errorf("Create() con nombre repetido = %v, se esperaba %v", err, domain.ErrVehicleTypeNameAlreadyExists)
+		}
+
+		vehicleType.HourlyRate = 9
+		vehicleType.Description = "Tarifa actualizada"
+
+		if err := repos.VehicleType.Update(ctx, vehicleType); err != nil {
+			t.Fatalf("Update() = %v", err)
+		}
+
+		stored, err := repos.VehicleType.FindByID(ctx, vehicleType.ID)
+		if err != nil {
+			t.Fatalf("FindByID() = %v", err)
+		}
+
+		if *stored != *vehicleType {
+			t.Errorf("FindByID() = %+v, se esperaba %+v", stored, vehicleType)
+		}
+
+		renamed := *vehicleType
+		renamed.Name = "Normal"
+
+		if err := repos.VehicleType.Update(ctx, &renamed); !errors.Is(err, domain.ErrVehicleTypeNameAlreadyExists) {
+			t.Errorf("Update() con nombre repetido = %v, se esperaba %v", err, domain.ErrVehicleTypeNameAlreadyExists)
+		}
+
+		missing := *vehicleType
+		missing.ID = ulid.GenerateNewULID()
+
+		if err := repos.VehicleType.Update(ctx, &missing); !errors.Is(err, domain.ErrVehicleTypeNotFound) {
+			t.Errorf("Update() de un tipo inexistente = %v, se esperaba %v", err, domain.ErrVehicleTypeNotFound)
+		}
+	})
+
+	t.Run("Listado ordenado por nombre", func(t *testing.T) {
+		vehicleTypes, err := repos.VehicleType.ListAll(ctx)
+		if err != nil {
+			t.Fatalf("ListAll() = %v", err)
+		}
+
+		if len(vehicleTypes) < 3 {
+			t.Fatalf("ListAll() = %d tipos, se esperaban al menos los 3 de las migraciones", len(vehicleTypes))
+		}
+
+		// El orden no distingue mayúsculas de minúsculas.
+		sorted := sort.SliceIsSorted(vehicleTypes, func(i, j int) bool {
+			return strings.ToLower(vehicleTypes[i].Name) < strings.ToLower(vehicleTypes[j].Name)
+		})
+
+		if !sorted {
+			t.Errorf("ListAll() = %+v, se esperaba ordenado por nombre", vehicleTypes)
+		}
+	})
+
+	t.Run("Plazo vencido", func(t *testing.T) {
+		ctx := expiredContext()
+
+		_, err := repos.VehicleType.FindByID(ctx, VehicleTypeNormalID)
+		assertTimeout(t, "FindByID()", err)
+
+		_, err = repos.VehicleType.FindByName(ctx, "Normal")
+		assertTimeout(t, "FindByName()", err)
+
+		_, err = repos.VehicleType.ListAll(ctx)
+		assertTimeout(t, "ListAll()", err)
+	})
+}
@@ -0,0 +1,238 @@
+package persistencetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/ulid"
+)
+
+// RunWebhookRepository verifica el contrato de webhook.Repository.
+func RunWebhookRepository(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	t.Run("Suscripciones", func(t *testing.T) {
+		if _, err := repos.Webhook.FindSubscription(ctx, ulid.GenerateNewULID()); !errors.Is(err, domain.ErrWebhookNotFound) {
+			t.Errorf("FindSubscription() de una suscripción inexistente = %v, se esperaba %v", err, domain.ErrWebhookNotFound)
+		}
+
+		subscription := newSubscription(t, repos)
+
+		subscription.URL = "https://example.com/otro"
+		subscription.EventTypes = []domain.EventType{domain.EventVehicleExited}
+		subscription.Secret = "otro-secreto"
+		subscription.IsActive = false
+
+		if err := repos.Webhook.UpdateSubscription(ctx, subscription); err != nil {
+			t.Fatalf("UpdateSubscription() = %v", err)
+		}
+
+		got, err := repos.Webhook.FindSubscription(ctx, subscription.ID)
+		if err != nil {
+			t.Fatalf("FindSubscription() = %v", err)
+		}
+
+		if got.URL != subscription.URL || !slices.Equal(got.EventTypes, subscription.EventTypes) ||
+			got.Secret != subscription.Secret || got.IsActive || !got.CreatedAt.Equal(subscription.CreatedAt) {
+			t.Errorf("FindSubscription() = %+v, se esperaba %+v", got, subscription)
+		}
+
+		subscriptions, err := repos.Webhook.ListSubscriptions(ctx)
+		if err != nil {
+			t.Fatalf("ListSubscriptions() = %v", err)
+		}
+
+		ids := []string{}
+		for _, s := range subscriptions {
+			ids = append(ids, s.ID)
+		}
+
+		if !slices.Contains(ids, subscription.ID) {
+			t.Errorf("ListSubscriptions() no incluye la suscripción %s", subscription.ID)
+		}
+	})
+
+	t.Run("Entregas", func(t *testing.T) {
+		subscription := newSubscription(t, repos)
+
+		if _, err := repos.Webhook.FindDelivery(ctx, ulid.GenerateNewULID()); !errors.Is(err, domain.ErrWebhookDeliveryNotFound) {
+			t.Errorf("FindDelivery() de una entrega inexistente = %v, se esperaba %v", err, domain.ErrWebhookDeliveryNotFound)
+		}
+
+		// Una fecha antigua permite listar solo las entregas vencidas de esta prueba en una base
+		// compartida.
+		now := time.Date(2001, time.January, 1, 12, 0, 0, 0, time.UTC)
+		deliveries := []domain.WebhookDelivery{
+			newDelivery(subscription.ID, now.Add(-2*time.Hour), now.Add(-time.Hour)),
+			newDelivery(subscription.ID, now.Add(-time.Hour), now.Add(-2*time.Hour)),
+			newDelivery(subscription.ID, now, now.Add(time.Hour)),
+		}
+
+		if err := repos.Webhook.InsertDeliveries(ctx, deliveries); err != nil {
+			t.Fatalf("InsertDeliveries() = %v", err)
+		}
+
+		got, err := repos.Webhook.FindDelivery(ctx, deliveries[0].ID)
+		if err != nil {
+			t.Fatalf("FindDelivery() = %v", err)
+		}
+
+		if got.SubscriptionID != subscription.ID || got.EventType != domain.EventVehicleEntered ||
+			string(got.Payload) != string(deliveries[0].Payload) || got.Status != domain.WebhookDeliveryPending ||
+			!got.NextAttemptAt.Equal(deliveries[0].NextAttemptAt) || got.DeliveredAt != nil {
+			t.Errorf("FindDelivery() = %+v, se esperaba %+v", got, deliveries[0])
+		}
+
+		due, err := repos.Webhook.ListDue(ctx, now, 10)
+		if err != nil {
+			t.Fatalf("ListDue() = %v", err)
+		}
+
+		if got, want := deliveryIDs(due), []string{deliveries[1].ID, deliveries[0].ID}; !slices.Equal(got, want) {
+			t.Errorf("ListDue() = %v, se esperaba %v", got, want)
+		}
+
+		// Solo una de dos instancias que leyeron el mismo intento puede reservar la entrega.
+		lease := now.Add(5 * time.Minute)
+		for i, want := range []bool{true, false} {
+			claimed, err := repos.Webhook.Claim(ctx, deliveries[0].ID, deliveries[0].NextAttemptAt, lease)
+			if err != nil || claimed != want {
+				t.Errorf("Claim() #%d = %v, %v; se esperaba %v", i+1, claimed, err, want)
+			}
+		}
+
+		delivered := deliveries[0]
+		deliveredAt := now.Add(time.Minute)
+		delivered.Status = domain.WebhookDeliveryDelivered
+		delivered.Attempts = 1
+		delivered.LastStatusCode = 200
+		delivered.DeliveredAt = &deliveredAt
+
+		failed := deliveries[1]
+		failed.Status = domain.WebhookDeliveryFailed
+		failed.Attempts = 5
+		failed.LastStatusCode = 500
+		failed.LastError = "error del servidor"
+
+		for _, delivery := range []domain.WebhookDelivery{delivered, failed} {
+			if err := repos.Webhook.UpdateDelivery(ctx, &delivery); err != nil {
+				t.Fatalf("UpdateDelivery() = %v", err)
+			}
+		}
+
+		if claimed, err := repos.Webhook.Claim(ctx, failed.ID, failed.NextAttemptAt, lease); err != nil || claimed {
+			t.Errorf("Claim() de una entrega fallida = %v, %v; se esperaba false", claimed, err)
+		}
+
+		got, err = repos.Webhook.FindDelivery(ctx, delivered.ID)
+		if err != nil {
+			t.Fatalf("FindDelivery() = %v", err)
+		}
+
+		if got.Status != domain.WebhookDeliveryDelivered || got.Attempts != 1 || got.LastStatusCode != 200 ||
+			got.DeliveredAt == nil || !got.DeliveredAt.Equal(deliveredAt) {
+			t.Errorf("FindDelivery() = %+v, se esperaba %+v", got, delivered)
+		}
+
+		tests := []struct {
+			name   string
+			status string
+			limit  int
+			want   []string
+		}{
+			{"Todas, de la más reciente a la más antigua", "", 10, []string{deliveries[2].ID, deliveries[1].ID, deliveries[0].ID}},
+			{"Por estado", domain.WebhookDeliveryFailed, 10, []string{failed.ID}},
+			{"Con límite", "", 1, []string{deliveries[2].ID}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				list, err := repos.Webhook.ListDeliveries(ctx, subscription.ID, tt.status, tt.limit)
+				if err != nil {
+					t.Fatalf("ListDeliveries() = %v", err)
+				}
+
+				if got := deliveryIDs(list); !slices.Equal(got, tt.want) {
+					t.Errorf("ListDeliveries() = %v, se esperaba %v", got, tt.want)
+				}
+			})
+		}
+
+		if err := repos.Webhook.DeleteSubscription(ctx, subscription.ID); err != nil {
+			t.Fatalf("DeleteSubscription() = %v", err)
+		}
+
+		if _, err := repos.Webhook.FindSubscription(ctx, subscription.ID); !errors.Is(err, domain.ErrWebhookNotFound) {
+			t.Errorf("FindSubscription() tras eliminar = %v, se esperaba %v", err, domain.ErrWebhookNotFound)
+		}
+
+		if _, err := repos.Webhook.FindDelivery(ctx, deliveries[2].ID); !errors.Is(err, domain.ErrWebhookDeliveryNotFound) {
+			t.Errorf("FindDelivery() tras eliminar la suscripción = %v, se esperaba %v", err, domain.ErrWebhookDeliveryNotFound)
+		}
+	})
+
+	t.Run("Plazo vencido", func(t *testing.T) {
+		ctx := expiredContext()
+		id := ulid.GenerateNewULID()
+
+		_, err := repos.Webhook.ListSubscriptions(ctx)
+		assertTimeout(t, "ListSubscriptions()", err)
+
+		_, err = repos.Webhook.FindSubscription(ctx, id)
+		assertTimeout(t, "FindSubscription()", err)
+
+		assertTimeout(t, "DeleteSubscription()", repos.Webhook.DeleteSubscription(ctx, id))
+
+		_, err = repos.Webhook.ListDue(ctx, time.Now(), 1)
+		assertTimeout(t, "ListDue()", err)
+
+		_, err = repos.Webhook.Claim(ctx, id, time.Now(), time.Now())
+		assertTimeout(t, "Claim()", err)
+	})
+}
+
+func newSubscription(t *testing.T, repos Repositories) *domain.WebhookSubscription {
+	t.Helper()
+
+	subscription := &domain.WebhookSubscription{
+		ID:         ulid.GenerateNewULID(),
+		URL:        "https://example.com/webhook",
+		EventTypes: []domain.EventType{domain.EventVehicleEntered, domain.EventParkingVoided},
+		Secret:     "secreto",
+		IsActive:   true,
+		CreatedAt:  time.Now().UTC().Truncate(time.Second),
+	}
+
+	if err := repos.Webhook.CreateSubscription(context.Background(), subscription); err != nil {
+		t.Fatalf("error al crear suscripción de prueba: %v", err)
+	}
+
+	return subscription
+}
+
+func newDelivery(subscriptionID string, createdAt, nextAttemptAt time.Time) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		ID:             ulid.GenerateNewULID(),
+		SubscriptionID: subscriptionID,
+		EventID:        ulid.GenerateNewULID(),
+		EventType:      domain.EventVehicleEntered,
+		Payload:        []byte(`{"type":"vehicle.entered"}`),
+		Status:         domain.WebhookDeliveryPending,
+		NextAttemptAt:  nextAttemptAt,
+		CreatedAt:      createdAt,
+	}
+}
+
+func deliveryIDs(deliveries []domain.WebhookDelivery) []string {
+	ids := []string{}
+
+	for _, delivery := range deliveries {
+		ids = append(ids, delivery.ID)
+	}
+
+	return ids
+}
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+func NewConnection(ctx context.Context, dsn string, timeout time.Duration) (*sql.DB, error) {
+	config, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error al interpretar el DSN de PostgreSQL: %w", err)
+	}
+
+	conn := stdlib.OpenDB(*config, stdlib.OptionAfterConnect(scanTimesInUTC))
+
+	conn.SetMaxOpenConns(20)
+	conn.SetMaxIdleConns(20)
+	conn.SetConnMaxLifetime(2 * time.Minute)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err = conn.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("error al hacer ping con PostgreSQL: %w", err)
+	}
+
+	return conn, nil
+}
+
+// scanTimesInUTC hace que las columnas TIMESTAMPTZ se lean en UTC, como con SQLite y MySQL, en
+// lugar de la zona horaria local del proceso.
+func scanTimesInUTC(_ context.Context, conn *pgx.Conn) error {
+	conn.TypeMap().RegisterType(&pgtype.Type{
+		Name:  "timestamptz",
+		OID:   pgtype.TimestamptzOID,
+		Codec: &pgtype.TimestamptzCodec{ScanLocation: time.UTC},
+	})
+
+	return nil
+}
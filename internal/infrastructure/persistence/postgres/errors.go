@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Códigos SQLSTATE de PostgreSQL para violaciones de restricción.
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+// isConstraint indica si err es un error de PostgreSQL con el código SQLSTATE indicado.
+func isConstraint(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
+
+// isUniqueViolation indica si err se debe a un índice único o una clave primaria duplicada.
+func isUniqueViolation(err error) bool {
+	return isConstraint(err, uniqueViolation)
+}
+
+// isForeignKeyViolation indica si err se debe a una clave foránea inexistente o en uso.
+func isForeignKeyViolation(err error) bool {
+	return isConstraint(err, foreignKeyViolation)
+}
@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/mfa"
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/ulid"
+)
+
+type mfaRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+func NewMFARepository(db *sql.DB, timeout time.Duration) mfa.Repository {
+	return &mfaRepository{DB: db, Timeout: timeout}
+}
+
+func (r *mfaRepository) Find(ctx context.Context, userID string) (*domain.UserMFA, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT user_id, secret, enabled_at, last_used_step, created_at
+		FROM USER_MFA
+		WHERE user_id = $1;`
+
+	var m domain.UserMFA
+	var enabledAt sql.NullTime
+
+	err := conn(ctx, r.DB).QueryRowContext(ctx, query, userID).Scan(
+		&m.UserID,
+		&m.Secret,
+		&enabledAt,
+		&m.LastUsedStep,
+		&m.CreatedAt,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al buscar configuración de dos factores: %w", ctx.Err())
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrMFANotEnrolled
+		}
+
+		return nil, fmt.Errorf("error al buscar configuración de dos factores: %w", err)
+	}
+
+	if enabledAt.Valid {
+		m.EnabledAt = &enabledAt.Time
+	}
+
+	return &m, nil
+}
+
+func (r *mfaRepository) Save(ctx context.Context, m *domain.UserMFA, recoveryCodeHashes []string) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := deleteMFA(ctx, tx, m.UserID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO USER_MFA (user_id, secret, enabled_at, last_used_step, created_at)
+		VALUES ($1, $2, NULL, 0, $3);`
+
+	if _, err := tx.ExecContext(ctx, query, m.UserID, m.Secret, m.CreatedAt); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al guardar configuración de dos factores: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al guardar configuración de dos factores: %w", err)
+	}
+
+	codeQuery := `
+		INSERT INTO MFA_RECOVERY_CODES (id, user_id, code_hash)
+		VALUES ($1, $2, $3);`
+
+	for _, hash := range recoveryCodeHashes {
+		if _, err := tx.ExecContext(ctx, codeQuery, ulid.GenerateNewULID(), m.UserID, hash); err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("timeout de DB excedido al guardar códigos de recuperación: %w", ctx.Err())
+			}
+
+			return fmt.Errorf("error al guardar códigos de recuperación: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar configuración de dos factores: %w", err)
+	}
+
+	return nil
+}
+
+func (r *mfaRepository) Enable(ctx context.Context, userID string, enabledAt time.Time) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		UPDATE USER_MFA
+		SET enabled_at = $1
+		WHERE user_id = $2;`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, query, enabledAt, userID)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al activar dos factores: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al activar dos factores: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return domain.ErrMFANotEnrolled
+	}
+
+	return nil
+}
+
+func (r *mfaRepository) ConsumeStep(ctx context.Context, userID string, step int64) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	// La condición sobre last_used_step evita que dos solicitudes concurrentes acepten el mismo código.
+	query := `
+		UPDATE USER_MFA
+		SET last_used_step = $1
+		WHERE user_id = $2 AND last_used_step < $3;`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, query, step, userID, step)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return false, fmt.Errorf("timeout de DB excedido al registrar código utilizado: %w", ctx.Err())
+		}
+
+		return false, fmt.Errorf("error al registrar código utilizado: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+
+	return rowsAffected > 0, nil
+}
+
+func (r *mfaRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string, usedAt time.Time) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		UPDATE MFA_RECOVERY_CODES
+		SET used_at = $1
+		WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL;`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, query, usedAt, userID, codeHash)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return false, fmt.Errorf("timeout de DB excedido al utilizar código de recuperación: %w", ctx.Err())
+		}
+
+		return false, fmt.Errorf("error al utilizar código de recuperación: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+
+	return rowsAffected > 0, nil
+}
+
+func (r *mfaRepository) Delete(ctx context.Context, userID string) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := deleteMFA(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar eliminación de dos factores: %w", err)
+	}
+
+	return nil
+}
+
+func deleteMFA(ctx context.Context, tx dbtx, userID string) error {
+	queries := []string{
+		`DELETE FROM MFA_RECOVERY_CODES WHERE user_id = $1;`,
+		`DELETE FROM USER_MFA WHERE user_id = $1;`,
+	}
+
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("timeout de DB excedido al eliminar configuración de dos factores: %w", ctx.Err())
+			}
+
+			return fmt.Errorf("error al eliminar configuración de dos factores: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/outbox"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type outboxRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+func NewOutboxRepository(db *sql.DB, timeout time.Duration) outbox.Repository {
+	return &outboxRepository{DB: db, Timeout: timeout}
+}
+
+func (r *outboxRepository) Insert(ctx context.Context, message *domain.OutboxMessage) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO OUTBOX (id, event_type, payload, occurred_at)
+		VALUES ($1, $2, $3, $4);`
+
+	_, err := conn(ctx, r.DB).ExecContext(
+		ctx,
+		query,
+		message.ID,
+		message.EventType,
+		string(message.Payload),
+		message.OccurredAt,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al registrar evento en la bandeja de salida: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al registrar evento en la bandeja de salida: %w", err)
+	}
+
+	return nil
+}
+
+func (r *outboxRepository) ListPending(ctx context.Context, limit int) ([]domain.OutboxMessage, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT id, event_type, payload, occurred_at
+		FROM OUTBOX
+		WHERE processed_at IS NULL
+		ORDER BY id
+		LIMIT $1;`
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, limit)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al listar la bandeja de salida: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("error al listar la bandeja de salida: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []domain.OutboxMessage{}
+
+	for rows.Next() {
+		var message domain.OutboxMessage
+		var payload string
+
+		if err := rows.Scan(&message.ID, &message.EventType, &payload, &message.OccurredAt); err != nil {
+			return nil, fmt.Errorf("error al escanear mensaje de la bandeja de salida: %w", err)
+		}
+
+		message.Payload = []byte(payload)
+		messages = append(messages, message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar sobre la bandeja de salida: %w", err)
+	}
+
+	return messages, nil
+}
+
+func (r *outboxRepository) MarkProcessed(ctx context.Context, id string, processedAt time.Time) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		UPDATE OUTBOX
+		SET processed_at = $1
+		WHERE id = $2 AND processed_at IS NULL;`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, query, processedAt, id)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return false, fmt.Errorf("timeout de DB excedido al marcar mensaje como procesado: %w", ctx.Err())
+		}
+
+		return false, fmt.Errorf("error al marcar mensaje como procesado: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+func (r *outboxRepository) DeleteProcessed(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `DELETE FROM OUTBOX WHERE processed_at IS NOT NULL AND processed_at < $1;`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, query, before)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return 0, fmt.Errorf("timeout de DB excedido al depurar la bandeja de salida: %w", ctx.Err())
+		}
+
+		return 0, fmt.Errorf("error al depurar la bandeja de salida: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,228 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/audit"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+const auditColumns = `id, seq, actor_id, action, entity_type, entity_id, before_data, after_data, ip, request_id, created_at, prev_hash, hash`
+
+type auditRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+func NewAuditRepository(db *sql.DB, timeout time.Duration) audit.Repository {
+	return &auditRepository{DB: db, Timeout: timeout}
+}
+
+func (r *auditRepository) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Incrementar la secuencia toma el bloqueo de escritura hasta confirmar, de modo que dos
+	// inserciones concurrentes no puedan enlazarse a la misma entrada.
+	var seq int64
+	var prevHash string
+
+	err = tx.QueryRowContext(ctx, `UPDATE AUDIT_CHAIN_HEAD SET seq = seq + 1 WHERE id = 1 RETURNING seq, hash;`).Scan(&seq, &prevHash)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al reservar secuencia de auditoría: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al reservar secuencia de auditoría: %w", err)
+	}
+
+	entry.Link(seq, prevHash)
+
+	query := `
+		INSERT INTO AUDIT_LOG (` + auditColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		entry.ID,
+		entry.Seq,
+		entry.ActorID,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		nullableJSON(entry.Before),
+		nullableJSON(entry.After),
+		entry.IP,
+		entry.RequestID,
+		entry.CreatedAt,
+		entry.PrevHash,
+		entry.Hash,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al registrar auditoría: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al registrar auditoría: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE AUDIT_CHAIN_HEAD SET hash = ? WHERE id = 1;`, entry.Hash); err != nil {
+		return fmt.Errorf("error al actualizar la cabeza de la cadena de auditoría: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar registro de auditoría: %w", err)
+	}
+
+	return nil
+}
+
+func (r *auditRepository) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + auditColumns + `
+		FROM AUDIT_LOG
+		WHERE (? = '' OR actor_id = ?)
+		  AND (? = '' OR action = ?)
+		  AND (? = '' OR entity_type = ?)
+		  AND (? = '' OR entity_id = ?)
+		  AND (? IS NULL OR created_at >= ?)
+		  AND (? IS NULL OR created_at < ?)
+		  AND (? = 0 OR seq < ?)
+		ORDER BY seq DESC
+		LIMIT ?;`
+
+	rows, err := conn(ctx, r.DB).QueryContext(
+		ctx,
+		query,
+		filter.ActorID, filter.ActorID,
+		filter.Action, filter.Action,
+		filter.EntityType, filter.EntityType,
+		filter.EntityID, filter.EntityID,
+		filter.From, filter.From,
+		filter.To, filter.To,
+		filter.BeforeSeq, filter.BeforeSeq,
+		filter.Limit,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al listar auditoría: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("error al listar auditoría: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAuditEntries(rows)
+}
+
+func (r *auditRepository) ListAfter(ctx context.Context, afterSeq int64, limit int) ([]domain.AuditEntry, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + auditColumns + `
+		FROM AUDIT_LOG
+		WHERE seq > ?
+		ORDER BY seq ASC
+		LIMIT ?;`
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, afterSeq, limit)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al leer la cadena de auditoría: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("error al leer la cadena de auditoría: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAuditEntries(rows)
+}
+
+func (r *auditRepository) Head(ctx context.Context) (int64, string, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	var seq int64
+	var hash string
+
+	err := conn(ctx, r.DB).QueryRowContext(ctx, `SELECT seq, hash FROM AUDIT_CHAIN_HEAD WHERE id = 1;`).Scan(&seq, &hash)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return 0, "", fmt.Errorf("timeout de DB excedido al leer la cabeza de la cadena de auditoría: %w", ctx.Err())
+		}
+
+		return 0, "", fmt.Errorf("error al leer la cabeza de la cadena de auditoría: %w", err)
+	}
+
+	return seq, hash, nil
+}
+
+func scanAuditEntries(rows *sql.Rows) ([]domain.AuditEntry, error) {
+	entries := []domain.AuditEntry{}
+
+	for rows.Next() {
+		var entry domain.AuditEntry
+		var before, after sql.NullString
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Seq,
+			&entry.ActorID,
+			&entry.Action,
+			&entry.EntityType,
+			&entry.EntityID,
+			&before,
+			&after,
+			&entry.IP,
+			&entry.RequestID,
+			&entry.CreatedAt,
+			&entry.PrevHash,
+			&entry.Hash,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear fila de auditoría: %w", err)
+		}
+
+		if before.Valid {
+			entry.Before = json.RawMessage(before.String)
+		}
+
+		if after.Valid {
+			entry.After = json.RawMessage(after.String)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar sobre auditoría: %w", err)
+	}
+
+	return entries, nil
+}
+
+func nullableJSON(data json.RawMessage) any {
+	if data == nil {
+		return nil
+	}
+
+	return string(data)
+}
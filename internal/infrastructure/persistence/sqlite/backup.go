@@ -0,0 +1,354 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/backup"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+// Backup escribe en path una copia consistente de la base de datos con VACUUM INTO. Puede
+// ejecutarse mientras el servidor atiende solicitudes: la copia refleja las transacciones
+// confirmadas al iniciar. path no debe existir.
+func Backup(ctx context.Context, db *sql.DB, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%w: %s", domain.ErrBackupExists, path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error al verificar el archivo de destino: %w", err)
+	}
+
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?;", path); err != nil {
+		return fmt.Errorf("error al crear la copia de seguridad: %w", err)
+	}
+
+	return nil
+}
+
+type backupStorage struct {
+	DB  *sql.DB
+	dir string
+}
+
+// NewBackupStorage crea el almacenamiento de copias de seguridad en el directorio dir.
+func NewBackupStorage(db *sql.DB, dir string) backup.Storage {
+	return &backupStorage{DB: db, dir: dir}
+}
+
+// Snapshot escribe la copia en un archivo temporal y lo renombra al terminar, de modo que nunca
+// queda una copia incompleta con un nombre válido.
+func (s *backupStorage) Snapshot(ctx context.Context, name string) (*domain.Backup, error) {
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return nil, fmt.Errorf("error al crear el directorio de copias de seguridad: %w", err)
+	}
+
+	path := filepath.Join(s.dir, name)
+	if _, err := os.Stat(path); err == nil {
+		return nil, domain.ErrBackupExists
+	}
+
+	tmp := path + ".tmp"
+	os.Remove(tmp)
+	defer os.Remove(tmp)
+
+	if err := Backup(ctx, s.DB, tmp); err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, fmt.Errorf("error al renombrar la copia de seguridad: %w", err)
+	}
+
+	return statBackup(path)
+}
+
+func (s *backupStorage) List(ctx context.Context) ([]domain.Backup, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []domain.Backup{}, nil
+		}
+
+		return nil, fmt.Errorf("error al listar las copias de seguridad: %w", err)
+	}
+
+	backups := []domain.Backup{}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !domain.BackupNamePattern.MatchString(entry.Name()) {
+			continue
+		}
+
+		backup, err := statBackup(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		backups = append(backups, *backup)
+	}
+
+	return backups, nil
+}
+
+func (s *backupStorage) Open(ctx context.Context, name string) (io.ReadCloser, *domain.Backup, error) {
+	path := filepath.Join(s.dir, name)
+
+	backup, err := statBackup(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error al abrir la copia de seguridad: %w", err)
+	}
+
+	return file, backup, nil
+}
+
+func (s *backupStorage) Delete(ctx context.Context, name string) error {
+	if err := os.Remove(filepath.Join(s.dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error al eliminar la copia de seguridad %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func statBackup(path string) (*domain.Backup, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, domain.ErrBackupNotFound
+		}
+
+		return nil, fmt.Errorf("error al consultar la copia de seguridad: %w", err)
+	}
+
+	return &domain.Backup{
+		Name:      filepath.Base(path),
+		Size:      info.Size(),
+		CreatedAt: info.ModTime().UTC().Truncate(time.Second),
+	}, nil
+}
+
+// Restore reemplaza el archivo de la DB de dsn por la copia src. La copia se valida antes del
+// reemplazo: debe superar PRAGMA integrity_check y la función validate (ej. la versión del
+// esquema), que recibe una conexión a ella. La DB reemplazada se conserva junto al original y se
+// retorna su ruta. El servidor debe estar detenido: si otra conexión tiene abierta la DB (en modo
+// WAL, aunque esté inactiva), retorna domain.ErrDatabaseInUse sin reemplazarla.
+func Restore(ctx context.Context, dsn, src string, validate func(ctx context.Context, db *sql.DB) error) (string, error) {
+	dst, err := FilePath(dsn)
+	if err != nil {
+		return "", err
+	}
+
+	// Se valida una copia en el mismo directorio que la DB, que es la que se instala después.
+	tmp, err := copyToTemp(src, filepath.Dir(dst))
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp)
+
+	if err := checkCandidate(ctx, tmp, validate); err != nil {
+		return "", err
+	}
+
+	var previous string
+
+	if _, err := os.Stat(dst); err == nil {
+		// El bloqueo se conserva hasta reemplazar el archivo, de modo que nadie escriba en la DB
+		// después de respaldarla.
+		lock, err := lockExclusive(ctx, dst)
+		if err != nil {
+			return "", err
+		}
+		defer lock.release()
+
+		previous = fmt.Sprintf("%s.pre-restore-%s", dst, time.Now().UTC().Format("20060102-150405"))
+
+		if err := lock.backup(ctx, previous); err != nil {
+			return "", fmt.Errorf("error al respaldar la DB actual: %w", err)
+		}
+	}
+
+	// Un WAL de la DB anterior se aplicaría sobre la restaurada y la dañaría.
+	for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+		if err := os.Remove(dst + suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("error al eliminar %s: %w", dst+suffix, err)
+		}
+	}
+
+	if err := os.Rename(tmp, dst); err != nil {
+		return "", fmt.Errorf("error al reemplazar la DB: %w", err)
+	}
+
+	return previous, nil
+}
+
+// exclusiveLock es una transacción BEGIN EXCLUSIVE abierta sobre la DB que se va a reemplazar.
+type exclusiveLock struct {
+	db   *sql.DB
+	conn *sql.Conn
+	path string
+}
+
+// lockExclusive toma el bloqueo exclusivo de la DB path sin esperar. En modo WAL, BEGIN EXCLUSIVE
+// no impide que otras conexiones sigan abiertas, por lo que antes se cambia a journal_mode=DELETE:
+// SQLite lo rechaza mientras otra conexión tenga la DB abierta, aunque esté inactiva. Después, el
+// bloqueo impide cualquier lectura o escritura hasta liberarlo. Si la DB ya no usaba WAL, las
+// conexiones inactivas no toman bloqueos y solo se detectan las que tienen una transacción abierta.
+// El servidor vuelve a activar WAL al conectarse con el DSN por defecto.
+func lockExclusive(ctx context.Context, path string) (*exclusiveLock, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(0)")
+	if err != nil {
+		return nil, fmt.Errorf("error al abrir la DB actual: %w", err)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error al abrir la DB actual: %w", err)
+	}
+
+	var mode string
+	if err := conn.QueryRowContext(ctx, "PRAGMA journal_mode=DELETE;").Scan(&mode); err != nil || !strings.EqualFold(mode, "delete") {
+		conn.Close()
+		db.Close()
+		return nil, fmt.Errorf("%w: no se pudo desactivar WAL (modo %q): %v", domain.ErrDatabaseInUse, mode, err)
+	}
+
+	if _, err := conn.ExecContext(ctx, "BEGIN EXCLUSIVE;"); err != nil {
+		conn.Close()
+		db.Close()
+		return nil, fmt.Errorf("%w: %v", domain.ErrDatabaseInUse, err)
+	}
+
+	return &exclusiveLock{db: db, conn: conn, path: path}, nil
+}
+
+// backup copia la DB bloqueada en dst. Sin WAL y con el bloqueo exclusivo, el archivo contiene
+// todas las transacciones confirmadas y nadie puede modificarlo, por lo que se copia tal cual.
+func (l *exclusiveLock) backup(ctx context.Context, dst string) error {
+	tmp, err := copyToTemp(l.path, filepath.Dir(dst))
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("error al renombrar la copia de la DB actual: %w", err)
+	}
+
+	return nil
+}
+
+func (l *exclusiveLock) release() {
+	l.conn.ExecContext(context.Background(), "ROLLBACK;")
+	l.conn.Close()
+	l.db.Close()
+}
+
+// FilePath obtiene la ruta del archivo de un DSN de SQLite (ej. file:parking.db?_pragma=...).
+func FilePath(dsn string) (string, error) {
+	path, query, _ := strings.Cut(strings.TrimPrefix(dsn, "file:"), "?")
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return "", fmt.Errorf("DSN de SQLite inválido: %w", err)
+	}
+
+	if path == "" || path == ":memory:" || values.Get("mode") == "memory" {
+		return "", errors.New("la DB de SQLite está en memoria y no tiene un archivo que restaurar")
+	}
+
+	return path, nil
+}
+
+// checkCandidate abre la copia path y verifica su integridad y validate.
+func checkCandidate(ctx context.Context, path string, validate func(ctx context.Context, db *sql.DB) error) error {
+	db, err := sql.Open("sqlite", "file:"+path+"?_time_format=sqlite")
+	if err != nil {
+		return fmt.Errorf("error al abrir la copia de seguridad: %w", err)
+	}
+	defer db.Close()
+
+	if err := checkIntegrity(ctx, db); err != nil {
+		return err
+	}
+
+	return validate(ctx, db)
+}
+
+// checkIntegrity ejecuta PRAGMA integrity_check y retorna ErrBackupCorrupt con los problemas
+// encontrados.
+func checkIntegrity(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, "PRAGMA integrity_check;")
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrBackupCorrupt, err)
+	}
+	defer rows.Close()
+
+	var problems []string
+
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return fmt.Errorf("error al leer el resultado de integrity_check: %w", err)
+		}
+
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrBackupCorrupt, err)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", domain.ErrBackupCorrupt, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// copyToTemp copia src a un archivo temporal de dir y retorna su ruta.
+func copyToTemp(src, dir string) (string, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", fmt.Errorf("error al abrir la copia de seguridad: %w", err)
+	}
+	defer in.Close()
+
+	out, err := os.CreateTemp(dir, ".restore-*.db")
+	if err != nil {
+		return "", fmt.Errorf("error al crear archivo temporal: %w", err)
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(out.Name())
+		return "", fmt.Errorf("error al copiar la copia de seguridad: %w", err)
+	}
+
+	if err := out.Sync(); err != nil {
+		out.Close()
+		os.Remove(out.Name())
+		return "", fmt.Errorf("error al sincronizar archivo: %w", err)
+	}
+
+	if err := out.Close(); err != nil {
+		os.Remove(out.Name())
+		return "", fmt.Errorf("error al cerrar archivo: %w", err)
+	}
+
+	return out.Name(), nil
+}
@@ -0,0 +1,26 @@
+package sqlite
+
+import (
+	"errors"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// isConstraint indica si err es una violación de restricción de SQLite con el código extendido
+// indicado (ej. SQLITE_CONSTRAINT_UNIQUE).
+func isConstraint(err error, code int) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == code
+}
+
+// isUniqueViolation indica si err se debe a un índice único o una clave primaria duplicada.
+func isUniqueViolation(err error) bool {
+	return isConstraint(err, sqlite3.SQLITE_CONSTRAINT_UNIQUE) || isConstraint(err, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY)
+}
+
+// isForeignKeyViolation indica si err se debe a una clave foránea inexistente o en uso. Solo
+// ocurre si la conexión activa PRAGMA foreign_keys.
+func isForeignKeyViolation(err error) bool {
+	return isConstraint(err, sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY)
+}
@@ -0,0 +1,95 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/sso"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type identityRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+func NewIdentityRepository(db *sql.DB, timeout time.Duration) sso.IdentityRepository {
+	return &identityRepository{DB: db, Timeout: timeout}
+}
+
+func (r *identityRepository) FindBySubject(ctx context.Context, issuer, subject string) (*domain.UserIdentity, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT id, user_id, issuer, subject, email, created_at
+		FROM USER_IDENTITIES
+		WHERE issuer = ? AND subject = ?;`
+
+	var identity domain.UserIdentity
+
+	err := conn(ctx, r.DB).QueryRowContext(ctx, query, issuer, subject).Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Issuer,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al buscar identidad externa: %w", ctx.Err())
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrIdentityNotFound
+		}
+
+		return nil, fmt.Errorf("error al buscar identidad externa: %w", err)
+	}
+
+	return &identity, nil
+}
+
+func (r *identityRepository) Create(ctx context.Context, identity *domain.UserIdentity) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	// Si otra solicitud vinculó el mismo sujeto, el INSERT no retorna filas.
+	query := `
+		INSERT INTO USER_IDENTITIES (id, user_id, issuer, subject, email, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (issuer, subject) DO NOTHING
+		RETURNING id;`
+
+	var id string
+
+	err := conn(ctx, r.DB).QueryRowContext(
+		ctx,
+		query,
+		identity.ID,
+		identity.UserID,
+		identity.Issuer,
+		identity.Subject,
+		identity.Email,
+		identity.CreatedAt,
+	).Scan(&id)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al vincular identidad externa: %w", ctx.Err())
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrIdentityAlreadyLinked
+		}
+
+		return fmt.Errorf("error al vincular identidad externa: %w", err)
+	}
+
+	return nil
+}
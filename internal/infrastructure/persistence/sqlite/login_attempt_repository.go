@@ -0,0 +1,152 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/auth"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type loginAttemptRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+func NewLoginAttemptRepository(db *sql.DB, timeout time.Duration) auth.LoginAttemptRepository {
+	return &loginAttemptRepository{DB: db, Timeout: timeout}
+}
+
+func (r *loginAttemptRepository) Create(ctx context.Context, attempt *domain.LoginAttempt) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO LOGIN_ATTEMPTS (id, username, ip, success, reason, created_at)
+		VALUES (?, ?, ?, ?, ?, ?);`
+
+	_, err := conn(ctx, r.DB).ExecContext(
+		ctx,
+		query,
+		attempt.ID,
+		attempt.Username,
+		attempt.IP,
+		attempt.Success,
+		attempt.Reason,
+		attempt.CreatedAt,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al registrar intento de inicio de sesión: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al registrar intento de inicio de sesión: %w", err)
+	}
+
+	return nil
+}
+
+func (r *loginAttemptRepository) FailuresByIP(ctx context.Context, ip string, since time.Time) (int, *time.Time, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	// Los intentos rechazados por espera no cuentan como fallos para no prolongarla indefinidamente.
+	query := `
+		SELECT COUNT(*), MAX(created_at)
+		FROM LOGIN_ATTEMPTS
+		WHERE ip = ? AND success = FALSE AND reason <> ? AND created_at >= ?;`
+
+	var count int
+	var last sql.NullString
+
+	err := conn(ctx, r.DB).QueryRowContext(ctx, query, ip, domain.LoginReasonThrottled, since).Scan(&count, &last)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return 0, nil, fmt.Errorf("timeout de DB excedido al contar intentos fallidos: %w", ctx.Err())
+		}
+
+		return 0, nil, fmt.Errorf("error al contar intentos fallidos: %w", err)
+	}
+
+	if !last.Valid {
+		return count, nil, nil
+	}
+
+	lastTime, err := parseAggregateTime(last.String)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error al leer fecha del último intento fallido: %w", err)
+	}
+
+	return count, &lastTime, nil
+}
+
+func (r *loginAttemptRepository) List(ctx context.Context, username string, limit int) ([]domain.LoginAttempt, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT id, username, ip, success, reason, created_at
+		FROM LOGIN_ATTEMPTS
+		WHERE (? = '' OR username = lower(?))
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?;`
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, username, username, limit)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al listar intentos de inicio de sesión: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("error al listar intentos de inicio de sesión: %w", err)
+	}
+	defer rows.Close()
+
+	attempts := []domain.LoginAttempt{}
+
+	for rows.Next() {
+		var attempt domain.LoginAttempt
+
+		err := rows.Scan(
+			&attempt.ID,
+			&attempt.Username,
+			&attempt.IP,
+			&attempt.Success,
+			&attempt.Reason,
+			&attempt.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear fila de intento de inicio de sesión: %w", err)
+		}
+
+		attempts = append(attempts, attempt)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar sobre intentos de inicio de sesión: %w", err)
+	}
+
+	return attempts, nil
+}
+
+// parseAggregateTime interpreta fechas devueltas por funciones de agregación (MAX, MIN), que
+// SQLite entrega como texto porque el resultado pierde el tipo declarado de la columna.
+func parseAggregateTime(value string) (time.Time, error) {
+	layouts := []string{
+		time.RFC3339Nano,
+		"2006-01-02 15:04:05.999999999-07:00",
+		"2006-01-02 15:04:05.999999999 -0700 MST",
+		"2006-01-02 15:04:05",
+	}
+
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("formato de fecha no reconocido: %q", value)
+}
@@ -0,0 +1,385 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/parking"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type parkingRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+func NewParkingRepository(db *sql.DB, timeout time.Duration) parking.Repository {
+	return &parkingRepository{DB: db, Timeout: timeout}
+}
+
+func (r *parkingRepository) CreateEntry(ctx context.Context, record *domain.ParkingRecord) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO PARKING_RECORDS
+		(id, user_id, vehicle_type_id, license_plate, entry_time)
+		VALUES (?, ?, ?, ?, ?);`
+
+	_, err := conn(ctx, r.DB).ExecContext(
+		ctx,
+		query,
+		record.ID,
+		record.UserID,
+		record.VehicleTypeID,
+		record.LicensePlate,
+		record.EntryTime,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al crear registro de entrada: %w", ctx.Err())
+		}
+
+		// idx_parking_records_one_active: la placa ya tiene un registro abierto.
+		if isUniqueViolation(err) {
+			return domain.ErrActiveParkingAlreadyExists
+		}
+
+		return fmt.Errorf("error al crear registro de entrada: %w", err)
+	}
+
+	return nil
+}
+
+func (r *parkingRepository) FindByID(ctx context.Context, id string) (*domain.ParkingRecord, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT p.id, p.user_id, p.vehicle_type_id, p.license_plate, p.entry_time, p.exit_time,
+			p.total_charge, p.calculated_hours, COALESCE(u.username, ''),
+			p.voided_at, p.voided_by, p.void_reason
+		FROM PARKING_RECORDS p
+		LEFT JOIN USERS u ON u.id = p.user_id
+		WHERE p.id = ?;`
+
+	var record domain.ParkingRecord
+
+	row := conn(ctx, r.DB).QueryRowContext(ctx, query, id)
+
+	var exitTime sql.NullTime
+	var totalCharge sql.NullFloat64
+	var calculatedHours sql.NullInt32
+	var voidedAt sql.NullTime
+	var voidedBy, voidReason sql.NullString
+
+	err := row.Scan(
+		&record.ID,
+		&record.UserID,
+		&record.VehicleTypeID,
+		&record.LicensePlate,
+		&record.EntryTime,
+		&exitTime,
+		&totalCharge,
+		&calculatedHours,
+		&record.Username,
+		&voidedAt,
+		&voidedBy,
+		&voidReason,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al buscar registro de estacionamiento: %w", ctx.Err())
+		}
+
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrParkingRecordNotFound
+		}
+
+		return nil, fmt.Errorf("error al buscar registro de estacionamiento: %w", err)
+	}
+
+	if exitTime.Valid {
+		record.ExitTime = &exitTime.Time
+	}
+
+	if totalCharge.Valid {
+		record.TotalCharge = &totalCharge.Float64
+	}
+
+	if calculatedHours.Valid {
+		h := int(calculatedHours.Int32)
+		record.CalculatedHours = &h
+	}
+
+	if voidedAt.Valid {
+		record.VoidedAt = &voidedAt.Time
+		record.VoidedBy = voidedBy.String
+		record.VoidReason = voidReason.String
+	}
+
+	return &record, nil
+}
+
+func (r *parkingRepository) FindOpenByLicensePlate(ctx context.Context, licensePlate string) (*domain.ParkingRecord, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT id, user_id, vehicle_type_id, license_plate, entry_time
+		FROM PARKING_RECORDS
+		WHERE license_plate = ? COLLATE NOCASE AND exit_time IS NULL;`
+
+	var record domain.ParkingRecord
+
+	row := conn(ctx, r.DB).QueryRowContext(ctx, query, licensePlate)
+
+	err := row.Scan(
+		&record.ID,
+		&record.UserID,
+		&record.VehicleTypeID,
+		&record.LicensePlate,
+		&record.EntryTime,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al buscar registro de estacionamiento abierto: %w", ctx.Err())
+		}
+
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrParkingRecordNotFound
+		}
+
+		return nil, fmt.Errorf("error al buscar registro de estacionamiento abierto: %w", err)
+	}
+
+	return &record, nil
+}
+
+func (r *parkingRepository) UpdateExit(ctx context.Context, record *domain.ParkingRecord) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		UPDATE PARKING_RECORDS
+		SET exit_time = ?, total_charge = ?, calculated_hours = ?
+		WHERE id = ? AND exit_time IS NULL
+		RETURNING id;`
+
+	var id string
+
+	err := conn(ctx, r.DB).QueryRowContext(
+		ctx,
+		query,
+		record.ExitTime,
+		*record.TotalCharge,
+		*record.CalculatedHours,
+		record.ID,
+	).Scan(&id)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al actualizar registro de salida: %w", ctx.Err())
+		}
+
+		if err == sql.ErrNoRows {
+			return domain.ErrParkingRecordNotFound
+		}
+
+		return fmt.Errorf("error al actualizar registro de salida: %w", err)
+	}
+
+	return nil
+}
+
+func (r *parkingRepository) Void(ctx context.Context, record *domain.ParkingRecord) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	// Si el registro se cerró mientras tanto, se conserva su hora de salida.
+	query := `
+		UPDATE PARKING_RECORDS
+		SET exit_time = COALESCE(exit_time, ?), voided_at = ?, voided_by = ?, void_reason = ?
+		WHERE id = ? AND voided_at IS NULL
+		RETURNING id;`
+
+	var id string
+
+	err := conn(ctx, r.DB).QueryRowContext(
+		ctx,
+		query,
+		record.ExitTime,
+		record.VoidedAt,
+		record.VoidedBy,
+		record.VoidReason,
+		record.ID,
+	).Scan(&id)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al anular registro: %w", ctx.Err())
+		}
+
+		if err == sql.ErrNoRows {
+			return domain.ErrParkingRecordNotFound
+		}
+
+		return fmt.Errorf("error al anular registro: %w", err)
+	}
+
+	return nil
+}
+
+func (r *parkingRepository) ListCurrent(ctx context.Context) ([]domain.ParkingRecord, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT p.id, p.user_id, p.vehicle_type_id, p.license_plate, p.entry_time, COALESCE(u.username, '')
+		FROM PARKING_RECORDS p
+		LEFT JOIN USERS u ON u.id = p.user_id
+		WHERE p.exit_time IS NULL
+		ORDER BY p.entry_time DESC;`
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al listar vehículos actuales: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("error al ejecutar la consulta de vehículos actuales: %w", err)
+	}
+	defer rows.Close()
+
+	records := []domain.ParkingRecord{}
+
+	for rows.Next() {
+		var record domain.ParkingRecord
+
+		err := rows.Scan(
+			&record.ID,
+			&record.UserID,
+			&record.VehicleTypeID,
+			&record.LicensePlate,
+			&record.EntryTime,
+			&record.Username,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear fila de registro actual: %w", err)
+		}
+
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar sobre resultados de registros actuales: %w", err)
+	}
+
+	return records, nil
+}
+
+func (r *parkingRepository) ListHistory(ctx context.Context) ([]domain.ParkingRecord, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT p.id, p.user_id, p.vehicle_type_id, p.license_plate, p.entry_time, p.exit_time,
+			p.total_charge, p.calculated_hours, COALESCE(u.username, ''),
+			p.voided_at, p.voided_by, p.void_reason
+		FROM PARKING_RECORDS p
+		LEFT JOIN USERS u ON u.id = p.user_id
+		WHERE p.exit_time IS NOT NULL
+		ORDER BY p.exit_time DESC;`
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al listar historial: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("error al ejecutar la consulta de historial: %w", err)
+	}
+	defer rows.Close()
+
+	var records []domain.ParkingRecord
+
+	for rows.Next() {
+		var record domain.ParkingRecord
+
+		var exitTime sql.NullTime
+		var totalCharge sql.NullFloat64
+		var calculatedHours sql.NullInt32
+		var voidedAt sql.NullTime
+		var voidedBy, voidReason sql.NullString
+
+		err := rows.Scan(
+			&record.ID,
+			&record.UserID,
+			&record.VehicleTypeID,
+			&record.LicensePlate,
+			&record.EntryTime,
+			&exitTime,
+			&totalCharge,
+			&calculatedHours,
+			&record.Username,
+			&voidedAt,
+			&voidedBy,
+			&voidReason,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear fila de historial: %w", err)
+		}
+
+		if exitTime.Valid {
+			record.ExitTime = &exitTime.Time
+		}
+
+		if totalCharge.Valid {
+			record.TotalCharge = &totalCharge.Float64
+		}
+
+		if calculatedHours.Valid {
+			h := int(calculatedHours.Int32)
+			record.CalculatedHours = &h
+		}
+
+		if voidedAt.Valid {
+			record.VoidedAt = &voidedAt.Time
+			record.VoidedBy = voidedBy.String
+			record.VoidReason = voidReason.String
+		}
+
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar sobre resultados de historial: %w", err)
+	}
+
+	return records, nil
+}
+
+func (r *parkingRepository) CountCurrent(ctx context.Context) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	var count int
+	err := conn(ctx, r.DB).QueryRowContext(ctx, "SELECT COUNT(*) FROM PARKING_RECORDS WHERE exit_time IS NULL;").Scan(&count)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return 0, fmt.Errorf("timeout de DB excedido al contar vehículos actuales: %w", ctx.Err())
+		}
+
+		return 0, fmt.Errorf("error al contar vehículos actuales: %w", err)
+	}
+
+	return count, nil
+}
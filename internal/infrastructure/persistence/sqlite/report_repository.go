@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/report"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type reportRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+func NewReportRepository(db *sql.DB, timeout time.Duration) report.Repository {
+	return &reportRepository{DB: db, Timeout: timeout}
+}
+
+func (r *reportRepository) DailySummaries(ctx context.Context, from, to string) ([]domain.DailyParkingSummary, error) {
+	fromTime, _ := time.Parse(time.DateOnly, from)
+	toTime, _ := time.Parse(time.DateOnly, to)
+
+	// DATE() retorna el día en texto con formato YYYY-MM-DD. exit_time se guarda en UTC, por lo
+	// que el día coincide con el de PARKING_RECORDS_DAILY.
+	query := `
+		SELECT DATE(exit_time), vehicle_type_id, COUNT(*),
+			COALESCE(SUM(calculated_hours), 0), COALESCE(SUM(total_charge), 0)
+		FROM PARKING_RECORDS
+		WHERE exit_time IS NOT NULL AND voided_at IS NULL AND exit_time >= ? AND exit_time < ?
+		GROUP BY DATE(exit_time), vehicle_type_id;`
+
+	return r.querySummaries(ctx, query, fromTime, toTime.AddDate(0, 0, 1))
+}
+
+func (r *reportRepository) ArchivedDailySummaries(ctx context.Context, from, to string) ([]domain.DailyParkingSummary, error) {
+	query := `
+		SELECT day, vehicle_type_id, records, hours, revenue
+		FROM PARKING_RECORDS_DAILY
+		WHERE day >= ? AND day <= ?;`
+
+	return r.querySummaries(ctx, query, from, to)
+}
+
+func (r *reportRepository) querySummaries(ctx context.Context, query string, args ...any) ([]domain.DailyParkingSummary, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al calcular el reporte: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("error al calcular el reporte: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := []domain.DailyParkingSummary{}
+
+	for rows.Next() {
+		var summary domain.DailyParkingSummary
+
+		err := rows.Scan(
+			&summary.Day,
+			&summary.VehicleTypeID,
+			&summary.Records,
+			&summary.Hours,
+			&summary.Revenue,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear fila del reporte: %w", err)
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar sobre resultados del reporte: %w", err)
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,208 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/retention"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type retentionRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+func NewRetentionRepository(db *sql.DB, timeout time.Duration) retention.Repository {
+	return &retentionRepository{DB: db, Timeout: timeout}
+}
+
+func (r *retentionRepository) ListExpired(ctx context.Context, cutoff time.Time, limit int) ([]domain.ParkingRecord, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT id, user_id, vehicle_type_id, license_plate, entry_time, exit_time, total_charge, calculated_hours,
+			voided_at, voided_by, void_reason
+		FROM PARKING_RECORDS
+		WHERE exit_time IS NOT NULL AND exit_time < ?
+		ORDER BY exit_time, id
+		LIMIT ?;`
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, cutoff, limit)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al listar registros a archivar: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("error al listar registros a archivar: %w", err)
+	}
+	defer rows.Close()
+
+	records := []domain.ParkingRecord{}
+
+	for rows.Next() {
+		var record domain.ParkingRecord
+
+		var exitTime time.Time
+		var totalCharge sql.NullFloat64
+		var calculatedHours sql.NullInt32
+		var voidedAt sql.NullTime
+		var voidedBy, voidReason sql.NullString
+
+		err := rows.Scan(
+			&record.ID,
+			&record.UserID,
+			&record.VehicleTypeID,
+			&record.LicensePlate,
+			&record.EntryTime,
+			&exitTime,
+			&totalCharge,
+			&calculatedHours,
+			&voidedAt,
+			&voidedBy,
+			&voidReason,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear registro a archivar: %w", err)
+		}
+
+		record.ExitTime = &exitTime
+
+		if totalCharge.Valid {
+			record.TotalCharge = &totalCharge.Float64
+		}
+
+		if calculatedHours.Valid {
+			h := int(calculatedHours.Int32)
+			record.CalculatedHours = &h
+		}
+
+		if voidedAt.Valid {
+			record.VoidedAt = &voidedAt.Time
+			record.VoidedBy = voidedBy.String
+			record.VoidReason = voidReason.String
+		}
+
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar sobre registros a archivar: %w", err)
+	}
+
+	return records, nil
+}
+
+func (r *retentionRepository) Archive(
+	ctx context.Context,
+	records []domain.ParkingRecord,
+	summaries []domain.DailyParkingSummary,
+	keepRows bool,
+	archivedAt time.Time,
+) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción de archivado: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids := make([]any, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+
+	// Se elimina primero: si otra ejecución ya archivó parte del lote, el conteo no coincide y
+	// la transacción se revierte sin duplicar los totales.
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+
+	result, err := tx.ExecContext(
+		ctx,
+		"DELETE FROM PARKING_RECORDS WHERE exit_time IS NOT NULL AND id IN ("+placeholders+");",
+		ids...,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al eliminar registros archivados: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al eliminar registros archivados: %w", err)
+	}
+
+	if deleted, _ := result.RowsAffected(); deleted != int64(len(records)) {
+		return domain.ErrArchiveConflict
+	}
+
+	if keepRows {
+		insert := `
+			INSERT INTO PARKING_RECORDS_ARCHIVE
+			(id, user_id, vehicle_type_id, license_plate, entry_time, exit_time, total_charge, calculated_hours, archived_at,
+				voided_at, voided_by, void_reason)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''));`
+
+		for _, record := range records {
+			_, err := tx.ExecContext(
+				ctx,
+				insert,
+				record.ID,
+				record.UserID,
+				record.VehicleTypeID,
+				record.LicensePlate,
+				record.EntryTime,
+				record.ExitTime,
+				record.TotalCharge,
+				record.CalculatedHours,
+				archivedAt,
+				record.VoidedAt,
+				record.VoidedBy,
+				record.VoidReason,
+			)
+
+			if err != nil {
+				return fmt.Errorf("error al insertar registro archivado: %w", err)
+			}
+		}
+	}
+
+	upsert := `
+		INSERT INTO PARKING_RECORDS_DAILY (day, vehicle_type_id, records, hours, revenue)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (day, vehicle_type_id) DO UPDATE SET
+			records = records + excluded.records,
+			hours = hours + excluded.hours,
+			revenue = revenue + excluded.revenue;`
+
+	for _, summary := range summaries {
+		_, err := tx.ExecContext(
+			ctx,
+			upsert,
+			summary.Day,
+			summary.VehicleTypeID,
+			summary.Records,
+			summary.Hours,
+			summary.Revenue,
+		)
+
+		if err != nil {
+			return fmt.Errorf("error al actualizar totales diarios: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al confirmar el archivado: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al confirmar el archivado: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,319 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/role"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type roleRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+func NewRoleRepository(db *sql.DB, timeout time.Duration) role.Repository {
+	return &roleRepository{DB: db, Timeout: timeout}
+}
+
+func (r *roleRepository) ListAll(ctx context.Context) ([]domain.RoleDefinition, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT name, description, is_system, created_at
+		FROM ROLES
+		ORDER BY name;`
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al listar roles: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("error al listar roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := []domain.RoleDefinition{}
+	index := map[domain.Role]int{}
+
+	for rows.Next() {
+		var role domain.RoleDefinition
+
+		if err := rows.Scan(&role.Name, &role.Description, &role.IsSystem, &role.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error al escanear fila de rol: %w", err)
+		}
+
+		role.Permissions = []domain.Permission{}
+		index[role.Name] = len(roles)
+		roles = append(roles, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar sobre resultados de roles: %w", err)
+	}
+
+	permQuery := `
+		SELECT role_name, permission
+		FROM ROLE_PERMISSIONS
+		ORDER BY role_name, permission;`
+
+	permRows, err := conn(ctx, r.DB).QueryContext(ctx, permQuery)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al listar permisos: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("error al listar permisos: %w", err)
+	}
+	defer permRows.Close()
+
+	for permRows.Next() {
+		var name domain.Role
+		var permission domain.Permission
+
+		if err := permRows.Scan(&name, &permission); err != nil {
+			return nil, fmt.Errorf("error al escanear fila de permiso: %w", err)
+		}
+
+		if i, ok := index[name]; ok {
+			roles[i].Permissions = append(roles[i].Permissions, permission)
+		}
+	}
+
+	if err := permRows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar sobre resultados de permisos: %w", err)
+	}
+
+	return roles, nil
+}
+
+func (r *roleRepository) FindByName(ctx context.Context, name domain.Role) (*domain.RoleDefinition, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT name, description, is_system, created_at
+		FROM ROLES
+		WHERE name = ?;`
+
+	var role domain.RoleDefinition
+
+	err := conn(ctx, r.DB).QueryRowContext(ctx, query, name).Scan(
+		&role.Name,
+		&role.Description,
+		&role.IsSystem,
+		&role.CreatedAt,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al buscar rol: %w", ctx.Err())
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRoleNotFound
+		}
+
+		return nil, fmt.Errorf("error al buscar rol: %w", err)
+	}
+
+	permQuery := `
+		SELECT permission
+		FROM ROLE_PERMISSIONS
+		WHERE role_name = ?
+		ORDER BY permission;`
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, permQuery, name)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al buscar permisos del rol: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("error al buscar permisos del rol: %w", err)
+	}
+	defer rows.Close()
+
+	role.Permissions = []domain.Permission{}
+
+	for rows.Next() {
+		var permission domain.Permission
+		if err := rows.Scan(&permission); err != nil {
+			return nil, fmt.Errorf("error al escanear fila de permiso: %w", err)
+		}
+
+		role.Permissions = append(role.Permissions, permission)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar sobre permisos del rol: %w", err)
+	}
+
+	return &role, nil
+}
+
+func (r *roleRepository) Create(ctx context.Context, role *domain.RoleDefinition) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO ROLES (name, description, is_system, created_at)
+		VALUES (?, ?, ?, ?);`
+
+	if _, err := tx.ExecContext(ctx, query, role.Name, role.Description, role.IsSystem, role.CreatedAt); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al crear rol: %w", ctx.Err())
+		}
+
+		if isUniqueViolation(err) {
+			return domain.ErrRoleAlreadyExists
+		}
+
+		return fmt.Errorf("error al crear rol: %w", err)
+	}
+
+	if err := insertRolePermissions(ctx, tx, role); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar creación de rol: %w", err)
+	}
+
+	return nil
+}
+
+func (r *roleRepository) Update(ctx context.Context, role *domain.RoleDefinition) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	var name domain.Role
+
+	err = tx.QueryRowContext(ctx, "UPDATE ROLES SET description = ? WHERE name = ? RETURNING name;", role.Description, role.Name).Scan(&name)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al actualizar rol: %w", ctx.Err())
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrRoleNotFound
+		}
+
+		return fmt.Errorf("error al actualizar rol: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM ROLE_PERMISSIONS WHERE role_name = ?;", role.Name); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al reemplazar permisos del rol: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al reemplazar permisos del rol: %w", err)
+	}
+
+	if err := insertRolePermissions(ctx, tx, role); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar actualización de rol: %w", err)
+	}
+
+	return nil
+}
+
+func (r *roleRepository) Delete(ctx context.Context, name domain.Role) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Se eliminan los permisos explícitamente, ya que SQLite no aplica ON DELETE CASCADE
+	// si las llaves foráneas no están habilitadas.
+	if _, err := tx.ExecContext(ctx, "DELETE FROM ROLE_PERMISSIONS WHERE role_name = ?;", name); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al eliminar permisos del rol: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al eliminar permisos del rol: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM ROLES WHERE name = ?;", name)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al eliminar rol: %w", ctx.Err())
+		}
+
+		// El rol se asignó a un usuario después de verificar que no estaba en uso.
+		if isForeignKeyViolation(err) {
+			return domain.ErrRoleInUse
+		}
+
+		return fmt.Errorf("error al eliminar rol: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return domain.ErrRoleNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar eliminación de rol: %w", err)
+	}
+
+	return nil
+}
+
+func (r *roleRepository) IsInUse(ctx context.Context, name domain.Role) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	var inUse bool
+	query := "SELECT EXISTS(SELECT 1 FROM USERS WHERE role = ?);"
+
+	if err := conn(ctx, r.DB).QueryRowContext(ctx, query, name).Scan(&inUse); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return false, fmt.Errorf("timeout de DB excedido al verificar uso del rol: %w", ctx.Err())
+		}
+
+		return false, fmt.Errorf("error al verificar uso del rol: %w", err)
+	}
+
+	return inUse, nil
+}
+
+func insertRolePermissions(ctx context.Context, tx dbtx, role *domain.RoleDefinition) error {
+	query := "INSERT INTO ROLE_PERMISSIONS (role_name, permission) VALUES (?, ?);"
+
+	for _, permission := range role.Permissions {
+		if _, err := tx.ExecContext(ctx, query, role.Name, permission); err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("timeout de DB excedido al guardar permisos del rol: %w", ctx.Err())
+			}
+
+			return fmt.Errorf("error al guardar permisos del rol: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+
+	"github.com/JGCaceres97/parking/internal/infrastructure/persistence/persistencetest"
+	"github.com/JGCaceres97/parking/internal/infrastructure/tracing"
+)
+
+func TestRepositoryTracing(t *testing.T) {
+	db, err := NewConnection(context.Background(), "file::memory:?_time_format=sqlite", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	persistencetest.Migrate(t, db, goose.DialectSQLite3, "sqlite")
+
+	exporter := tracetest.NewInMemoryExporter()
+	shutdown := tracing.Install(sdktrace.NewSimpleSpanProcessor(exporter), 1)
+	defer shutdown(context.Background())
+
+	ctx, root := sdktrace.NewTracerProvider().Tracer("prueba").Start(context.Background(), "raíz")
+	if _, err := NewVehicleTypeRepository(db, time.Second).FindByID(ctx, persistencetest.VehicleTypeNormalID); err != nil {
+		t.Fatal(err)
+	}
+	root.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("se esperaban 2 spans, se obtuvieron %d", len(spans))
+	}
+
+	statement, operation := spans[0], spans[1]
+
+	if operation.Name != "vehicle_type.FindByID" || operation.Parent.SpanID() != root.SpanContext().SpanID() {
+		t.Errorf("operación = %q, padre %s", operation.Name, operation.Parent.SpanID())
+	}
+
+	if statement.Name != "SELECT" || statement.Parent.SpanID() != operation.SpanContext.SpanID() {
+		t.Errorf("sentencia = %q, padre %s", statement.Name, statement.Parent.SpanID())
+	}
+
+	for _, attr := range statement.Attributes {
+		if attr.Key == semconv.DBQueryTextKey && (strings.ContainsAny(attr.Value.AsString(), "\n\t") || !strings.HasPrefix(attr.Value.AsString(), "SELECT id, name")) {
+			t.Errorf("consulta sin sanear: %q", attr.Value.AsString())
+		}
+	}
+}
@@ -0,0 +1,641 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/user"
+	"github.com/JGCaceres97/parking/internal/domain"
+	"github.com/JGCaceres97/parking/pkg/ulid"
+)
+
+// userColumns es el listado de columnas que espera scanUser.
+const userColumns = `id, username, password_hash, role, is_active, must_change_password,
+		failed_login_attempts, last_failed_login_at, locked_at, created_at, deleted_at, token_version`
+
+type userRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+func NewUserRepository(db *sql.DB, timeout time.Duration) user.Repository {
+	return &userRepository{DB: db, Timeout: timeout}
+}
+
+func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO USERS (id, username, password_hash, role, is_active, must_change_password, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?);`
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		user.ID,
+		strings.ToLower(user.Username),
+		user.Password,
+		user.Role,
+		user.IsActive,
+		user.MustChangePassword,
+		user.CreatedAt,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al crear usuario: %w", ctx.Err())
+		}
+
+		if isUniqueViolation(err) {
+			return domain.ErrUsernameAlreadyExists
+		}
+
+		return fmt.Errorf("error al crear usuario: %w", err)
+	}
+
+	if err := insertPasswordHistory(ctx, tx, user.ID, user.Password, user.CreatedAt); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar creación de usuario: %w", err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + userColumns + `
+		FROM USERS
+		WHERE id = ? AND deleted_at IS NULL;`
+
+	user, err := scanUser(conn(ctx, r.DB).QueryRowContext(ctx, query, id))
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al buscar usuario: %w", ctx.Err())
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+
+		return nil, fmt.Errorf("error al buscar usuario: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *userRepository) FindByIDWithDeleted(ctx context.Context, id string) (*domain.User, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + userColumns + `
+		FROM USERS
+		WHERE id = ?;`
+
+	user, err := scanUser(conn(ctx, r.DB).QueryRowContext(ctx, query, id))
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al buscar usuario: %w", ctx.Err())
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+
+		return nil, fmt.Errorf("error al buscar usuario: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *userRepository) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + userColumns + `
+		FROM USERS
+		WHERE username = ? COLLATE NOCASE AND deleted_at IS NULL;`
+
+	user, err := scanUser(conn(ctx, r.DB).QueryRowContext(ctx, query, username))
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al buscar usuario: %w", ctx.Err())
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+
+		return nil, fmt.Errorf("error al buscar usuario: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *userRepository) ExistsUsername(ctx context.Context, username string) bool {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	var exists bool
+	query := "SELECT EXISTS(SELECT 1 FROM USERS WHERE username = ? COLLATE NOCASE AND deleted_at IS NULL);"
+
+	err := conn(ctx, r.DB).QueryRowContext(ctx, query, username).Scan(&exists)
+	if err != nil {
+		return false
+	}
+
+	return exists
+}
+
+func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		UPDATE USERS
+		SET username = ?, role = ?, is_active = ?
+		WHERE id = ? AND deleted_at IS NULL
+		RETURNING id;`
+
+	var id string
+
+	err := conn(ctx, r.DB).QueryRowContext(
+		ctx,
+		query,
+		user.Username,
+		user.Role,
+		user.IsActive,
+		user.ID,
+	).Scan(&id)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al actualizar usuario: %w", ctx.Err())
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+
+		if isUniqueViolation(err) {
+			return domain.ErrUsernameAlreadyExists
+		}
+
+		return fmt.Errorf("error al actualizar usuario: %w", err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateLoginState(ctx context.Context, user *domain.User) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		UPDATE USERS
+		SET failed_login_attempts = ?, last_failed_login_at = ?, locked_at = ?
+		WHERE id = ?;`
+
+	result, err := conn(ctx, r.DB).ExecContext(
+		ctx,
+		query,
+		user.FailedLoginAttempts,
+		user.LastFailedLoginAt,
+		user.LockedAt,
+		user.ID,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al actualizar estado de inicio de sesión: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al actualizar estado de inicio de sesión: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// RegisterLoginFailure incrementa los fallos consecutivos en la misma sentencia, para que los
+// intentos concurrentes no se pierdan, y bloquea la cuenta al alcanzar maxFailures.
+func (r *userRepository) RegisterLoginFailure(ctx context.Context, id string, at time.Time, maxFailures int) (int, bool, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		UPDATE USERS
+		SET failed_login_attempts = failed_login_attempts + 1,
+			last_failed_login_at = ?2,
+			locked_at = CASE
+				WHEN locked_at IS NULL AND ?1 > 0 AND failed_login_attempts + 1 >= ?1 THEN ?2
+				ELSE locked_at
+			END
+		WHERE id = ?3
+		RETURNING failed_login_attempts, locked_at IS NOT NULL;`
+
+	var failures int
+	var locked bool
+
+	err := conn(ctx, r.DB).QueryRowContext(ctx, query, maxFailures, at, id).Scan(&failures, &locked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, domain.ErrUserNotFound
+		}
+
+		if ctx.Err() == context.DeadlineExceeded {
+			return 0, false, fmt.Errorf("timeout de DB excedido al registrar intento fallido: %w", ctx.Err())
+		}
+
+		return 0, false, fmt.Errorf("error al registrar intento fallido: %w", err)
+	}
+
+	return failures, locked, nil
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, id, passwordHash string, mustChange bool) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE USERS
+		SET password_hash = ?, must_change_password = ?
+		WHERE id = ?;`
+
+	result, err := tx.ExecContext(ctx, query, passwordHash, mustChange, id)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al actualizar contraseña: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al actualizar contraseña: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	if err := insertPasswordHistory(ctx, tx, id, passwordHash, time.Now().UTC().Truncate(time.Second)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar cambio de contraseña: %w", err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) RevokeTokens(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `UPDATE USERS SET token_version = token_version + 1 WHERE id = ?;`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, query, id)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al revocar tokens: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al revocar tokens: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *userRepository) ListPasswordHistory(ctx context.Context, id string, limit int) ([]string, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT password_hash
+		FROM PASSWORD_HISTORY
+		WHERE user_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?;`
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, id, limit)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al listar historial de contraseñas: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("error al listar historial de contraseñas: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := []string{}
+
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("error al escanear fila de historial de contraseñas: %w", err)
+		}
+
+		hashes = append(hashes, hash)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar sobre historial de contraseñas: %w", err)
+	}
+
+	return hashes, nil
+}
+
+func (r *userRepository) SoftDelete(ctx context.Context, id string, deletedAt time.Time) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		UPDATE USERS
+		SET is_active = FALSE, deleted_at = ?
+		WHERE id = ? AND deleted_at IS NULL;`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, query, deletedAt, id)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al eliminar usuario: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al eliminar usuario: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *userRepository) Anonymize(ctx context.Context, id, username string, deletedAt time.Time) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previousUsername string
+	if err := tx.QueryRowContext(ctx, `SELECT username FROM USERS WHERE id = ?;`, id).Scan(&previousUsername); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+
+		return fmt.Errorf("error al buscar usuario: %w", err)
+	}
+
+	// La contraseña se reemplaza por un valor que ningún hash bcrypt puede igualar.
+	query := `
+		UPDATE USERS
+		SET username = ?, password_hash = '!', is_active = FALSE, must_change_password = FALSE,
+			failed_login_attempts = 0, last_failed_login_at = NULL, locked_at = NULL,
+			deleted_at = COALESCE(deleted_at, ?)
+		WHERE id = ?;`
+
+	if _, err := tx.ExecContext(ctx, query, username, deletedAt, id); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al anonimizar usuario: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al anonimizar usuario: %w", err)
+	}
+
+	if err := deleteUserData(ctx, tx, id); err != nil {
+		return err
+	}
+
+	// Solo los intentos anteriores a la eliminación pertenecen al usuario; su nombre pudo
+	// reutilizarse después.
+	attemptsQuery := `
+		UPDATE LOGIN_ATTEMPTS
+		SET username = ?
+		WHERE username = ? AND created_at <= ?;`
+
+	if _, err := tx.ExecContext(ctx, attemptsQuery, username, previousUsername, deletedAt); err != nil {
+		return fmt.Errorf("error al anonimizar intentos de inicio de sesión: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar anonimización de usuario: %w", err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists, hasRecords bool
+	checkQuery := `
+		SELECT
+			EXISTS(SELECT 1 FROM USERS WHERE id = ?),
+			EXISTS(SELECT 1 FROM PARKING_RECORDS WHERE user_id = ?)
+				OR EXISTS(SELECT 1 FROM PARKING_RECORDS_ARCHIVE WHERE user_id = ?);`
+
+	if err := tx.QueryRowContext(ctx, checkQuery, id, id, id).Scan(&exists, &hasRecords); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al verificar existencia de usuario: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al verificar existencia de usuario: %w", err)
+	}
+
+	if !exists {
+		return domain.ErrUserNotFound
+	}
+
+	if hasRecords {
+		return domain.ErrUserHasRecords
+	}
+
+	// SQLite no aplica ON DELETE CASCADE sin PRAGMA foreign_keys, por lo que se eliminan
+	// explícitamente los datos asociados.
+	if err := deleteUserData(ctx, tx, id); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM USERS WHERE id = ?;`, id); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al eliminar usuario: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al eliminar usuario: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar eliminación de usuario: %w", err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) ListAll(ctx context.Context, id string, includeDeleted bool) ([]domain.User, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + userColumns + `
+		FROM USERS
+		WHERE id != ? AND (? OR deleted_at IS NULL);`
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, id, includeDeleted)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al listar usuarios: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("error al ejecutar la consulta de listado de usuarios: %w", err)
+	}
+	defer rows.Close()
+
+	users := []domain.User{}
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear fila de usuario: %w", err)
+		}
+
+		user.Password = ""
+		users = append(users, *user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar sobre resultados de usuarios: %w", err)
+	}
+
+	return users, nil
+}
+
+func insertPasswordHistory(ctx context.Context, tx dbtx, userID, passwordHash string, createdAt time.Time) error {
+	query := `
+		INSERT INTO PASSWORD_HISTORY (id, user_id, password_hash, created_at)
+		VALUES (?, ?, ?, ?);`
+
+	if _, err := tx.ExecContext(ctx, query, ulid.GenerateNewULID(), userID, passwordHash, createdAt); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al guardar historial de contraseñas: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al guardar historial de contraseñas: %w", err)
+	}
+
+	return nil
+}
+
+// deleteUserData elimina las credenciales y vínculos del usuario: historial de contraseñas,
+// segundo factor e identidades externas.
+func deleteUserData(ctx context.Context, tx dbtx, id string) error {
+	queries := []string{
+		`DELETE FROM PASSWORD_HISTORY WHERE user_id = ?;`,
+		`DELETE FROM MFA_RECOVERY_CODES WHERE user_id = ?;`,
+		`DELETE FROM USER_MFA WHERE user_id = ?;`,
+		`DELETE FROM USER_IDENTITIES WHERE user_id = ?;`,
+	}
+
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("timeout de DB excedido al eliminar datos del usuario: %w", ctx.Err())
+			}
+
+			return fmt.Errorf("error al eliminar datos del usuario: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// scanner abstrae *sql.Row y *sql.Rows para reutilizar la lectura de filas.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(row scanner) (*domain.User, error) {
+	var user domain.User
+
+	var lastFailedLoginAt sql.NullTime
+	var lockedAt sql.NullTime
+	var deletedAt sql.NullTime
+
+	err := row.Scan(
+		&user.ID,
+		&user.Username,
+		&user.Password,
+		&user.Role,
+		&user.IsActive,
+		&user.MustChangePassword,
+		&user.FailedLoginAttempts,
+		&lastFailedLoginAt,
+		&lockedAt,
+		&user.CreatedAt,
+		&deletedAt,
+		&user.TokenVersion,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if lastFailedLoginAt.Valid {
+		user.LastFailedLoginAt = &lastFailedLoginAt.Time
+	}
+
+	if lockedAt.Valid {
+		user.LockedAt = &lockedAt.Time
+	}
+
+	if deletedAt.Valid {
+		user.DeletedAt = &deletedAt.Time
+	}
+
+	return &user, nil
+}
@@ -0,0 +1,207 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/vehicle_type"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+type vehicleTypeRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+func NewVehicleTypeRepository(db *sql.DB, timeout time.Duration) vehicle_type.Repository {
+	return &vehicleTypeRepository{DB: db, Timeout: timeout}
+}
+
+func (r *vehicleTypeRepository) FindByID(ctx context.Context, id string) (*domain.VehicleType, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT id, name, hourly_rate, description
+		FROM VEHICLE_TYPES
+		WHERE id = ?;`
+
+	var record domain.VehicleType
+
+	row := conn(ctx, r.DB).QueryRowContext(ctx, query, id)
+
+	err := row.Scan(
+		&record.ID,
+		&record.Name,
+		&record.HourlyRate,
+		&record.Description,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al buscar tipo de vehículo: %w", ctx.Err())
+		}
+
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrVehicleTypeNotFound
+		}
+
+		return nil, fmt.Errorf("error al buscar tipo de vehículo: %w", err)
+	}
+
+	return &record, nil
+}
+
+func (r *vehicleTypeRepository) ListAll(ctx context.Context) ([]domain.VehicleType, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT id, name, hourly_rate, description
+		FROM VEHICLE_TYPES
+		ORDER BY name COLLATE NOCASE;`
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al listar tipos de vehículo: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("error al listar tipos de vehículo: %w", err)
+	}
+	defer rows.Close()
+
+	vehicleTypes := []domain.VehicleType{}
+
+	for rows.Next() {
+		var vt domain.VehicleType
+
+		err := rows.Scan(
+			&vt.ID,
+			&vt.Name,
+			&vt.HourlyRate,
+			&vt.Description,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear fila de tipo de vehículo: %w", err)
+		}
+
+		vehicleTypes = append(vehicleTypes, vt)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar sobre resultados de tipos de vehículo: %w", err)
+	}
+
+	return vehicleTypes, nil
+}
+
+func (r *vehicleTypeRepository) FindByName(ctx context.Context, name string) (*domain.VehicleType, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT id, name, hourly_rate, description
+		FROM VEHICLE_TYPES
+		WHERE name = ? COLLATE NOCASE;`
+
+	var record domain.VehicleType
+
+	row := conn(ctx, r.DB).QueryRowContext(ctx, query, name)
+
+	err := row.Scan(
+		&record.ID,
+		&record.Name,
+		&record.HourlyRate,
+		&record.Description,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al buscar tipo de vehículo: %w", ctx.Err())
+		}
+
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrVehicleTypeNotFound
+		}
+
+		return nil, fmt.Errorf("error al buscar tipo de vehículo: %w", err)
+	}
+
+	return &record, nil
+}
+
+func (r *vehicleTypeRepository) Create(ctx context.Context, vehicleType *domain.VehicleType) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO VEHICLE_TYPES (id, name, hourly_rate, description)
+		VALUES (?, ?, ?, ?);`
+
+	_, err := conn(ctx, r.DB).ExecContext(
+		ctx,
+		query,
+		vehicleType.ID,
+		vehicleType.Name,
+		vehicleType.HourlyRate,
+		vehicleType.Description,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al crear tipo de vehículo: %w", ctx.Err())
+		}
+
+		if isUniqueViolation(err) {
+			return domain.ErrVehicleTypeNameAlreadyExists
+		}
+
+		return fmt.Errorf("error al crear tipo de vehículo: %w", err)
+	}
+
+	return nil
+}
+
+func (r *vehicleTypeRepository) Update(ctx context.Context, vehicleType *domain.VehicleType) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		UPDATE VEHICLE_TYPES
+		SET name = ?, hourly_rate = ?, description = ?
+		WHERE id = ?
+		RETURNING id;`
+
+	var id string
+
+	err := conn(ctx, r.DB).QueryRowContext(
+		ctx,
+		query,
+		vehicleType.Name,
+		vehicleType.HourlyRate,
+		vehicleType.Description,
+		vehicleType.ID,
+	).Scan(&id)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al actualizar tipo de vehículo: %w", ctx.Err())
+		}
+
+		if err == sql.ErrNoRows {
+			return domain.ErrVehicleTypeNotFound
+		}
+
+		if isUniqueViolation(err) {
+			return domain.ErrVehicleTypeNameAlreadyExists
+		}
+
+		return fmt.Errorf("error al actualizar tipo de vehículo: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,417 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JGCaceres97/parking/internal/application/webhook"
+	"github.com/JGCaceres97/parking/internal/domain"
+)
+
+const deliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts,
+	next_attempt_at, last_status_code, last_error, created_at, delivered_at`
+
+type webhookRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+func NewWebhookRepository(db *sql.DB, timeout time.Duration) webhook.Repository {
+	return &webhookRepository{DB: db, Timeout: timeout}
+}
+
+func (r *webhookRepository) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT id, url, event_types, secret, is_active, created_at
+		FROM WEBHOOK_SUBSCRIPTIONS
+		ORDER BY created_at, id;`
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al listar suscripciones de webhook: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("error al listar suscripciones de webhook: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []domain.WebhookSubscription{}
+
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear suscripción de webhook: %w", err)
+		}
+
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar sobre suscripciones de webhook: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func (r *webhookRepository) FindSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		SELECT id, url, event_types, secret, is_active, created_at
+		FROM WEBHOOK_SUBSCRIPTIONS
+		WHERE id = ?;`
+
+	subscription, err := scanWebhookSubscription(conn(ctx, r.DB).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al buscar suscripción de webhook: %w", ctx.Err())
+		}
+
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrWebhookNotFound
+		}
+
+		return nil, fmt.Errorf("error al buscar suscripción de webhook: %w", err)
+	}
+
+	return subscription, nil
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO WEBHOOK_SUBSCRIPTIONS (id, url, event_types, secret, is_active, created_at)
+		VALUES (?, ?, ?, ?, ?, ?);`
+
+	_, err := conn(ctx, r.DB).ExecContext(
+		ctx,
+		query,
+		subscription.ID,
+		subscription.URL,
+		strings.Join(subscription.EventTypes, ","),
+		subscription.Secret,
+		subscription.IsActive,
+		subscription.CreatedAt,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al crear suscripción de webhook: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al crear suscripción de webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) UpdateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		UPDATE WEBHOOK_SUBSCRIPTIONS
+		SET url = ?, event_types = ?, secret = ?, is_active = ?
+		WHERE id = ?;`
+
+	_, err := conn(ctx, r.DB).ExecContext(
+		ctx,
+		query,
+		subscription.URL,
+		strings.Join(subscription.EventTypes, ","),
+		subscription.Secret,
+		subscription.IsActive,
+		subscription.ID,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al actualizar suscripción de webhook: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al actualizar suscripción de webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Las entregas se eliminan explícitamente, ya que ON DELETE CASCADE solo se aplica si el
+	// SQLITE_DSN activa PRAGMA foreign_keys.
+	queries := []string{
+		"DELETE FROM WEBHOOK_DELIVERIES WHERE subscription_id = ?;",
+		"DELETE FROM WEBHOOK_SUBSCRIPTIONS WHERE id = ?;",
+	}
+
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("timeout de DB excedido al eliminar suscripción de webhook: %w", ctx.Err())
+			}
+
+			return fmt.Errorf("error al eliminar suscripción de webhook: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar la eliminación de la suscripción: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) InsertDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := beginTx(ctx, r.DB)
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO WEBHOOK_DELIVERIES
+		(id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);`
+
+	for _, delivery := range deliveries {
+		_, err := tx.ExecContext(
+			ctx,
+			query,
+			delivery.ID,
+			delivery.SubscriptionID,
+			delivery.EventID,
+			delivery.EventType,
+			string(delivery.Payload),
+			delivery.Status,
+			delivery.Attempts,
+			delivery.NextAttemptAt,
+			delivery.CreatedAt,
+		)
+
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("timeout de DB excedido al registrar entregas de webhook: %w", ctx.Err())
+			}
+
+			return fmt.Errorf("error al registrar entrega de webhook: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar entregas de webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) FindDelivery(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := "SELECT " + deliveryColumns + " FROM WEBHOOK_DELIVERIES WHERE id = ?;"
+
+	delivery, err := scanWebhookDelivery(conn(ctx, r.DB).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al buscar entrega de webhook: %w", ctx.Err())
+		}
+
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrWebhookDeliveryNotFound
+		}
+
+		return nil, fmt.Errorf("error al buscar entrega de webhook: %w", err)
+	}
+
+	return delivery, nil
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, subscriptionID, status string, limit int) ([]domain.WebhookDelivery, error) {
+	query := "SELECT " + deliveryColumns + " FROM WEBHOOK_DELIVERIES WHERE subscription_id = ?"
+	args := []any{subscriptionID}
+
+	if status != "" {
+		query += " AND status = ?"
+		args = append(args, status)
+	}
+
+	query += " ORDER BY created_at DESC, id DESC LIMIT ?;"
+	args = append(args, limit)
+
+	return r.listDeliveries(ctx, query, args...)
+}
+
+func (r *webhookRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	query := "SELECT " + deliveryColumns + ` FROM WEBHOOK_DELIVERIES
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at, id
+		LIMIT ?;`
+
+	return r.listDeliveries(ctx, query, domain.WebhookDeliveryPending, now, limit)
+}
+
+func (r *webhookRepository) Claim(ctx context.Context, id string, expectedNextAttempt, leaseUntil time.Time) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		UPDATE WEBHOOK_DELIVERIES
+		SET next_attempt_at = ?
+		WHERE id = ? AND status = ? AND next_attempt_at = ?
+		RETURNING id;`
+
+	err := conn(ctx, r.DB).QueryRowContext(ctx, query, leaseUntil, id, domain.WebhookDeliveryPending, expectedNextAttempt).Scan(&id)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return false, fmt.Errorf("timeout de DB excedido al reservar entrega de webhook: %w", ctx.Err())
+		}
+
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("error al reservar entrega de webhook: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `
+		UPDATE WEBHOOK_DELIVERIES
+		SET status = ?, attempts = ?, next_attempt_at = ?, last_status_code = ?, last_error = ?, delivered_at = ?
+		WHERE id = ?;`
+
+	_, err := conn(ctx, r.DB).ExecContext(
+		ctx,
+		query,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastStatusCode,
+		delivery.LastError,
+		delivery.DeliveredAt,
+		delivery.ID,
+	)
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timeout de DB excedido al actualizar entrega de webhook: %w", ctx.Err())
+		}
+
+		return fmt.Errorf("error al actualizar entrega de webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) listDeliveries(ctx context.Context, query string, args ...any) ([]domain.WebhookDelivery, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout de DB excedido al listar entregas de webhook: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("error al listar entregas de webhook: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []domain.WebhookDelivery{}
+
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear entrega de webhook: %w", err)
+		}
+
+		deliveries = append(deliveries, *delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar sobre entregas de webhook: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func scanWebhookSubscription(row scanner) (*domain.WebhookSubscription, error) {
+	var subscription domain.WebhookSubscription
+	var eventTypes string
+
+	err := row.Scan(
+		&subscription.ID,
+		&subscription.URL,
+		&eventTypes,
+		&subscription.Secret,
+		&subscription.IsActive,
+		&subscription.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.EventTypes = strings.Split(eventTypes, ",")
+
+	return &subscription, nil
+}
+
+func scanWebhookDelivery(row scanner) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	var payload string
+	var deliveredAt sql.NullTime
+
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.SubscriptionID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.LastStatusCode,
+		&delivery.LastError,
+		&delivery.CreatedAt,
+		&deliveredAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Payload = []byte(payload)
+
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+
+	return &delivery, nil
+}
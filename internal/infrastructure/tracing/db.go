@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Querier es la interfaz común de *sql.DB y *sql.Tx con la que los repositorios ejecutan las
+// consultas.
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// StartQuery crea el span de una operación de repositorio (ej. parking.FindOpenByLicensePlate),
+// que agrupa las sentencias que ejecuta.
+func StartQuery(ctx context.Context, system, name string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(semconv.DBSystemNameKey.String(system)))
+}
+
+// WrapDB crea un span por cada sentencia ejecutada con q. system es el nombre del motor según las
+// convenciones de OpenTelemetry (sqlite, mysql o postgresql).
+func WrapDB(q Querier, system string) Querier {
+	return &tracedQuerier{q: q, system: semconv.DBSystemNameKey.String(system)}
+}
+
+type tracedQuerier struct {
+	q      Querier
+	system attribute.KeyValue
+}
+
+func (t *tracedQuerier) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := t.start(ctx, query)
+	defer span.End()
+
+	result, err := t.q.ExecContext(ctx, query, args...)
+	recordError(span, err)
+
+	return result, err
+}
+
+func (t *tracedQuerier) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := t.start(ctx, query)
+	defer span.End()
+
+	rows, err := t.q.QueryContext(ctx, query, args...)
+	recordError(span, err)
+
+	return rows, err
+}
+
+func (t *tracedQuerier) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := t.start(ctx, query)
+	defer span.End()
+
+	row := t.q.QueryRowContext(ctx, query, args...)
+	recordError(span, row.Err())
+
+	return row
+}
+
+// start crea el span de una sentencia solo dentro de una traza muestreada, para no sanear la
+// consulta en vano.
+func (t *tracedQuerier) start(ctx context.Context, query string) (context.Context, trace.Span) {
+	if !trace.SpanFromContext(ctx).IsRecording() {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+
+	query = SanitizeQuery(query)
+	operation, _, _ := strings.Cut(query, " ")
+	operation = strings.ToUpper(operation)
+
+	return tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(t.system, semconv.DBOperationName(operation), semconv.DBQueryText(query)))
+}
+
+func recordError(span trace.Span, err error) {
+	if err != nil && err != sql.ErrNoRows {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+var (
+	whitespace     = regexp.MustCompile(`\s+`)
+	stringLiterals = regexp.MustCompile(`'(?:[^']|'')*'`)
+)
+
+// SanitizeQuery compacta los espacios de la consulta y reemplaza los textos literales por ?. Los
+// valores de los parámetros nunca se registran.
+func SanitizeQuery(query string) string {
+	query = stringLiterals.ReplaceAllString(query, "?")
+	return strings.TrimSpace(whitespace.ReplaceAllString(query, " "))
+}
@@ -0,0 +1,53 @@
+// Package tracing configura las trazas de OpenTelemetry: un span por solicitud HTTP, por método de
+// servicio, por operación de repositorio y por sentencia SQL.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// serviceName identifica a la aplicación en el backend de trazas.
+const serviceName = "parking"
+
+const instrumentationName = "github.com/JGCaceres97/parking/internal/infrastructure/tracing"
+
+var tracer = otel.Tracer(instrumentationName)
+
+// Setup envía las trazas por OTLP/HTTP a endpoint (ej. http://localhost:4318) y propaga el
+// contexto de la cabecera traceparent. sampleRatio es la fracción de trazas nuevas que se
+// conservan; las que llegan con traceparent respetan la decisión del llamador. La función
+// retornada envía las trazas pendientes y debe llamarse al terminar.
+func Setup(ctx context.Context, endpoint string, sampleRatio float64) (func(context.Context) error, error) {
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("error al crear el exportador OTLP: %w", err)
+	}
+
+	return Install(sdktrace.NewBatchSpanProcessor(exporter), sampleRatio), nil
+}
+
+// Install registra un proveedor de trazas que entrega los spans a processor. Las pruebas lo usan
+// con un exportador en memoria.
+func Install(processor sdktrace.SpanProcessor, sampleRatio float64) func(context.Context) error {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider.Shutdown
+}
@@ -0,0 +1,151 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+var (
+	exporter    = tracetest.NewInMemoryExporter()
+	installOnce sync.Once
+)
+
+// install registra el exportador en memoria y lo vacía. El proveedor global se registra una sola
+// vez, ya que los tracers obtenidos antes no cambian de proveedor.
+func install(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	installOnce.Do(func() { Install(sdktrace.NewSimpleSpanProcessor(exporter), 1) })
+	exporter.Reset()
+
+	return exporter
+}
+
+func TestMiddleware(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parent  = "00f067aa0ba902b7"
+	)
+
+	tests := []struct {
+		name        string
+		path        string
+		traceparent string
+		wantName    string
+		wantStatus  codes.Code
+	}{
+		{
+			name:        "Continúa la traza recibida",
+			path:        "/vehiculos/ABC123",
+			traceparent: "00-" + traceID + "-" + parent + "-01",
+			wantName:    "GET /vehiculos/{placa}",
+			wantStatus:  codes.Unset,
+		},
+		{
+			name:       "Error del servidor",
+			path:       "/fallo",
+			wantName:   "GET /fallo",
+			wantStatus: codes.Error,
+		},
+		{
+			name:       "Ruta inexistente",
+			path:       "/nada",
+			wantName:   "GET",
+			wantStatus: codes.Unset,
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Get("/vehiculos/{placa}", func(w http.ResponseWriter, r *http.Request) {})
+	r.Get("/fallo", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := install(t)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("se esperaba 1 span, se obtuvieron %d", len(spans))
+			}
+
+			span := spans[0]
+			if span.Name != tt.wantName || span.Status.Code != tt.wantStatus {
+				t.Errorf("span = %q (%v), se esperaba %q (%v)", span.Name, span.Status.Code, tt.wantName, tt.wantStatus)
+			}
+
+			if tt.traceparent != "" {
+				if span.SpanContext.TraceID().String() != traceID || span.Parent.SpanID().String() != parent {
+					t.Errorf("no se continuó la traza: %s, padre %s", span.SpanContext.TraceID(), span.Parent.SpanID())
+				}
+			}
+		})
+	}
+}
+
+func TestWrapDB(t *testing.T) {
+	exporter := install(t)
+
+	ctx, root := tracer.Start(context.Background(), "raíz")
+	db := WrapDB(stubQuerier{}, "sqlite")
+	db.ExecContext(ctx, "UPDATE USERS\n\t\tSET name = 'secreto', password = ?\n\t\tWHERE id = ?;", "clave", "1")
+	root.End()
+
+	// Sin una traza en curso no se crean spans.
+	db.ExecContext(context.Background(), "SELECT 1;")
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("se esperaban 2 spans, se obtuvieron %d", len(spans))
+	}
+
+	statement := spans[0]
+	if statement.Name != "UPDATE" || statement.Parent.SpanID() != root.SpanContext().SpanID() {
+		t.Errorf("span = %q, padre %s", statement.Name, statement.Parent.SpanID())
+	}
+
+	want := map[string]string{
+		string(semconv.DBSystemNameKey):    "sqlite",
+		string(semconv.DBOperationNameKey): "UPDATE",
+		string(semconv.DBQueryTextKey):     "UPDATE USERS SET name = ?, password = ? WHERE id = ?;",
+	}
+
+	for _, attr := range statement.Attributes {
+		if expected, ok := want[string(attr.Key)]; ok && attr.Value.AsString() != expected {
+			t.Errorf("%s = %q, se esperaba %q", attr.Key, attr.Value.AsString(), expected)
+		}
+
+		delete(want, string(attr.Key))
+	}
+
+	for key := range want {
+		t.Errorf("falta el atributo %s", key)
+	}
+}
+
+type stubQuerier struct {
+	Querier
+}
+
+func (stubQuerier) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return nil, nil
+}
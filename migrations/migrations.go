@@ -0,0 +1,20 @@
+// Package migrations incluye en el binario las migraciones de cada driver de DB.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var migrations embed.FS
+
+// FS retorna las migraciones del driver indicado (sqlite, mysql o postgres).
+func FS(driver string) (fs.FS, error) {
+	if _, err := fs.Stat(migrations, driver); err != nil {
+		return nil, fmt.Errorf("no hay migraciones para el driver de DB: %s", driver)
+	}
+
+	return fs.Sub(migrations, driver)
+}
@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE USERS ADD COLUMN must_change_password BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE PASSWORD_HISTORY (
+  id VARCHAR(26) PRIMARY KEY NOT NULL, -- ULID
+  user_id VARCHAR(26) NOT NULL,
+  password_hash VARCHAR(255) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  FOREIGN KEY (user_id) REFERENCES USERS(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_password_history_user ON PASSWORD_HISTORY(user_id, created_at);
+
+-- La contraseña vigente de cada usuario forma parte del historial.
+INSERT INTO PASSWORD_HISTORY (id, user_id, password_hash, created_at)
+SELECT id, id, password_hash, created_at FROM USERS;
+
+-- +goose Down
+-- El índice respalda la llave foránea, por lo que se elimina junto con la tabla.
+DROP TABLE PASSWORD_HISTORY;
+
+ALTER TABLE USERS DROP COLUMN must_change_password;
@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE USERS
+  ADD COLUMN failed_login_attempts INT NOT NULL DEFAULT 0,
+  ADD COLUMN last_failed_login_at DATETIME NULL,
+  ADD COLUMN locked_at DATETIME NULL;
+
+CREATE TABLE LOGIN_ATTEMPTS (
+  id VARCHAR(26) PRIMARY KEY NOT NULL, -- ULID
+  username VARCHAR(255) NOT NULL COLLATE utf8mb4_general_ci,
+  ip VARCHAR(45) NOT NULL,
+  success BOOLEAN NOT NULL,
+  reason VARCHAR(20) NOT NULL,
+  created_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_login_attempts_ip ON LOGIN_ATTEMPTS(ip, created_at);
+CREATE INDEX idx_login_attempts_username ON LOGIN_ATTEMPTS(username, created_at);
+
+-- +goose Down
+DROP TABLE LOGIN_ATTEMPTS;
+
+ALTER TABLE USERS
+  DROP COLUMN failed_login_attempts,
+  DROP COLUMN last_failed_login_at,
+  DROP COLUMN locked_at;
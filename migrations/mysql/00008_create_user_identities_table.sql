@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE USER_IDENTITIES (
+  id VARCHAR(26) PRIMARY KEY NOT NULL, -- ULID
+  user_id VARCHAR(26) NOT NULL,
+  issuer VARCHAR(255) NOT NULL COLLATE utf8mb4_bin,
+  subject VARCHAR(255) NOT NULL COLLATE utf8mb4_bin,
+  email VARCHAR(255) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL,
+
+  FOREIGN KEY (user_id) REFERENCES USERS(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_user_identities_subject ON USER_IDENTITIES(issuer, subject);
+CREATE INDEX idx_user_identities_user ON USER_IDENTITIES(user_id);
+
+-- +goose Down
+DROP TABLE USER_IDENTITIES;
@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE USERS ADD COLUMN deleted_at DATETIME NULL;
+
+-- El nombre de usuario solo es único entre los usuarios no eliminados, para poder reutilizarlo.
+DROP INDEX username ON USERS;
+DROP INDEX idx_users_username ON USERS;
+
+CREATE UNIQUE INDEX idx_users_username ON USERS((CASE WHEN deleted_at IS NULL THEN username END));
+CREATE INDEX idx_users_username_lookup ON USERS(username, deleted_at);
+
+-- +goose Down
+DROP INDEX idx_users_username_lookup ON USERS;
+DROP INDEX idx_users_username ON USERS;
+
+-- Falla si un nombre de usuario eliminado fue reutilizado.
+CREATE UNIQUE INDEX idx_users_username ON USERS(username);
+
+ALTER TABLE USERS DROP COLUMN deleted_at;
@@ -0,0 +1,36 @@
+-- +goose Up
+-- Registros cerrados movidos fuera de PARKING_RECORDS por la política de retención. Sin llaves
+-- foráneas: el archivo debe sobrevivir a cambios en usuarios y tipos de vehículo.
+CREATE TABLE PARKING_RECORDS_ARCHIVE (
+  id VARCHAR(26) PRIMARY KEY NOT NULL, -- ULID
+  user_id VARCHAR(26) NOT NULL,
+  vehicle_type_id VARCHAR(26) NOT NULL,
+  license_plate VARCHAR(20) NOT NULL COLLATE utf8mb4_general_ci, -- Placa o seudónimo
+  entry_time DATETIME NOT NULL,
+  exit_time DATETIME NOT NULL,
+  total_charge DECIMAL(10, 2) NULL,
+  calculated_hours INT NULL,
+  archived_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_parking_records_archive_exit_time ON PARKING_RECORDS_ARCHIVE(exit_time);
+CREATE INDEX idx_parking_records_archive_user ON PARKING_RECORDS_ARCHIVE(user_id);
+
+-- Totales diarios de los registros archivados; los reportes los combinan con PARKING_RECORDS.
+CREATE TABLE PARKING_RECORDS_DAILY (
+  day CHAR(10) NOT NULL, -- YYYY-MM-DD (UTC) de la salida
+  vehicle_type_id VARCHAR(26) NOT NULL,
+  records INT NOT NULL,
+  hours INT NOT NULL,
+  revenue DECIMAL(12, 2) NOT NULL,
+
+  PRIMARY KEY (day, vehicle_type_id)
+);
+
+-- +goose Down
+DROP TABLE PARKING_RECORDS_DAILY;
+
+DROP INDEX idx_parking_records_archive_user ON PARKING_RECORDS_ARCHIVE;
+DROP INDEX idx_parking_records_archive_exit_time ON PARKING_RECORDS_ARCHIVE;
+
+DROP TABLE PARKING_RECORDS_ARCHIVE;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Bandeja de salida: eventos guardados en la misma transacción que el cambio que los origina,
+-- pendientes de entregarse a los consumidores durables (ej. webhooks).
+CREATE TABLE OUTBOX (
+  id VARCHAR(26) PRIMARY KEY NOT NULL, -- ULID del evento
+  event_type VARCHAR(50) NOT NULL,
+  payload LONGTEXT NOT NULL,
+  occurred_at DATETIME NOT NULL,
+  processed_at DATETIME NULL
+);
+
+CREATE INDEX idx_outbox_processed ON OUTBOX(processed_at, id);
+
+-- +goose Down
+DROP TABLE OUTBOX;
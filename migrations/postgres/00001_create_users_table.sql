@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE USERS (
+  id VARCHAR(26) PRIMARY KEY NOT NULL, -- ULID
+  username VARCHAR(255) NOT NULL,
+  password_hash VARCHAR(255) NOT NULL,
+  role VARCHAR(50) NOT NULL CONSTRAINT chk_users_role CHECK (role IN ('admin', 'common')),
+  is_active BOOLEAN DEFAULT TRUE,
+  created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Los nombres de usuario no distinguen mayúsculas de minúsculas.
+CREATE UNIQUE INDEX idx_users_username ON USERS(lower(username));
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_users_username;
+
+DROP TABLE USERS;
@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE PARKING_RECORDS (
+  id VARCHAR(26) PRIMARY KEY NOT NULL, -- ULID
+  user_id VARCHAR(26) NOT NULL,
+  vehicle_type_id VARCHAR(26) NOT NULL,
+  license_plate VARCHAR(10) NOT NULL,
+  entry_time TIMESTAMPTZ NOT NULL,
+  exit_time TIMESTAMPTZ NULL,
+  total_charge NUMERIC(10, 2) NULL,
+  calculated_hours INT NULL,
+
+  FOREIGN KEY (user_id) REFERENCES USERS(id),
+  FOREIGN KEY (vehicle_type_id) REFERENCES VEHICLE_TYPES(id)
+);
+
+-- Una placa solo puede tener un registro abierto, sin distinguir mayúsculas de minúsculas.
+CREATE UNIQUE INDEX idx_parking_records_one_active ON PARKING_RECORDS(lower(license_plate)) WHERE exit_time IS NULL;
+CREATE INDEX idx_parking_records_plate_active ON PARKING_RECORDS(lower(license_plate), exit_time);
+CREATE INDEX idx_parking_records_exit_time ON PARKING_RECORDS(exit_time);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_parking_records_one_active;
+DROP INDEX IF EXISTS idx_parking_records_plate_active;
+DROP INDEX IF EXISTS idx_parking_records_exit_time;
+
+DROP TABLE PARKING_RECORDS;
@@ -0,0 +1,60 @@
+-- +goose Up
+CREATE TABLE ROLES (
+  name VARCHAR(50) PRIMARY KEY NOT NULL,
+  description VARCHAR(255) NOT NULL DEFAULT '',
+  is_system BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE ROLE_PERMISSIONS (
+  role_name VARCHAR(50) NOT NULL,
+  permission VARCHAR(50) NOT NULL,
+
+  PRIMARY KEY (role_name, permission),
+  FOREIGN KEY (role_name) REFERENCES ROLES(name) ON DELETE CASCADE
+);
+
+INSERT INTO ROLES (name, description, is_system) VALUES
+('admin', 'Acceso total al sistema', TRUE),
+('common', 'Operador de estacionamiento', TRUE),
+('supervisor', 'Supervisa la operación: anula registros y consulta reportes', TRUE),
+('cashier', 'Registra entradas y salidas', TRUE),
+('auditor', 'Acceso de solo lectura', TRUE);
+
+INSERT INTO ROLE_PERMISSIONS (role_name, permission) VALUES
+('admin', 'parking:read'),
+('admin', 'parking:write'),
+('admin', 'parking:void'),
+('admin', 'reports:read'),
+('admin', 'vehicle_types:read'),
+('admin', 'users:read'),
+('admin', 'users:manage'),
+('admin', 'roles:manage'),
+('admin', 'audit:read'),
+('common', 'parking:read'),
+('common', 'parking:write'),
+('common', 'vehicle_types:read'),
+('supervisor', 'parking:read'),
+('supervisor', 'parking:write'),
+('supervisor', 'parking:void'),
+('supervisor', 'reports:read'),
+('supervisor', 'vehicle_types:read'),
+('cashier', 'parking:read'),
+('cashier', 'parking:write'),
+('cashier', 'vehicle_types:read'),
+('auditor', 'parking:read'),
+('auditor', 'reports:read'),
+('auditor', 'vehicle_types:read'),
+('auditor', 'users:read'),
+('auditor', 'audit:read');
+
+-- Los roles dejan de ser un conjunto fijo y pasan a ser una llave foránea.
+ALTER TABLE USERS DROP CONSTRAINT chk_users_role;
+ALTER TABLE USERS ADD CONSTRAINT fk_users_role FOREIGN KEY (role) REFERENCES ROLES(name);
+
+-- +goose Down
+ALTER TABLE USERS DROP CONSTRAINT fk_users_role;
+ALTER TABLE USERS ADD CONSTRAINT chk_users_role CHECK (role IN ('admin', 'common'));
+
+DROP TABLE ROLE_PERMISSIONS;
+DROP TABLE ROLES;
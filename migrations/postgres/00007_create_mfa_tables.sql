@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE USER_MFA (
+  user_id VARCHAR(26) PRIMARY KEY NOT NULL,
+  secret VARCHAR(64) NOT NULL, -- Base32
+  enabled_at TIMESTAMPTZ NULL,
+  last_used_step BIGINT NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL,
+
+  FOREIGN KEY (user_id) REFERENCES USERS(id) ON DELETE CASCADE
+);
+
+CREATE TABLE MFA_RECOVERY_CODES (
+  id VARCHAR(26) PRIMARY KEY NOT NULL, -- ULID
+  user_id VARCHAR(26) NOT NULL,
+  code_hash CHAR(64) NOT NULL, -- SHA-256
+  used_at TIMESTAMPTZ NULL,
+
+  FOREIGN KEY (user_id) REFERENCES USERS(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_mfa_recovery_codes_user ON MFA_RECOVERY_CODES(user_id, code_hash);
+
+-- +goose Down
+DROP TABLE MFA_RECOVERY_CODES;
+
+DROP TABLE USER_MFA;
@@ -0,0 +1,54 @@
+-- +goose Up
+CREATE TABLE AUDIT_LOG (
+  id VARCHAR(26) PRIMARY KEY NOT NULL, -- ULID
+  seq BIGINT NOT NULL,
+  actor_id VARCHAR(26) NOT NULL DEFAULT '', -- Vacío para acciones del sistema
+  action VARCHAR(50) NOT NULL,
+  entity_type VARCHAR(50) NOT NULL,
+  entity_id VARCHAR(255) NOT NULL,
+  before_data TEXT NULL, -- JSON; se guarda como texto para conservar los bytes del hash
+  after_data TEXT NULL,
+  ip VARCHAR(45) NOT NULL DEFAULT '',
+  request_id VARCHAR(255) NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL,
+  prev_hash CHAR(64) NOT NULL, -- SHA-256
+  hash CHAR(64) NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_audit_log_seq ON AUDIT_LOG(seq);
+CREATE INDEX idx_audit_log_actor ON AUDIT_LOG(actor_id, seq);
+CREATE INDEX idx_audit_log_entity ON AUDIT_LOG(entity_type, entity_id, seq);
+CREATE INDEX idx_audit_log_created_at ON AUDIT_LOG(created_at);
+
+-- Última entrada de la cadena. La fila única sirve además como bloqueo para serializar las inserciones.
+CREATE TABLE AUDIT_CHAIN_HEAD (
+  id INT PRIMARY KEY NOT NULL,
+  seq BIGINT NOT NULL,
+  hash CHAR(64) NOT NULL
+);
+
+INSERT INTO AUDIT_CHAIN_HEAD (id, seq, hash)
+VALUES (1, 0, '0000000000000000000000000000000000000000000000000000000000000000');
+
+-- +goose StatementBegin
+CREATE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'AUDIT_LOG es de solo inserción';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON AUDIT_LOG
+FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON AUDIT_LOG
+FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS audit_log_no_delete ON AUDIT_LOG;
+DROP TRIGGER IF EXISTS audit_log_no_update ON AUDIT_LOG;
+DROP FUNCTION IF EXISTS audit_log_append_only();
+
+DROP TABLE AUDIT_CHAIN_HEAD;
+
+DROP TABLE AUDIT_LOG;
@@ -0,0 +1,39 @@
+-- +goose Up
+CREATE TABLE WEBHOOK_SUBSCRIPTIONS (
+  id VARCHAR(26) PRIMARY KEY NOT NULL, -- ULID
+  url VARCHAR(2048) NOT NULL,
+  event_types VARCHAR(255) NOT NULL, -- Separados por comas
+  secret VARCHAR(255) NOT NULL,
+  is_active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMPTZ NOT NULL
+);
+
+-- Bandeja de salida y registro de entregas. Las filas pendientes se insertan en la misma
+-- transacción que el cambio que origina el evento.
+CREATE TABLE WEBHOOK_DELIVERIES (
+  id VARCHAR(26) PRIMARY KEY NOT NULL, -- ULID
+  subscription_id VARCHAR(26) NOT NULL,
+  event_id VARCHAR(26) NOT NULL,
+  event_type VARCHAR(50) NOT NULL,
+  payload TEXT NOT NULL,
+  status VARCHAR(20) NOT NULL, -- pending, delivered, failed
+  attempts INT NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ NOT NULL,
+  last_status_code INT NOT NULL DEFAULT 0,
+  last_error VARCHAR(500) NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL,
+  delivered_at TIMESTAMPTZ NULL,
+
+  FOREIGN KEY (subscription_id) REFERENCES WEBHOOK_SUBSCRIPTIONS(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON WEBHOOK_DELIVERIES(status, next_attempt_at);
+CREATE INDEX idx_webhook_deliveries_subscription ON WEBHOOK_DELIVERIES(subscription_id, created_at);
+
+INSERT INTO ROLE_PERMISSIONS (role_name, permission) VALUES ('admin', 'webhooks:manage');
+
+-- +goose Down
+DELETE FROM ROLE_PERMISSIONS WHERE permission = 'webhooks:manage';
+
+DROP TABLE WEBHOOK_DELIVERIES;
+DROP TABLE WEBHOOK_SUBSCRIPTIONS;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Versión de los tokens del usuario; al incrementarla se revocan todos los tokens emitidos.
+ALTER TABLE USERS ADD COLUMN token_version INT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE USERS DROP COLUMN token_version;
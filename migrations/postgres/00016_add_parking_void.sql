@@ -0,0 +1,22 @@
+-- +goose Up
+-- Un registro anulado se conserva, pero no cuenta en los reportes ni en los totales diarios.
+ALTER TABLE PARKING_RECORDS
+  ADD COLUMN voided_at TIMESTAMPTZ NULL,
+  ADD COLUMN voided_by VARCHAR(26) NULL,
+  ADD COLUMN void_reason VARCHAR(255) NULL;
+
+ALTER TABLE PARKING_RECORDS_ARCHIVE
+  ADD COLUMN voided_at TIMESTAMPTZ NULL,
+  ADD COLUMN voided_by VARCHAR(26) NULL,
+  ADD COLUMN void_reason VARCHAR(255) NULL;
+
+-- +goose Down
+ALTER TABLE PARKING_RECORDS_ARCHIVE
+  DROP COLUMN voided_at,
+  DROP COLUMN voided_by,
+  DROP COLUMN void_reason;
+
+ALTER TABLE PARKING_RECORDS
+  DROP COLUMN voided_at,
+  DROP COLUMN voided_by,
+  DROP COLUMN void_reason;
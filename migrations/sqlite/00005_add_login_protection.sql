@@ -0,0 +1,26 @@
+-- +goose Up
+ALTER TABLE USERS ADD COLUMN failed_login_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE USERS ADD COLUMN last_failed_login_at DATETIME;
+ALTER TABLE USERS ADD COLUMN locked_at DATETIME;
+
+CREATE TABLE LOGIN_ATTEMPTS (
+  id TEXT PRIMARY KEY NOT NULL, -- ULID
+  username TEXT NOT NULL COLLATE NOCASE,
+  ip TEXT NOT NULL,
+  success INTEGER NOT NULL,
+  reason TEXT NOT NULL,
+  created_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_login_attempts_ip ON LOGIN_ATTEMPTS(ip, created_at);
+CREATE INDEX idx_login_attempts_username ON LOGIN_ATTEMPTS(username, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_login_attempts_ip;
+DROP INDEX IF EXISTS idx_login_attempts_username;
+
+DROP TABLE LOGIN_ATTEMPTS;
+
+ALTER TABLE USERS DROP COLUMN failed_login_attempts;
+ALTER TABLE USERS DROP COLUMN last_failed_login_at;
+ALTER TABLE USERS DROP COLUMN locked_at;
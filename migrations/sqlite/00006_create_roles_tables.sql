@@ -0,0 +1,97 @@
+-- +goose Up
+CREATE TABLE ROLES (
+  name TEXT PRIMARY KEY NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  is_system INTEGER NOT NULL DEFAULT 0,
+  created_at DATETIME NOT NULL DEFAULT (CURRENT_TIMESTAMP)
+);
+
+CREATE TABLE ROLE_PERMISSIONS (
+  role_name TEXT NOT NULL,
+  permission TEXT NOT NULL,
+
+  PRIMARY KEY (role_name, permission),
+  FOREIGN KEY (role_name) REFERENCES ROLES(name) ON DELETE CASCADE
+);
+
+INSERT INTO ROLES (name, description, is_system) VALUES
+('admin', 'Acceso total al sistema', 1),
+('common', 'Operador de estacionamiento', 1),
+('supervisor', 'Supervisa la operación: anula registros y consulta reportes', 1),
+('cashier', 'Registra entradas y salidas', 1),
+('auditor', 'Acceso de solo lectura', 1);
+
+INSERT INTO ROLE_PERMISSIONS (role_name, permission) VALUES
+('admin', 'parking:read'),
+('admin', 'parking:write'),
+('admin', 'parking:void'),
+('admin', 'reports:read'),
+('admin', 'vehicle_types:read'),
+('admin', 'users:read'),
+('admin', 'users:manage'),
+('admin', 'roles:manage'),
+('admin', 'audit:read'),
+('common', 'parking:read'),
+('common', 'parking:write'),
+('common', 'vehicle_types:read'),
+('supervisor', 'parking:read'),
+('supervisor', 'parking:write'),
+('supervisor', 'parking:void'),
+('supervisor', 'reports:read'),
+('supervisor', 'vehicle_types:read'),
+('cashier', 'parking:read'),
+('cashier', 'parking:write'),
+('cashier', 'vehicle_types:read'),
+('auditor', 'parking:read'),
+('auditor', 'reports:read'),
+('auditor', 'vehicle_types:read'),
+('auditor', 'users:read'),
+('auditor', 'audit:read');
+
+-- SQLite no permite modificar el CHECK de la columna role, por lo que se reconstruye la tabla
+-- para reemplazarlo por una referencia a ROLES.
+CREATE TABLE USERS_NEW (
+  id TEXT PRIMARY KEY NOT NULL, -- ULID
+  username TEXT NOT NULL,
+  password_hash TEXT NOT NULL,
+  role TEXT NOT NULL REFERENCES ROLES(name),
+  is_active INTEGER NOT NULL DEFAULT 1,
+  created_at DATETIME NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  must_change_password INTEGER NOT NULL DEFAULT 0,
+  failed_login_attempts INTEGER NOT NULL DEFAULT 0,
+  last_failed_login_at DATETIME,
+  locked_at DATETIME
+);
+
+INSERT INTO USERS_NEW (id, username, password_hash, role, is_active, created_at, must_change_password, failed_login_attempts, last_failed_login_at, locked_at)
+SELECT id, username, password_hash, role, is_active, created_at, must_change_password, failed_login_attempts, last_failed_login_at, locked_at FROM USERS;
+
+DROP TABLE USERS;
+ALTER TABLE USERS_NEW RENAME TO USERS;
+
+CREATE UNIQUE INDEX idx_users_username ON USERS(username COLLATE NOCASE);
+
+-- +goose Down
+CREATE TABLE USERS_OLD (
+  id TEXT PRIMARY KEY NOT NULL, -- ULID
+  username TEXT NOT NULL,
+  password_hash TEXT NOT NULL,
+  role TEXT NOT NULL CHECK(role IN ('admin', 'common')),
+  is_active INTEGER NOT NULL DEFAULT 1,
+  created_at DATETIME NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  must_change_password INTEGER NOT NULL DEFAULT 0,
+  failed_login_attempts INTEGER NOT NULL DEFAULT 0,
+  last_failed_login_at DATETIME,
+  locked_at DATETIME
+);
+
+INSERT INTO USERS_OLD (id, username, password_hash, role, is_active, created_at, must_change_password, failed_login_attempts, last_failed_login_at, locked_at)
+SELECT id, username, password_hash, role, is_active, created_at, must_change_password, failed_login_attempts, last_failed_login_at, locked_at FROM USERS;
+
+DROP TABLE USERS;
+ALTER TABLE USERS_OLD RENAME TO USERS;
+
+CREATE UNIQUE INDEX idx_users_username ON USERS(username COLLATE NOCASE);
+
+DROP TABLE ROLE_PERMISSIONS;
+DROP TABLE ROLES;
@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TABLE USER_MFA (
+  user_id TEXT PRIMARY KEY NOT NULL,
+  secret TEXT NOT NULL, -- Base32
+  enabled_at DATETIME,
+  last_used_step INTEGER NOT NULL DEFAULT 0,
+  created_at DATETIME NOT NULL,
+
+  FOREIGN KEY (user_id) REFERENCES USERS(id) ON DELETE CASCADE
+);
+
+CREATE TABLE MFA_RECOVERY_CODES (
+  id TEXT PRIMARY KEY NOT NULL, -- ULID
+  user_id TEXT NOT NULL,
+  code_hash TEXT NOT NULL, -- SHA-256
+  used_at DATETIME,
+
+  FOREIGN KEY (user_id) REFERENCES USERS(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_mfa_recovery_codes_user ON MFA_RECOVERY_CODES(user_id, code_hash);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_mfa_recovery_codes_user;
+
+DROP TABLE MFA_RECOVERY_CODES;
+
+DROP TABLE USER_MFA;
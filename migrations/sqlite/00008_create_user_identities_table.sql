@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE USER_IDENTITIES (
+  id TEXT PRIMARY KEY NOT NULL, -- ULID
+  user_id TEXT NOT NULL,
+  issuer TEXT NOT NULL,
+  subject TEXT NOT NULL,
+  email TEXT NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL,
+
+  FOREIGN KEY (user_id) REFERENCES USERS(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_user_identities_subject ON USER_IDENTITIES(issuer, subject);
+CREATE INDEX idx_user_identities_user ON USER_IDENTITIES(user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_user_identities_subject;
+DROP INDEX IF EXISTS idx_user_identities_user;
+
+DROP TABLE USER_IDENTITIES;
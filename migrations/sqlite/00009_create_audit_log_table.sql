@@ -0,0 +1,58 @@
+-- +goose Up
+CREATE TABLE AUDIT_LOG (
+  id TEXT PRIMARY KEY NOT NULL, -- ULID
+  seq INTEGER NOT NULL,
+  actor_id TEXT NOT NULL DEFAULT '', -- Vacío para acciones del sistema
+  action TEXT NOT NULL,
+  entity_type TEXT NOT NULL,
+  entity_id TEXT NOT NULL,
+  before_data TEXT, -- JSON
+  after_data TEXT,
+  ip TEXT NOT NULL DEFAULT '',
+  request_id TEXT NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL,
+  prev_hash TEXT NOT NULL, -- SHA-256
+  hash TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_audit_log_seq ON AUDIT_LOG(seq);
+CREATE INDEX idx_audit_log_actor ON AUDIT_LOG(actor_id, seq);
+CREATE INDEX idx_audit_log_entity ON AUDIT_LOG(entity_type, entity_id, seq);
+CREATE INDEX idx_audit_log_created_at ON AUDIT_LOG(created_at);
+
+-- Última entrada de la cadena. La fila única sirve además como bloqueo para serializar las inserciones.
+CREATE TABLE AUDIT_CHAIN_HEAD (
+  id INTEGER PRIMARY KEY NOT NULL,
+  seq INTEGER NOT NULL,
+  hash TEXT NOT NULL
+);
+
+INSERT INTO AUDIT_CHAIN_HEAD (id, seq, hash)
+VALUES (1, 0, '0000000000000000000000000000000000000000000000000000000000000000');
+
+-- +goose StatementBegin
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON AUDIT_LOG
+BEGIN
+  SELECT RAISE(ABORT, 'AUDIT_LOG es de solo inserción');
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON AUDIT_LOG
+BEGIN
+  SELECT RAISE(ABORT, 'AUDIT_LOG es de solo inserción');
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS audit_log_no_delete;
+DROP TRIGGER IF EXISTS audit_log_no_update;
+
+DROP INDEX IF EXISTS idx_audit_log_seq;
+DROP INDEX IF EXISTS idx_audit_log_actor;
+DROP INDEX IF EXISTS idx_audit_log_entity;
+DROP INDEX IF EXISTS idx_audit_log_created_at;
+
+DROP TABLE AUDIT_CHAIN_HEAD;
+
+DROP TABLE AUDIT_LOG;
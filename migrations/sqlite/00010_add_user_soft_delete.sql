@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE USERS ADD COLUMN deleted_at DATETIME;
+
+-- El nombre de usuario solo es único entre los usuarios no eliminados, para poder reutilizarlo.
+DROP INDEX IF EXISTS idx_users_username;
+
+CREATE UNIQUE INDEX idx_users_username ON USERS(username COLLATE NOCASE) WHERE deleted_at IS NULL;
+CREATE INDEX idx_users_username_lookup ON USERS(username COLLATE NOCASE, deleted_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_users_username_lookup;
+DROP INDEX IF EXISTS idx_users_username;
+
+-- Falla si un nombre de usuario eliminado fue reutilizado.
+CREATE UNIQUE INDEX idx_users_username ON USERS(username COLLATE NOCASE);
+
+ALTER TABLE USERS DROP COLUMN deleted_at;
@@ -0,0 +1,36 @@
+-- +goose Up
+-- Registros cerrados movidos fuera de PARKING_RECORDS por la política de retención. Sin llaves
+-- foráneas: el archivo debe sobrevivir a cambios en usuarios y tipos de vehículo.
+CREATE TABLE PARKING_RECORDS_ARCHIVE (
+  id TEXT PRIMARY KEY NOT NULL, -- ULID
+  user_id TEXT NOT NULL,
+  vehicle_type_id TEXT NOT NULL,
+  license_plate TEXT NOT NULL, -- Placa o seudónimo
+  entry_time DATETIME NOT NULL,
+  exit_time DATETIME NOT NULL,
+  total_charge REAL,
+  calculated_hours INTEGER,
+  archived_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_parking_records_archive_exit_time ON PARKING_RECORDS_ARCHIVE(exit_time);
+CREATE INDEX idx_parking_records_archive_user ON PARKING_RECORDS_ARCHIVE(user_id);
+
+-- Totales diarios de los registros archivados; los reportes los combinan con PARKING_RECORDS.
+CREATE TABLE PARKING_RECORDS_DAILY (
+  day TEXT NOT NULL, -- YYYY-MM-DD (UTC) de la salida
+  vehicle_type_id TEXT NOT NULL,
+  records INTEGER NOT NULL,
+  hours INTEGER NOT NULL,
+  revenue REAL NOT NULL,
+
+  PRIMARY KEY (day, vehicle_type_id)
+);
+
+-- +goose Down
+DROP TABLE PARKING_RECORDS_DAILY;
+
+DROP INDEX IF EXISTS idx_parking_records_archive_user;
+DROP INDEX IF EXISTS idx_parking_records_archive_exit_time;
+
+DROP TABLE PARKING_RECORDS_ARCHIVE;
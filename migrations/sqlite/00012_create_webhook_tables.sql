@@ -0,0 +1,42 @@
+-- +goose Up
+CREATE TABLE WEBHOOK_SUBSCRIPTIONS (
+  id TEXT PRIMARY KEY NOT NULL, -- ULID
+  url TEXT NOT NULL,
+  event_types TEXT NOT NULL, -- Separados por comas
+  secret TEXT NOT NULL,
+  is_active INTEGER NOT NULL DEFAULT 1,
+  created_at DATETIME NOT NULL
+);
+
+-- Bandeja de salida y registro de entregas. Las filas pendientes se insertan en la misma
+-- transacción que el cambio que origina el evento.
+CREATE TABLE WEBHOOK_DELIVERIES (
+  id TEXT PRIMARY KEY NOT NULL, -- ULID
+  subscription_id TEXT NOT NULL,
+  event_id TEXT NOT NULL,
+  event_type TEXT NOT NULL,
+  payload TEXT NOT NULL,
+  status TEXT NOT NULL, -- pending, delivered, failed
+  attempts INTEGER NOT NULL DEFAULT 0,
+  next_attempt_at DATETIME NOT NULL,
+  last_status_code INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL,
+  delivered_at DATETIME,
+
+  FOREIGN KEY (subscription_id) REFERENCES WEBHOOK_SUBSCRIPTIONS(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON WEBHOOK_DELIVERIES(status, next_attempt_at);
+CREATE INDEX idx_webhook_deliveries_subscription ON WEBHOOK_DELIVERIES(subscription_id, created_at);
+
+INSERT INTO ROLE_PERMISSIONS (role_name, permission) VALUES ('admin', 'webhooks:manage');
+
+-- +goose Down
+DELETE FROM ROLE_PERMISSIONS WHERE permission = 'webhooks:manage';
+
+DROP INDEX IF EXISTS idx_webhook_deliveries_subscription;
+DROP INDEX IF EXISTS idx_webhook_deliveries_due;
+
+DROP TABLE WEBHOOK_DELIVERIES;
+DROP TABLE WEBHOOK_SUBSCRIPTIONS;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Bandeja de salida: eventos guardados en la misma transacción que el cambio que los origina,
+-- pendientes de entregarse a los consumidores durables (ej. webhooks).
+CREATE TABLE OUTBOX (
+  id TEXT PRIMARY KEY NOT NULL, -- ULID del evento
+  event_type TEXT NOT NULL,
+  payload TEXT NOT NULL,
+  occurred_at DATETIME NOT NULL,
+  processed_at DATETIME
+);
+
+CREATE INDEX idx_outbox_processed ON OUTBOX(processed_at, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_outbox_processed;
+
+DROP TABLE OUTBOX;
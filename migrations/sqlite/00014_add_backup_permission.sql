@@ -0,0 +1,5 @@
+-- +goose Up
+INSERT INTO ROLE_PERMISSIONS (role_name, permission) VALUES ('admin', 'backups:manage');
+
+-- +goose Down
+DELETE FROM ROLE_PERMISSIONS WHERE permission = 'backups:manage';
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Un registro anulado se conserva, pero no cuenta en los reportes ni en los totales diarios.
+ALTER TABLE PARKING_RECORDS ADD COLUMN voided_at DATETIME;
+ALTER TABLE PARKING_RECORDS ADD COLUMN voided_by TEXT;
+ALTER TABLE PARKING_RECORDS ADD COLUMN void_reason TEXT;
+
+ALTER TABLE PARKING_RECORDS_ARCHIVE ADD COLUMN voided_at DATETIME;
+ALTER TABLE PARKING_RECORDS_ARCHIVE ADD COLUMN voided_by TEXT;
+ALTER TABLE PARKING_RECORDS_ARCHIVE ADD COLUMN void_reason TEXT;
+
+-- +goose Down
+ALTER TABLE PARKING_RECORDS_ARCHIVE DROP COLUMN voided_at;
+ALTER TABLE PARKING_RECORDS_ARCHIVE DROP COLUMN voided_by;
+ALTER TABLE PARKING_RECORDS_ARCHIVE DROP COLUMN void_reason;
+
+ALTER TABLE PARKING_RECORDS DROP COLUMN voided_at;
+ALTER TABLE PARKING_RECORDS DROP COLUMN voided_by;
+ALTER TABLE PARKING_RECORDS DROP COLUMN void_reason;
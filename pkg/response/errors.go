@@ -3,29 +3,24 @@ package response
 import "errors"
 
 var (
-	ErrUserIDNotInContext    = errors.New("identidad del usuario no disponible")
-	ErrMissingToken          = errors.New("falta el token de autenticación")
-	ErrMissingMetadata       = errors.New("falta la metadata")
-	ErrInvalidTokenFormat    = errors.New("formato de token inválido")
-	ErrExpiredToken          = errors.New("token expirado")
-	ErrInvalidToken          = errors.New("token inválido")
-	ErrTokenValidationFailed = errors.New("error al validar token")
-	ErrPermissionDenied      = errors.New("permiso denegado")
-
-	ErrInvalidCredentials = errors.New("credenciales inválidas")
-	ErrUserBlocked        = errors.New("usuario inactivo o bloqueado")
+	ErrUserIDNotInContext = errors.New("identidad del usuario no disponible")
+	ErrMissingToken       = errors.New("falta el token de autenticación")
+	ErrMissingMetadata    = errors.New("falta la metadata")
+	ErrInvalidTokenFormat = errors.New("formato de token inválido")
+	ErrPermissionDenied   = errors.New("permiso denegado")
 )
 
 var (
 	ErrRegistryIDRequired   = errors.New("ID de registro es requerido")
-	ErrPlateRequired        = errors.New("la placa es requerida")
-	ErrPlateAndTypeRequired = errors.New("placa y tipo de vehículo son requeridos")
-	ErrUserCreateValidation = errors.New("el nombre de usuario, contraseña y rol son requeridos")
 	ErrInvalidID            = errors.New("ID de usuario inválido o ausente")
-	ErrInvalidRole          = errors.New("rol de usuario inválido. Los roles permitidos son 'admin' y 'common'")
+	ErrRoleNameRequired     = errors.New("el nombre del rol es requerido")
 	ErrChangeOwnRole        = errors.New("no puedes cambiar tu propio rol")
 	ErrOwnDelete            = errors.New("no puedes eliminarte a ti mismo")
+	ErrInvalidDeleteMode    = errors.New("modo de eliminación inválido: use soft, anonymize o hard")
 	ErrUpdateValidation     = errors.New("al menos un campo (username, rol, is_active) debe ser proporcionado para la actualización")
+	ErrInvalidLimit         = errors.New("el parámetro limit debe ser un número entre 1 y 1000")
+	ErrInvalidDeliveryState = errors.New("estado inválido: use pending, delivered o failed")
+	ErrInvalidAuditFilter   = errors.New("filtro inválido: from y to deben usar formato RFC 3339 y before_seq debe ser un número positivo")
 )
 
 var (
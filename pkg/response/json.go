@@ -3,6 +3,7 @@ package response
 import (
 	"encoding/json"
 	"errors"
+	"log/slog"
 	"net/http"
 )
 
@@ -15,9 +16,19 @@ func JSON(w http.ResponseWriter, status int, data any) {
 	json.NewEncoder(w).Encode(data)
 }
 
-func ErrorJSON(w http.ResponseWriter, err error, status int) {
-	JSON(w, status, map[string]any{
-		"status": status,
-		"error":  err.Error(),
+// InternalError responde un error interno genérico sin registrar la causa.
+func InternalError(w http.ResponseWriter, r *http.Request) {
+	WriteProblem(w, r, &Problem{
+		Status: http.StatusInternalServerError,
+		Code:   CodeInternalError,
+		Detail: ErrInternalError.Error(),
 	})
 }
+
+// LogServerError registra la causa de una respuesta 5xx junto con los datos de la solicitud.
+func LogServerError(r *http.Request, err error) {
+	slog.ErrorContext(r.Context(), "error al atender la solicitud",
+		"method", r.Method,
+		"path", r.URL.Path,
+		"error", err)
+}
@@ -0,0 +1,87 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ProblemContentType es el tipo de contenido de las respuestas de error (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// Códigos de error genéricos. Los demás se definen en el catálogo de la API.
+const (
+	CodeInternalError    = "INTERNAL_ERROR"
+	CodeValidationFailed = "VALIDATION_FAILED"
+	CodeRequired         = "REQUIRED"
+)
+
+// Problem es el cuerpo de las respuestas de error, en el formato de RFC 7807. Code identifica el
+// error de forma estable; Detail es el mensaje para el usuario y puede cambiar.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describe un campo inválido de la solicitud (cuerpo o parámetro de consulta).
+type FieldError struct {
+	Field  string `json:"field"`
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+}
+
+// ValidationError agrupa los campos inválidos de una solicitud. Se responde con
+// VALIDATION_FAILED y el detalle de cada campo.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	names := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		names[i] = field.Field
+	}
+
+	return "la solicitud tiene campos inválidos: " + strings.Join(names, ", ")
+}
+
+// Required retorna un error de validación con los campos cuyo valor está vacío, en el orden
+// indicado, o nil si todos tienen valor. fields alterna nombre y valor.
+func Required(fields ...string) error {
+	var invalid []FieldError
+	for i := 0; i+1 < len(fields); i += 2 {
+		if strings.TrimSpace(fields[i+1]) == "" {
+			invalid = append(invalid, FieldError{Field: fields[i], Code: CodeRequired, Detail: "el campo es requerido"})
+		}
+	}
+
+	if len(invalid) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Fields: invalid}
+}
+
+// WriteProblem responde el problema completando el título, la ruta y el ID de la solicitud.
+func WriteProblem(w http.ResponseWriter, r *http.Request, p *Problem) {
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+
+	p.Title = http.StatusText(p.Status)
+	p.Instance = r.URL.Path
+	p.RequestID = middleware.GetReqID(r.Context())
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+
+	json.NewEncoder(w).Encode(p)
+}
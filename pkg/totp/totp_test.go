@@ -0,0 +1,82 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Secreto ASCII "12345678901234567890" del apéndice B de la RFC 6238.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name string
+		unix int64
+		want string
+	}{
+		{"Vector 59", 59, "287082"},
+		{"Vector 1111111109", 1111111109, "081804"},
+		{"Vector 1111111111", 1111111111, "050471"},
+		{"Vector 1234567890", 1234567890, "005924"},
+		{"Vector 2000000000", 2000000000, "279037"},
+		{"Vector 20000000000", 20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Code() = %s, se esperaba %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	current, _ := Code(rfcSecret, Step(now))
+	previous, _ := Code(rfcSecret, Step(now)-1)
+	old, _ := Code(rfcSecret, Step(now)-3)
+
+	tests := []struct {
+		name   string
+		code   string
+		wantOK bool
+		step   int64
+	}{
+		{"Código actual", current, true, Step(now)},
+		{"Periodo anterior dentro del margen", previous, true, Step(now) - 1},
+		{"Código fuera del margen", old, false, 0},
+		{"Longitud inválida", "12345", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(rfcSecret, tt.code, now, 1)
+			if ok != tt.wantOK || step != tt.step {
+				t.Errorf("Validate() = (%d, %t), se esperaba (%d, %t)", step, ok, tt.step, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSecretAndURI(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	if _, err := Code(secret, 1); err != nil {
+		t.Fatalf("el secreto generado no es válido: %v", err)
+	}
+
+	uri := URI("Parking", "admin", secret)
+	if !strings.HasPrefix(uri, "otpauth://totp/Parking:admin?") || !strings.Contains(uri, "secret="+secret) {
+		t.Errorf("URI inesperado: %s", uri)
+	}
+}
@@ -46,7 +46,7 @@ function Toolbar({ title, role }: ToolbarProps) {
       });
 
       const data = await res.json();
-      if (!res.ok) throw new Error(data.error);
+      if (!res.ok) throw new Error(data.detail);
 
       setShowModal(false);
       setUsername("");
@@ -59,7 +59,7 @@ function Dashboard() {
           return;
         }
 
-        throw new Error(data.error);
+        throw new Error(data.detail);
       }
 
       setTypes(data as VehicleType[]);
@@ -89,7 +89,7 @@ function Dashboard() {
           return;
         }
 
-        throw new Error(data.error);
+        throw new Error(data.detail);
       }
 
       setRecords(data as Record[]);
@@ -144,7 +144,7 @@ function Dashboard() {
 
       const data = await res.json();
 
-      if (!res.ok) throw new Error(data.error);
+      if (!res.ok) throw new Error(data.detail);
       setLicensePlate("");
 
       if (isCurrent) await fetchRecords();
@@ -176,7 +176,7 @@ function Dashboard() {
 
       const data = await res.json();
 
-      if (!res.ok) throw new Error(data.error);
+      if (!res.ok) throw new Error(data.detail);
       await fetchRecords();
     } catch (err) {
       console.error(err);
@@ -34,7 +34,7 @@ function Login() {
       const data = await res.json();
 
       if (!res.ok) {
-        setError(data.error || "error al iniciar sesión");
+        setError(data.detail || "error al iniciar sesión");
         return;
       }
 
@@ -52,7 +52,7 @@ function User() {
           return;
         }
 
-        throw new Error(data.error);
+        throw new Error(data.detail);
       }
 
       setUsers(data as User[]);
@@ -126,7 +126,7 @@ function User() {
       });
 
       const data = await res.json();
-      if (!res.ok) throw new Error(data.error);
+      if (!res.ok) throw new Error(data.detail);
 
       fetchUsers();
       setShowCreate(false);
@@ -169,7 +169,7 @@ function User() {
       });
 
       const data = await res.json();
-      if (!res.ok) throw new Error(data.error);
+      if (!res.ok) throw new Error(data.detail);
 
       fetchUsers();
       setShowUpdate(null);
@@ -197,7 +197,7 @@ function User() {
       });
 
       const data = await res.json();
-      if (!res.ok) throw new Error(data.error);
+      if (!res.ok) throw new Error(data.detail);
 
       fetchUsers();
     } catch (err) {